@@ -0,0 +1,576 @@
+package app
+
+import (
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	fbfirestore "cloud.google.com/go/firestore"
+	fb "firebase.google.com/go/v4"
+	fbauth "firebase.google.com/go/v4/auth"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/auth/store"
+	"github.com/dkinzler/linkboards/internal/auth/tokens"
+	"github.com/dkinzler/linkboards/internal/boards"
+	boardsdomain "github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/dashboard"
+	"github.com/dkinzler/linkboards/internal/graph"
+	"github.com/dkinzler/linkboards/internal/health"
+	"github.com/dkinzler/linkboards/internal/links"
+	linksdomain "github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+	"github.com/dkinzler/linkboards/internal/realtime"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	lfb "github.com/dkinzler/kit/firebase"
+
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	dhttp "github.com/dkinzler/kit/transport/http"
+
+	"github.com/dkinzler/kit/log"
+)
+
+type Config struct {
+	Port    int
+	Address string
+	// If not 0, the API is also served using gRPC on this port.
+	GRPCPort int
+
+	// If true, use in memory data stores and authentication mechanism.
+	UseInmemDependencies bool
+
+	// If true, attempt to connect to firebase emulators to use for authentication and data stores.
+	// Note that the emulators will have to be running already.
+	UseFirebaseEmulators bool
+	FirebaseProjectId    string
+	// If not empty, try to connect to firebase services using the given service account file.
+	// If empty, attempt to use application default credentials.
+	// These are usually set automatically if the application is run in a google cloud product like Cloud Run or App Engine.
+	FirebaseServiceAccountFile string
+
+	// Secret used to create and verify the tokens that authenticate requests for board feeds.
+	// If empty, a random secret is generated, i.e. feed tokens will no longer be valid after a restart.
+	FeedTokenSecret string
+
+	// Authentication mechanism, either "firebase" or "jwt".
+	// If empty, Firebase authentication is used, or the fake authentication mechanism if UseInmemDependencies is true.
+	// With "jwt", requests are authenticated using JSON Web Tokens verified with one of
+	// JWTSecret (HS256), JWTPublicKeyFile (RS256) or JWKSURL.
+	AuthMode  string
+	JWTSecret string
+	// Path to a PEM encoded RSA public key.
+	JWTPublicKeyFile string
+	JWKSURL          string
+	// If not empty, tokens must have been issued by this issuer.
+	JWTIssuer string
+	// If not empty, tokens must have been issued for this audience.
+	JWTAudience  string
+	JWTClockSkew time.Duration
+	// Claims that contain the id and name of the user, default to "sub" and "name".
+	JWTUserIdClaim string
+	JWTNameClaim   string
+
+	// How long the roles of users are cached, caching is disabled if 0.
+	AuthCacheTTL time.Duration
+	// Maximum number of cached roles.
+	AuthCacheSize int
+
+	// Limits for the number of users and invites of boards and the size of boards and links,
+	// zero values are replaced by the defaults of the domain packages.
+	BoardLimits boardsdomain.LimitsConfig
+	LinkLimits  linksdomain.LimitsConfig
+	// Maximum rate of requests per user for endpoints, e.g. "createLink".
+	// Endpoints without a limit are not limited.
+	RateLimits ratelimit.Limits
+
+	// Origins of browser applications that are allowed to make cross-origin requests, "*" allows all origins.
+	// If empty, cross-origin requests are not allowed.
+	CORSAllowedOrigins []string
+
+	// Maximum duration of a single check of the /readyz endpoint.
+	ReadinessCheckTimeout time.Duration
+
+	// If true, Prometheus metrics of endpoints, data stores and the authorization cache are served on /metrics.
+	EnableMetrics bool
+	// Requests for /metrics must send this token in an Authorization header "Bearer <token>".
+	// If empty, metrics are disabled.
+	MetricsToken string
+
+	// Exporter for traces, either "stdout" or "otlp", tracing is disabled if empty.
+	TracingExporter string
+	// Endpoint of the OTLP collector (e.g. "localhost:4318"), if empty the default of the OTLP exporter is used.
+	OTLPEndpoint string
+	// If true, spans are sent to the OTLP collector using HTTP instead of HTTPS.
+	OTLPInsecure bool
+	// Fraction of new traces that are sampled, requests that continue a trace are sampled if the caller sampled them.
+	TracingSampleRatio float64
+
+	// In debug mode:
+	//   - log messages with level Debug will be output
+	//   - log messages will be pretty printed as JSON with multiple indented lines
+	DebugMode bool
+
+	// Version and git commit of the application, returned by the /version endpoint.
+	Version string
+	Commit  string
+}
+
+// The components and http handlers of the application.
+type App struct {
+	BoardComponent *boards.Component
+	LinksComponent *links.Component
+	// Distributes board and link events to the clients streaming the events of a board.
+	// Closing it ends all open streams.
+	Hub *realtime.Hub
+
+	// Serves all requests except streams, wrapped with the CORS handler if enabled.
+	Handler http.Handler
+	// Routes of Handler, without the CORS handler.
+	Router *mux.Router
+	// Serves long-lived streams, i.e. server-sent events and WebSocket connections.
+	StreamRouter *mux.Router
+
+	// Nil if tracing is disabled.
+	TracerProvider *sdktrace.TracerProvider
+}
+
+// Creates the components of the application and registers their http handlers.
+// Does not start any servers.
+func New(config Config, logger *log.Logger) (*App, error) {
+	var fbAuthClient *fbauth.Client
+	var fbFirestoreClient *fbfirestore.Client
+	var err error
+
+	if !config.UseInmemDependencies {
+		_, fbAuthClient, fbFirestoreClient, err = initFirebase(config)
+		if err != nil {
+			return nil, fmt.Errorf("could not init firebase: %w", err)
+		}
+	}
+
+	// endpoint authentication middleware
+	authMiddleware, err := newAuthMiddleware(config, fbAuthClient)
+	if err != nil {
+		return nil, fmt.Errorf("could not create auth middleware: %w", err)
+	}
+
+	// Personal access tokens can be used in addition to the configured authentication mechanism.
+	var tokenStore tokens.Store
+	var feedTokenVersions auth.FeedTokenVersionStore
+	if config.UseInmemDependencies {
+		tokenStore = tokens.NewInmemStore()
+		feedTokenVersions = tokens.NewInmemFeedTokenVersionStore()
+	} else {
+		tokenStore = tokens.NewFirestoreStore(fbFirestoreClient)
+		feedTokenVersions = tokens.NewFirestoreFeedTokenVersionStore(fbFirestoreClient)
+	}
+	accessTokens := tokens.NewService(tokenStore)
+	authMiddleware = middleware.NewAccessTokenEndpointMiddleware(accessTokens, authMiddleware)
+	// Used for endpoints that unauthenticated users can use to view boards that are visible to non-members.
+	optionalAuthMiddleware := middleware.NewOptionalAuthEndpointMiddleware(authMiddleware)
+
+	feedTokenSecret := []byte(config.FeedTokenSecret)
+	if len(feedTokenSecret) == 0 {
+		logger.Warn().Log("msg", "no feed token secret configured, using a random secret, feed tokens will be invalid after a restart")
+		feedTokenSecret = make([]byte, 32)
+		if _, err := crand.Read(feedTokenSecret); err != nil {
+			return nil, fmt.Errorf("could not generate feed token secret: %w", err)
+		}
+	}
+
+	enableMetrics := config.EnableMetrics
+	if enableMetrics && config.MetricsToken == "" {
+		logger.Warn().Log("msg", "no metrics token configured, metrics are disabled")
+		enableMetrics = false
+	}
+
+	var m *metrics.Metrics
+	registry := prometheus.NewRegistry()
+	if enableMetrics {
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		m, err = metrics.New(registry)
+		if err != nil {
+			return nil, fmt.Errorf("could not create metrics: %w", err)
+		}
+	}
+
+	tracerProvider, err := newTracerProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create tracer provider: %w", err)
+	}
+	// components expect a nil interface value if tracing is disabled
+	var tp trace.TracerProvider
+	if tracerProvider != nil {
+		tp = tracerProvider
+	}
+
+	// With in-memory dependencies rate limits and daily quotas are enforced per instance of the application,
+	// otherwise they are stored in Firestore and shared by all instances.
+	var rateLimitStore ratelimit.Store
+	var quotas interface {
+		boardsdomain.QuotaStore
+		linksdomain.QuotaStore
+	}
+	if config.UseInmemDependencies {
+		rateLimitStore = ratelimit.NewInmemStore()
+		quotas = ratelimit.NewInmemQuotaStore()
+	} else {
+		rateLimitStore = ratelimit.NewFirestoreStore(fbFirestoreClient)
+		quotas = ratelimit.NewFirestoreQuotaStore(fbFirestoreClient)
+	}
+	rateLimitMiddleware := ratelimit.NewEndpointMiddleware(rateLimitStore, config.RateLimits)
+
+	// Distributes board and link events to the clients streaming the events of a board.
+	hub := realtime.NewHub(0)
+
+	// create boards component
+	boardsConfig := boards.Config{
+		Logger:                 logger,
+		AuthMiddleware:         authMiddleware,
+		OptionalAuthMiddleware: optionalAuthMiddleware,
+		UseLoggingMiddleware:   true,
+		EventPublisher:         hub.BoardEventPublisher(),
+		Limits:                 config.BoardLimits,
+		Quotas:                 quotas,
+		Middlewares:            []endpoint.Middleware{rateLimitMiddleware},
+		Metrics:                m,
+		TracerProvider:         tp,
+	}
+	if config.UseInmemDependencies {
+		boardsConfig.UseInmemDataStore = true
+	} else {
+		boardsConfig.FirestoreConfig = &boards.FirestoreConfig{
+			Client: fbFirestoreClient,
+		}
+	}
+	if config.AuthCacheTTL > 0 {
+		boardsConfig.AuthorizationCache = &store.CacheConfig{
+			TTL:        config.AuthCacheTTL,
+			MaxEntries: config.AuthCacheSize,
+		}
+	}
+	boardComponent, err := boards.NewComponent(boardsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create boards component: %w", err)
+	}
+
+	// Shared with the links component, such that cached roles are invalidated by the events of the boards component.
+	authorizationStore := boardComponent.AuthorizationStore
+	if cache, ok := authorizationStore.(*store.CachingAuthorizationStore); ok && enableMetrics {
+		registry.MustRegister(newCacheStatsCollectors(cache)...)
+	}
+
+	// create links component
+	linksConfig := links.Config{
+		Logger:                 logger,
+		AuthMiddleware:         authMiddleware,
+		OptionalAuthMiddleware: optionalAuthMiddleware,
+		UseLoggingMiddleware:   true,
+		AuthorizationStore:     authorizationStore,
+		FeedTokens:             auth.NewFeedTokens(feedTokenSecret, feedTokenVersions),
+		EventPublisher:         hub.LinkEventPublisher(),
+		Limits:                 config.LinkLimits,
+		Quotas:                 quotas,
+		Middlewares:            []endpoint.Middleware{rateLimitMiddleware},
+		Metrics:                m,
+		TracerProvider:         tp,
+	}
+	if config.UseInmemDependencies {
+		linksConfig.UseInmemDataStore = true
+	} else {
+		linksConfig.FirestoreConfig = &links.FirestoreConfig{
+			Client: fbFirestoreClient,
+		}
+	}
+	linksComponent, err := links.NewComponent(linksConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create links component: %w", err)
+	}
+
+	router := mux.NewRouter()
+	httpErrorEncoder := func(ctx context.Context, err error, w http.ResponseWriter) {
+		// errors caused by exceeded rate limits or quotas are sent with status 429, other errors are handled by dhttp.EncodeError
+		ratelimit.EncodeError(ctx, err, w)
+	}
+
+	var beforeFunc kithttp.RequestFunc
+	if usesFakeAuth(config) {
+		beforeFunc = kithttp.PopulateRequestContext
+	} else {
+		beforeFunc = kitjwt.HTTPToContext()
+	}
+
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(beforeFunc),
+		kithttp.ServerErrorEncoder(httpErrorEncoder),
+		kithttp.ServerErrorHandler(dhttp.NewLogErrorHandler(logger)),
+	}
+	if tp != nil {
+		opts = append(opts, tracing.HTTPServerOptions(tp)...)
+	}
+
+	boardComponent.RegisterHttpHandlers(router, opts)
+	linksComponent.RegisterHttpHandlers(router, opts)
+
+	tokens.RegisterHttpHandlers(router, accessTokens, tokens.HttpConfig{
+		AuthMiddleware: authMiddleware,
+		Middlewares:    []endpoint.Middleware{rateLimitMiddleware},
+		Metrics:        m,
+		TracerProvider: tp,
+	}, opts)
+
+	graphHandler, err := graph.NewHttpHandler(graph.Config{
+		BoardApplicationService: boardComponent.ApplicationService,
+		LinkApplicationService:  linksComponent.ApplicationService,
+		BoardEndpoints:          &boardComponent.Endpoints,
+		LinkEndpoints:           &linksComponent.Endpoints,
+		Middlewares:             []endpoint.Middleware{rateLimitMiddleware},
+		AuthMiddleware:          authMiddleware,
+		OptionalAuthMiddleware:  optionalAuthMiddleware,
+		Metrics:                 m,
+		TracerProvider:          tp,
+		Logger:                  logger,
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create graphql handler: %w", err)
+	}
+	router.Methods(http.MethodPost).Path("/graphql").Handler(graphHandler)
+
+	dashboardHandler, err := dashboard.NewHttpHandler(dashboard.Config{
+		BoardApplicationService: boardComponent.ApplicationService,
+		LinkApplicationService:  linksComponent.ApplicationService,
+		Middlewares:             []endpoint.Middleware{rateLimitMiddleware},
+		AuthMiddleware:          authMiddleware,
+		Metrics:                 m,
+		TracerProvider:          tp,
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create dashboard handler: %w", err)
+	}
+	router.Methods(http.MethodGet).Path("/me/dashboard").Handler(dashboardHandler)
+
+	healthConfig := newHealthConfig(config, boardComponent, linksComponent, fbAuthClient)
+	healthConfig.Logger = logger
+	health.RegisterHttpHandlers(router, healthConfig)
+
+	if enableMetrics {
+		router.Methods(http.MethodGet).Path("/metrics").Handler(metrics.Handler(registry, config.MetricsToken))
+	}
+
+	// Streams are served without a request timeout, therefore they use a separate router.
+	streamRouter := mux.NewRouter()
+
+	var handler http.Handler = router
+	var checkWebSocketOrigin func(r *http.Request) bool
+	if len(config.CORSAllowedOrigins) > 0 {
+		cors := newCORS(config.CORSAllowedOrigins)
+		handler = cors.handler(router)
+		streamRouter.Use(cors.handler)
+		checkWebSocketOrigin = cors.checkWebSocketOrigin
+	}
+
+	streamRouter.Methods(http.MethodGet).Path("/boards/{boardId}/stream").Handler(realtime.NewSSEHandler(hub, realtime.SSEConfig{
+		Authorize:    linksComponent.AuthorizeLinkQueries,
+		RequestFuncs: []kithttp.RequestFunc{beforeFunc},
+		ErrorEncoder: httpErrorEncoder,
+		Logger:       logger,
+	}))
+	streamRouter.Methods(http.MethodGet).Path("/ws").Handler(realtime.NewWebSocketHandler(hub, realtime.WebSocketConfig{
+		Authorize:    linksComponent.AuthorizeLinkQueries,
+		RequestFuncs: []kithttp.RequestFunc{beforeFunc},
+		ErrorEncoder: httpErrorEncoder,
+		Commands:     linksComponent.WebSocketCommands(),
+		CheckOrigin:  checkWebSocketOrigin,
+		Logger:       logger,
+	}))
+
+	return &App{
+		BoardComponent: boardComponent,
+		LinksComponent: linksComponent,
+		Hub:            hub,
+		Handler:        handler,
+		Router:         router,
+		StreamRouter:   streamRouter,
+		TracerProvider: tracerProvider,
+	}, nil
+}
+
+// Serves all requests of the application, without the timeouts and limits applied by a server.
+// Useful to run the application in-process, e.g. with httptest.NewServer.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	NewStreamHandler(a.StreamRouter, a.Handler).ServeHTTP(w, r)
+}
+
+// Returns a handler that serves requests matching a route of streamRouter using streamRouter and all other requests using next.
+func NewStreamHandler(streamRouter *mux.Router, next http.Handler) http.Handler {
+	return &streamHandler{streamRouter: streamRouter, next: next}
+}
+
+type streamHandler struct {
+	streamRouter *mux.Router
+	next         http.Handler
+}
+
+func (s *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var match mux.RouteMatch
+	if s.streamRouter != nil && s.streamRouter.Match(r, &match) {
+		s.streamRouter.ServeHTTP(w, r)
+		return
+	}
+	s.next.ServeHTTP(w, r)
+}
+
+// Creates a gRPC server for the components of the application and starts listening on the configured port.
+// Requests are authenticated using the "authorization" metadata and go through the same endpoints as http requests.
+// Panics in handlers are recovered and if tracing is enabled, calls continue the trace of the caller.
+func NewGRPCServer(config Config, a *App, logger *log.Logger) (*grpc.Server, error) {
+	var beforeFunc kitgrpc.ServerRequestFunc
+	if usesFakeAuth(config) {
+		beforeFunc = middleware.GRPCAuthorizationToContext
+	} else {
+		beforeFunc = kitjwt.GRPCToContext()
+	}
+
+	opts := []kitgrpc.ServerOption{
+		kitgrpc.ServerBefore(beforeFunc),
+		kitgrpc.ServerErrorHandler(dhttp.NewLogErrorHandler(logger)),
+	}
+
+	// the tracing interceptor runs first, so that errors caused by panics are recorded in the span of the call
+	var interceptors []grpc.UnaryServerInterceptor
+	if a.TracerProvider != nil {
+		interceptors = append(interceptors, tracing.GRPCUnaryServerInterceptor(a.TracerProvider))
+	}
+	interceptors = append(interceptors, recoverGRPCPanics(logger))
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	a.BoardComponent.RegisterGRPCServer(s, opts)
+	a.LinksComponent.RegisterGRPCServer(s, opts)
+
+	lis, err := net.Listen("tcp", config.Address+":"+strconv.Itoa(config.GRPCPort))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			logger.Error().Log("msg", "error running grpc server", "error", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// The fake authentication mechanism reads basic auth credentials, all others read bearer tokens.
+func usesFakeAuth(config Config) bool {
+	return config.UseInmemDependencies && config.AuthMode == ""
+}
+
+func newAuthMiddleware(config Config, fbAuthClient *fbauth.Client) (endpoint.Middleware, error) {
+	switch config.AuthMode {
+	case "":
+		if config.UseInmemDependencies {
+			return middleware.NewFakeAuthEndpointMiddleware(), nil
+		}
+		return middleware.NewFirebaseAuthEndpointMiddleware(fbAuthClient, false), nil
+	case "firebase":
+		if fbAuthClient == nil {
+			return nil, fmt.Errorf("firebase authentication cannot be used with in-memory dependencies")
+		}
+		return middleware.NewFirebaseAuthEndpointMiddleware(fbAuthClient, false), nil
+	case "jwt":
+		jwtConfig := middleware.JWTConfig{
+			HMACSecret:  []byte(config.JWTSecret),
+			JWKSURL:     config.JWKSURL,
+			Issuer:      config.JWTIssuer,
+			Audience:    config.JWTAudience,
+			ClockSkew:   config.JWTClockSkew,
+			UserIdClaim: config.JWTUserIdClaim,
+			NameClaim:   config.JWTNameClaim,
+		}
+		if config.JWTPublicKeyFile != "" {
+			b, err := os.ReadFile(config.JWTPublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			key, err := jwt.ParseRSAPublicKeyFromPEM(b)
+			if err != nil {
+				return nil, err
+			}
+			jwtConfig.RSAPublicKey = key
+		}
+		return middleware.NewJWTAuthEndpointMiddleware(jwtConfig)
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %v", config.AuthMode)
+	}
+}
+
+func initFirebase(config Config) (*fb.App, *fbauth.Client, *fbfirestore.Client, error) {
+	fbApp, err := lfb.NewApp(lfb.Config{
+		UseEmulators:       config.UseFirebaseEmulators,
+		ProjectId:          config.FirebaseProjectId,
+		ServiceAccountFile: config.FirebaseServiceAccountFile,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fbAuth, err := lfb.NewAuthClient(fbApp)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fbFirestore, err := lfb.NewFirestoreClient(fbApp)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return fbApp, fbAuth, fbFirestore, nil
+}
+
+// Returns collectors that export the statistics of the authorization cache, see store.CacheStats.
+func newCacheStatsCollectors(cache *store.CachingAuthorizationStore) []prometheus.Collector {
+	counter := func(name, help string, value func(store.CacheStats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "linkboards",
+			Subsystem: "auth_cache",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return float64(value(cache.Stats()))
+		})
+	}
+	return []prometheus.Collector{
+		counter("hits_total", "Number of roles answered from the cache.", func(s store.CacheStats) uint64 { return s.Hits }),
+		counter("misses_total", "Number of roles not found in the cache.", func(s store.CacheStats) uint64 { return s.Misses }),
+		counter("evictions_total", "Number of cached roles evicted because the cache was full.", func(s store.CacheStats) uint64 { return s.Evictions }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "linkboards",
+			Subsystem: "auth_cache",
+			Name:      "entries",
+			Help:      "Number of cached roles.",
+		}, func() float64 {
+			return float64(cache.Stats().Entries)
+		}),
+	}
+}
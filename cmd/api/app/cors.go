@@ -0,0 +1,75 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	linkstransport "github.com/dkinzler/linkboards/internal/links/transport"
+)
+
+// Headers browser applications can send and read when making cross-origin requests.
+var (
+	corsAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	corsAllowedHeaders = []string{"Authorization", "Content-Type", "traceparent", "tracestate"}
+	corsExposedHeaders = []string{"Retry-After", linkstransport.ExportTruncatedHeader}
+)
+
+// How long browsers can cache the result of a preflight request.
+const corsMaxAge = 10 * 60
+
+// Allows browser applications served from other origins to use the API, see https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS.
+// Requests are authenticated using the Authorization header, therefore credentials like cookies are not allowed.
+type cors struct {
+	allowAll       bool
+	allowedOrigins map[string]bool
+}
+
+// Origins must have the form "scheme://host[:port]", "*" allows all origins.
+func newCORS(allowedOrigins []string) *cors {
+	c := &cors{allowedOrigins: make(map[string]bool, len(allowedOrigins))}
+	for _, o := range allowedOrigins {
+		if o == "*" {
+			c.allowAll = true
+		}
+		c.allowedOrigins[strings.ToLower(o)] = true
+	}
+	return c
+}
+
+func (c *cors) allowsOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	return c.allowAll || c.allowedOrigins[strings.ToLower(origin)]
+}
+
+// Can be used as the CheckOrigin function of WebSocket handlers, requests without an Origin header are not made by browsers and always allowed.
+func (c *cors) checkWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || c.allowsOrigin(origin)
+}
+
+// Returns a handler that adds CORS headers to the responses for requests from allowed origins.
+// Preflight requests are answered directly, without calling next.
+func (c *cors) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if !c.allowsOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(corsAllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(corsAllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+		next.ServeHTTP(w, r)
+	})
+}
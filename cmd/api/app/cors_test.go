@@ -0,0 +1,48 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	a := assert.New(t)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	h := newCORS([]string{"https://app.example.com"}).handler(next)
+
+	// preflight requests are answered directly
+	r := httptest.NewRequest(http.MethodOptions, "/boards", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	a.Equal(http.StatusNoContent, w.Code)
+	a.Equal("https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	a.Contains(w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+
+	r = httptest.NewRequest(http.MethodGet, "/boards", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	a.Equal(http.StatusTeapot, w.Code)
+	a.Equal("https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	a.Equal("Retry-After, Linkboards-Export-Truncated", w.Header().Get("Access-Control-Expose-Headers"))
+
+	// other origins don't get CORS headers
+	r = httptest.NewRequest(http.MethodGet, "/boards", nil)
+	r.Header.Set("Origin", "https://other.example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	a.Equal(http.StatusTeapot, w.Code)
+	a.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+
+	c := newCORS([]string{"*"})
+	a.True(c.allowsOrigin("https://other.example.com"))
+	a.False(c.allowsOrigin(""))
+}
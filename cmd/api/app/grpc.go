@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/dkinzler/kit/log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Returns a gRPC interceptor that turns a panic in a handler into an Internal error, instead of crashing the whole server.
+// The panic is logged together with the stack trace.
+func recoverGRPCPanics(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error().Log("msg", "panic in grpc handler", "method", info.FullMethod, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
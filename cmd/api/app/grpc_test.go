@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dkinzler/kit/log"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoverGRPCPanics(t *testing.T) {
+	a := assert.New(t)
+
+	interceptor := recoverGRPCPanics(log.DefaultJSONLogger(log.AllowError))
+	info := &grpc.UnaryServerInfo{FullMethod: "/boards.v1.BoardService/GetBoard"}
+
+	response, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	a.Nil(err)
+	a.Equal("ok", response)
+
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("test panic")
+	})
+	a.Equal(codes.Internal, status.Code(err))
+}
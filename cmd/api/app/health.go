@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/dkinzler/linkboards/internal/boards"
+	"github.com/dkinzler/linkboards/internal/health"
+	"github.com/dkinzler/linkboards/internal/links"
+
+	fbauth "firebase.google.com/go/v4/auth"
+)
+
+func newHealthConfig(config Config, boardComponent *boards.Component, linksComponent *links.Component, fbAuthClient *fbauth.Client) health.Config {
+	checks := map[string]health.Check{
+		"boards": boardComponent.Ping,
+		"links":  linksComponent.Ping,
+	}
+	if authCheck := newAuthCheck(config, fbAuthClient); authCheck != nil {
+		checks["auth"] = authCheck
+	}
+
+	backends := map[string]string{
+		"dataStore": "firestore",
+		"auth":      authBackend(config),
+	}
+	if config.UseInmemDependencies {
+		backends["dataStore"] = "inmem"
+	}
+	if config.AuthCacheTTL > 0 {
+		backends["authCache"] = "inmem"
+	}
+	if config.TracingExporter != "" {
+		backends["tracing"] = config.TracingExporter
+	}
+
+	return health.Config{
+		Checks:       checks,
+		CheckTimeout: config.ReadinessCheckTimeout,
+		Version: health.VersionInfo{
+			Version:  config.Version,
+			Commit:   config.Commit,
+			Backends: backends,
+		},
+	}
+}
+
+func authBackend(config Config) string {
+	if usesFakeAuth(config) {
+		return "fake"
+	}
+	if config.AuthMode == "" {
+		return "firebase"
+	}
+	return config.AuthMode
+}
+
+// Returns a check for the authentication backend, or nil if tokens are verified without contacting another service,
+// e.g. for JWTs verified with a static key.
+func newAuthCheck(config Config, fbAuthClient *fbauth.Client) health.Check {
+	switch {
+	case fbAuthClient != nil && authBackend(config) == "firebase":
+		return func(ctx context.Context) error {
+			// Looking up a user that does not exist still requires a round trip to Firebase.
+			_, err := fbAuthClient.GetUser(ctx, "healthcheck")
+			if err != nil && !fbauth.IsUserNotFound(err) {
+				return err
+			}
+			return nil
+		}
+	case config.AuthMode == "jwt" && config.JWKSURL != "":
+		return func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.JWKSURL, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("could not get JWKS, status %v", resp.StatusCode)
+			}
+			return nil
+		}
+	default:
+		return nil
+	}
+}
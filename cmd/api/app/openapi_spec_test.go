@@ -0,0 +1,504 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An OpenAPI document used to validate the requests and responses of the API.
+//
+// Only the parts of OpenAPI used by api/openapi.yaml are supported, i.e. local $refs, allOf, enum, nullable and the
+// length and item limits. Validation is strict: objects that document their properties must not contain other properties,
+// unless additionalProperties is set.
+type openAPISpec struct {
+	doc        map[string]interface{}
+	operations []*specOperation
+}
+
+type specOperation struct {
+	method string
+	// Path template as in the spec, e.g. "/boards/{boardId}".
+	path    string
+	pattern *regexp.Regexp
+	// Parameters of the path item and operation.
+	params []map[string]interface{}
+	op     map[string]interface{}
+}
+
+func (o *specOperation) String() string {
+	return o.method + " " + o.path
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{[^}]+\}`)
+
+func loadOpenAPISpec(file string) (*openAPISpec, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	s := &openAPISpec{doc: doc}
+	paths, _ := doc["paths"].(map[string]interface{})
+	for path, item := range paths {
+		item := s.resolve(item)
+		var pathParams []map[string]interface{}
+		for _, p := range asSlice(item["parameters"]) {
+			pathParams = append(pathParams, s.resolve(p))
+		}
+
+		// path parameters match a single segment or a part of it, e.g. "feed.{format}"
+		parts := pathParamRegexp.Split(path, -1)
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		pattern := "^" + strings.Join(parts, "[^/]+") + "$"
+
+		for method, op := range item {
+			if method == "parameters" || method == "summary" || method == "description" {
+				continue
+			}
+			o := &specOperation{
+				method:  strings.ToUpper(method),
+				path:    path,
+				pattern: regexp.MustCompile(pattern),
+				op:      s.resolve(op),
+			}
+			o.params = append(o.params, pathParams...)
+			for _, p := range asSlice(o.op["parameters"]) {
+				o.params = append(o.params, s.resolve(p))
+			}
+			s.operations = append(s.operations, o)
+		}
+	}
+	sort.Slice(s.operations, func(i, j int) bool {
+		return s.operations[i].String() < s.operations[j].String()
+	})
+	return s, nil
+}
+
+// Returns the operation for a request, or nil if it is not documented.
+func (s *openAPISpec) findOperation(method string, path string) *specOperation {
+	for _, o := range s.operations {
+		if o.method == method && o.pattern.MatchString(path) {
+			return o
+		}
+	}
+	return nil
+}
+
+// Follows $refs like "#/components/schemas/board".
+func (s *openAPISpec) resolve(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	for i := 0; i < 10; i++ {
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m
+		}
+		var current interface{} = s.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			cm, _ := current.(map[string]interface{})
+			current = cm[part]
+		}
+		m, _ = current.(map[string]interface{})
+	}
+	return m
+}
+
+// Merges the schemas of allOf into a single schema.
+func (s *openAPISpec) flatten(schema interface{}) map[string]interface{} {
+	sch := s.resolve(schema)
+	allOf := asSlice(sch["allOf"])
+	if len(allOf) == 0 {
+		return sch
+	}
+
+	result := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	var required []interface{}
+	merge := func(m map[string]interface{}) {
+		for k, v := range m {
+			switch k {
+			case "allOf":
+			case "properties":
+				for name, p := range v.(map[string]interface{}) {
+					properties[name] = p
+				}
+			case "required":
+				required = append(required, asSlice(v)...)
+			default:
+				result[k] = v
+			}
+		}
+	}
+	merge(sch)
+	for _, sub := range allOf {
+		merge(s.flatten(sub))
+	}
+	if len(properties) > 0 {
+		result["properties"] = properties
+		result["type"] = "object"
+	}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	return result
+}
+
+// Validates a JSON value decoded with UseNumber, returns a description of every violation.
+func (s *openAPISpec) validate(schema interface{}, value interface{}, at string) []string {
+	sch := s.flatten(schema)
+	if sch == nil {
+		return nil
+	}
+	if value == nil {
+		if nullable, _ := sch["nullable"].(bool); nullable || len(sch) == 0 {
+			return nil
+		}
+		return []string{fmt.Sprintf("%v: is null", at)}
+	}
+
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if enum := asSlice(sch["enum"]); len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+			}
+		}
+		if !found {
+			fail("%v is not one of %v", value, enum)
+		}
+	}
+
+	switch sch["type"] {
+	case "object":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %T", value)
+			break
+		}
+		for _, r := range asSlice(sch["required"]) {
+			if _, ok := m[r.(string)]; !ok {
+				fail("required property %v is missing", r)
+			}
+		}
+		properties, _ := sch["properties"].(map[string]interface{})
+		additional, hasAdditional := sch["additionalProperties"]
+		for name, v := range m {
+			if p, ok := properties[name]; ok {
+				errs = append(errs, s.validate(p, v, at+"."+name)...)
+			} else if am, ok := additional.(map[string]interface{}); ok {
+				errs = append(errs, s.validate(am, v, at+"."+name)...)
+			} else if additional == true || (len(properties) == 0 && !hasAdditional) {
+				// free-form object
+			} else {
+				fail("undocumented property %v", name)
+			}
+		}
+	case "array":
+		a, ok := value.([]interface{})
+		if !ok {
+			fail("expected array, got %T", value)
+			break
+		}
+		if max, ok := sch["maxItems"].(int); ok && len(a) > max {
+			fail("more than %v items", max)
+		}
+		if min, ok := sch["minItems"].(int); ok && len(a) < min {
+			fail("less than %v items", min)
+		}
+		for i, v := range a {
+			errs = append(errs, s.validate(sch["items"], v, fmt.Sprintf("%v[%v]", at, i))...)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("expected string, got %T", value)
+			break
+		}
+		if max, ok := sch["maxLength"].(int); ok && utf8.RuneCountInString(str) > max {
+			fail("longer than %v characters", max)
+		}
+		if min, ok := sch["minLength"].(int); ok && utf8.RuneCountInString(str) < min {
+			fail("shorter than %v characters", min)
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("expected integer, got %T", value)
+		} else if _, err := n.Int64(); err != nil {
+			fail("expected integer, got %v", n)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			fail("expected number, got %T", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %T", value)
+		}
+	}
+	return errs
+}
+
+// Validates the query parameters and body of a request.
+// Violations of the request body schema are only returned if the request was accepted, i.e. if accepted is true,
+// since tests deliberately send invalid requests to check that they are rejected.
+func (s *openAPISpec) validateRequest(o *specOperation, r *http.Request, body []byte, accepted bool) []string {
+	var errs []string
+	for key := range r.URL.Query() {
+		if !o.hasParam("query", key) {
+			errs = append(errs, fmt.Sprintf("%v: undocumented query parameter %v", o, key))
+		}
+	}
+
+	if len(body) == 0 {
+		return errs
+	}
+	requestBody := s.resolve(o.op["requestBody"])
+	if requestBody == nil {
+		return append(errs, fmt.Sprintf("%v: request has a body, but the operation has no request body", o))
+	}
+	if !accepted {
+		return errs
+	}
+	return append(errs, s.validateContent(o.String()+" request", requestBody, r.Header.Get("Content-Type"), body)...)
+}
+
+func (o *specOperation) hasParam(in string, name string) bool {
+	for _, p := range o.params {
+		if p["in"] == in && p["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validates the status code and body of a response.
+// If contentType is empty, the handler did not set the Content-Type header and the body is validated
+// with the first documented media type, e.g. error responses written by kit's EncodeError have no content type.
+func (s *openAPISpec) validateResponse(o *specOperation, status int, contentType string, body []byte) []string {
+	responses, _ := o.op["responses"].(map[string]interface{})
+	response, ok := responses[fmt.Sprint(status)]
+	if !ok {
+		response, ok = responses["default"]
+	}
+	if !ok {
+		return []string{fmt.Sprintf("%v: undocumented status %v", o, status)}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	at := fmt.Sprintf("%v response %v", o, status)
+	errs := s.validateContent(at, s.resolve(response), contentType, body)
+	return append(errs, validateErrorCode(at, s.resolve(response), body)...)
+}
+
+// Matches the error codes listed in the description of a response, e.g. "- 2 - Invalid name".
+var errorCodeRegexp = regexp.MustCompile(`(?m)^\s*- (\d+) -`)
+
+// If the description of a response lists error codes, the code of an error in the body must be one of them.
+func validateErrorCode(at string, response map[string]interface{}, body []byte) []string {
+	description, _ := response["description"].(string)
+	codes := errorCodeRegexp.FindAllStringSubmatch(description, -1)
+	if len(codes) == 0 {
+		return nil
+	}
+	var e struct {
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || e.Error.Code == 0 {
+		return nil
+	}
+	for _, c := range codes {
+		if c[1] == fmt.Sprint(e.Error.Code) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%v: undocumented error code %v", at, e.Error.Code)}
+}
+
+// Validates a body against the content of a request body or response object.
+func (s *openAPISpec) validateContent(at string, object map[string]interface{}, contentType string, body []byte) []string {
+	content, _ := object["content"].(map[string]interface{})
+	if len(content) == 0 {
+		return []string{fmt.Sprintf("%v: has a body, but no content is documented", at)}
+	}
+
+	var mediaType string
+	if contentType != "" {
+		mt, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return []string{fmt.Sprintf("%v: invalid content type %q", at, contentType)}
+		}
+		if _, ok := content[mt]; !ok {
+			return []string{fmt.Sprintf("%v: undocumented content type %v", at, mt)}
+		}
+		mediaType = mt
+	} else {
+		for mt := range content {
+			if mediaType == "" || mt < mediaType {
+				mediaType = mt
+			}
+		}
+	}
+	schema := s.resolve(content[mediaType])["schema"]
+
+	switch mediaType {
+	case "application/json":
+		value, err := decodeJSONValue(body)
+		if err != nil {
+			return []string{fmt.Sprintf("%v: invalid JSON: %v", at, err)}
+		}
+		return s.validate(schema, value, at)
+	case "text/event-stream":
+		// the data of every event is validated against the schema
+		var errs []string
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			data := strings.TrimPrefix(line, "data: ")
+			value, err := decodeJSONValue([]byte(data))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%v: invalid JSON in event: %v", at, err))
+				continue
+			}
+			errs = append(errs, s.validate(schema, value, at+" event")...)
+		}
+		return errs
+	}
+	return nil
+}
+
+func decodeJSONValue(b []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	err := d.Decode(&v)
+	return v, err
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// Wraps a handler and validates every request and response against the spec.
+// Records the violations and the operations that were called.
+type specValidator struct {
+	spec *openAPISpec
+	next http.Handler
+
+	mu     sync.Mutex
+	errs   []string
+	called map[*specOperation]bool
+}
+
+func newSpecValidator(spec *openAPISpec, next http.Handler) *specValidator {
+	return &specValidator{spec: spec, next: next, called: map[*specOperation]bool{}}
+}
+
+func (v *specValidator) addErrors(errs ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.errs = append(v.errs, errs...)
+}
+
+func (v *specValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o := v.spec.findOperation(r.Method, r.URL.Path)
+	if o == nil {
+		v.addErrors(fmt.Sprintf("undocumented route %v %v", r.Method, r.URL.Path))
+		v.next.ServeHTTP(w, r)
+		return
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	rec := &responseRecorder{ResponseWriter: w}
+	v.next.ServeHTTP(rec, r)
+
+	status := rec.status
+	if rec.hijacked {
+		status = http.StatusSwitchingProtocols
+	} else if status == 0 {
+		status = http.StatusOK
+	}
+	errs := v.spec.validateRequest(o, r, body, status < 400)
+	errs = append(errs, v.spec.validateResponse(o, status, rec.contentType, rec.body.Bytes())...)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.called[o] = true
+	v.errs = append(v.errs, errs...)
+}
+
+// Records the status, content type and body of a response while writing it.
+// Supports flushing and hijacking, such that streams and WebSocket connections work.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	contentType string
+	body        bytes.Buffer
+	hijacked    bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+		r.contentType = r.Header().Get("Content-Type")
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer cannot be hijacked")
+	}
+	r.hijacked = true
+	return h.Hijack()
+}
@@ -0,0 +1,323 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkinzler/linkboards/pkg/client"
+
+	"github.com/dkinzler/kit/log"
+	"github.com/gorilla/mux"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Starts the application with in-memory dependencies, calls every operation of api/openapi.yaml
+// and validates the requests and responses against the spec.
+func TestOpenAPISpec(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	spec, err := loadOpenAPISpec("../../../api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application, err := New(Config{
+		UseInmemDependencies: true,
+		FeedTokenSecret:      "secret",
+		EnableMetrics:        true,
+		MetricsToken:         "metrics-token",
+	}, log.DefaultJSONLogger(log.AllowError))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer application.Hub.Close()
+
+	// every route of the application must be documented and vice versa
+	a.ElementsMatch(routesOfSpec(spec), append(routesOfRouter(t, application.Router), routesOfRouter(t, application.StreamRouter)...))
+
+	validator := newSpecValidator(spec, application)
+	server := httptest.NewServer(validator)
+	defer server.Close()
+
+	newClient := func(userId string) *client.Client {
+		config := client.Config{BaseURL: server.URL, MaxRetries: -1}
+		if userId != "" {
+			config.Authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(userId+":"))
+		}
+		c, err := client.New(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	owner := newClient("u-owner")
+	member := newClient("u-member")
+	other := newClient("u-other")
+	anonymous := newClient("")
+
+	// boards
+	board, err := owner.CreateBoard(ctx, client.NewBoard{Name: "Go", Description: "links about Go"})
+	a.Nil(err)
+	boardId := board.BoardId
+	_, err = owner.CreateBoard(ctx, client.NewBoard{})
+	a.True(client.IsBadRequest(err))
+	_, err = anonymous.CreateBoard(ctx, client.NewBoard{Name: "Go"})
+	a.True(client.IsUnauthenticated(err))
+
+	_, err = owner.EditBoard(ctx, boardId, client.BoardEdit{}.WithDescription("links about the Go programming language"))
+	a.Nil(err)
+	_, err = owner.EditBoard(ctx, boardId, client.BoardEdit{}.WithVisibility("secret"))
+	a.True(client.IsBadRequest(err))
+	_, err = other.EditBoard(ctx, boardId, client.BoardEdit{}.WithName("Rust"))
+	a.True(client.IsForbidden(err))
+
+	_, err = owner.GetBoards(ctx, client.QueryParams{Limit: 10})
+	a.Nil(err)
+	_, err = other.GetBoard(ctx, boardId)
+	a.True(client.IsForbidden(err))
+
+	// invites
+	invite, err := owner.CreateInvite(ctx, boardId, client.NewInvite{User: client.User{UserId: "u-member"}, Role: client.RoleEditor})
+	a.Nil(err)
+	_, err = owner.CreateInvite(ctx, boardId, client.NewInvite{Role: "admin"})
+	a.True(client.IsBadRequest(err))
+	_, err = member.GetInvites(ctx, client.QueryParams{})
+	a.Nil(err)
+	a.Nil(member.RespondToInvite(ctx, boardId, invite.InviteId, client.InviteResponse{Response: client.InviteResponseAccept}))
+	invite, err = owner.CreateInvite(ctx, boardId, client.NewInvite{Role: client.RoleViewer})
+	a.Nil(err)
+	a.Nil(owner.DeleteInvite(ctx, boardId, invite.InviteId))
+	a.True(client.IsNotFound(owner.DeleteInvite(ctx, boardId, invite.InviteId)))
+
+	// board users and custom roles
+	_, err = owner.SetCustomRole(ctx, boardId, "curator", []string{"boards:view", "links:query"})
+	a.Nil(err)
+	_, err = owner.EditBoardUser(ctx, boardId, "u-member", client.BoardUserEdit{Role: "curator"})
+	a.Nil(err)
+	_, err = owner.DeleteCustomRole(ctx, boardId, "curator")
+	a.True(client.IsBadRequest(err))
+	_, err = owner.EditBoardUser(ctx, boardId, "u-member", client.BoardUserEdit{Role: client.RoleEditor})
+	a.Nil(err)
+	_, err = owner.DeleteCustomRole(ctx, boardId, "curator")
+	a.Nil(err)
+
+	// join requests
+	_, err = owner.EditBoard(ctx, boardId, client.BoardEdit{}.WithVisibility(client.VisibilityPublic))
+	a.Nil(err)
+	_, err = owner.GetPublicBoards(ctx, client.QueryParams{Limit: 10})
+	a.Nil(err)
+	_, err = anonymous.GetBoard(ctx, boardId)
+	a.Nil(err)
+	joinRequest, err := other.CreateJoinRequest(ctx, boardId)
+	a.Nil(err)
+	_, err = other.CreateJoinRequest(ctx, boardId)
+	a.True(client.IsBadRequest(err))
+	_, err = owner.GetJoinRequests(ctx, boardId)
+	a.Nil(err)
+	a.Nil(owner.RespondToJoinRequest(ctx, boardId, joinRequest.JoinRequestId, client.JoinRequestResponse{Response: client.JoinRequestResponseApprove}))
+	a.Nil(owner.RemoveUserFromBoard(ctx, boardId, "u-other"))
+	a.True(client.IsBadRequest(owner.RemoveUserFromBoard(ctx, boardId, "u-other")))
+
+	// stream the events of the board while creating links
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
+	stream, err := member.StreamBoardEvents(streamCtx, boardId, "")
+	a.Nil(err)
+
+	// links
+	link, err := member.CreateLink(ctx, boardId, client.NewLink{Title: "Go", Url: "https://go.dev", Tags: []string{"go"}})
+	a.Nil(err)
+	_, err = member.CreateLink(ctx, boardId, client.NewLink{Title: "Go", Url: "http://go.dev"})
+	a.True(client.IsBadRequest(err))
+	a.Nil(owner.RateLink(ctx, boardId, link.LinkId, client.LinkRating{Rating: 1}))
+	err = owner.RateLink(ctx, boardId, link.LinkId, client.LinkRating{Rating: 2})
+	a.True(client.IsBadRequest(err))
+	_, err = anonymous.GetLink(ctx, boardId, link.LinkId)
+	a.Nil(err)
+	_, err = owner.GetLinks(ctx, boardId, client.LinkQueryParams{Limit: 10, Sort: client.LinkSortTop}.After(link))
+	a.Nil(err)
+
+	event, err := stream.Next()
+	a.Nil(err)
+	a.Equal(client.EventLinkCreated, event.Type)
+	cancelStream()
+	stream.Close()
+
+	result, err := member.ImportBookmarks(ctx, boardId, strings.NewReader(`<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+<DT><A HREF="https://pkg.go.dev">Packages</A>
+<DT><A HREF="http://insecure.example.com">Insecure</A>
+</DL><p>`))
+	a.Nil(err)
+	a.Len(result.Failed, 1)
+	truncated, err := member.ExportBookmarks(ctx, boardId, io.Discard)
+	a.Nil(err)
+	a.False(truncated)
+
+	feedToken, err := member.GetFeedToken(ctx)
+	a.Nil(err)
+	feed, err := anonymous.GetFeed(ctx, boardId, client.FeedFormatAtom, feedToken, "")
+	a.Nil(err)
+	feed, err = anonymous.GetFeed(ctx, boardId, client.FeedFormatRSS, feedToken, feed.ETag)
+	a.Nil(err)
+	_, err = anonymous.GetFeed(ctx, boardId, client.FeedFormatRSS, "invalid", "")
+	a.True(client.IsUnauthenticated(err))
+	renewedFeedToken, err := member.RenewFeedToken(ctx)
+	a.Nil(err)
+	_, err = anonymous.GetFeed(ctx, boardId, client.FeedFormatAtom, feedToken, "")
+	a.True(client.IsUnauthenticated(err))
+	_, err = anonymous.GetFeed(ctx, boardId, client.FeedFormatAtom, renewedFeedToken, "")
+	a.Nil(err)
+
+	a.Nil(member.DeleteLink(ctx, boardId, link.LinkId))
+	_, err = member.GetLink(ctx, boardId, link.LinkId)
+	a.True(client.IsNotFound(err))
+
+	// WebSocket messages are validated by the client, since they are not part of http responses
+	ws, err := member.DialWebSocket(ctx)
+	a.Nil(err)
+	sendMessages := []client.ClientMessage{
+		{Id: "1", Type: client.MessageTypeSubscribe, BoardId: boardId},
+		{Id: "2", Type: client.MessageTypeCreateLink, BoardId: boardId, Data: client.NewLink{Title: "Go blog", Url: "https://go.dev/blog"}},
+		{Id: "3", Type: client.MessageTypeRateLink, BoardId: boardId, LinkId: "l-unknown", Data: client.LinkRating{Rating: 1}},
+	}
+	for _, m := range sendMessages {
+		b, _ := json.Marshal(m)
+		value, _ := decodeJSONValue(b)
+		validator.addErrors(spec.validate(map[string]interface{}{"$ref": "#/components/schemas/clientMessage"}, value, "clientMessage")...)
+		a.Nil(ws.Send(m))
+	}
+	// results of the 3 messages and the event of the created link
+	for i := 0; i < 4; i++ {
+		m, err := ws.Receive()
+		a.Nil(err)
+		b, _ := json.Marshal(m)
+		value, _ := decodeJSONValue(b)
+		validator.addErrors(spec.validate(map[string]interface{}{"$ref": "#/components/schemas/serverMessage"}, value, "serverMessage")...)
+	}
+	ws.Close()
+
+	// personal access tokens
+	token, err := owner.CreateAccessToken(ctx, client.NewAccessToken{Name: "CI", BoardIds: []string{boardId}, Scopes: []string{"links:query"}})
+	a.Nil(err)
+	_, err = owner.GetAccessTokens(ctx)
+	a.Nil(err)
+	_, err = owner.WithToken(token.Token).GetAccessTokens(ctx)
+	a.True(client.IsForbidden(err))
+	a.Nil(owner.RevokeAccessToken(ctx, token.TokenId))
+	a.True(client.IsNotFound(owner.RevokeAccessToken(ctx, token.TokenId)))
+
+	// dashboard and GraphQL
+	_, err = owner.GetDashboard(ctx, 2)
+	a.Nil(err)
+	_, err = owner.GetDashboard(ctx, 100)
+	a.True(client.IsBadRequest(err))
+	_, err = owner.GraphQL(ctx, client.GraphQLRequest{Query: "{ boards { boardId name links(sort: TOP) { title score } } }"})
+	a.Nil(err)
+	_, err = owner.GraphQL(ctx, client.GraphQLRequest{})
+	a.True(client.IsBadRequest(err))
+
+	// operations
+	_, err = anonymous.Health(ctx)
+	a.Nil(err)
+	_, err = anonymous.Ready(ctx)
+	a.Nil(err)
+	_, err = anonymous.Version(ctx)
+	a.Nil(err)
+	a.True(client.IsUnauthenticated(anonymous.Metrics(ctx, &bytes.Buffer{})))
+	monitoring, err := client.New(client.Config{BaseURL: server.URL, MaxRetries: -1, Authorization: "Bearer metrics-token"})
+	a.Nil(err)
+	var metricsBody bytes.Buffer
+	a.Nil(monitoring.Metrics(ctx, &metricsBody))
+	// endpoints that are not part of a component are instrumented as well
+	for _, labels := range []string{
+		`component="graph",endpoint="graphql"`,
+		`component="dashboard",endpoint="getDashboard"`,
+		`component="tokens",endpoint="createToken"`,
+		`component="tokens",endpoint="getTokens"`,
+		`component="tokens",endpoint="revokeToken"`,
+	} {
+		// labels are sorted by name, i.e. the code label comes first
+		a.Regexp(`linkboards_endpoint_requests_total\{code="\w+",`+labels+`\}`, metricsBody.String())
+	}
+
+	a.Nil(owner.DeleteBoard(ctx, boardId))
+	a.True(client.IsForbidden(owner.DeleteBoard(ctx, boardId)))
+
+	// wait for the stream to end, it is validated when the handler returns
+	waitFor(t, func() bool {
+		validator.mu.Lock()
+		defer validator.mu.Unlock()
+		for o := range validator.called {
+			if o.path == "/boards/{boardId}/stream" {
+				return true
+			}
+		}
+		return false
+	})
+
+	validator.mu.Lock()
+	defer validator.mu.Unlock()
+	for _, e := range validator.errs {
+		t.Error(e)
+	}
+	for _, o := range spec.operations {
+		if !validator.called[o] {
+			t.Errorf("operation %v was not called", o)
+		}
+	}
+}
+
+// Returns the routes of the spec like "GET /boards/{}", i.e. without the names of path parameters.
+func routesOfSpec(spec *openAPISpec) []string {
+	var result []string
+	for _, o := range spec.operations {
+		result = append(result, o.method+" "+pathParamRegexp.ReplaceAllString(o.path, "{}"))
+	}
+	return result
+}
+
+func routesOfRouter(t *testing.T, router *mux.Router) []string {
+	var result []string
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, m := range methods {
+			// CORS preflight requests are not part of the API
+			if m != http.MethodOptions {
+				result = append(result, m+" "+pathParamRegexp.ReplaceAllString(path, "{}"))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("condition not met in time")
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const tracingServiceName = "linkboards-api"
+
+// Creates a tracer provider that uses the exporter configured by config.TracingExporter.
+// Returns nil if tracing is disabled.
+func newTracerProvider(config Config) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.TracingExporter {
+	case "":
+		return nil, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if config.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(config.OTLPEndpoint))
+		}
+		if config.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q, must be one of \"stdout\" or \"otlp\"", config.TracingExporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create tracing exporter: %w", err)
+	}
+	return tracing.NewTracerProvider(exporter, tracingServiceName, config.TracingSampleRatio), nil
+}
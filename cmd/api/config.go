@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkinzler/linkboards/cmd/api/app"
+	boards "github.com/dkinzler/linkboards/internal/boards/domain"
+	links "github.com/dkinzler/linkboards/internal/links/domain"
+
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/BurntSushi/toml"
+	"github.com/agnivade/levenshtein"
+	cli "github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+	"gopkg.in/yaml.v3"
+)
+
+// Name of the flag that contains the path of the config file.
+const configFileFlag = "config"
+
+// Flags whose values are replaced by "REDACTED" when the config is printed or logged.
+var secretFlags = map[string]bool{
+	"feedTokenSecret": true,
+	"jwtSecret":       true,
+	"metricsToken":    true,
+}
+
+const redacted = "REDACTED"
+
+// A list of problems found in a config, returned to report all of them at once instead of only the first one.
+type configErrors struct {
+	// Describes what was validated, e.g. "config file config.yaml".
+	source   string
+	problems []string
+}
+
+func (e *configErrors) add(format string, args ...interface{}) {
+	e.problems = append(e.problems, fmt.Sprintf(format, args...))
+}
+
+func (e *configErrors) err() error {
+	if len(e.problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *configErrors) Error() string {
+	return "invalid " + e.source + ":\n  - " + strings.Join(e.problems, "\n  - ")
+}
+
+// Returns a function for the Before field of a cli.App that sets flags to the values of the config file given by the "config" flag.
+// Flags set on the command line or using environment variables take precedence over the config file.
+//
+// The config file can be a YAML (.yaml or .yml) or TOML (.toml) file, its keys are the names of the flags, e.g.:
+//
+//	port: 9001
+//	auth: jwt
+//	jwksUrl: https://example.com/.well-known/jwks.json
+//	rateLimit: ["createLink=60", "createBoard=10"]
+func loadConfigFile(flags []cli.Flag) cli.BeforeFunc {
+	return func(ctx *cli.Context) error {
+		path := ctx.String(configFileFlag)
+		if path == "" {
+			return nil
+		}
+		source, err := newConfigFileSource(path, flags)
+		if err != nil {
+			return err
+		}
+		return altsrc.ApplyInputSourceValues(ctx, source, flags)
+	}
+}
+
+// Reads the config file at the given path and checks that every key is the name of a flag and that every value has the type of the flag.
+// Unlike the file loaders of package altsrc, unknown keys are not ignored, e.g. a typo in a key should not go unnoticed.
+func newConfigFileSource(path string, flags []cli.Flag) (altsrc.InputSourceContext, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q, use .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse config file %v: %w", path, err)
+	}
+
+	flagsByName := map[string]cli.Flag{}
+	for _, f := range flags {
+		for _, name := range f.Names() {
+			flagsByName[name] = f
+		}
+	}
+
+	errs := &configErrors{source: "config file " + path}
+	m := make(map[interface{}]interface{}, len(values))
+	for _, key := range sortedKeys(values) {
+		f, ok := flagsByName[key]
+		if !ok || key == configFileFlag {
+			if suggestion := closestFlagName(key, flags); suggestion != "" {
+				errs.add("unknown key %q, did you mean %q?", key, suggestion)
+			} else {
+				errs.add("unknown key %q", key)
+			}
+			continue
+		}
+		v, err := configFileValue(f, values[key])
+		if err != nil {
+			errs.add("%v: %v", key, err)
+			continue
+		}
+		m[key] = v
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return altsrc.NewMapInputSource(path, m), nil
+}
+
+// Checks that the value read from a config file has the type of the flag and converts it to the type altsrc expects,
+// e.g. TOML integers are decoded as int64 but altsrc expects int.
+func configFileValue(f cli.Flag, v interface{}) (interface{}, error) {
+	switch f.(type) {
+	case *altsrc.IntFlag:
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case int64:
+			return int(n), nil
+		}
+		return nil, fmt.Errorf("expected an integer, got %v", describeValue(v))
+	case *altsrc.Float64Flag:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		}
+		return nil, fmt.Errorf("expected a number, got %v", describeValue(v))
+	case *altsrc.BoolFlag:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected true or false, got %v", describeValue(v))
+	case *altsrc.StringFlag:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("expected a string, got %v", describeValue(v))
+	case *altsrc.DurationFlag:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a duration like \"30s\" or \"72h\", got %v", describeValue(v))
+		}
+		if _, err := time.ParseDuration(s); err != nil {
+			return nil, fmt.Errorf("expected a duration like \"30s\" or \"72h\", got %q", s)
+		}
+		return s, nil
+	case *altsrc.StringSliceFlag:
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a list of strings, got %v", describeValue(v))
+		}
+		for i, item := range list {
+			if _, ok := item.(string); !ok {
+				return nil, fmt.Errorf("expected a list of strings, item %v is %v", i, describeValue(item))
+			}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("cannot be set in the config file")
+}
+
+func describeValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "a nested table, keys must not be nested"
+	case []interface{}:
+		return "a list"
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+	return fmt.Sprintf("%v (%T)", v, v)
+}
+
+// Returns the name of the flag that is most similar to the given key, or the empty string if no flag name is similar enough.
+func closestFlagName(key string, flags []cli.Flag) string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		if name := f.Names()[0]; name != configFileFlag {
+			names = append(names, name)
+		}
+	}
+	return closestName(key, names)
+}
+
+// Returns the name that is most similar to s, or an empty string if no name is similar enough.
+func closestName(s string, names []string) string {
+	best, bestDistance := "", len(s)/2+1
+	for _, name := range names {
+		if d := levenshtein.ComputeDistance(strings.ToLower(s), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Creates the config of the application from the values of the flags, which may have been set using the command line,
+// environment variables or the config file, and validates it.
+func configFromContext(ctx *cli.Context) (app.Config, error) {
+	config := app.Config{
+		Port:                       ctx.Int("port"),
+		Address:                    ctx.String("address"),
+		GRPCPort:                   ctx.Int("grpcPort"),
+		UseInmemDependencies:       ctx.Bool("inmem"),
+		UseFirebaseEmulators:       ctx.Bool("emulators"),
+		FirebaseProjectId:          ctx.String("firebaseProjectId"),
+		FirebaseServiceAccountFile: ctx.String("firebaseServiceAccountFile"),
+		FeedTokenSecret:            ctx.String("feedTokenSecret"),
+		AuthMode:                   ctx.String("auth"),
+		JWTSecret:                  ctx.String("jwtSecret"),
+		JWTPublicKeyFile:           ctx.String("jwtPublicKeyFile"),
+		JWKSURL:                    ctx.String("jwksUrl"),
+		JWTIssuer:                  ctx.String("jwtIssuer"),
+		JWTAudience:                ctx.String("jwtAudience"),
+		JWTClockSkew:               ctx.Duration("jwtClockSkew"),
+		JWTUserIdClaim:             ctx.String("jwtUserIdClaim"),
+		JWTNameClaim:               ctx.String("jwtNameClaim"),
+		AuthCacheTTL:               ctx.Duration("authCacheTtl"),
+		AuthCacheSize:              ctx.Int("authCacheSize"),
+		BoardLimits: boards.LimitsConfig{
+			Default: boards.Limits{
+				MaxUsersPerBoard:       ctx.Int("maxUsersPerBoard"),
+				MaxInvitesPerBoard:     ctx.Int("maxInvitesPerBoard"),
+				InviteExpiryDuration:   ctx.Duration("inviteExpiry"),
+				NameMaxLength:          ctx.Int("boardNameMaxLength"),
+				DescriptionMaxLength:   ctx.Int("boardDescriptionMaxLength"),
+				MaxBoardsPerUserPerDay: ctx.Int("maxBoardsPerUserPerDay"),
+			},
+		},
+		LinkLimits: links.LimitsConfig{
+			Default: links.Limits{
+				MaxTitleLength:        ctx.Int("linkTitleMaxLength"),
+				MaxTagsPerLink:        ctx.Int("maxTagsPerLink"),
+				MaxTagLength:          ctx.Int("tagMaxLength"),
+				MaxLinksPerUserPerDay: ctx.Int("maxLinksPerUserPerDay"),
+			},
+		},
+		CORSAllowedOrigins:    ctx.StringSlice("corsAllowedOrigins"),
+		ReadinessCheckTimeout: ctx.Duration("readinessCheckTimeout"),
+		EnableMetrics:         ctx.Bool("metrics"),
+		MetricsToken:          ctx.String("metricsToken"),
+		TracingExporter:       ctx.String("tracingExporter"),
+		OTLPEndpoint:          ctx.String("otlpEndpoint"),
+		OTLPInsecure:          ctx.Bool("otlpInsecure"),
+		TracingSampleRatio:    ctx.Float64("tracingSampleRatio"),
+		DebugMode:             ctx.Bool("debug"),
+	}
+
+	errs := &configErrors{source: "config"}
+	rateLimits, err := parseRateLimits(ctx.StringSlice("rateLimit"))
+	if err != nil {
+		errs.add("rateLimit: %v", err)
+	}
+	config.RateLimits = rateLimits
+	if path := ctx.String("limitsFile"); path != "" {
+		if err := loadLimitOverrides(path, &config); err != nil {
+			errs.add("limitsFile: %v", err)
+		}
+	}
+	validateConfig(config, errs)
+	return config, errs.err()
+}
+
+// Adds a problem to errs for every invalid value of the config.
+// Problems are reported using the names of the flags, which are also the keys of the config file.
+func validateConfig(config app.Config, errs *configErrors) {
+	if config.Port < 1 || config.Port > 65535 {
+		errs.add("port: must be between 1 and 65535, got %v", config.Port)
+	}
+	if config.GRPCPort < 0 || config.GRPCPort > 65535 {
+		errs.add("grpcPort: must be between 0 and 65535, got %v", config.GRPCPort)
+	} else if config.GRPCPort == config.Port {
+		errs.add("grpcPort: must be different from port %v", config.Port)
+	}
+	if config.UseInmemDependencies && config.UseFirebaseEmulators {
+		errs.add("emulators: cannot be used together with inmem")
+	}
+	checkFile(errs, "firebaseServiceAccountFile", config.FirebaseServiceAccountFile)
+
+	switch config.AuthMode {
+	case "":
+	case "firebase":
+		if config.UseInmemDependencies {
+			errs.add("auth: firebase authentication cannot be used with inmem")
+		}
+	case "jwt":
+		n := 0
+		for _, v := range []string{config.JWTSecret, config.JWTPublicKeyFile, config.JWKSURL} {
+			if v != "" {
+				n++
+			}
+		}
+		if n != 1 {
+			errs.add("auth: exactly one of jwtSecret, jwtPublicKeyFile and jwksUrl must be set if auth is \"jwt\"")
+		}
+		checkFile(errs, "jwtPublicKeyFile", config.JWTPublicKeyFile)
+		if config.JWKSURL != "" {
+			if u, err := url.Parse(config.JWKSURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				errs.add("jwksUrl: must be an http or https url, got %q", config.JWKSURL)
+			}
+		}
+		if config.JWTClockSkew < 0 {
+			errs.add("jwtClockSkew: must not be negative, got %v", config.JWTClockSkew)
+		}
+	default:
+		errs.add("auth: must be \"firebase\" or \"jwt\", got %q", config.AuthMode)
+	}
+
+	if config.AuthCacheTTL < 0 {
+		errs.add("authCacheTtl: must not be negative, got %v", config.AuthCacheTTL)
+	}
+	if config.AuthCacheTTL > 0 && config.AuthCacheSize <= 0 {
+		errs.add("authCacheSize: must be positive if the cache is enabled, got %v", config.AuthCacheSize)
+	}
+
+	if err := config.BoardLimits.Validate(); err != nil {
+		errs.add("board limits: %v", errorMessage(err))
+	}
+	if err := config.LinkLimits.Validate(); err != nil {
+		errs.add("link limits: %v", errorMessage(err))
+	}
+
+	for _, origin := range config.CORSAllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs.add("corsAllowedOrigins: origins must have the form scheme://host[:port] or be \"*\", got %q", origin)
+		}
+	}
+
+	if config.ReadinessCheckTimeout <= 0 {
+		errs.add("readinessCheckTimeout: must be positive, got %v", config.ReadinessCheckTimeout)
+	}
+	switch config.TracingExporter {
+	case "", "stdout", "otlp":
+	default:
+		errs.add("tracingExporter: must be \"stdout\" or \"otlp\", got %q", config.TracingExporter)
+	}
+	if config.TracingSampleRatio < 0 || config.TracingSampleRatio > 1 {
+		errs.add("tracingSampleRatio: must be between 0 and 1, got %v", config.TracingSampleRatio)
+	}
+}
+
+// Adds a problem if path is not empty and there is no file at path.
+func checkFile(errs *configErrors, flagName, path string) {
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		errs.add("%v: %v", flagName, err)
+	}
+}
+
+// Returns the internal messages of an error from package "github.com/dkinzler/kit/errors" and its inner errors,
+// which describe the problem better than the full error.
+func errorMessage(err error) string {
+	var messages []string
+	for err != nil {
+		e, ok := err.(errors.Error)
+		if !ok {
+			messages = append(messages, err.Error())
+			break
+		}
+		if e.InternalMessage != "" {
+			messages = append(messages, e.InternalMessage)
+		}
+		err = e.Inner
+	}
+	return strings.Join(messages, ": ")
+}
+
+// Writes the effective value of every flag, i.e. after applying the command line, environment variables, the config file and defaults,
+// using the given format ("yaml" or "toml"). The output can be used as a config file.
+// Secrets are redacted.
+func printConfig(ctx *cli.Context, flags []cli.Flag, format string, w io.Writer) error {
+	values := map[string]interface{}{}
+	for _, f := range flags {
+		name := f.Names()[0]
+		if name == configFileFlag {
+			continue
+		}
+		var v interface{}
+		switch f.(type) {
+		case *altsrc.IntFlag:
+			v = ctx.Int(name)
+		case *altsrc.Float64Flag:
+			v = ctx.Float64(name)
+		case *altsrc.BoolFlag:
+			v = ctx.Bool(name)
+		case *altsrc.DurationFlag:
+			v = ctx.Duration(name).String()
+		case *altsrc.StringSliceFlag:
+			s := ctx.StringSlice(name)
+			if s == nil {
+				s = []string{}
+			}
+			v = s
+		default:
+			s := ctx.String(name)
+			if secretFlags[name] && s != "" {
+				s = redacted
+			}
+			v = s
+		}
+		values[name] = v
+	}
+
+	var b bytes.Buffer
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err := enc.Encode(values); err != nil {
+			return err
+		}
+	case "toml":
+		if err := toml.NewEncoder(&b).Encode(values); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, use \"yaml\" or \"toml\"", format)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Returns a copy of the config with secrets replaced by "REDACTED", e.g. to log it.
+func redactConfig(config app.Config) app.Config {
+	if config.FeedTokenSecret != "" {
+		config.FeedTokenSecret = redacted
+	}
+	if config.JWTSecret != "" {
+		config.JWTSecret = redacted
+	}
+	return config
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkinzler/linkboards/cmd/api/app"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Runs an app with the flags of the application and returns the resulting config.
+func runWithArgs(args ...string) (app.Config, error) {
+	flags := newFlags()
+	var config app.Config
+	cliApp := &cli.App{
+		Flags:  flags,
+		Before: loadConfigFile(flags),
+		Action: func(ctx *cli.Context) error {
+			var err error
+			config, err = configFromContext(ctx)
+			return err
+		},
+	}
+	err := cliApp.Run(append([]string{"api"}, args...))
+	return config, err
+}
+
+func TestConfigFile(t *testing.T) {
+	a := assert.New(t)
+
+	yamlFile := writeConfigFile(t, "config.yaml", `
+port: 9100
+inmem: true
+auth: jwt
+jwtSecret: secret
+inviteExpiry: 48h
+tracingSampleRatio: 1
+rateLimit: ["createLink=5"]
+corsAllowedOrigins: ["https://app.example.com"]
+`)
+	config, err := runWithArgs("--config", yamlFile)
+	a.Nil(err)
+	a.Equal(9100, config.Port)
+	a.True(config.UseInmemDependencies)
+	a.Equal("jwt", config.AuthMode)
+	a.Equal("secret", config.JWTSecret)
+	a.Equal(48*time.Hour, config.BoardLimits.Default.InviteExpiryDuration)
+	a.Equal(1.0, config.TracingSampleRatio)
+	a.Len(config.RateLimits, 1)
+	a.Equal([]string{"https://app.example.com"}, config.CORSAllowedOrigins)
+	// defaults are used for values not in the file
+	a.Equal(32, config.BoardLimits.Default.MaxUsersPerBoard)
+
+	// command line and environment variables take precedence over the file
+	t.Setenv("MAX_USERS_PER_BOARD", "20")
+	config, err = runWithArgs("--config", yamlFile, "--port", "9200")
+	a.Nil(err)
+	a.Equal(9200, config.Port)
+	a.Equal(20, config.BoardLimits.Default.MaxUsersPerBoard)
+
+	tomlFile := writeConfigFile(t, "config.toml", `
+port = 9300
+authCacheTtl = "1m"
+tracingSampleRatio = 0.5
+`)
+	config, err = runWithArgs("--config", tomlFile)
+	a.Nil(err)
+	a.Equal(9300, config.Port)
+	a.Equal(time.Minute, config.AuthCacheTTL)
+	a.Equal(0.5, config.TracingSampleRatio)
+}
+
+func TestInvalidConfigFile(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := runWithArgs("--config", writeConfigFile(t, "config.json", `{}`))
+	a.ErrorContains(err, "unsupported config file extension")
+
+	_, err = runWithArgs("--config", writeConfigFile(t, "config.yaml", `
+maxUserPerBoard: 10
+someKey: 1
+port: "9001"
+inviteExpiry: 3
+auth:
+  mode: jwt
+`))
+	a.ErrorContains(err, `unknown key "maxUserPerBoard", did you mean "maxUsersPerBoard"?`)
+	a.ErrorContains(err, `unknown key "someKey"`)
+	a.NotContains(err.Error(), `"someKey", did you mean`)
+	a.ErrorContains(err, `port: expected an integer, got "9001"`)
+	a.ErrorContains(err, "inviteExpiry: expected a duration")
+	a.ErrorContains(err, "auth: expected a string, got a nested table")
+}
+
+func TestValidateConfig(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := runWithArgs("--inmem")
+	a.Nil(err)
+
+	_, err = runWithArgs(
+		"--port", "0",
+		"--auth", "jwt",
+		"--tracingExporter", "zipkin",
+		"--tracingSampleRatio", "2",
+		"--maxUsersPerBoard", "-1",
+		"--rateLimit", "createLink",
+		"--corsAllowedOrigins", "app.example.com",
+	)
+	a.ErrorContains(err, "port: must be between 1 and 65535, got 0")
+	a.ErrorContains(err, "auth: exactly one of jwtSecret, jwtPublicKeyFile and jwksUrl must be set")
+	a.ErrorContains(err, `tracingExporter: must be "stdout" or "otlp", got "zipkin"`)
+	a.ErrorContains(err, "tracingSampleRatio: must be between 0 and 1")
+	a.ErrorContains(err, "board limits: limits must not be negative")
+	a.ErrorContains(err, `rateLimit: invalid rate limit "createLink"`)
+	a.ErrorContains(err, `corsAllowedOrigins: origins must have the form scheme://host[:port] or be "*", got "app.example.com"`)
+
+	_, err = runWithArgs("--inmem", "--rateLimit", "graphql=100", "--rateLimit", "createLinks=5")
+	a.ErrorContains(err, `rateLimit: invalid rate limit "createLinks=5", unknown endpoint "createLinks", did you mean "createLink"?`)
+	a.NotContains(err.Error(), "graphql")
+
+	_, err = runWithArgs("--inmem", "--auth", "firebase")
+	a.ErrorContains(err, "auth: firebase authentication cannot be used with inmem")
+}
+
+func TestPrintConfig(t *testing.T) {
+	a := assert.New(t)
+
+	flags := newFlags()
+	var out bytes.Buffer
+	cliApp := &cli.App{
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			return printConfig(ctx, flags, "yaml", &out)
+		},
+	}
+	a.Nil(cliApp.Run([]string{"api", "--jwtSecret", "secret", "--port", "9100", "--inviteExpiry", "1h"}))
+	a.Contains(out.String(), "jwtSecret: REDACTED\n")
+	a.Contains(out.String(), "feedTokenSecret: \"\"\n")
+	a.Contains(out.String(), "port: 9100\n")
+	a.Contains(out.String(), "inviteExpiry: 1h0m0s\n")
+	a.NotContains(out.String(), "secret\n")
+	a.NotContains(out.String(), "config:")
+
+	// the output can be used as a config file
+	config, err := runWithArgs("--config", writeConfigFile(t, "config.yaml", out.String()))
+	a.Nil(err)
+	a.Equal(9100, config.Port)
+	a.Equal(time.Hour, config.BoardLimits.Default.InviteExpiryDuration)
+	a.Equal(redacted, config.JWTSecret)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkinzler/linkboards/cmd/api/app"
+	boards "github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/endpointname"
+	links "github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+)
+
+// Per-board overrides of the limits for boards and links, e.g. for boards of premium teams.
+// Fields that are not set use the configured defaults.
+//
+// Example file:
+//
+//	{
+//	  "boards": {
+//	    "b-55067be9-62a4-4861-8bbe-9e8382dd9751": {"maxUsersPerBoard": 100, "inviteExpiry": "168h"}
+//	  }
+//	}
+type limitOverridesFile struct {
+	Boards map[string]boardLimitOverride `json:"boards"`
+}
+
+type boardLimitOverride struct {
+	MaxUsersPerBoard     int    `json:"maxUsersPerBoard"`
+	MaxInvitesPerBoard   int    `json:"maxInvitesPerBoard"`
+	InviteExpiry         string `json:"inviteExpiry"`
+	NameMaxLength        int    `json:"nameMaxLength"`
+	DescriptionMaxLength int    `json:"descriptionMaxLength"`
+	MaxTitleLength       int    `json:"maxTitleLength"`
+	MaxTagsPerLink       int    `json:"maxTagsPerLink"`
+	MaxTagLength         int    `json:"maxTagLength"`
+	// Number of links a user can create per day on the board.
+	MaxLinksPerUserPerDay int `json:"maxLinksPerUserPerDay"`
+}
+
+// Reads the limit overrides in the given file and adds them to the board and link limits of config.
+func loadLimitOverrides(path string, config *app.Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read limits file: %w", err)
+	}
+
+	var f limitOverridesFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&f); err != nil {
+		return fmt.Errorf("could not parse limits file: %w", err)
+	}
+
+	if config.BoardLimits.BoardOverrides == nil {
+		config.BoardLimits.BoardOverrides = map[string]boards.Limits{}
+	}
+	if config.LinkLimits.BoardOverrides == nil {
+		config.LinkLimits.BoardOverrides = map[string]links.Limits{}
+	}
+
+	for boardId, o := range f.Boards {
+		var inviteExpiry time.Duration
+		if o.InviteExpiry != "" {
+			inviteExpiry, err = time.ParseDuration(o.InviteExpiry)
+			if err != nil {
+				return fmt.Errorf("invalid invite expiry for board %v: %w", boardId, err)
+			}
+		}
+		config.BoardLimits.BoardOverrides[boardId] = boards.Limits{
+			MaxUsersPerBoard:     o.MaxUsersPerBoard,
+			MaxInvitesPerBoard:   o.MaxInvitesPerBoard,
+			InviteExpiryDuration: inviteExpiry,
+			NameMaxLength:        o.NameMaxLength,
+			DescriptionMaxLength: o.DescriptionMaxLength,
+		}
+		config.LinkLimits.BoardOverrides[boardId] = links.Limits{
+			MaxTitleLength:        o.MaxTitleLength,
+			MaxTagsPerLink:        o.MaxTagsPerLink,
+			MaxTagLength:          o.MaxTagLength,
+			MaxLinksPerUserPerDay: o.MaxLinksPerUserPerDay,
+		}
+	}
+
+	return nil
+}
+
+// Parses rate limits of the form "endpointName=requestsPerMinute", e.g. "createLink=60".
+func parseRateLimits(values []string) (ratelimit.Limits, error) {
+	limits := make(ratelimit.Limits, len(values))
+	for _, v := range values {
+		name, n, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, expected endpointName=requestsPerMinute", v)
+		}
+		if !endpointname.IsRegistered(name) {
+			if suggestion := closestName(name, endpointname.Registered()); suggestion != "" {
+				return nil, fmt.Errorf("invalid rate limit %q, unknown endpoint %q, did you mean %q?", v, name, suggestion)
+			}
+			return nil, fmt.Errorf("invalid rate limit %q, unknown endpoint %q", v, name)
+		}
+		perMinute, err := strconv.Atoi(n)
+		if err != nil || perMinute <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q, requests per minute must be a positive integer", v)
+		}
+		limits[name] = ratelimit.PerMinute(perMinute)
+	}
+	return limits, nil
+}
@@ -3,72 +3,315 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	cli "github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
 )
 
 const version string = "1.0.0"
 
 func main() {
+	flags := newFlags()
 	app := &cli.App{
 		Name:    "Linkboards",
 		Usage:   "A sample API application for sharing links.",
 		Version: version,
-		Flags: []cli.Flag{
-			&cli.IntFlag{
-				Name:    "port",
-				Value:   9001,
-				EnvVars: []string{"PORT"},
-				Aliases: []string{"p"},
-				Usage:   "port the application will listen on",
-			},
-			&cli.StringFlag{
-				Name:    "address",
-				Value:   "",
-				EnvVars: []string{"address"},
-				Aliases: []string{"a"},
-				Usage:   "address the application will listen on",
-			},
-			&cli.BoolFlag{
-				Name:  "inmem",
-				Value: false,
-				Usage: "use local in-memory dependencies for authentication and data stores, useful for development/testing",
-			},
-			&cli.BoolFlag{
-				Name:  "emulators",
-				Value: false,
-				Usage: "use firebase emulators for authentication/data stores",
-			},
-			&cli.StringFlag{
-				Name:  "firebaseProjectId",
-				Value: "",
-			},
-			&cli.StringFlag{
-				Name:  "firebaseServiceAccountFile",
-				Value: "",
-				Usage: "path to service account file used to authenticate with Firebase services",
-			},
-			&cli.BoolFlag{
-				Name:  "debug",
-				Value: false,
-				Usage: "will pretty print JSON log messages",
-			},
-		},
+		Flags:   flags,
+		Before:  loadConfigFile(flags),
 		Action: func(ctx *cli.Context) error {
-			config := Config{
-				Port:                       ctx.Int("port"),
-				Address:                    ctx.String("address"),
-				UseInmemDependencies:       ctx.Bool("inmem"),
-				UseFirebaseEmulators:       ctx.Bool("emulators"),
-				FirebaseProjectId:          ctx.String("firebaseProjectId"),
-				FirebaseServiceAccountFile: ctx.String("firebaseServiceAccountFile"),
-				DebugMode:                  ctx.Bool("debug"),
+			config, err := configFromContext(ctx)
+			if err != nil {
+				return err
 			}
 			return runApp(config)
 		},
+		Commands: []*cli.Command{
+			{
+				Name:  "config",
+				Usage: "inspect the configuration",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "print",
+						Usage: "print the effective configuration with secrets redacted, after applying flags, environment variables, the config file and defaults",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "format",
+								Value: "yaml",
+								Usage: "output format, \"yaml\" or \"toml\"",
+							},
+						},
+						Action: func(ctx *cli.Context) error {
+							if err := printConfig(ctx, flags, ctx.String("format"), os.Stdout); err != nil {
+								return err
+							}
+							// still report problems, such that the command can be used to check a configuration
+							_, err := configFromContext(ctx)
+							return err
+						},
+					},
+				},
+			},
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// Returns the flags of the application.
+// Every flag can also be set using an environment variable or the config file given by --config, see loadConfigFile.
+// Values given on the command line take precedence over environment variables, which take precedence over the config file.
+func newFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    configFileFlag,
+			Value:   "",
+			EnvVars: []string{"CONFIG_FILE"},
+			Aliases: []string{"c"},
+			Usage:   "path to a YAML (.yaml, .yml) or TOML (.toml) config file, the keys of the file are the names of the flags",
+		},
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "port",
+			Value:   9001,
+			EnvVars: []string{"PORT"},
+			Aliases: []string{"p"},
+			Usage:   "port the application will listen on",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "address",
+			Value:   "",
+			EnvVars: []string{"ADDRESS"},
+			Aliases: []string{"a"},
+			Usage:   "address the application will listen on",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "grpcPort",
+			Value:   0,
+			EnvVars: []string{"GRPC_PORT"},
+			Usage:   "port the gRPC server will listen on, if 0 the gRPC server is disabled",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:    "inmem",
+			Value:   false,
+			EnvVars: []string{"INMEM"},
+			Usage:   "use local in-memory dependencies for authentication and data stores, useful for development/testing",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:    "emulators",
+			Value:   false,
+			EnvVars: []string{"FIREBASE_EMULATORS"},
+			Usage:   "use firebase emulators for authentication/data stores",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "firebaseProjectId",
+			Value:   "",
+			EnvVars: []string{"FIREBASE_PROJECT_ID"},
+			Usage:   "id of the Firebase project, if empty it is determined from the credentials",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "firebaseServiceAccountFile",
+			Value:   "",
+			EnvVars: []string{"FIREBASE_SERVICE_ACCOUNT_FILE"},
+			Usage:   "path to service account file used to authenticate with Firebase services",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "feedTokenSecret",
+			Value:   "",
+			EnvVars: []string{"FEED_TOKEN_SECRET"},
+			Usage:   "secret used to sign feed tokens, if empty a random secret is used and feed tokens will be invalid after a restart",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "auth",
+			Value:   "",
+			EnvVars: []string{"AUTH_MODE"},
+			Usage:   "authentication mechanism, \"firebase\" or \"jwt\", defaults to firebase or fake authentication if --inmem is set",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwtSecret",
+			Value:   "",
+			EnvVars: []string{"JWT_SECRET"},
+			Usage:   "secret used to verify HS256 tokens if --auth=jwt",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwtPublicKeyFile",
+			Value:   "",
+			EnvVars: []string{"JWT_PUBLIC_KEY_FILE"},
+			Usage:   "path to a PEM encoded RSA public key used to verify RS256 tokens if --auth=jwt",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwksUrl",
+			Value:   "",
+			EnvVars: []string{"JWKS_URL"},
+			Usage:   "url of a JSON Web Key Set used to verify tokens if --auth=jwt",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwtIssuer",
+			Value:   "",
+			EnvVars: []string{"JWT_ISSUER"},
+			Usage:   "if not empty, the iss claim of tokens must match",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwtAudience",
+			Value:   "",
+			EnvVars: []string{"JWT_AUDIENCE"},
+			Usage:   "if not empty, the aud claim of tokens must contain this value",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "jwtClockSkew",
+			Value:   30 * time.Second,
+			EnvVars: []string{"JWT_CLOCK_SKEW"},
+			Usage:   "tolerance when checking the expiry of tokens",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwtUserIdClaim",
+			Value:   "sub",
+			EnvVars: []string{"JWT_USER_ID_CLAIM"},
+			Usage:   "claim that contains the user id",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "jwtNameClaim",
+			Value:   "name",
+			EnvVars: []string{"JWT_NAME_CLAIM"},
+			Usage:   "claim that contains the name of the user",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "authCacheTtl",
+			Value:   30 * time.Second,
+			EnvVars: []string{"AUTH_CACHE_TTL"},
+			Usage:   "how long the roles of users are cached, 0 disables caching",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "authCacheSize",
+			Value:   10000,
+			EnvVars: []string{"AUTH_CACHE_SIZE"},
+			Usage:   "maximum number of cached roles",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "maxUsersPerBoard",
+			Value:   32,
+			EnvVars: []string{"MAX_USERS_PER_BOARD"},
+			Usage:   "maximum number of users of a board, pending join requests count against this limit",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "maxInvitesPerBoard",
+			Value:   32,
+			EnvVars: []string{"MAX_INVITES_PER_BOARD"},
+			Usage:   "maximum number of invites of a board",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "inviteExpiry",
+			Value:   3 * 24 * time.Hour,
+			EnvVars: []string{"INVITE_EXPIRY"},
+			Usage:   "duration after which invites expire",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "boardNameMaxLength",
+			Value:   100,
+			EnvVars: []string{"BOARD_NAME_MAX_LENGTH"},
+			Usage:   "maximum length of board names in bytes",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "boardDescriptionMaxLength",
+			Value:   1000,
+			EnvVars: []string{"BOARD_DESCRIPTION_MAX_LENGTH"},
+			Usage:   "maximum length of board descriptions in bytes",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "linkTitleMaxLength",
+			Value:   200,
+			EnvVars: []string{"LINK_TITLE_MAX_LENGTH"},
+			Usage:   "maximum length of link titles in bytes",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "maxTagsPerLink",
+			Value:   10,
+			EnvVars: []string{"MAX_TAGS_PER_LINK"},
+			Usage:   "maximum number of tags of a link",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "tagMaxLength",
+			Value:   50,
+			EnvVars: []string{"TAG_MAX_LENGTH"},
+			Usage:   "maximum length of link tags in bytes",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "maxBoardsPerUserPerDay",
+			Value:   50,
+			EnvVars: []string{"MAX_BOARDS_PER_USER_PER_DAY"},
+			Usage:   "maximum number of boards a user can create per day",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "maxLinksPerUserPerDay",
+			Value:   1000,
+			EnvVars: []string{"MAX_LINKS_PER_USER_PER_DAY"},
+			Usage:   "maximum number of links a user can create per day on a board",
+		}),
+		altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
+			Name:    "rateLimit",
+			Value:   cli.NewStringSlice("createBoard=10", "createLink=60", "createInvite=30", "importLinks=5"),
+			EnvVars: []string{"RATE_LIMITS"},
+			Usage:   "maximum number of requests per minute and user for an endpoint, e.g. createLink=60",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "limitsFile",
+			Value:   "",
+			EnvVars: []string{"LIMITS_FILE"},
+			Usage:   "path to a JSON file with per-board overrides of the board and link limits",
+		}),
+		altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
+			Name:    "corsAllowedOrigins",
+			EnvVars: []string{"CORS_ALLOWED_ORIGINS"},
+			Usage:   "origins of browser applications that can use the API, e.g. https://app.example.com, \"*\" allows all origins",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "readinessCheckTimeout",
+			Value:   2 * time.Second,
+			EnvVars: []string{"READINESS_CHECK_TIMEOUT"},
+			Usage:   "maximum duration of a single check of the /readyz endpoint, e.g. pinging a data store",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:    "metrics",
+			Value:   true,
+			EnvVars: []string{"METRICS"},
+			Usage:   "serve Prometheus metrics on /metrics, requires --metricsToken",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "metricsToken",
+			Value:   "",
+			EnvVars: []string{"METRICS_TOKEN"},
+			Usage:   "token that requests for /metrics must send in an Authorization header \"Bearer <token>\", metrics are disabled if empty",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "tracingExporter",
+			Value:   "",
+			EnvVars: []string{"TRACING_EXPORTER"},
+			Usage:   "exporter for OpenTelemetry traces, \"stdout\" or \"otlp\", tracing is disabled if empty",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "otlpEndpoint",
+			Value:   "",
+			EnvVars: []string{"OTLP_ENDPOINT"},
+			Usage:   "host and port of the OTLP collector that traces are sent to, e.g. localhost:4318",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:    "otlpInsecure",
+			Value:   false,
+			EnvVars: []string{"OTLP_INSECURE"},
+			Usage:   "send traces to the OTLP collector using HTTP instead of HTTPS",
+		}),
+		altsrc.NewFloat64Flag(&cli.Float64Flag{
+			Name:    "tracingSampleRatio",
+			Value:   1,
+			EnvVars: []string{"TRACING_SAMPLE_RATIO"},
+			Usage:   "fraction of new traces that are sampled",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:    "debug",
+			Value:   false,
+			EnvVars: []string{"DEBUG"},
+			Usage:   "will output debug log messages and pretty print JSON log messages",
+		}),
+	}
+}
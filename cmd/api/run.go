@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/dkinzler/linkboards/cmd/api/app"
+	"github.com/dkinzler/linkboards/internal/auth/store"
+
+	dhttp "github.com/dkinzler/kit/transport/http"
+
+	"github.com/dkinzler/kit/log"
+)
+
+// Runs the application using the given config.
+// Will create a boards and links component and expose their endpoints using a http server.
+func runApp(config app.Config) error {
+	var options []log.Option
+	if config.DebugMode {
+		options = append(options, log.AllowDebug, log.PrettyPrint)
+	}
+	logger := log.DefaultJSONLogger(options...)
+
+	config.Version = version
+	config.Commit = buildCommit()
+
+	// don't log secrets
+	logger.Info().Log("using config", redactConfig(config))
+
+	// seed rng
+	rand.Seed(time.Now().UnixNano())
+
+	a, err := app.New(config, logger)
+	if err != nil {
+		logger.Log("message", "could not create application", "error", err)
+		os.Exit(1)
+	}
+
+	if cache, ok := a.BoardComponent.AuthorizationStore.(*store.CachingAuthorizationStore); ok {
+		go logCacheStats(cache, logger, authCacheStatsInterval)
+	}
+
+	// Functions called when the server shuts down.
+	onShutdown := []func(){
+		// end open streams, so that the server can shut down
+		a.Hub.Close,
+	}
+
+	if config.GRPCPort != 0 {
+		grpcServer, err := app.NewGRPCServer(config, a, logger)
+		if err != nil {
+			logger.Log("message", "could not start grpc server", "error", err)
+			os.Exit(1)
+		}
+		onShutdown = append(onShutdown, grpcServer.GracefulStop)
+	}
+
+	err = runServer(
+		a.Handler,
+		a.StreamRouter,
+		dhttp.NewServerConfig().
+			WithAddress(config.Address).
+			WithPort(config.Port).
+			WithOnShutdownFunc(func(err error) {
+				logger.Info().Log("msg", "shutting down...")
+				if err != nil {
+					logger.Info().Log("msg", "error shutting down", "error", err)
+				}
+			}).
+			WithOnPanicFunc(func(i interface{}) {
+				logger.Warn().Log("msg", "caught panic in http handler", "error", i)
+			}),
+		onShutdown...,
+	)
+
+	if err != nil {
+		logger.Error().Log("msg", "error running http server", "error", err)
+	}
+
+	if a.TracerProvider != nil {
+		// export remaining spans
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.TracerProvider.Shutdown(ctx); err != nil {
+			logger.Error().Log("msg", "could not shut down tracer provider", "error", err)
+		}
+	}
+
+	return err
+}
+
+const authCacheStatsInterval = 5 * time.Minute
+
+// Periodically logs the hit rate of the authorization cache.
+func logCacheStats(cache *store.CachingAuthorizationStore, logger *log.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		stats := cache.Stats()
+		logger.Info().Log("message", "authorization cache stats", "hits", stats.Hits, "misses", stats.Misses, "hitRate", stats.HitRate(), "evictions", stats.Evictions, "entries", stats.Entries)
+	}
+}
+
+// Git commit the application was built from, can be set with: go build -ldflags "-X main.commit=<commit>".
+// If not set, the commit recorded by the go command is used if available.
+var commit string
+
+func buildCommit() string {
+	if commit != "" {
+		return commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				return s.Value
+			}
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/dkinzler/linkboards/cmd/api/app"
+
+	dhttp "github.com/dkinzler/kit/transport/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Like dhttp.RunDefaultServer but requests matching a route of streamRouter are long-lived streams,
+// that are not subject to the request timeout.
+// Therefore the server itself does not use a write timeout, the request timeout still bounds the duration of all other requests.
+//
+// The onShutdown functions are called when the server starts to shut down, they should end any open streams,
+// otherwise shutting down will block until the shutdown timeout expires.
+func runServer(router http.Handler, streamRouter *mux.Router, config dhttp.ServerConfig, onShutdown ...func()) error {
+	var h http.Handler = router
+	if config.RequestTimeout > 0 {
+		h = http.TimeoutHandler(h, config.RequestTimeout, "request timed out")
+	}
+
+	h = app.NewStreamHandler(streamRouter, h)
+
+	if config.RequestMaxBodyBytes > 0 {
+		h = dhttp.NewMaxRequestBodySizeHandler(h, int64(config.RequestMaxBodyBytes))
+	}
+
+	// catch panics
+	h = dhttp.PanicMiddleware(h, config.OnPanicFunc)
+
+	srv := &http.Server{
+		Handler: h,
+		Addr:    config.Address + ":" + strconv.Itoa(config.Port),
+		// this timeout also applies to reading the request header
+		ReadTimeout:    config.ReadTimeout,
+		MaxHeaderBytes: config.RequestMaxHeaderBytes,
+	}
+	for _, f := range onShutdown {
+		srv.RegisterOnShutdown(f)
+	}
+
+	// Sending a value on this channel will shutdown the server.
+	c := make(chan struct{}, 1)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}()
+
+	shutdown := dhttp.HandleShutdown(srv, c, config.OnShutdownFunc, 10*time.Second)
+
+	var returnError error
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		returnError = err
+		// Shutdown has not been called, make sure the shutdown handler runs, otherwise we would block forever below.
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+
+	// Wait for server shutdown to complete, there might still be open connections/requests.
+	<-shutdown
+	return returnError
+}
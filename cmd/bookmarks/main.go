@@ -0,0 +1,129 @@
+// Command bookmarks imports browser bookmark files into a board and exports the links of a board as a bookmark file.
+//
+// Most browsers can import and export bookmarks as HTML files in the Netscape bookmark file format,
+// the folders of a bookmark become tags of the imported link.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/dkinzler/linkboards/pkg/client"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "bookmarks",
+		Usage: "Import and export the links of a board as browser bookmarks.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "address",
+				Value:   "localhost:9001",
+				EnvVars: []string{"LINKBOARDS_ADDRESS"},
+				Aliases: []string{"a"},
+				Usage:   "address of the API, e.g. localhost:9001 or https://api.example.com",
+			},
+			&cli.StringFlag{
+				Name:     "token",
+				EnvVars:  []string{"LINKBOARDS_TOKEN"},
+				Required: true,
+				Usage:    "token used to authenticate with the API",
+			},
+			&cli.StringFlag{
+				Name:     "board",
+				Aliases:  []string{"b"},
+				Required: true,
+				Usage:    "id of the board",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:      "import",
+				Usage:     "import links from a bookmark file",
+				ArgsUsage: "FILE",
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 1 {
+						return cli.Exit("expected path of bookmark file", 1)
+					}
+					f, err := os.Open(ctx.Args().First())
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					return importBookmarks(ctx.Context, c, ctx.String("board"), f, ctx.App.Writer)
+				},
+			},
+			{
+				Name:      "export",
+				Usage:     "export links to a bookmark file, writes to stdout if no file is given",
+				ArgsUsage: "[FILE]",
+				Action: func(ctx *cli.Context) error {
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					var w io.Writer = ctx.App.Writer
+					if ctx.NArg() > 0 {
+						f, err := os.Create(ctx.Args().First())
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+						w = f
+					}
+					return exportBookmarks(ctx.Context, c, ctx.String("board"), w)
+				},
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// The address can be given with or without scheme, http is used if there is none.
+func newClient(ctx *cli.Context) (*client.Client, error) {
+	address := ctx.String("address")
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return client.New(client.Config{BaseURL: address, Token: ctx.String("token")})
+}
+
+func importBookmarks(ctx context.Context, c *client.Client, boardId string, r io.Reader, out io.Writer) error {
+	result, err := c.ImportBookmarks(ctx, boardId, r)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	fmt.Fprintf(out, "imported %v links\n", len(result.Imported))
+	if len(result.Failed) > 0 {
+		fmt.Fprintf(out, "could not import %v links:\n", len(result.Failed))
+		for _, f := range result.Failed {
+			fmt.Fprintf(out, "  %v (%v): %v\n", f.Title, f.Url, f.Message)
+		}
+	}
+	return nil
+}
+
+func exportBookmarks(ctx context.Context, c *client.Client, boardId string, w io.Writer) error {
+	truncated, err := c.ExportBookmarks(ctx, boardId, w)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	if truncated {
+		fmt.Fprintln(os.Stderr, "the board has more links than can be exported, only the newest links were exported")
+	}
+	return nil
+}
@@ -0,0 +1,374 @@
+// Command linkboards-admin lets operators inspect and repair the data of the application, e.g. list boards and their members,
+// remove users from boards, transfer ownership of boards or recompute the ratings of links.
+//
+// It connects directly to the Firestore data stores used by the API, see package internal/admin.
+// Authorization checks of the API do not apply.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/admin"
+	"github.com/dkinzler/linkboards/internal/auth"
+	boardsfs "github.com/dkinzler/linkboards/internal/boards/datastore/firestore"
+	boards "github.com/dkinzler/linkboards/internal/boards/domain"
+	linksfs "github.com/dkinzler/linkboards/internal/links/datastore/firestore"
+
+	lfb "github.com/dkinzler/kit/firebase"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "linkboards-admin",
+		Usage: "Inspect and repair the boards and links of the application.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "emulators",
+				EnvVars: []string{"FIREBASE_EMULATORS"},
+				Usage:   "use the Firestore emulator",
+			},
+			&cli.StringFlag{
+				Name:    "firebaseProjectId",
+				EnvVars: []string{"FIREBASE_PROJECT_ID"},
+				Usage:   "id of the Firebase project, if empty it is determined from the credentials",
+			},
+			&cli.StringFlag{
+				Name:    "firebaseServiceAccountFile",
+				EnvVars: []string{"FIREBASE_SERVICE_ACCOUNT_FILE"},
+				Usage:   "path to service account file used to authenticate with Firebase services, if empty application default credentials are used",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output JSON instead of tables",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Value: 5 * stdtime.Minute,
+				Usage: "maximum duration of a command",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "boards",
+				Usage: "list, search and modify boards",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "ls",
+						Usage: "list boards from newest to oldest",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "user", Aliases: []string{"u"}, Usage: "only list boards the user with this id is a member of"},
+							&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Value: 20, Usage: "maximum number of boards, between 1 and 100"},
+							&cli.Int64Flag{Name: "cursor", Usage: "only list boards created at or before this time (Unix nanoseconds), use the created time of the last board minus 1 to get the next page"},
+						},
+						Action: func(ctx *cli.Context) error {
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								qp := boards.NewQueryParams().WithLimit(ctx.Int("limit")).WithCursor(ctx.Int64("cursor"))
+								var result []boards.Board
+								var err error
+								if userId := ctx.String("user"); userId != "" {
+									result, err = s.BoardsForUser(c, userId, qp)
+								} else {
+									result, err = s.Boards(c, qp)
+								}
+								if err != nil {
+									return err
+								}
+								return out.boards(result)
+							})
+						},
+					},
+					{
+						Name:      "search",
+						Usage:     "find boards by id or by text contained in their name or description, reads all boards if necessary",
+						ArgsUsage: "QUERY",
+						Flags: []cli.Flag{
+							&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Value: 20, Usage: "maximum number of boards"},
+						},
+						Action: func(ctx *cli.Context) error {
+							if ctx.NArg() != 1 {
+								return cli.Exit("expected search query", 1)
+							}
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								result, err := s.SearchBoards(c, ctx.Args().First(), ctx.Int("limit"))
+								if err != nil {
+									return err
+								}
+								return out.boards(result)
+							})
+						},
+					},
+					{
+						Name:      "show",
+						Usage:     "show a board with its members, invites and join requests",
+						ArgsUsage: "BOARD_ID",
+						Action: func(ctx *cli.Context) error {
+							if ctx.NArg() != 1 {
+								return cli.Exit("expected board id", 1)
+							}
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								b, err := s.Board(c, ctx.Args().First())
+								if err != nil {
+									return err
+								}
+								return out.board(b)
+							})
+						},
+					},
+					{
+						Name:      "remove-user",
+						Usage:     "remove a user from a board, the owner cannot be removed",
+						ArgsUsage: "BOARD_ID USER_ID",
+						Action: func(ctx *cli.Context) error {
+							if ctx.NArg() != 2 {
+								return cli.Exit("expected board id and user id", 1)
+							}
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								boardId, userId := ctx.Args().Get(0), ctx.Args().Get(1)
+								if err := s.RemoveUser(c, boardId, userId); err != nil {
+									return err
+								}
+								return out.message("removed user %v from board %v", userId, boardId)
+							})
+						},
+					},
+					{
+						Name:      "transfer-ownership",
+						Usage:     "make a member of a board its owner",
+						ArgsUsage: "BOARD_ID USER_ID",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "previousOwnerRole", Value: auth.BoardRoleEditor, Usage: "new role of the previous owner"},
+						},
+						Action: func(ctx *cli.Context) error {
+							if ctx.NArg() != 2 {
+								return cli.Exit("expected board id and user id of the new owner", 1)
+							}
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								boardId, userId := ctx.Args().Get(0), ctx.Args().Get(1)
+								if err := s.TransferOwnership(c, boardId, userId, ctx.String("previousOwnerRole")); err != nil {
+									return err
+								}
+								return out.message("user %v is now the owner of board %v", userId, boardId)
+							})
+						},
+					},
+				},
+			},
+			{
+				Name:  "links",
+				Usage: "repair links and ratings",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "delete-orphaned",
+						Usage: "delete the links of boards that no longer exist",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{Name: "dryRun", Usage: "only list the boards and count their links, without deleting anything"},
+						},
+						Action: func(ctx *cli.Context) error {
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								result, err := s.DeleteOrphanedLinks(c, ctx.Bool("dryRun"))
+								if err != nil {
+									return err
+								}
+								return out.orphanedLinks(result, ctx.Bool("dryRun"))
+							})
+						},
+					},
+					{
+						Name:  "recompute-ratings",
+						Usage: "recompute the ratings of links from the ratings of the users, and list the ratings that changed",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "board", Aliases: []string{"b"}, Usage: "only recompute the ratings of the links of this board"},
+						},
+						Action: func(ctx *cli.Context) error {
+							return run(ctx, func(c context.Context, s *admin.Service, out output) error {
+								result, err := s.RecomputeRatings(c, ctx.String("board"))
+								if err != nil {
+									return err
+								}
+								return out.recomputedRatings(result)
+							})
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Connects to the data stores and runs f with a context that is canceled after the configured timeout.
+func run(ctx *cli.Context, f func(ctx context.Context, s *admin.Service, out output) error) error {
+	fbApp, err := lfb.NewApp(lfb.Config{
+		UseEmulators:       ctx.Bool("emulators"),
+		ProjectId:          ctx.String("firebaseProjectId"),
+		ServiceAccountFile: ctx.String("firebaseServiceAccountFile"),
+	})
+	if err != nil {
+		return fmt.Errorf("could not init firebase: %w", err)
+	}
+	client, err := lfb.NewFirestoreClient(fbApp)
+	if err != nil {
+		return fmt.Errorf("could not create firestore client: %w", err)
+	}
+	defer client.Close()
+
+	s := admin.NewService(boardsfs.NewFirestoreBoardDataStore(client), linksfs.NewFirestoreLinkDataStore(client))
+
+	c, cancel := context.WithTimeout(ctx.Context, ctx.Duration("timeout"))
+	defer cancel()
+	return f(c, s, output{w: ctx.App.Writer, json: ctx.Bool("json")})
+}
+
+// Writes the results of commands either as tables or JSON.
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+func (o output) writeJSON(v interface{}) error {
+	enc := json.NewEncoder(o.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (o output) table(header string, rows [][]string) error {
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (o output) message(format string, args ...interface{}) error {
+	if o.json {
+		return o.writeJSON(map[string]string{"message": fmt.Sprintf(format, args...)})
+	}
+	_, err := fmt.Fprintf(o.w, format+"\n", args...)
+	return err
+}
+
+func (o output) boards(result []boards.Board) error {
+	if o.json {
+		return o.writeJSON(result)
+	}
+	rows := make([][]string, len(result))
+	for i, b := range result {
+		rows[i] = []string{b.BoardId, b.Name, visibility(b.Visibility), formatTime(b.CreatedTime), fmt.Sprint(b.CreatedTime), b.CreatedBy.UserId}
+	}
+	return o.table("ID\tNAME\tVISIBILITY\tCREATED\tCREATED (UNIX NS)\tCREATED BY", rows)
+}
+
+func (o output) board(b boards.BoardWithUsersAndInvites) error {
+	if o.json {
+		return o.writeJSON(b)
+	}
+	fmt.Fprintf(o.w, "Board:       %v\nName:        %v\nDescription: %v\nVisibility:  %v\nCreated:     %v by %v\nModified:    %v by %v\n",
+		b.Board.BoardId, b.Board.Name, b.Board.Description, visibility(b.Board.Visibility),
+		formatTime(b.Board.CreatedTime), formatUser(b.Board.CreatedBy), formatTime(b.Board.ModifiedTime), formatUser(b.Board.ModifiedBy))
+	if len(b.Board.CustomRoles) > 0 {
+		names := make([]string, len(b.Board.CustomRoles))
+		for i, r := range b.Board.CustomRoles {
+			names[i] = r.Name
+		}
+		fmt.Fprintf(o.w, "Roles:       %v\n", strings.Join(names, ", "))
+	}
+
+	fmt.Fprintf(o.w, "\nMembers (%v):\n", len(b.Users))
+	rows := make([][]string, len(b.Users))
+	for i, u := range b.Users {
+		rows[i] = []string{u.User.UserId, u.User.Name, u.Role, formatTime(u.CreatedTime), formatUser(u.InvitedBy)}
+	}
+	if err := o.table("USER ID\tNAME\tROLE\tJOINED\tINVITED BY", rows); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.w, "\nInvites (%v):\n", len(b.Invites))
+	rows = make([][]string, len(b.Invites))
+	for i, inv := range b.Invites {
+		rows[i] = []string{inv.InviteId, formatUser(inv.User), inv.Role, formatTime(inv.ExpiresTime), formatUser(inv.CreatedBy)}
+	}
+	if err := o.table("INVITE ID\tFOR USER\tROLE\tEXPIRES\tCREATED BY", rows); err != nil {
+		return err
+	}
+
+	if len(b.JoinRequests) > 0 {
+		fmt.Fprintf(o.w, "\nJoin requests (%v):\n", len(b.JoinRequests))
+		rows = make([][]string, len(b.JoinRequests))
+		for i, jr := range b.JoinRequests {
+			rows[i] = []string{jr.JoinRequestId, formatUser(jr.User), formatTime(jr.CreatedTime)}
+		}
+		return o.table("JOIN REQUEST ID\tUSER\tCREATED", rows)
+	}
+	return nil
+}
+
+func (o output) orphanedLinks(result admin.OrphanedLinks, dryRun bool) error {
+	if o.json {
+		return o.writeJSON(result)
+	}
+	verb := "deleted"
+	if dryRun {
+		verb = "found"
+	}
+	_, err := fmt.Fprintf(o.w, "%v %v links of %v boards that do not exist\n", verb, result.Links, len(result.BoardIds))
+	for _, boardId := range result.BoardIds {
+		fmt.Fprintln(o.w, "  "+boardId)
+	}
+	return err
+}
+
+func (o output) recomputedRatings(result admin.RecomputedRatings) error {
+	if o.json {
+		return o.writeJSON(result)
+	}
+	fmt.Fprintf(o.w, "recomputed the ratings of %v links, %v were inconsistent\n", result.Links, len(result.Changes))
+	if len(result.Changes) == 0 {
+		return nil
+	}
+	rows := make([][]string, len(result.Changes))
+	for i, c := range result.Changes {
+		rows[i] = []string{c.BoardId, c.LinkId,
+			fmt.Sprintf("%v (+%v/%v)", c.Old.Score, c.Old.Upvotes, c.Old.Downvotes),
+			fmt.Sprintf("%v (+%v/%v)", c.New.Score, c.New.Upvotes, c.New.Downvotes)}
+	}
+	return o.table("BOARD ID\tLINK ID\tOLD SCORE\tNEW SCORE", rows)
+}
+
+func formatTime(unixNano int64) string {
+	if unixNano == 0 {
+		return "-"
+	}
+	return stdtime.Unix(0, unixNano).UTC().Format(stdtime.RFC3339)
+}
+
+func formatUser(u boards.User) string {
+	if u.UserId == "" {
+		return "-"
+	}
+	if u.Name == "" {
+		return u.UserId
+	}
+	return u.Name + " (" + u.UserId + ")"
+}
+
+// Boards created before visibility settings were introduced have no visibility, they are private.
+func visibility(v string) string {
+	if v == "" {
+		return boards.VisibilityPrivate
+	}
+	return v
+}
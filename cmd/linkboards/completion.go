@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+// Completion scripts call the command with the --generate-bash-completion flag, that makes it print the possible
+// completions of the current arguments, see https://cli.urfave.org/v2/examples/bash-completions/.
+const bashCompletion = `_linkboards_complete() {
+  local cur opts
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} ${cur} --generate-bash-completion )
+  else
+    opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} --generate-bash-completion )
+  fi
+  COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+  return 0
+}
+
+complete -o bashdefault -o default -o nospace -F _linkboards_complete PROG
+`
+
+const zshCompletion = `#compdef PROG
+
+_linkboards_complete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion)}")
+  else
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _linkboards_complete PROG
+`
+
+func completionCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Usage:     "print a shell completion script, e.g. add 'source <(linkboards completion bash)' to ~/.bashrc",
+		ArgsUsage: "bash|zsh|fish",
+		Action: func(ctx *cli.Context) error {
+			var script string
+			switch ctx.Args().First() {
+			case "bash":
+				script = strings.ReplaceAll(bashCompletion, "PROG", ctx.App.Name)
+			case "zsh":
+				script = strings.ReplaceAll(zshCompletion, "PROG", ctx.App.Name)
+			case "fish":
+				var err error
+				script, err = ctx.App.ToFishCompletion()
+				if err != nil {
+					return err
+				}
+			default:
+				return cli.Exit("expected shell, one of bash, zsh and fish", 1)
+			}
+			_, err := fmt.Fprint(ctx.App.Writer, script)
+			return err
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dkinzler/linkboards/pkg/client"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+// Returns the file the token is saved to by default, e.g. ~/.config/linkboards/token on Linux.
+func defaultTokenFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "linkboards", "token")
+}
+
+// Returns the token given with the token flag or environment variable, or otherwise the token saved by the login command.
+func token(ctx *cli.Context) (string, error) {
+	if t := ctx.String("token"); t != "" {
+		return t, nil
+	}
+	path := ctx.String("tokenFile")
+	if path == "" {
+		return "", cli.Exit("not logged in, run \"linkboards login\" or set LINKBOARDS_TOKEN", 1)
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", cli.Exit("not logged in, run \"linkboards login\" or set LINKBOARDS_TOKEN", 1)
+	} else if err != nil {
+		return "", fmt.Errorf("could not read token file: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func newClient(ctx *cli.Context) (*client.Client, error) {
+	t, err := token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newClientWithToken(ctx, t)
+}
+
+// The address can be given with or without scheme, http is used if there is none.
+func newClientWithToken(ctx *cli.Context, token string) (*client.Client, error) {
+	address := ctx.String("address")
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	c, err := client.New(client.Config{BaseURL: address, Token: token})
+	if err != nil {
+		return nil, cli.Exit(err.Error(), 1)
+	}
+	return c, nil
+}
+
+// Converts an error returned by the API into a message for the user.
+func requestError(err error) error {
+	var e *client.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	if e.StatusCode == http.StatusUnauthorized {
+		return cli.Exit("token is invalid or expired, run \"linkboards login\" again", 1)
+	}
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Errorf("request failed with status %v: %v", e.StatusCode, message)
+}
+
+func loginCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "login",
+		Usage:     "check a token and save it, so that other commands can use it, the token is read from stdin if not given",
+		ArgsUsage: "[TOKEN]",
+		Action: func(ctx *cli.Context) error {
+			path := ctx.String("tokenFile")
+			if path == "" {
+				return cli.Exit("no token file, use the --tokenFile flag", 1)
+			}
+
+			t := ctx.Args().First()
+			if t == "" {
+				fmt.Fprint(ctx.App.ErrWriter, "Token: ")
+				line, err := bufio.NewReader(ctx.App.Reader).ReadString('\n')
+				if err != nil && line == "" {
+					return fmt.Errorf("could not read token: %w", err)
+				}
+				t = strings.TrimSpace(line)
+			}
+			if t == "" {
+				return cli.Exit("token must not be empty", 1)
+			}
+
+			// make sure the token is accepted by the API before saving it
+			c, err := newClientWithToken(ctx, t)
+			if err != nil {
+				return err
+			}
+			if _, err := c.GetBoards(ctx.Context, client.QueryParams{Limit: 1}); err != nil {
+				return requestError(err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return fmt.Errorf("could not create directory for token file: %w", err)
+			}
+			if err := os.WriteFile(path, []byte(t+"\n"), 0600); err != nil {
+				return fmt.Errorf("could not save token: %w", err)
+			}
+			fmt.Fprintf(ctx.App.Writer, "logged in, token saved to %v\n", path)
+			return nil
+		},
+	}
+}
+
+func logoutCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "logout",
+		Usage: "delete the token saved by the login command",
+		Action: func(ctx *cli.Context) error {
+			path := ctx.String("tokenFile")
+			if path == "" {
+				return nil
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("could not delete token file: %w", err)
+			}
+			fmt.Fprintln(ctx.App.Writer, "logged out")
+			return nil
+		},
+	}
+}
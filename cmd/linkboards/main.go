@@ -0,0 +1,323 @@
+// Command linkboards is a command-line client for the API, that lets users list and create boards,
+// respond to invites as well as add, list and rate links.
+//
+// Use "linkboards login" to save a token, or provide it with the --token flag or LINKBOARDS_TOKEN environment variable.
+// Shell completion scripts can be generated with "linkboards completion bash|zsh|fish".
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dkinzler/linkboards/pkg/client"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func main() {
+	if err := newApp().Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newApp() *cli.App {
+	return &cli.App{
+		Name:                 "linkboards",
+		Usage:                "Share links on boards from the terminal.",
+		EnableBashCompletion: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "address",
+				Value:   "localhost:9001",
+				EnvVars: []string{"LINKBOARDS_ADDRESS"},
+				Aliases: []string{"a"},
+				Usage:   "address of the API, e.g. localhost:9001 or https://api.example.com",
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				EnvVars: []string{"LINKBOARDS_TOKEN"},
+				Usage:   "token used to authenticate with the API, if empty the token saved by the login command is used",
+			},
+			&cli.StringFlag{
+				Name:    "tokenFile",
+				Value:   defaultTokenFile(),
+				EnvVars: []string{"LINKBOARDS_TOKEN_FILE"},
+				Usage:   "file the login command saves the token to",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output JSON instead of tables",
+			},
+		},
+		Commands: []*cli.Command{
+			loginCommand(),
+			logoutCommand(),
+			boardsCommand(),
+			invitesCommand(),
+			linksCommand(),
+			completionCommand(),
+		},
+	}
+}
+
+func boardsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "boards",
+		Usage: "list, create and edit boards",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "ls",
+				Usage: "list the boards you are a member of, sorted by the time you joined",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Usage: "maximum number of boards"},
+					&cli.Int64Flag{Name: "cursor", Usage: "only list boards you joined at or before this time (Unix nanoseconds)"},
+				},
+				Action: func(ctx *cli.Context) error {
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					boards, err := c.GetBoards(ctx.Context, client.QueryParams{Limit: ctx.Int("limit"), Cursor: ctx.Int64("cursor")})
+					if err != nil {
+						return requestError(err)
+					}
+					return newOutput(ctx).boards(boards)
+				},
+			},
+			{
+				Name:      "create",
+				Usage:     "create a new board",
+				ArgsUsage: "NAME",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "description", Aliases: []string{"d"}, Usage: "description of the board"},
+					&cli.StringFlag{Name: "visibility", Usage: "one of \"private\", \"link\" and \"public\""},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 1 {
+						return cli.Exit("expected name of the board", 1)
+					}
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					created, err := c.CreateBoard(ctx.Context, client.NewBoard{
+						Name:        ctx.Args().First(),
+						Description: ctx.String("description"),
+					})
+					if err != nil {
+						return requestError(err)
+					}
+					board := created.Board
+					// new boards are private, the visibility can only be changed afterwards
+					if ctx.IsSet("visibility") {
+						edited, err := c.EditBoard(ctx.Context, board.BoardId, client.BoardEdit{}.WithVisibility(ctx.String("visibility")))
+						if err != nil {
+							return fmt.Errorf("board %v was created, but its visibility could not be changed: %w", board.BoardId, requestError(err))
+						}
+						board = edited
+					}
+					return newOutput(ctx).boards([]client.Board{board})
+				},
+			},
+			{
+				Name:      "edit",
+				Usage:     "change the name, description or visibility of a board",
+				ArgsUsage: "BOARD_ID",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "name", Usage: "new name of the board"},
+					&cli.StringFlag{Name: "description", Aliases: []string{"d"}, Usage: "new description of the board"},
+					&cli.StringFlag{Name: "visibility", Usage: "one of \"private\", \"link\" and \"public\", only the owner of a board can change it"},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 1 {
+						return cli.Exit("expected board id", 1)
+					}
+					// only send the fields that were given, so that e.g. the description can also be set to the empty string
+					be := client.BoardEdit{}
+					if ctx.IsSet("name") {
+						be.WithName(ctx.String("name"))
+					}
+					if ctx.IsSet("description") {
+						be.WithDescription(ctx.String("description"))
+					}
+					if ctx.IsSet("visibility") {
+						be.WithVisibility(ctx.String("visibility"))
+					}
+					if len(be) == 0 {
+						return cli.Exit("nothing to change, use --name, --description or --visibility", 1)
+					}
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					board, err := c.EditBoard(ctx.Context, ctx.Args().First(), be)
+					if err != nil {
+						return requestError(err)
+					}
+					return newOutput(ctx).boards([]client.Board{board})
+				},
+			},
+		},
+	}
+}
+
+func invitesCommand() *cli.Command {
+	respond := func(response string, verb string) cli.ActionFunc {
+		return func(ctx *cli.Context) error {
+			if ctx.NArg() != 2 {
+				return cli.Exit("expected board id and invite id", 1)
+			}
+			c, err := newClient(ctx)
+			if err != nil {
+				return err
+			}
+			boardId, inviteId := ctx.Args().Get(0), ctx.Args().Get(1)
+			err = c.RespondToInvite(ctx.Context, boardId, inviteId, client.InviteResponse{Response: response})
+			if err != nil {
+				return requestError(err)
+			}
+			return newOutput(ctx).message("%v invite %v to board %v", verb, inviteId, boardId)
+		}
+	}
+
+	return &cli.Command{
+		Name:  "invites",
+		Usage: "list and respond to invites to boards",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "ls",
+				Usage: "list the invites you received",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Usage: "maximum number of invites"},
+					&cli.Int64Flag{Name: "cursor", Usage: "only list invites created at or before this time (Unix nanoseconds)"},
+				},
+				Action: func(ctx *cli.Context) error {
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					invites, err := c.GetInvites(ctx.Context, client.QueryParams{Limit: ctx.Int("limit"), Cursor: ctx.Int64("cursor")})
+					if err != nil {
+						return requestError(err)
+					}
+					return newOutput(ctx).invites(invites)
+				},
+			},
+			{
+				Name:      "accept",
+				Usage:     "accept an invite and join the board",
+				ArgsUsage: "BOARD_ID INVITE_ID",
+				Action:    respond(client.InviteResponseAccept, "accepted"),
+			},
+			{
+				Name:      "decline",
+				Usage:     "decline an invite",
+				ArgsUsage: "BOARD_ID INVITE_ID",
+				Action:    respond(client.InviteResponseDecline, "declined"),
+			},
+		},
+	}
+}
+
+func linksCommand() *cli.Command {
+	boardFlag := &cli.StringFlag{
+		Name:     "board",
+		Aliases:  []string{"b"},
+		EnvVars:  []string{"LINKBOARDS_BOARD"},
+		Required: true,
+		Usage:    "id of the board",
+	}
+
+	return &cli.Command{
+		Name:  "links",
+		Usage: "add, list and rate the links of a board",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "add a link to a board, the url is used as title if none is given",
+				ArgsUsage: "URL [TITLE]",
+				Flags: []cli.Flag{
+					boardFlag,
+					&cli.StringSliceFlag{Name: "tag", Aliases: []string{"t"}, Usage: "tag of the link, can be given multiple times"},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() < 1 || ctx.NArg() > 2 {
+						return cli.Exit("expected url and optional title of the link", 1)
+					}
+					nl := client.NewLink{
+						Url:   ctx.Args().Get(0),
+						Title: ctx.Args().Get(1),
+						Tags:  ctx.StringSlice("tag"),
+					}
+					if nl.Title == "" {
+						nl.Title = nl.Url
+					}
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					link, err := c.CreateLink(ctx.Context, ctx.String("board"), nl)
+					if err != nil {
+						return requestError(err)
+					}
+					return newOutput(ctx).links([]client.Link{link})
+				},
+			},
+			{
+				Name:  "ls",
+				Usage: "list the links of a board",
+				Flags: []cli.Flag{
+					boardFlag,
+					&cli.StringFlag{Name: "sort", Aliases: []string{"s"}, Value: client.LinkSortNewest, Usage: "\"newest\" or \"top\""},
+					&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Usage: "maximum number of links, between 10 and 100"},
+				},
+				Action: func(ctx *cli.Context) error {
+					sort := ctx.String("sort")
+					if sort != client.LinkSortNewest && sort != client.LinkSortTop {
+						return cli.Exit("sort must be \"newest\" or \"top\"", 1)
+					}
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					links, err := c.GetLinks(ctx.Context, ctx.String("board"), client.LinkQueryParams{Limit: ctx.Int("limit"), Sort: sort})
+					if err != nil {
+						return requestError(err)
+					}
+					return newOutput(ctx).links(links)
+				},
+			},
+			{
+				Name:      "rate",
+				Usage:     "upvote or downvote a link",
+				ArgsUsage: "LINK_ID up|down",
+				Flags:     []cli.Flag{boardFlag},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 2 {
+						return cli.Exit("expected link id and rating", 1)
+					}
+					var rating int
+					switch ctx.Args().Get(1) {
+					case "up":
+						rating = 1
+					case "down":
+						rating = -1
+					default:
+						return cli.Exit("rating must be \"up\" or \"down\"", 1)
+					}
+					c, err := newClient(ctx)
+					if err != nil {
+						return err
+					}
+					linkId := ctx.Args().First()
+					err = c.RateLink(ctx.Context, ctx.String("board"), linkId, client.LinkRating{Rating: rating})
+					if err != nil {
+						return requestError(err)
+					}
+					return newOutput(ctx).message("rated link %v", linkId)
+				},
+			},
+		},
+	}
+}
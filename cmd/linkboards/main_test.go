@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dkinzler/linkboards/pkg/client"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "token-1"
+
+// Fake API that records the requests it receives.
+type fakeApi struct {
+	requests []*http.Request
+	bodies   []map[string]interface{}
+}
+
+func (f *fakeApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r)
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.bodies = append(f.bodies, body)
+
+	if r.Header.Get("Authorization") != "Bearer "+testToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":0,"message":"unauthenticated"}}`))
+		return
+	}
+
+	var result interface{}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/boards":
+		result = []client.Board{{BoardId: "b-1", Name: "Go", Visibility: client.VisibilityPrivate, CreatedTime: 1}}
+	case r.Method == http.MethodPost && r.URL.Path == "/boards":
+		result = client.Board{BoardId: "b-1", Name: body["name"].(string), Visibility: client.VisibilityPrivate}
+	case r.Method == http.MethodPatch && r.URL.Path == "/boards/b-1":
+		result = client.Board{BoardId: "b-1", Name: "Go", Visibility: client.VisibilityPrivate}
+		if name, ok := body["name"].(string); ok {
+			result = client.Board{BoardId: "b-1", Name: name}
+		}
+		if v, ok := body["visibility"].(string); ok {
+			result = client.Board{BoardId: "b-1", Name: "Go", Visibility: v}
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/invites":
+		result = []client.BoardInvite{{BoardId: "b-2", InviteId: "i-1", Role: client.RoleEditor, CreatedBy: client.User{UserId: "u-2", Name: "Alice"}}}
+	case r.Method == http.MethodPost && r.URL.Path == "/boards/b-2/invites/i-1":
+		result = struct{}{}
+	case r.Method == http.MethodPost && r.URL.Path == "/boards/b-1/links":
+		result = client.Link{BoardId: "b-1", LinkId: "l-1", Title: body["title"].(string), Url: body["url"].(string)}
+	case r.Method == http.MethodGet && r.URL.Path == "/boards/b-1/links":
+		result = []client.Link{
+			{LinkId: "l-1", Title: "Go", Url: "https://go.dev", Score: 3, UserRating: 1, Tags: []string{"go", "docs"}},
+			{LinkId: "l-2", Title: "Tour", Url: "https://go.dev/tour", Score: -1},
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":0,"message":"not found"}}`))
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeApi) lastRequest() (*http.Request, map[string]interface{}) {
+	return f.requests[len(f.requests)-1], f.bodies[len(f.bodies)-1]
+}
+
+// Runs the app against the given server and returns its output.
+func run(server *httptest.Server, tokenFile string, args ...string) (string, error) {
+	var out bytes.Buffer
+	app := newApp()
+	app.Writer = &out
+	app.ErrWriter = &out
+	app.Reader = strings.NewReader(testToken + "\n")
+	// don't exit on errors created with cli.Exit
+	app.ExitErrHandler = func(*cli.Context, error) {}
+	address := strings.TrimPrefix(server.URL, "http://")
+	err := app.Run(append([]string{"linkboards", "--address", address, "--tokenFile", tokenFile}, args...))
+	return out.String(), err
+}
+
+func TestLogin(t *testing.T) {
+	a := assert.New(t)
+	api := &fakeApi{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	t.Setenv("LINKBOARDS_TOKEN", "")
+	tokenFile := filepath.Join(t.TempDir(), "linkboards", "token")
+
+	_, err := run(server, tokenFile, "boards", "ls")
+	a.ErrorContains(err, "not logged in")
+
+	_, err = run(server, tokenFile, "login", "invalid")
+	a.ErrorContains(err, "token is invalid")
+	a.NoFileExists(tokenFile)
+
+	// token is read from stdin
+	out, err := run(server, tokenFile, "login")
+	a.Nil(err)
+	a.Contains(out, "logged in")
+	b, err := os.ReadFile(tokenFile)
+	a.Nil(err)
+	a.Equal(testToken+"\n", string(b))
+
+	_, err = run(server, tokenFile, "boards", "ls")
+	a.Nil(err)
+
+	// flag takes precedence over the saved token
+	_, err = run(server, tokenFile, "--token", "invalid", "boards", "ls")
+	a.ErrorContains(err, "token is invalid")
+
+	_, err = run(server, tokenFile, "logout")
+	a.Nil(err)
+	a.NoFileExists(tokenFile)
+}
+
+func TestCommands(t *testing.T) {
+	a := assert.New(t)
+	api := &fakeApi{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	t.Setenv("LINKBOARDS_TOKEN", testToken)
+
+	out, err := run(server, "", "boards", "ls")
+	a.Nil(err)
+	a.Contains(out, "ID   NAME  VISIBILITY")
+	a.Contains(out, "b-1  Go    private")
+
+	out, err = run(server, "", "--json", "boards", "ls")
+	a.Nil(err)
+	var boards []client.Board
+	a.Nil(json.Unmarshal([]byte(out), &boards))
+	a.Len(boards, 1)
+
+	// visibility is set with a separate request
+	out, err = run(server, "", "boards", "create", "--visibility", "public", "Go")
+	a.Nil(err)
+	a.Contains(out, "b-1  Go    public")
+	r, body := api.lastRequest()
+	a.Equal(http.MethodPatch, r.Method)
+	a.Equal(map[string]interface{}{"visibility": "public"}, body)
+
+	_, err = run(server, "", "boards", "edit", "b-1")
+	a.ErrorContains(err, "nothing to change")
+	_, err = run(server, "", "boards", "edit", "--name", "Golang", "b-1")
+	a.Nil(err)
+	_, body = api.lastRequest()
+	a.Equal(map[string]interface{}{"name": "Golang"}, body)
+
+	out, err = run(server, "", "invites", "ls")
+	a.Nil(err)
+	a.Contains(out, "b-2       i-1        editor  Alice")
+	out, err = run(server, "", "invites", "accept", "b-2", "i-1")
+	a.Nil(err)
+	a.Contains(out, "accepted invite i-1 to board b-2")
+	_, body = api.lastRequest()
+	a.Equal("accept", body["response"])
+
+	_, err = run(server, "", "links", "add", "--board", "b-1", "--tag", "go", "https://go.dev")
+	a.Nil(err)
+	_, body = api.lastRequest()
+	a.Equal("https://go.dev", body["title"])
+	a.Equal([]interface{}{"go"}, body["tags"])
+
+	out, err = run(server, "", "links", "ls", "-b", "b-1", "--sort", "top")
+	a.Nil(err)
+	r, _ = api.lastRequest()
+	a.Equal("top", r.URL.Query().Get("sort"))
+	a.Contains(out, "l-1  3      up           Go     https://go.dev       go,docs")
+	_, err = run(server, "", "links", "ls", "-b", "b-1", "--sort", "rating")
+	a.ErrorContains(err, "sort must be")
+
+	_, err = run(server, "", "links", "rate", "-b", "b-2", "l-1", "up")
+	a.ErrorContains(err, "request failed with status 404: not found")
+
+	out, err = run(server, "", "completion", "bash")
+	a.Nil(err)
+	a.Contains(out, "-F _linkboards_complete linkboards")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dkinzler/linkboards/pkg/client"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+// Writes the results of commands either as tables or JSON.
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+func newOutput(ctx *cli.Context) output {
+	return output{w: ctx.App.Writer, json: ctx.Bool("json")}
+}
+
+func (o output) writeJSON(v interface{}) error {
+	enc := json.NewEncoder(o.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (o output) table(header string, rows [][]string) error {
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (o output) message(format string, args ...interface{}) error {
+	if o.json {
+		return o.writeJSON(map[string]string{"message": fmt.Sprintf(format, args...)})
+	}
+	_, err := fmt.Fprintf(o.w, format+"\n", args...)
+	return err
+}
+
+func (o output) boards(boards []client.Board) error {
+	if o.json {
+		return o.writeJSON(boards)
+	}
+	rows := make([][]string, len(boards))
+	for i, b := range boards {
+		rows[i] = []string{b.BoardId, b.Name, b.Visibility, formatTime(b.CreatedTime), b.Description}
+	}
+	return o.table("ID\tNAME\tVISIBILITY\tCREATED\tDESCRIPTION", rows)
+}
+
+func (o output) invites(invites []client.BoardInvite) error {
+	if o.json {
+		return o.writeJSON(invites)
+	}
+	rows := make([][]string, len(invites))
+	for i, inv := range invites {
+		rows[i] = []string{inv.BoardId, inv.InviteId, inv.Role, formatUser(inv.CreatedBy), formatTime(inv.ExpiresTime)}
+	}
+	return o.table("BOARD ID\tINVITE ID\tROLE\tFROM\tEXPIRES", rows)
+}
+
+func (o output) links(links []client.Link) error {
+	if o.json {
+		return o.writeJSON(links)
+	}
+	rows := make([][]string, len(links))
+	for i, l := range links {
+		rows[i] = []string{l.LinkId, fmt.Sprint(l.Score), formatRating(l.UserRating), l.Title, l.Url, strings.Join(l.Tags, ",")}
+	}
+	return o.table("ID\tSCORE\tYOUR RATING\tTITLE\tURL\tTAGS", rows)
+}
+
+func formatTime(unixNano int64) string {
+	if unixNano == 0 {
+		return "-"
+	}
+	return time.Unix(0, unixNano).Format("2006-01-02 15:04")
+}
+
+func formatUser(u client.User) string {
+	if u.Name == "" {
+		return u.UserId
+	}
+	return u.Name
+}
+
+func formatRating(rating int) string {
+	switch {
+	case rating > 0:
+		return "up"
+	case rating < 0:
+		return "down"
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,263 @@
+// Package admin provides operations for operators of the application, e.g. to inspect boards or repair inconsistent data.
+//
+// Unlike the application services of the boards and links components, the Service accesses the data stores directly
+// and does not perform any authorization, it should only be used by trusted tools like the linkboards-admin command.
+// No events are published, i.e. clients streaming the events of a board will not see the changes and
+// cached roles of users (see package auth/store) are only updated once they expire.
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	boards "github.com/dkinzler/linkboards/internal/boards/domain"
+	links "github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/paging"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+)
+
+// Recorded as the user that modified a board user, e.g. when transferring ownership of a board.
+var AdminUser = boards.User{UserId: "admin", Name: "Administrator"}
+
+// Number of boards and links read per data store query.
+const pageSize = 100
+
+type Service struct {
+	boards boards.BoardAdminDataStore
+	links  links.LinkAdminDataStore
+}
+
+func NewService(boardDataStore boards.BoardAdminDataStore, linkDataStore links.LinkAdminDataStore) *Service {
+	return &Service{
+		boards: boardDataStore,
+		links:  linkDataStore,
+	}
+}
+
+func newError(inner error, code errors.ErrorCode) errors.Error {
+	return errors.New(inner, "admin", code)
+}
+
+// Returns all boards, regardless of visibility, sorted from newest to oldest.
+func (s *Service) Boards(ctx context.Context, qp boards.QueryParams) ([]boards.Board, error) {
+	return s.boards.AllBoards(ctx, qp)
+}
+
+// Returns the boards the user is a member of, sorted by descending time the user joined the board.
+func (s *Service) BoardsForUser(ctx context.Context, userId string, qp boards.QueryParams) ([]boards.Board, error) {
+	return s.boards.BoardsForUser(ctx, userId, qp)
+}
+
+// Returns at most limit boards with the given id or whose name or description contains the query (ignoring case), sorted from newest to oldest.
+// Since data stores cannot search the text of boards, all boards might have to be read.
+func (s *Service) SearchBoards(ctx context.Context, query string, limit int) ([]boards.Board, error) {
+	query = strings.ToLower(query)
+	result := make([]boards.Board, 0)
+	err := paging.Each(ctx, pageSize, s.allBoardsQuery, boardId, func(b boards.Board) bool {
+		if strings.EqualFold(b.BoardId, query) || strings.Contains(strings.ToLower(b.Name), query) || strings.Contains(strings.ToLower(b.Description), query) {
+			result = append(result, b)
+		}
+		return len(result) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Reads all boards from newest to oldest, to be used with paging.Each.
+// The created time of the last board of a page is used as an inclusive cursor, such that boards created at the same time are not skipped.
+func (s *Service) allBoardsQuery(ctx context.Context, last *boards.Board) ([]boards.Board, error) {
+	qp := boards.NewQueryParams().WithLimit(pageSize)
+	if last != nil {
+		qp = qp.WithCursor(last.CreatedTime)
+	}
+	return s.boards.AllBoards(ctx, qp)
+}
+
+func boardId(b boards.Board) string {
+	return b.BoardId
+}
+
+// Returns the board with all its users, invites and join requests.
+func (s *Service) Board(ctx context.Context, boardId string) (boards.BoardWithUsersAndInvites, error) {
+	b, _, err := s.boards.Board(ctx, boardId)
+	return b, err
+}
+
+// Removes a user from a board.
+// The owner of a board cannot be removed, transfer ownership to another user first.
+func (s *Service) RemoveUser(ctx context.Context, boardId string, userId string) error {
+	b, te, err := s.boards.Board(ctx, boardId)
+	if err != nil {
+		return err
+	}
+	user, ok := b.User(userId)
+	if !ok {
+		return newError(nil, errors.NotFound).WithInternalMessage("user is not a member of the board")
+	}
+	if user.Role == auth.BoardRoleOwner {
+		return newError(nil, errors.FailedPrecondition).WithInternalMessage("cannot remove the owner of a board, transfer ownership first")
+	}
+	return s.boards.UpdateBoard(ctx, boardId, boards.NewDatastoreBoardUpdate(te).RemoveUser(userId))
+}
+
+// Makes a member of the board its owner, the previous owner gets the given role.
+// The role must be a built-in role other than owner or one of the custom roles of the board.
+func (s *Service) TransferOwnership(ctx context.Context, boardId string, newOwnerId string, previousOwnerRole string) error {
+	b, te, err := s.boards.Board(ctx, boardId)
+	if err != nil {
+		return err
+	}
+	if previousOwnerRole == auth.BoardRoleOwner {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("previous owner cannot keep the owner role")
+	}
+	if _, ok := b.Board.CustomRole(previousOwnerRole); !ok && !auth.IsBoardRoleValid(previousOwnerRole) {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("invalid role for previous owner: " + previousOwnerRole)
+	}
+
+	newOwner, ok := b.User(newOwnerId)
+	if !ok {
+		return newError(nil, errors.NotFound).WithInternalMessage("new owner is not a member of the board")
+	}
+	if newOwner.Role == auth.BoardRoleOwner {
+		return newError(nil, errors.FailedPrecondition).WithInternalMessage("user already is the owner of the board")
+	}
+
+	now := time.CurrTimeUnixNano()
+	update := boards.NewDatastoreBoardUpdate(te)
+	for _, u := range b.Users {
+		if u.Role == auth.BoardRoleOwner {
+			u.Role = previousOwnerRole
+			u.ModifiedTime = now
+			u.ModifiedBy = AdminUser
+			update.UpdateUser(u)
+		}
+	}
+	newOwner.Role = auth.BoardRoleOwner
+	newOwner.ModifiedTime = now
+	newOwner.ModifiedBy = AdminUser
+	update.UpdateUser(newOwner)
+
+	return s.boards.UpdateBoard(ctx, boardId, update)
+}
+
+// Links of boards that no longer exist.
+type OrphanedLinks struct {
+	// Ids of boards that do not exist but have links.
+	BoardIds []string
+	// Number of links of these boards.
+	Links int
+}
+
+// Finds and deletes all links of boards that no longer exist.
+// If dryRun is true, the links are only counted.
+func (s *Service) DeleteOrphanedLinks(ctx context.Context, dryRun bool) (OrphanedLinks, error) {
+	boardIds, err := s.links.BoardIdsWithLinks(ctx)
+	if err != nil {
+		return OrphanedLinks{}, err
+	}
+
+	result := OrphanedLinks{BoardIds: []string{}}
+	for start := 0; start < len(boardIds); start += pageSize {
+		end := start + pageSize
+		if end > len(boardIds) {
+			end = len(boardIds)
+		}
+		existing, err := s.boards.Boards(ctx, boardIds[start:end])
+		if err != nil {
+			return OrphanedLinks{}, err
+		}
+		exists := make(map[string]bool, len(existing))
+		for _, b := range existing {
+			exists[b.BoardId] = true
+		}
+
+		for _, boardId := range boardIds[start:end] {
+			if exists[boardId] {
+				continue
+			}
+			linkIds, err := s.linkIds(ctx, boardId)
+			if err != nil {
+				return OrphanedLinks{}, err
+			}
+			if !dryRun {
+				for _, linkId := range linkIds {
+					if err := s.links.DeleteLink(ctx, boardId, linkId); err != nil {
+						return OrphanedLinks{}, err
+					}
+				}
+			}
+			result.BoardIds = append(result.BoardIds, boardId)
+			result.Links += len(linkIds)
+		}
+	}
+	return result, nil
+}
+
+// A rating that was inconsistent with the user ratings of the link and has been recomputed.
+type RatingChange struct {
+	BoardId string
+	LinkId  string
+	Old     links.Rating
+	New     links.Rating
+}
+
+// Result of recomputing the ratings of links.
+type RecomputedRatings struct {
+	// Number of links whose rating was recomputed.
+	Links   int
+	Changes []RatingChange
+}
+
+// Recomputes the ratings of all links of the given board from the ratings of the users.
+// If boardId is empty, the ratings of all links are recomputed.
+func (s *Service) RecomputeRatings(ctx context.Context, boardId string) (RecomputedRatings, error) {
+	boardIds := []string{boardId}
+	if boardId == "" {
+		var err error
+		boardIds, err = s.links.BoardIdsWithLinks(ctx)
+		if err != nil {
+			return RecomputedRatings{}, err
+		}
+	}
+
+	result := RecomputedRatings{Changes: []RatingChange{}}
+	for _, boardId := range boardIds {
+		linkIds, err := s.linkIds(ctx, boardId)
+		if err != nil {
+			return RecomputedRatings{}, err
+		}
+		for _, linkId := range linkIds {
+			old, new, err := s.links.RecomputeRating(ctx, boardId, linkId)
+			if err != nil {
+				if errors.IsNotFoundError(err) {
+					// link was deleted in the meantime
+					continue
+				}
+				return RecomputedRatings{}, err
+			}
+			result.Links++
+			if old != new {
+				result.Changes = append(result.Changes, RatingChange{BoardId: boardId, LinkId: linkId, Old: old, New: new})
+			}
+		}
+	}
+	return result, nil
+}
+
+// Returns the ids of all links of the board.
+func (s *Service) linkIds(ctx context.Context, boardId string) ([]string, error) {
+	result := make([]string, 0)
+	err := paging.Each(ctx, pageSize, links.AllLinksQuery(s.links, boardId, links.LinkReturnFields{}, pageSize), links.LinkWithRatingId, func(l links.LinkWithRating) bool {
+		result = append(result, l.Link.LinkId)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,212 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	boardsinmem "github.com/dkinzler/linkboards/internal/boards/datastore/inmem"
+	boards "github.com/dkinzler/linkboards/internal/boards/domain"
+	linksinmem "github.com/dkinzler/linkboards/internal/links/datastore/inmem"
+	links "github.com/dkinzler/linkboards/internal/links/domain"
+
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(t *testing.T) (*Service, boards.BoardAdminDataStore, links.LinkAdminDataStore) {
+	bds := boardsinmem.NewInmemBoardDataStore()
+	lds := linksinmem.NewInmemLinkDataStore()
+	return NewService(bds, lds), bds, lds
+}
+
+func createBoard(t *testing.T, ds boards.BoardDataStore, board boards.Board, users ...boards.BoardUser) {
+	update := boards.NewDatastoreBoardUpdate(nil).WithBoard(board)
+	for _, u := range users {
+		update.UpdateUser(u)
+	}
+	if err := ds.UpdateBoard(context.Background(), board.BoardId, update); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchBoards(t *testing.T) {
+	a := assert.New(t)
+	s, bds, _ := newTestService(t)
+	ctx := context.Background()
+
+	for i, name := range []string{"Go links", "Recipes", "golang talks", "Music"} {
+		createBoard(t, bds, boards.Board{BoardId: "b-" + name, Name: name, CreatedTime: int64(i + 1)})
+	}
+	createBoard(t, bds, boards.Board{BoardId: "b-other", Name: "Other", Description: "Everything about GO", CreatedTime: 10})
+
+	result, err := s.SearchBoards(ctx, "go", 10)
+	a.Nil(err)
+	a.Len(result, 3)
+	// newest first
+	a.Equal("b-other", result[0].BoardId)
+	a.Equal("b-golang talks", result[1].BoardId)
+	a.Equal("b-Go links", result[2].BoardId)
+
+	result, err = s.SearchBoards(ctx, "go", 1)
+	a.Nil(err)
+	a.Len(result, 1)
+
+	result, err = s.SearchBoards(ctx, "b-music", 10)
+	a.Nil(err)
+	a.Len(result, 1)
+
+	result, err = s.SearchBoards(ctx, "none", 10)
+	a.Nil(err)
+	a.Empty(result)
+}
+
+func TestSearchBoardsWithSameCreatedTime(t *testing.T) {
+	a := assert.New(t)
+	s, bds, _ := newTestService(t)
+
+	// boards created at the same time are not skipped, even if they are split across pages
+	for i := 0; i < 2*pageSize+1; i++ {
+		createBoard(t, bds, boards.Board{BoardId: fmt.Sprintf("b-%v", i), Name: "Board", CreatedTime: int64(i/2 + 1)})
+	}
+	result, err := s.SearchBoards(context.Background(), "board", 1000)
+	a.Nil(err)
+	a.Equal(2*pageSize+1, len(result))
+}
+
+func TestRemoveUserAndTransferOwnership(t *testing.T) {
+	a := assert.New(t)
+	s, bds, _ := newTestService(t)
+	ctx := context.Background()
+
+	owner := boards.BoardUser{User: boards.User{UserId: "u-1"}, Role: auth.BoardRoleOwner}
+	editor := boards.BoardUser{User: boards.User{UserId: "u-2"}, Role: auth.BoardRoleEditor}
+	viewer := boards.BoardUser{User: boards.User{UserId: "u-3"}, Role: auth.BoardRoleViewer}
+	createBoard(t, bds, boards.Board{BoardId: "b-1"}, owner, editor, viewer)
+
+	err := s.RemoveUser(ctx, "b-1", "u-4")
+	a.True(errors.IsNotFoundError(err))
+	err = s.RemoveUser(ctx, "b-2", "u-3")
+	a.True(errors.IsNotFoundError(err))
+	// the owner cannot be removed
+	err = s.RemoveUser(ctx, "b-1", "u-1")
+	a.True(errors.IsFailedPreconditionError(err))
+
+	a.Nil(s.RemoveUser(ctx, "b-1", "u-3"))
+	b, err := s.Board(ctx, "b-1")
+	a.Nil(err)
+	a.False(b.ContainsUser("u-3"))
+	a.Equal(2, b.UserCount())
+
+	// invalid roles for the previous owner
+	err = s.TransferOwnership(ctx, "b-1", "u-2", auth.BoardRoleOwner)
+	a.True(errors.IsInvalidArgumentError(err))
+	err = s.TransferOwnership(ctx, "b-1", "u-2", "moderator")
+	a.True(errors.IsInvalidArgumentError(err))
+	// new owner must be a member
+	err = s.TransferOwnership(ctx, "b-1", "u-3", auth.BoardRoleEditor)
+	a.True(errors.IsNotFoundError(err))
+	err = s.TransferOwnership(ctx, "b-1", "u-1", auth.BoardRoleEditor)
+	a.True(errors.IsFailedPreconditionError(err))
+
+	a.Nil(s.TransferOwnership(ctx, "b-1", "u-2", auth.BoardRoleViewer))
+	b, err = s.Board(ctx, "b-1")
+	a.Nil(err)
+	u, _ := b.User("u-1")
+	a.Equal(auth.BoardRoleViewer, u.Role)
+	a.Equal(AdminUser, u.ModifiedBy)
+	u, _ = b.User("u-2")
+	a.Equal(auth.BoardRoleOwner, u.Role)
+	a.NotZero(u.ModifiedTime)
+
+	// previous owner can now be removed
+	a.Nil(s.RemoveUser(ctx, "b-1", "u-1"))
+}
+
+func TestDeleteOrphanedLinks(t *testing.T) {
+	a := assert.New(t)
+	s, bds, lds := newTestService(t)
+	ctx := context.Background()
+
+	createBoard(t, bds, boards.Board{BoardId: "b-1"})
+	for i, l := range []links.Link{
+		{BoardId: "b-1", LinkId: "l-1"},
+		{BoardId: "b-2", LinkId: "l-2"},
+		{BoardId: "b-2", LinkId: "l-3"},
+		{BoardId: "b-3", LinkId: "l-4"},
+	} {
+		l.CreatedTime = int64(i + 1)
+		a.Nil(lds.CreateLink(ctx, l.BoardId, l))
+	}
+
+	result, err := s.DeleteOrphanedLinks(ctx, true)
+	a.Nil(err)
+	a.Equal(OrphanedLinks{BoardIds: []string{"b-2", "b-3"}, Links: 3}, result)
+	boardIds, _ := lds.BoardIdsWithLinks(ctx)
+	a.Len(boardIds, 3)
+
+	result, err = s.DeleteOrphanedLinks(ctx, false)
+	a.Nil(err)
+	a.Equal(OrphanedLinks{BoardIds: []string{"b-2", "b-3"}, Links: 3}, result)
+	boardIds, _ = lds.BoardIdsWithLinks(ctx)
+	a.Equal([]string{"b-1"}, boardIds)
+
+	result, err = s.DeleteOrphanedLinks(ctx, false)
+	a.Nil(err)
+	a.Empty(result.BoardIds)
+}
+
+// Simulates a link whose rating was inconsistent with its user ratings before it was recomputed.
+type inconsistentRatingDataStore struct {
+	links.LinkAdminDataStore
+	linkId string
+}
+
+func (d inconsistentRatingDataStore) RecomputeRating(ctx context.Context, boardId string, linkId string) (links.Rating, links.Rating, error) {
+	old, new, err := d.LinkAdminDataStore.RecomputeRating(ctx, boardId, linkId)
+	if linkId == d.linkId {
+		old.Upvotes += 2
+		old.Score += 2
+	}
+	return old, new, err
+}
+
+func TestRecomputeRatings(t *testing.T) {
+	a := assert.New(t)
+	_, bds, lds := newTestService(t)
+	s := NewService(bds, inconsistentRatingDataStore{LinkAdminDataStore: lds, linkId: "l-2"})
+	ctx := context.Background()
+
+	for i, l := range []links.Link{
+		{BoardId: "b-1", LinkId: "l-1"},
+		{BoardId: "b-1", LinkId: "l-2"},
+		{BoardId: "b-2", LinkId: "l-3"},
+	} {
+		l.CreatedTime = int64(i + 1)
+		a.Nil(lds.CreateLink(ctx, l.BoardId, l))
+	}
+	a.Nil(lds.UpdateRating(ctx, "b-1", "l-2", links.UserLinkRating{UserId: "u-1", Rating: -1}))
+
+	result, err := s.RecomputeRatings(ctx, "b-2")
+	a.Nil(err)
+	a.Equal(1, result.Links)
+	a.Empty(result.Changes)
+
+	result, err = s.RecomputeRatings(ctx, "b-1")
+	a.Nil(err)
+	a.Equal(2, result.Links)
+	a.Equal([]RatingChange{{
+		BoardId: "b-1",
+		LinkId:  "l-2",
+		Old:     links.Rating{Score: 1, Upvotes: 2, Downvotes: -1},
+		New:     links.Rating{Score: -1, Downvotes: -1},
+	}}, result.Changes)
+
+	// all boards
+	result, err = s.RecomputeRatings(ctx, "")
+	a.Nil(err)
+	a.Equal(3, result.Links)
+	a.Len(result.Changes, 1)
+}
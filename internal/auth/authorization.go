@@ -2,6 +2,9 @@ package auth
 
 import (
 	"context"
+	"sync"
+
+	"github.com/dkinzler/kit/errors"
 )
 
 // Scopes are the most fine-grained units of permission and are defined
@@ -18,6 +21,9 @@ const (
 	BoardRoleOwner  = "owner"
 	BoardRoleEditor = "editor"
 	BoardRoleViewer = "viewer"
+	// Not a role users can have, components use it to define the scopes of users that are not members of a board,
+	// but can view it because of the board's visibility settings, see BoardVisibilityStore.
+	BoardRoleVisitor = "visitor"
 )
 
 var BoardRoles = []string{BoardRoleOwner, BoardRoleEditor, BoardRoleViewer}
@@ -32,6 +38,12 @@ func IsBoardRoleValid(role string) bool {
 	return false
 }
 
+// Returns true if the given name cannot be used for a custom role,
+// i.e. it is the name of a built-in board role or BoardRoleVisitor.
+func IsRoleNameReserved(name string) bool {
+	return IsBoardRoleValid(name) || name == BoardRoleVisitor
+}
+
 // Authorization represents the set of scopes a user has access to.
 type Authorization map[Scope]struct{}
 
@@ -45,7 +57,25 @@ type AuthorizationStore interface {
 	Roles(ctx context.Context, boardId string, userId string) ([]string, error)
 }
 
+// CustomRoleStore can optionally be implemented by an AuthorizationStore,
+// if the owner of a board can define custom roles in addition to the built-in board roles.
+type CustomRoleStore interface {
+	// Returns the scopes of the custom roles defined for the board, keyed by role name.
+	CustomRoles(ctx context.Context, boardId string) (map[string][]Scope, error)
+}
+
+// BoardVisibilityStore can optionally be implemented by an AuthorizationStore,
+// if boards can be made visible to users that are not members.
+type BoardVisibilityStore interface {
+	// Returns true if users that are not members of the board, including unauthenticated users, can view the board.
+	IsBoardVisible(ctx context.Context, boardId string) (bool, error)
+}
+
 // BoardAuthorizationChecker can be used to obtain the set of scopes a user has access to for a given board.
+// If the context contains restrictions, only the scopes allowed by them are returned.
+//
+// Users that are not members of a board, i.e. the store returns an error with code PermissionDenied, and unauthenticated users,
+// i.e. userId is empty, get the scopes of the BoardRoleVisitor role if the board is visible to non-members.
 type BoardAuthorizationChecker struct {
 	roleToScopes map[string][]Scope
 	store        AuthorizationStore
@@ -59,19 +89,191 @@ func NewAuthorizationChecker(roleToScopes map[string][]Scope, store Authorizatio
 }
 
 func (ac *BoardAuthorizationChecker) GetAuthorization(ctx context.Context, boardId string, userId string) (Authorization, error) {
-	roles, err := ac.store.Roles(ctx, boardId, userId)
-	if err != nil {
-		return Authorization{}, err
+	var roles []string
+	var err error
+	isVisitor := false
+	if userId != "" {
+		roles, err = ac.store.Roles(ctx, boardId, userId)
+		if err != nil && !errors.IsPermissionDeniedError(err) {
+			return Authorization{}, err
+		}
+	}
+	if userId == "" || err != nil {
+		visible, verr := ac.isBoardVisible(ctx, boardId)
+		if verr != nil {
+			return Authorization{}, verr
+		}
+		if !visible {
+			if err == nil {
+				err = errors.New(nil, "BoardAuthorizationChecker", errors.PermissionDenied)
+			}
+			return Authorization{}, err
+		}
+		roles = []string{BoardRoleVisitor}
+		isVisitor = true
 	}
 
 	scopes := make(map[Scope]struct{})
+	var customRoles map[string][]Scope
 	for _, role := range roles {
 		scopesForRole, ok := ac.roleToScopes[role]
-		if ok {
+		// The scopes of the visitor role are only granted to non-members.
+		// A member with a role of that name has a custom role that was created before the name was reserved.
+		if ok && (role != BoardRoleVisitor || isVisitor) {
 			for _, scope := range scopesForRole {
 				scopes[scope] = struct{}{}
 			}
+			continue
+		}
+
+		// Not a built-in role, custom roles are only loaded when needed.
+		crs, ok := ac.store.(CustomRoleStore)
+		if !ok {
+			continue
+		}
+		if customRoles == nil {
+			customRoles, err = crs.CustomRoles(ctx, boardId)
+			if err != nil {
+				return Authorization{}, err
+			}
+		}
+		for _, scope := range customRoles[role] {
+			// Custom roles are validated when they are created, but make sure they can never grant owner-only scopes.
+			if !IsOwnerOnlyScope(scope) {
+				scopes[scope] = struct{}{}
+			}
+		}
+	}
+	return RestrictAuthorization(ctx, boardId, scopes), nil
+}
+
+// Returns true if the user has a role for the board.
+// Unlike GetAuthorization, this does not consider the visibility of the board, i.e. visitors are not members.
+func (ac *BoardAuthorizationChecker) IsMember(ctx context.Context, boardId string, userId string) (bool, error) {
+	if userId == "" {
+		return false, nil
+	}
+	roles, err := ac.store.Roles(ctx, boardId, userId)
+	if err != nil {
+		if errors.IsPermissionDeniedError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(roles) > 0, nil
+}
+
+func (ac *BoardAuthorizationChecker) isBoardVisible(ctx context.Context, boardId string) (bool, error) {
+	vs, ok := ac.store.(BoardVisibilityStore)
+	if !ok {
+		return false, nil
+	}
+	return vs.IsBoardVisible(ctx, boardId)
+}
+
+// Restrictions limit the authorization of a request,
+// e.g. if it was authenticated using an access token that can only be used for some boards.
+type Restrictions struct {
+	// If not empty, the request is only authorized for these boards.
+	BoardIds []string
+	// If not empty, the request is only authorized for these scopes.
+	Scopes []Scope
+}
+
+const restrictionsContextKey contextKey = "restrictions"
+
+func RestrictionsFromContext(ctx context.Context) (Restrictions, bool) {
+	r, ok := ctx.Value(restrictionsContextKey).(Restrictions)
+	return r, ok
+}
+
+// Used to add restrictions to the context, that will be applied by BoardAuthorizationChecker and RestrictAuthorization.
+func ContextWithRestrictions(ctx context.Context, r Restrictions) context.Context {
+	return context.WithValue(ctx, restrictionsContextKey, r)
+}
+
+// Returns the scopes of the given authorization that are allowed by the restrictions in the context.
+// If the context contains no restrictions, the authorization is returned unchanged.
+// Board restrictions are ignored if boardId is empty, i.e. for scopes that do not refer to a board.
+func RestrictAuthorization(ctx context.Context, boardId string, az Authorization) Authorization {
+	r, ok := RestrictionsFromContext(ctx)
+	if !ok {
+		return az
+	}
+
+	if boardId != "" && len(r.BoardIds) > 0 {
+		allowed := false
+		for _, id := range r.BoardIds {
+			if id == boardId {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return Authorization{}
+		}
+	}
+
+	if len(r.Scopes) == 0 {
+		return az
+	}
+	result := make(Authorization)
+	for _, scope := range r.Scopes {
+		if az.HasScope(scope) {
+			result[scope] = struct{}{}
+		}
+	}
+	return result
+}
+
+var registeredScopes = struct {
+	sync.RWMutex
+	scopes    map[Scope]struct{}
+	ownerOnly map[Scope]struct{}
+}{scopes: make(map[Scope]struct{}), ownerOnly: make(map[Scope]struct{})}
+
+// Components register the scopes they define, such that scopes provided by users,
+// e.g. when creating an access token, can be validated.
+func RegisterScopes(scopes ...Scope) {
+	registeredScopes.Lock()
+	defer registeredScopes.Unlock()
+	for _, scope := range scopes {
+		registeredScopes.scopes[scope] = struct{}{}
+	}
+}
+
+func IsScopeRegistered(scope Scope) bool {
+	registeredScopes.RLock()
+	defer registeredScopes.RUnlock()
+	_, ok := registeredScopes.scopes[scope]
+	return ok
+}
+
+// Components register the scopes of their built-in roles.
+// Scopes that only the owner role has, e.g. deleting a board, are owner-only and cannot be granted by custom roles.
+func RegisterRoles(roleToScopes map[string][]Scope) {
+	others := make(map[Scope]struct{})
+	for role, scopes := range roleToScopes {
+		if role == BoardRoleOwner {
+			continue
+		}
+		for _, scope := range scopes {
+			others[scope] = struct{}{}
+		}
+	}
+
+	registeredScopes.Lock()
+	defer registeredScopes.Unlock()
+	for _, scope := range roleToScopes[BoardRoleOwner] {
+		if _, ok := others[scope]; !ok {
+			registeredScopes.ownerOnly[scope] = struct{}{}
 		}
 	}
-	return scopes, nil
+}
+
+func IsOwnerOnlyScope(scope Scope) bool {
+	registeredScopes.RLock()
+	defer registeredScopes.RUnlock()
+	_, ok := registeredScopes.ownerOnly[scope]
+	return ok
 }
@@ -79,3 +79,183 @@ func TestBoardAuthorizationChecker(t *testing.T) {
 	a.True(az.HasScope("viewerScope1"))
 	a.True(az.HasScope("viewerScope2"))
 }
+
+func TestRestrictAuthorization(t *testing.T) {
+	a := assert.New(t)
+
+	az := Authorization{"scope1": {}, "scope2": {}, "scope3": {}}
+
+	// without restrictions the authorization is unchanged
+	a.Equal(az, RestrictAuthorization(context.Background(), "b-123", az))
+
+	ctx := ContextWithRestrictions(context.Background(), Restrictions{
+		BoardIds: []string{"b-123"},
+		Scopes:   []Scope{"scope1", "scope3", "scope4"},
+	})
+	a.Equal(Authorization{"scope1": {}, "scope3": {}}, RestrictAuthorization(ctx, "b-123", az))
+	a.Empty(RestrictAuthorization(ctx, "b-456", az))
+	// board restrictions don't apply to scopes that do not refer to a board
+	a.Equal(Authorization{"scope1": {}, "scope3": {}}, RestrictAuthorization(ctx, "", az))
+
+	// restrictions are applied by BoardAuthorizationChecker
+	checker := NewAuthorizationChecker(map[string][]Scope{
+		BoardRoleOwner: {"scope1", "scope2"},
+	}, &testAuthorizationStore{})
+	result, err := checker.GetAuthorization(ctx, "b-123", testUser1.UserId)
+	a.Nil(err)
+	a.Equal(Authorization{"scope1": {}}, result)
+	result, err = checker.GetAuthorization(ctx, "b-456", testUser1.UserId)
+	a.Nil(err)
+	a.Empty(result)
+}
+
+func TestRegisterScopes(t *testing.T) {
+	a := assert.New(t)
+
+	a.False(IsScopeRegistered("test:scope1"))
+	RegisterScopes("test:scope1", "test:scope2")
+	a.True(IsScopeRegistered("test:scope1"))
+	a.True(IsScopeRegistered("test:scope2"))
+	a.False(IsScopeRegistered("test:scope3"))
+}
+
+type testCustomRoleStore struct {
+	testAuthorizationStore
+	roles       map[string][]string
+	customRoles map[string][]Scope
+	calls       int
+}
+
+func (t *testCustomRoleStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	if roles, ok := t.roles[userId]; ok {
+		return roles, nil
+	}
+	return t.testAuthorizationStore.Roles(ctx, boardId, userId)
+}
+
+func (t *testCustomRoleStore) CustomRoles(ctx context.Context, boardId string) (map[string][]Scope, error) {
+	t.calls++
+	return t.customRoles, nil
+}
+
+func TestCustomRoles(t *testing.T) {
+	a := assert.New(t)
+
+	rolesToScope := map[string][]Scope{
+		BoardRoleOwner:   {"test:delete", "test:edit", "test:view"},
+		BoardRoleEditor:  {"test:edit", "test:view"},
+		BoardRoleViewer:  {"test:view"},
+		BoardRoleVisitor: {"test:view"},
+	}
+	RegisterRoles(rolesToScope)
+	a.True(IsOwnerOnlyScope("test:delete"))
+	a.False(IsOwnerOnlyScope("test:edit"))
+	a.False(IsOwnerOnlyScope("test:view"))
+
+	store := &testCustomRoleStore{
+		roles: map[string][]string{
+			"u-curator": {"curator"},
+			"u-unknown": {"notdefined"},
+			"u-visitor": {BoardRoleVisitor},
+		},
+		customRoles: map[string][]Scope{
+			"curator":        {"test:edit", "test:delete", "test:other"},
+			BoardRoleVisitor: {"test:other"},
+		},
+	}
+	checker := NewAuthorizationChecker(rolesToScope, store)
+	ctx := context.Background()
+
+	// custom roles are only loaded for roles that are not built-in
+	az, err := checker.GetAuthorization(ctx, "b-123", testUser1.UserId)
+	a.Nil(err)
+	a.True(az.HasScope("test:delete"))
+	a.Equal(0, store.calls)
+
+	// owner-only scopes are never granted by custom roles
+	az, err = checker.GetAuthorization(ctx, "b-123", "u-curator")
+	a.Nil(err)
+	a.Equal(Authorization{"test:edit": {}, "test:other": {}}, az)
+	a.Equal(1, store.calls)
+
+	az, err = checker.GetAuthorization(ctx, "b-123", "u-unknown")
+	a.Nil(err)
+	a.Empty(az)
+
+	// the name of the visitor role is reserved, but members never get the scopes of visitors
+	a.True(IsRoleNameReserved(BoardRoleVisitor))
+	az, err = checker.GetAuthorization(ctx, "b-123", "u-visitor")
+	a.Nil(err)
+	a.Equal(Authorization{"test:other": {}}, az)
+}
+
+type testVisibilityStore struct {
+	testAuthorizationStore
+	visible map[string]bool
+}
+
+func (t *testVisibilityStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	for _, user := range testUsers {
+		if user.UserId == userId {
+			return user.Roles, nil
+		}
+	}
+	return nil, errors.New(nil, "test", errors.PermissionDenied)
+}
+
+func (t *testVisibilityStore) IsBoardVisible(ctx context.Context, boardId string) (bool, error) {
+	return t.visible[boardId], nil
+}
+
+func TestBoardVisibility(t *testing.T) {
+	a := assert.New(t)
+
+	rolesToScope := map[string][]Scope{
+		BoardRoleOwner:   {"test:edit", "test:view"},
+		BoardRoleVisitor: {"test:view"},
+	}
+	checker := NewAuthorizationChecker(rolesToScope, &testVisibilityStore{visible: map[string]bool{"b-visible": true}})
+	ctx := context.Background()
+
+	// members get the scopes of their roles
+	az, err := checker.GetAuthorization(ctx, "b-visible", testUser1.UserId)
+	a.Nil(err)
+	a.Equal(Authorization{"test:edit": {}, "test:view": {}}, az)
+
+	// non-members and unauthenticated users get the scopes of the visitor role, if the board is visible
+	az, err = checker.GetAuthorization(ctx, "b-visible", "usernotonboard")
+	a.Nil(err)
+	a.Equal(Authorization{"test:view": {}}, az)
+	az, err = checker.GetAuthorization(ctx, "b-visible", "")
+	a.Nil(err)
+	a.Equal(Authorization{"test:view": {}}, az)
+
+	_, err = checker.GetAuthorization(ctx, "b-private", "usernotonboard")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	_, err = checker.GetAuthorization(ctx, "b-private", "")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	// restrictions still apply
+	restrictedCtx := ContextWithRestrictions(ctx, Restrictions{BoardIds: []string{"b-other"}})
+	az, err = checker.GetAuthorization(restrictedCtx, "b-visible", "usernotonboard")
+	a.Nil(err)
+	a.Empty(az)
+
+	// visitors are not members
+	member, err := checker.IsMember(ctx, "b-visible", testUser1.UserId)
+	a.Nil(err)
+	a.True(member)
+	member, err = checker.IsMember(ctx, "b-visible", "usernotonboard")
+	a.Nil(err)
+	a.False(member)
+	member, err = checker.IsMember(ctx, "b-visible", "")
+	a.Nil(err)
+	a.False(member)
+
+	// stores that don't implement BoardVisibilityStore never grant access to non-members
+	checker = NewAuthorizationChecker(rolesToScope, &testAuthorizationStore{})
+	_, err = checker.GetAuthorization(ctx, "b-visible", "")
+	a.NotNil(err)
+}
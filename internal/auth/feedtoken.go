@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+const feedTokenPrefix = "lbf_"
+
+// FeedTokens creates and verifies feed tokens.
+// Feed readers cannot obtain and send the JWTs used to authenticate regular requests,
+// instead a feed URL contains a long-lived token that identifies the user.
+//
+// A token consists of the user id and a HMAC of the user id and the current feed token version of the user,
+// it is therefore not necessary to store tokens.
+// Incrementing the version of a user, e.g. because a feed URL was leaked, invalidates the previous token of the user, see Revoke.
+// All tokens can be invalidated by changing the secret.
+type FeedTokens struct {
+	secret   []byte
+	versions FeedTokenVersionStore
+}
+
+// FeedTokenVersionStore stores the feed token versions of users.
+// Implementations must be safe for concurrent use.
+type FeedTokenVersionStore interface {
+	// Returns 0 if the version of the user was never incremented.
+	FeedTokenVersion(ctx context.Context, userId string) (int64, error)
+	IncrementFeedTokenVersion(ctx context.Context, userId string) error
+}
+
+// The FeedTokenVersionStore passed must not be nil.
+func NewFeedTokens(secret []byte, versions FeedTokenVersionStore) *FeedTokens {
+	return &FeedTokens{secret: secret, versions: versions}
+}
+
+func (f *FeedTokens) mac(userId string, version int64) []byte {
+	h := hmac.New(sha256.New, f.secret)
+	h.Write([]byte(userId))
+	// the version only consists of digits, separating it with a null byte makes the input unambiguous
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(version, 10)))
+	return h.Sum(nil)
+}
+
+// Returns the current feed token of the given user.
+func (f *FeedTokens) Token(ctx context.Context, userId string) (string, error) {
+	version, err := f.versions.FeedTokenVersion(ctx, userId)
+	if err != nil {
+		return "", errors.New(err, "FeedTokens", errors.Internal).WithInternalMessage("could not get feed token version")
+	}
+	encodedUserId := base64.RawURLEncoding.EncodeToString([]byte(userId))
+	encodedMac := base64.RawURLEncoding.EncodeToString(f.mac(userId, version))
+	return feedTokenPrefix + encodedUserId + "." + encodedMac, nil
+}
+
+// Invalidates the current feed token of the given user, use Token to obtain the new one.
+func (f *FeedTokens) Revoke(ctx context.Context, userId string) error {
+	err := f.versions.IncrementFeedTokenVersion(ctx, userId)
+	if err != nil {
+		return errors.New(err, "FeedTokens", errors.Internal).WithInternalMessage("could not increment feed token version")
+	}
+	return nil
+}
+
+// Returns the id of the user the token belongs to.
+// An error with code Unauthenticated is returned if the token is invalid.
+func (f *FeedTokens) UserId(ctx context.Context, token string) (string, error) {
+	invalidTokenError := errors.New(nil, "FeedTokens", errors.Unauthenticated).WithPublicMessage("invalid feed token")
+
+	if !strings.HasPrefix(token, feedTokenPrefix) {
+		return "", invalidTokenError
+	}
+
+	encodedUserId, encodedMac, ok := strings.Cut(token[len(feedTokenPrefix):], ".")
+	if !ok {
+		return "", invalidTokenError
+	}
+
+	userId, err := base64.RawURLEncoding.DecodeString(encodedUserId)
+	if err != nil || len(userId) == 0 {
+		return "", invalidTokenError
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil {
+		return "", invalidTokenError
+	}
+
+	version, err := f.versions.FeedTokenVersion(ctx, string(userId))
+	if err != nil {
+		return "", errors.New(err, "FeedTokens", errors.Internal).WithInternalMessage("could not get feed token version")
+	}
+
+	if !hmac.Equal(mac, f.mac(string(userId), version)) {
+		return "", invalidTokenError
+	}
+
+	return string(userId), nil
+}
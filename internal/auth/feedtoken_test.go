@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testFeedTokenVersionStore struct {
+	versions map[string]int64
+}
+
+func (s *testFeedTokenVersionStore) FeedTokenVersion(ctx context.Context, userId string) (int64, error) {
+	return s.versions[userId], nil
+}
+
+func (s *testFeedTokenVersionStore) IncrementFeedTokenVersion(ctx context.Context, userId string) error {
+	s.versions[userId]++
+	return nil
+}
+
+func TestFeedTokens(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	versions := &testFeedTokenVersionStore{versions: map[string]int64{}}
+	ft := NewFeedTokens([]byte("secret"), versions)
+
+	token, err := ft.Token(ctx, "u-123")
+	a.Nil(err)
+	a.NotEmpty(token)
+	userId, err := ft.UserId(ctx, token)
+	a.Nil(err)
+	a.Equal("u-123", userId)
+
+	// tokens are different for different users
+	other, _ := ft.Token(ctx, "u-456")
+	a.NotEqual(token, other)
+
+	// tokens created with a different secret are not valid
+	_, err = NewFeedTokens([]byte("other secret"), versions).UserId(ctx, token)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// tampered or malformed tokens are not valid
+	invalidTokens := []string{
+		"",
+		"abc",
+		"lbf_",
+		"lbf_.",
+		token[:len(token)-2],
+		token[:17] + other[17:],
+		"lbf_" + other[4:len(other)-43] + token[len(token)-43:],
+	}
+	for _, it := range invalidTokens {
+		_, err = ft.UserId(ctx, it)
+		a.NotNil(err, it)
+		a.True(errors.IsUnauthenticatedError(err))
+	}
+
+	// a revoked token is no longer valid, while the tokens of other users are not affected
+	a.Nil(ft.Revoke(ctx, "u-123"))
+	_, err = ft.UserId(ctx, token)
+	a.True(errors.IsUnauthenticatedError(err))
+	newToken, err := ft.Token(ctx, "u-123")
+	a.Nil(err)
+	a.NotEqual(token, newToken)
+	userId, err = ft.UserId(ctx, newToken)
+	a.Nil(err)
+	a.Equal("u-123", userId)
+	_, err = ft.UserId(ctx, other)
+	a.Nil(err)
+}
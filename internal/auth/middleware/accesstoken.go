@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/tokens"
+
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/transport/http"
+)
+
+// Authenticates requests that contain a personal access token, i.e. an Authorization header of the form "Bearer lbp_...".
+// Requests with any other or no credentials are passed to the next authentication middleware, e.g. the one for JWTs.
+//
+// The access token is read from the context, where it is stored by either the "HTTPToContext" RequestFunc from the go-kit/kit/auth/jwt package
+// or the "PopulateRequestContext" RequestFunc from the go-kit/kit/transport/http package,
+// i.e. it can be combined with all other authentication middlewares in this package.
+// The restrictions of the token are added to the context, see auth.Restrictions.
+func NewAccessTokenEndpointMiddleware(accessTokens *tokens.Service, next endpoint.Middleware) endpoint.Middleware {
+	return func(e endpoint.Endpoint) endpoint.Endpoint {
+		nextEndpoint := next(e)
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			token, ok := accessTokenFromContext(ctx)
+			if !ok {
+				return nextEndpoint(ctx, request)
+			}
+
+			at, err := accessTokens.Authenticate(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+
+			newCtx := auth.ContextWithUser(ctx, auth.User(at.User))
+			newCtx = auth.ContextWithRestrictions(newCtx, at.Restrictions())
+			return e(newCtx, request)
+		}
+	}
+}
+
+func accessTokenFromContext(ctx context.Context) (string, bool) {
+	if token, ok := ctx.Value(kitjwt.JWTContextKey).(string); ok && tokens.IsAccessToken(token) {
+		return token, true
+	}
+	if header, ok := ctx.Value(http.ContextKeyRequestAuthorization).(string); ok {
+		prefix := "Bearer "
+		if strings.HasPrefix(header, prefix) && tokens.IsAccessToken(header[len(prefix):]) {
+			return header[len(prefix):], true
+		}
+	}
+	return "", false
+}
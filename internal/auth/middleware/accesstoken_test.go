@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/tokens"
+
+	"github.com/dkinzler/kit/errors"
+
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTokenEndpointMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	accessTokens := tokens.NewService(tokens.NewInmemStore())
+	user := auth.User{UserId: "u-123", Name: "Peter"}
+	created, err := accessTokens.CreateToken(auth.ContextWithUser(context.Background(), user), tokens.NewToken{
+		Name:     "ci",
+		BoardIds: []string{"b-123"},
+	})
+	a.Nil(err)
+
+	mw := NewAccessTokenEndpointMiddleware(accessTokens, NewFakeAuthEndpointMiddleware())
+
+	type result struct {
+		user         auth.User
+		restrictions auth.Restrictions
+		restricted   bool
+	}
+	e := func(ctx context.Context, request interface{}) (interface{}, error) {
+		var r result
+		r.user, _ = auth.UserFromContext(ctx)
+		r.restrictions, r.restricted = auth.RestrictionsFromContext(ctx)
+		return r, nil
+	}
+
+	// token from the Authorization header
+	ctx := context.WithValue(context.Background(), http.ContextKeyRequestAuthorization, "Bearer "+created.Token)
+	r, err := mw(e)(ctx, nil)
+	a.Nil(err)
+	a.Equal(result{user: user, restrictions: auth.Restrictions{BoardIds: []string{"b-123"}}, restricted: true}, r)
+
+	// token stored by the jwt RequestFunc
+	ctx = context.WithValue(context.Background(), kitjwt.JWTContextKey, created.Token)
+	r, err = mw(e)(ctx, nil)
+	a.Nil(err)
+	a.Equal(user, r.(result).user)
+
+	// invalid access tokens are not passed on to the next middleware
+	ctx = context.WithValue(context.Background(), http.ContextKeyRequestAuthorization, "Bearer lbp_invalid")
+	_, err = mw(e)(ctx, nil)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// other credentials are handled by the next middleware
+	v := base64.StdEncoding.EncodeToString([]byte("u-456:somepw"))
+	ctx = context.WithValue(context.Background(), http.ContextKeyRequestAuthorization, "Basic "+v)
+	r, err = mw(e)(ctx, nil)
+	a.Nil(err)
+	a.Equal(result{user: auth.User{UserId: "u-456"}}, r)
+
+	_, err = mw(e)(context.Background(), nil)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+}
@@ -0,0 +1,420 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	stdhttp "net/http"
+	"sync"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultJWTClockSkew = 30 * stdtime.Second
+const defaultJWKSRefreshInterval = stdtime.Hour
+const defaultJWKSMinRefreshInterval = stdtime.Minute
+const defaultJWKSFetchTimeout = 10 * stdtime.Second
+
+type JWTConfig struct {
+	// Exactly one of HMACSecret, RSAPublicKey and JWKSURL must be set.
+	// HMACSecret verifies HS256 tokens, RSAPublicKey verifies RS256 tokens.
+	HMACSecret   []byte
+	RSAPublicKey *rsa.PublicKey
+	// URL of a JSON Web Key Set, containing RSA and/or symmetric keys.
+	// Keys are selected using the "kid" header of a token.
+	JWKSURL string
+	// Time after which the key set is fetched again, defaults to 1h.
+	JWKSRefreshInterval stdtime.Duration
+	// If a token references an unknown key, the key set is fetched again, e.g. because the keys were rotated.
+	// To prevent clients from triggering a request for every token, the key set is fetched at most once during this interval, defaults to 1m.
+	JWKSMinRefreshInterval stdtime.Duration
+	// Client used to fetch the key set, defaults to a client with a timeout of 10s.
+	// Fetches are canceled after 10s regardless of the client.
+	HTTPClient *stdhttp.Client
+
+	// If not empty, the "iss" claim of tokens must match.
+	Issuer string
+	// If not empty, the "aud" claim of tokens must contain this value.
+	Audience string
+	// Tolerance when checking the "exp", "nbf" and "iat" claims, to account for clocks that are not in sync, defaults to 30s.
+	ClockSkew stdtime.Duration
+
+	// Claim that contains the user id, defaults to "sub".
+	UserIdClaim string
+	// Claim that contains the name of the user, defaults to "name".
+	// The claim is optional, tokens without it are still valid.
+	NameClaim string
+}
+
+// Authenticates requests using JSON Web Tokens signed with HS256 or RS256.
+// Tokens must contain an "exp" claim, the "iss" and "aud" claims are checked if an issuer or audience is configured.
+//
+// The token is read from the context, use the "HTTPToContext" RequestFunc from the go-kit/kit/auth/jwt package
+// as a "ServerBefore" option when creating http handlers (or "GRPCToContext" for gRPC servers).
+func NewJWTAuthEndpointMiddleware(config JWTConfig) (endpoint.Middleware, error) {
+	keys, err := newJWTKeys(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.ClockSkew <= 0 {
+		config.ClockSkew = defaultJWTClockSkew
+	}
+	if config.UserIdClaim == "" {
+		config.UserIdClaim = "sub"
+	}
+	if config.NameClaim == "" {
+		config.NameClaim = "name"
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		// Claims are validated by us, since the parser does not allow for clock skew.
+		jwt.WithoutClaimsValidation(),
+	)
+
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			tokenString, ok := ctx.Value(kitjwt.JWTContextKey).(string)
+			if !ok || tokenString == "" {
+				return nil, errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).
+					WithPublicMessage("missing token")
+			}
+
+			claims := jwt.MapClaims{}
+			_, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+				kid, _ := token.Header["kid"].(string)
+				return keys.key(ctx, token.Method.Alg(), kid)
+			})
+			if err != nil {
+				return nil, errors.New(err, "jwtAuthMiddleware", errors.Unauthenticated).
+					WithPublicMessage("invalid token")
+			}
+
+			if err := validateJWTClaims(claims, config); err != nil {
+				return nil, err
+			}
+
+			userId, _ := claims[config.UserIdClaim].(string)
+			if userId == "" {
+				return nil, errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("missing user id claim").
+					WithPublicMessage("invalid token")
+			}
+			name, _ := claims[config.NameClaim].(string)
+
+			newCtx := auth.ContextWithUser(ctx, auth.User{
+				UserId: userId,
+				Name:   name,
+			})
+
+			return next(newCtx, request)
+		}
+	}, nil
+}
+
+func validateJWTClaims(claims jwt.MapClaims, config JWTConfig) error {
+	now := time.CurrTime()
+
+	exp, ok := numericDateClaim(claims, "exp")
+	if !ok {
+		return errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("missing exp claim").
+			WithPublicMessage("invalid token")
+	}
+	if now.After(exp.Add(config.ClockSkew)) {
+		return errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).
+			WithPublicMessage("token expired")
+	}
+	if nbf, ok := numericDateClaim(claims, "nbf"); ok && now.Before(nbf.Add(-config.ClockSkew)) {
+		return errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).
+			WithPublicMessage("token not valid yet")
+	}
+	if iat, ok := numericDateClaim(claims, "iat"); ok && now.Before(iat.Add(-config.ClockSkew)) {
+		return errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("token issued in the future").
+			WithPublicMessage("invalid token")
+	}
+
+	if config.Issuer != "" && !claims.VerifyIssuer(config.Issuer, true) {
+		return errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("invalid issuer").
+			WithPublicMessage("invalid token")
+	}
+	if config.Audience != "" && !claims.VerifyAudience(config.Audience, true) {
+		return errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("invalid audience").
+			WithPublicMessage("invalid token")
+	}
+	return nil
+}
+
+// Returns the value of a claim containing a NumericDate, i.e. a number of seconds since the Unix epoch.
+func numericDateClaim(claims jwt.MapClaims, name string) (stdtime.Time, bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return stdtime.Unix(0, int64(v*float64(stdtime.Second))), true
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return stdtime.Time{}, false
+		}
+		return stdtime.Unix(0, int64(f*float64(stdtime.Second))), true
+	}
+	return stdtime.Time{}, false
+}
+
+// Returns the keys used to verify tokens.
+// Keys are only returned for the algorithm they are meant for, e.g. an RSA public key is never used as an HMAC secret.
+type jwtKeys interface {
+	key(ctx context.Context, alg, kid string) (interface{}, error)
+}
+
+func newJWTKeys(config JWTConfig) (jwtKeys, error) {
+	n := 0
+	if len(config.HMACSecret) > 0 {
+		n++
+	}
+	if config.RSAPublicKey != nil {
+		n++
+	}
+	if config.JWKSURL != "" {
+		n++
+	}
+	if n != 1 {
+		return nil, errors.New(nil, "jwtAuthMiddleware", errors.InvalidArgument).
+			WithInternalMessage("exactly one of HMAC secret, RSA public key and JWKS url must be set")
+	}
+
+	switch {
+	case len(config.HMACSecret) > 0:
+		return staticJWTKey{alg: jwt.SigningMethodHS256.Alg(), value: config.HMACSecret}, nil
+	case config.RSAPublicKey != nil:
+		return staticJWTKey{alg: jwt.SigningMethodRS256.Alg(), value: config.RSAPublicKey}, nil
+	default:
+		return newJWKS(config), nil
+	}
+}
+
+type staticJWTKey struct {
+	alg   string
+	value interface{}
+}
+
+func (s staticJWTKey) key(ctx context.Context, alg, kid string) (interface{}, error) {
+	if alg != s.alg {
+		return nil, errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("unexpected signing algorithm")
+	}
+	return s.value, nil
+}
+
+// Caches the keys of a JSON Web Key Set.
+// The key set is fetched again if it is older than the refresh interval, or if a token references an unknown key.
+// If a fetch fails, the previously fetched keys continue to be used.
+//
+// Fetches happen without holding the lock, such that tokens with known keys can be verified in the meantime.
+// Concurrent requests that need the key set to be fetched share a single fetch.
+type jwks struct {
+	url                string
+	client             *stdhttp.Client
+	refreshInterval    stdtime.Duration
+	minRefreshInterval stdtime.Duration
+
+	group singleflight.Group
+
+	m sync.Mutex
+	// keys by id
+	keys map[string]staticJWTKey
+	// Time of the last successful fetch.
+	fetchedAt stdtime.Time
+	// Time of the last fetch, successful or not.
+	// To not send a request for every token while the key set is unavailable, fetches are attempted at most once per min refresh interval,
+	// unless no keys have been fetched yet.
+	attemptedAt stdtime.Time
+}
+
+func newJWKS(config JWTConfig) *jwks {
+	s := &jwks{
+		url:                config.JWKSURL,
+		client:             config.HTTPClient,
+		refreshInterval:    config.JWKSRefreshInterval,
+		minRefreshInterval: config.JWKSMinRefreshInterval,
+	}
+	if s.client == nil {
+		s.client = &stdhttp.Client{Timeout: defaultJWKSFetchTimeout}
+	}
+	if s.refreshInterval <= 0 {
+		s.refreshInterval = defaultJWKSRefreshInterval
+	}
+	if s.minRefreshInterval <= 0 {
+		s.minRefreshInterval = defaultJWKSMinRefreshInterval
+	}
+	return s
+}
+
+func (s *jwks) key(ctx context.Context, alg, kid string) (interface{}, error) {
+	k, found, refresh := s.cachedKey(kid)
+	if refresh {
+		// Keep using the old keys if the key set could not be fetched.
+		err := s.refresh(ctx)
+		s.m.Lock()
+		if err != nil && s.keys == nil {
+			s.m.Unlock()
+			return nil, err
+		}
+		k, found = s.lookup(kid)
+		s.m.Unlock()
+	}
+
+	if !found {
+		return nil, errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("unknown key")
+	}
+	if k.alg != alg {
+		return nil, errors.New(nil, "jwtAuthMiddleware", errors.Unauthenticated).WithInternalMessage("unexpected signing algorithm")
+	}
+	return k.value, nil
+}
+
+// Returns the cached key with the given id and whether the key set should be fetched again.
+func (s *jwks) cachedKey(kid string) (staticJWTKey, bool, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	now := stdtime.Now()
+	k, found := s.lookup(kid)
+	if s.keys == nil {
+		return k, found, true
+	}
+	if now.Sub(s.attemptedAt) <= s.minRefreshInterval {
+		return k, found, false
+	}
+	return k, found, !found || now.Sub(s.fetchedAt) > s.refreshInterval
+}
+
+// Fetches the key set and stores it if successful.
+// The fetch does not use the context of the request, since its result is shared with other requests,
+// but the caller stops waiting for it if ctx is done.
+func (s *jwks) refresh(ctx context.Context) error {
+	ch := s.group.DoChan("", func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.Background(), defaultJWKSFetchTimeout)
+		defer cancel()
+		keys, err := s.fetch(fetchCtx)
+
+		s.m.Lock()
+		defer s.m.Unlock()
+		now := stdtime.Now()
+		s.attemptedAt = now
+		if err != nil {
+			return nil, err
+		}
+		s.keys = keys
+		s.fetchedAt = now
+		return nil, nil
+	})
+
+	select {
+	case r := <-ch:
+		return r.Err
+	case <-ctx.Done():
+		return errors.New(ctx.Err(), "jwtAuthMiddleware", errors.Unavailable).WithInternalMessage("could not fetch jwks")
+	}
+}
+
+// Caller must hold the lock.
+// Tokens without a key id can only be used if the key set contains a single key.
+func (s *jwks) lookup(kid string) (staticJWTKey, bool) {
+	if kid == "" {
+		if len(s.keys) == 1 {
+			for _, k := range s.keys {
+				return k, true
+			}
+		}
+		return staticJWTKey{}, false
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA modulus and exponent
+	N string `json:"n"`
+	E string `json:"e"`
+	// symmetric key
+	K string `json:"k"`
+}
+
+// Fetches the key set, keys that are not meant for signatures or use unsupported algorithms are left out.
+func (s *jwks) fetch(ctx context.Context) (map[string]staticJWTKey, error) {
+	req, err := stdhttp.NewRequestWithContext(ctx, stdhttp.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, errors.New(err, "jwtAuthMiddleware", errors.Internal).WithInternalMessage("could not create jwks request")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.New(err, "jwtAuthMiddleware", errors.Unavailable).WithInternalMessage("could not fetch jwks")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != stdhttp.StatusOK {
+		return nil, errors.New(nil, "jwtAuthMiddleware", errors.Unavailable).WithInternalMessage("could not fetch jwks, status: " + resp.Status)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, errors.New(err, "jwtAuthMiddleware", errors.Internal).WithInternalMessage("invalid jwks")
+	}
+
+	keys := make(map[string]staticJWTKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodRS256.Alg() {
+				continue
+			}
+			key, err := parseRSAPublicKey(jwk.N, jwk.E)
+			if err != nil {
+				continue
+			}
+			keys[jwk.Kid] = staticJWTKey{alg: jwt.SigningMethodRS256.Alg(), value: key}
+		case "oct":
+			if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodHS256.Alg() {
+				continue
+			}
+			key, err := base64.RawURLEncoding.DecodeString(jwk.K)
+			if err != nil || len(key) == 0 {
+				continue
+			}
+			keys[jwk.Kid] = staticJWTKey{alg: jwt.SigningMethodHS256.Alg(), value: key}
+		}
+	}
+	return keys, nil
+}
+
+func parseRSAPublicKey(n, e string) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	exp := new(big.Int).SetBytes(eb)
+	if len(nb) == 0 || !exp.IsInt64() || exp.Int64() < 2 || exp.Int64() > 1<<31-1 {
+		return nil, errors.New(nil, "jwtAuthMiddleware", errors.InvalidArgument).WithInternalMessage("invalid rsa key")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(exp.Int64())}, nil
+}
@@ -0,0 +1,441 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+
+	"github.com/dkinzler/kit/errors"
+
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func userEndpoint(ctx context.Context, request interface{}) (interface{}, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if ok {
+		return user, nil
+	}
+	return nil, nil
+}
+
+func callWithToken(mw endpoint.Middleware, token string) (interface{}, error) {
+	ctx := context.WithValue(context.Background(), kitjwt.JWTContextKey, token)
+	return mw(userEndpoint)(ctx, nil)
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	now := stdtime.Now()
+	return jwt.MapClaims{
+		"sub":  "u-123",
+		"name": "Peter",
+		"iss":  "https://issuer.example.com",
+		"aud":  "linkboards",
+		"iat":  now.Unix(),
+		"exp":  now.Add(stdtime.Hour).Unix(),
+	}
+}
+
+func TestJWTAuthEndpointMiddlewareHS256(t *testing.T) {
+	a := assert.New(t)
+
+	secret := []byte("a-very-secret-secret")
+	mw, err := NewJWTAuthEndpointMiddleware(JWTConfig{
+		HMACSecret: secret,
+		Issuer:     "https://issuer.example.com",
+		Audience:   "linkboards",
+	})
+	a.Nil(err)
+
+	user, err := callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", validClaims()))
+	a.Nil(err)
+	a.Equal(auth.User{UserId: "u-123", Name: "Peter"}, user)
+
+	// missing token
+	_, err = mw(userEndpoint)(context.Background(), nil)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// wrong secret
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, []byte("another-secret"), "", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// unsigned tokens are not accepted
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// wrong issuer
+	claims := validClaims()
+	claims["iss"] = "https://other.example.com"
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// audience can also be a list
+	claims = validClaims()
+	claims["aud"] = []string{"other", "linkboards"}
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.Nil(err)
+	claims["aud"] = []string{"other"}
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// missing user id
+	claims = validClaims()
+	delete(claims, "sub")
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// name is optional
+	claims = validClaims()
+	delete(claims, "name")
+	user, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.Nil(err)
+	a.Equal(auth.User{UserId: "u-123"}, user)
+}
+
+func TestJWTAuthEndpointMiddlewareChecksExpiryWithClockSkew(t *testing.T) {
+	a := assert.New(t)
+
+	secret := []byte("a-very-secret-secret")
+	mw, err := NewJWTAuthEndpointMiddleware(JWTConfig{
+		HMACSecret: secret,
+		ClockSkew:  stdtime.Minute,
+	})
+	a.Nil(err)
+
+	now := stdtime.Now()
+
+	// expiry is required
+	claims := validClaims()
+	delete(claims, "exp")
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// expired within the clock skew
+	claims = validClaims()
+	claims["exp"] = now.Add(-30 * stdtime.Second).Unix()
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.Nil(err)
+
+	claims["exp"] = now.Add(-2 * stdtime.Minute).Unix()
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// not valid yet
+	claims = validClaims()
+	claims["nbf"] = now.Add(30 * stdtime.Second).Unix()
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.Nil(err)
+	claims["nbf"] = now.Add(2 * stdtime.Minute).Unix()
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// issued in the future
+	claims = validClaims()
+	claims["iat"] = now.Add(2 * stdtime.Minute).Unix()
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+}
+
+func TestJWTAuthEndpointMiddlewareClaimMapping(t *testing.T) {
+	a := assert.New(t)
+
+	secret := []byte("a-very-secret-secret")
+	mw, err := NewJWTAuthEndpointMiddleware(JWTConfig{
+		HMACSecret:  secret,
+		UserIdClaim: "uid",
+		NameClaim:   "username",
+	})
+	a.Nil(err)
+
+	claims := validClaims()
+	claims["uid"] = "u-456"
+	claims["username"] = "Paul"
+	user, err := callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.Nil(err)
+	a.Equal(auth.User{UserId: "u-456", Name: "Paul"}, user)
+
+	delete(claims, "uid")
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, secret, "", claims))
+	a.True(errors.IsUnauthenticatedError(err))
+}
+
+func TestJWTAuthEndpointMiddlewareRS256(t *testing.T) {
+	a := assert.New(t)
+
+	key := newRSAKey(t)
+	mw, err := NewJWTAuthEndpointMiddleware(JWTConfig{
+		RSAPublicKey: &key.PublicKey,
+	})
+	a.Nil(err)
+
+	user, err := callWithToken(mw, signToken(t, jwt.SigningMethodRS256, key, "", validClaims()))
+	a.Nil(err)
+	a.Equal(auth.User{UserId: "u-123", Name: "Peter"}, user)
+
+	// token signed with another key
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodRS256, newRSAKey(t), "", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// the public key must not be usable as an HMAC secret
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, key.PublicKey.N.Bytes(), "", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+}
+
+func TestNewJWTAuthEndpointMiddlewareRequiresExactlyOneKeySource(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := NewJWTAuthEndpointMiddleware(JWTConfig{})
+	a.NotNil(err)
+
+	key := newRSAKey(t)
+	_, err = NewJWTAuthEndpointMiddleware(JWTConfig{
+		HMACSecret:   []byte("secret"),
+		RSAPublicKey: &key.PublicKey,
+	})
+	a.NotNil(err)
+}
+
+// Serves a JSON Web Key Set that can be changed during a test.
+type testJWKSServer struct {
+	*httptest.Server
+
+	m        sync.Mutex
+	keys     []jsonWebKey
+	requests int
+	// If true, requests fail with status 500.
+	fail bool
+	// Time requests take to complete.
+	delay stdtime.Duration
+}
+
+func newTestJWKSServer() *testJWKSServer {
+	s := &testJWKSServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.m.Lock()
+		s.requests++
+		delay, fail, keys := s.delay, s.fail, s.keys
+		s.m.Unlock()
+
+		stdtime.Sleep(delay)
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	return s
+}
+
+func (s *testJWKSServer) setFail(fail bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.fail = fail
+}
+
+func (s *testJWKSServer) setDelay(delay stdtime.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.delay = delay
+}
+
+func (s *testJWKSServer) setKeys(keys ...jsonWebKey) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.keys = keys
+}
+
+func (s *testJWKSServer) requestCount() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.requests
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+	}
+}
+
+func TestJWTAuthEndpointMiddlewareJWKS(t *testing.T) {
+	a := assert.New(t)
+
+	key1 := newRSAKey(t)
+	key2 := newRSAKey(t)
+	hmacSecret := []byte("a-very-secret-secret")
+
+	server := newTestJWKSServer()
+	defer server.Close()
+	server.setKeys(rsaJWK("key1", key1), jsonWebKey{
+		Kty: "oct",
+		Kid: "hmac",
+		K:   base64.RawURLEncoding.EncodeToString(hmacSecret),
+	})
+
+	mw, err := NewJWTAuthEndpointMiddleware(JWTConfig{
+		JWKSURL:                server.URL,
+		JWKSMinRefreshInterval: 50 * stdtime.Millisecond,
+	})
+	a.Nil(err)
+
+	user, err := callWithToken(mw, signToken(t, jwt.SigningMethodRS256, key1, "key1", validClaims()))
+	a.Nil(err)
+	a.Equal(auth.User{UserId: "u-123", Name: "Peter"}, user)
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, hmacSecret, "hmac", validClaims()))
+	a.Nil(err)
+	// keys are cached
+	a.Equal(1, server.requestCount())
+
+	// a key can only be used with the algorithm of its type
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodHS256, hmacSecret, "key1", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+	// there is more than one key, so the key id is required
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodRS256, key1, "", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+
+	// rotate keys
+	server.setKeys(rsaJWK("key2", key2))
+
+	// unknown keys only cause the key set to be fetched again after the min refresh interval
+	token2 := signToken(t, jwt.SigningMethodRS256, key2, "key2", validClaims())
+	_, err = callWithToken(mw, token2)
+	a.True(errors.IsUnauthenticatedError(err))
+	a.Equal(1, server.requestCount())
+
+	stdtime.Sleep(60 * stdtime.Millisecond)
+	_, err = callWithToken(mw, token2)
+	a.Nil(err)
+	a.Equal(2, server.requestCount())
+
+	// old key was removed
+	_, err = callWithToken(mw, signToken(t, jwt.SigningMethodRS256, key1, "key1", validClaims()))
+	a.True(errors.IsUnauthenticatedError(err))
+}
+
+func TestJWKSKeepsKeysIfFetchFails(t *testing.T) {
+	a := assert.New(t)
+
+	key := newRSAKey(t)
+	server := newTestJWKSServer()
+	server.setKeys(rsaJWK("key1", key))
+
+	mw, err := NewJWTAuthEndpointMiddleware(JWTConfig{
+		JWKSURL:                server.URL,
+		JWKSRefreshInterval:    50 * stdtime.Millisecond,
+		JWKSMinRefreshInterval: 10 * stdtime.Millisecond,
+	})
+	a.Nil(err)
+
+	token := signToken(t, jwt.SigningMethodRS256, key, "key1", validClaims())
+	_, err = callWithToken(mw, token)
+	a.Nil(err)
+
+	server.Close()
+	stdtime.Sleep(60 * stdtime.Millisecond)
+	_, err = callWithToken(mw, token)
+	a.Nil(err)
+}
+
+func TestJWKSSharesFetches(t *testing.T) {
+	a := assert.New(t)
+
+	key := newRSAKey(t)
+	server := newTestJWKSServer()
+	defer server.Close()
+	server.setKeys(rsaJWK("key1", key))
+	server.setDelay(50 * stdtime.Millisecond)
+
+	s := newJWKS(JWTConfig{JWKSURL: server.URL})
+
+	// the fetch is not canceled together with the request that started it
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.key(ctx, "RS256", "key1")
+	a.NotNil(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.key(context.Background(), "RS256", "key1")
+			a.Nil(err)
+		}()
+	}
+	wg.Wait()
+	a.Equal(1, server.requestCount())
+}
+
+func TestJWKSRetriesFailedFetches(t *testing.T) {
+	a := assert.New(t)
+
+	key1 := newRSAKey(t)
+	key2 := newRSAKey(t)
+	server := newTestJWKSServer()
+	defer server.Close()
+	server.setKeys(rsaJWK("key1", key1))
+
+	s := newJWKS(JWTConfig{
+		JWKSURL:                server.URL,
+		JWKSRefreshInterval:    30 * stdtime.Millisecond,
+		JWKSMinRefreshInterval: 10 * stdtime.Millisecond,
+	})
+	_, err := s.key(context.Background(), "RS256", "key1")
+	a.Nil(err)
+
+	server.setFail(true)
+	stdtime.Sleep(40 * stdtime.Millisecond)
+	_, err = s.key(context.Background(), "RS256", "key1")
+	a.Nil(err)
+	a.Equal(2, server.requestCount())
+	// failed fetches are only retried after the min refresh interval
+	_, err = s.key(context.Background(), "RS256", "key1")
+	a.Nil(err)
+	a.Equal(2, server.requestCount())
+
+	// the key set is still outdated after a failed fetch, the next fetch gets the new keys
+	server.setFail(false)
+	server.setKeys(rsaJWK("key2", key2))
+	stdtime.Sleep(15 * stdtime.Millisecond)
+	_, err = s.key(context.Background(), "RS256", "key1")
+	a.NotNil(err)
+	a.Equal(3, server.requestCount())
+	_, err = s.key(context.Background(), "RS256", "key2")
+	a.Nil(err)
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
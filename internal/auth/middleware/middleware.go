@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"encoding/base64"
+	stdhttp "net/http"
 	"strings"
 
 	"github.com/dkinzler/linkboards/internal/auth"
@@ -12,8 +13,10 @@ import (
 
 	fbauth "firebase.google.com/go/v4/auth"
 
+	kitjwt "github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/transport/http"
+	"google.golang.org/grpc/metadata"
 )
 
 // Can be used for development/testing.
@@ -60,6 +63,17 @@ func NewFakeAuthEndpointMiddleware() endpoint.Middleware {
 	}
 }
 
+// Stores the "authorization" metadata of a gRPC request in the context, where NewFakeAuthEndpointMiddleware expects it.
+// This is the gRPC equivalent of the "PopulateRequestContext" RequestFunc,
+// use it as a "ServerBefore" option when creating servers with the go-kit/kit/transport/grpc package.
+func GRPCAuthorizationToContext(ctx context.Context, md metadata.MD) context.Context {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, http.ContextKeyRequestAuthorization, values[0])
+}
+
 func NewFirebaseAuthEndpointMiddleware(authClient *fbauth.Client, requireVerifiedEmail bool) endpoint.Middleware {
 	fbAuthChecker := lfbauth.NewAuthChecker(authClient, requireVerifiedEmail, func(m map[string]interface{}) (interface{}, error) {
 		name, ok := m["name"]
@@ -83,3 +97,71 @@ func NewFirebaseAuthEndpointMiddleware(authClient *fbauth.Client, requireVerifie
 		},
 	)
 }
+
+// Makes authentication optional for the endpoints it is applied to.
+// Requests without credentials are passed on without an authenticated user in the context,
+// all other requests are authenticated by the given middleware and fail if the credentials are invalid.
+// Used for endpoints that can also be used by unauthenticated users, e.g. to view boards that are visible to non-members.
+//
+// Credentials are read from the context, where they are stored by either the "HTTPToContext" RequestFunc from the go-kit/kit/auth/jwt package
+// or the "PopulateRequestContext" RequestFunc from the go-kit/kit/transport/http package.
+func NewOptionalAuthEndpointMiddleware(next endpoint.Middleware) endpoint.Middleware {
+	return func(e endpoint.Endpoint) endpoint.Endpoint {
+		authenticated := next(e)
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if !hasCredentials(ctx) {
+				return e(ctx, request)
+			}
+			return authenticated(ctx, request)
+		}
+	}
+}
+
+func hasCredentials(ctx context.Context) bool {
+	if token, ok := ctx.Value(kitjwt.JWTContextKey).(string); ok && token != "" {
+		return true
+	}
+	if header, ok := ctx.Value(http.ContextKeyRequestAuthorization).(string); ok && header != "" {
+		return true
+	}
+	return false
+}
+
+type contextKey string
+
+const feedTokenContextKey contextKey = "feedToken"
+
+// Adds the feed token contained in the "token" query parameter of a request to the context.
+// Use as a "ServerBefore" option when creating http handlers for endpoints that use the middleware returned by NewFeedTokenEndpointMiddleware.
+func FeedTokenToContext(ctx context.Context, r *stdhttp.Request) context.Context {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, feedTokenContextKey, token)
+}
+
+// Authenticates requests using a feed token, see auth.FeedTokens.
+// The token is read from the context, use FeedTokenToContext to add it.
+func NewFeedTokenEndpointMiddleware(feedTokens *auth.FeedTokens) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			token, ok := ctx.Value(feedTokenContextKey).(string)
+			if !ok {
+				return nil, errors.New(nil, "feedTokenAuthMiddleware", errors.Unauthenticated).
+					WithPublicMessage("missing feed token")
+			}
+
+			userId, err := feedTokens.UserId(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+
+			newCtx := auth.ContextWithUser(ctx, auth.User{
+				UserId: userId,
+			})
+
+			return next(newCtx, request)
+		}
+	}
+}
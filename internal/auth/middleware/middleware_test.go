@@ -3,9 +3,11 @@ package middleware
 import (
 	"context"
 	"encoding/base64"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/tokens"
 
 	"github.com/dkinzler/kit/errors"
 	"github.com/go-kit/kit/transport/http"
@@ -63,3 +65,80 @@ func TestFakeAuthEndpointMiddleware(t *testing.T) {
 	a.True(ok)
 	a.Equal("u-123-456", authUser.UserId)
 }
+
+func TestOptionalAuthEndpointMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	mw := NewOptionalAuthEndpointMiddleware(NewFakeAuthEndpointMiddleware())
+
+	e := func(ctx context.Context, request interface{}) (interface{}, error) {
+		user, ok := auth.UserFromContext(ctx)
+		if ok {
+			return user, nil
+		}
+		return nil, nil
+	}
+
+	// requests without credentials are not authenticated
+	user, err := mw(e)(context.Background(), nil)
+	a.Nil(err)
+	a.Nil(user)
+	ctx := context.WithValue(context.Background(), http.ContextKeyRequestAuthorization, "")
+	user, err = mw(e)(ctx, nil)
+	a.Nil(err)
+	a.Nil(user)
+
+	// invalid credentials are still rejected
+	ctx = context.WithValue(context.Background(), http.ContextKeyRequestAuthorization, "this isnt correct format")
+	_, err = mw(e)(ctx, nil)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	v := base64.StdEncoding.EncodeToString([]byte("u-123:somepw"))
+	ctx = context.WithValue(context.Background(), http.ContextKeyRequestAuthorization, "Basic "+v)
+	user, err = mw(e)(ctx, nil)
+	a.Nil(err)
+	a.Equal(auth.User{UserId: "u-123"}, user)
+}
+
+func TestFeedTokenEndpointMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	feedTokens := auth.NewFeedTokens([]byte("secret"), tokens.NewInmemFeedTokenVersionStore())
+	mw := NewFeedTokenEndpointMiddleware(feedTokens)
+
+	called := false
+	e := func(ctx context.Context, request interface{}) (interface{}, error) {
+		called = true
+		user, ok := auth.UserFromContext(ctx)
+		if ok {
+			return user, nil
+		}
+		return nil, nil
+	}
+
+	// no token
+	ctx := FeedTokenToContext(context.Background(), httptest.NewRequest("GET", "/boards/b-1/feed.atom", nil))
+	_, err := mw(e)(ctx, nil)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+	a.False(called)
+
+	// invalid token
+	ctx = FeedTokenToContext(context.Background(), httptest.NewRequest("GET", "/boards/b-1/feed.atom?token=lbf_abc.xyz", nil))
+	_, err = mw(e)(ctx, nil)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+	a.False(called)
+
+	// valid token
+	token, err := feedTokens.Token(context.Background(), "u-123")
+	a.Nil(err)
+	ctx = FeedTokenToContext(context.Background(), httptest.NewRequest("GET", "/boards/b-1/feed.atom?token="+token, nil))
+	user, err := mw(e)(ctx, nil)
+	a.Nil(err)
+	a.True(called)
+	authUser, ok := user.(auth.User)
+	a.True(ok)
+	a.Equal("u-123", authUser.UserId)
+}
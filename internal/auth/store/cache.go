@@ -0,0 +1,295 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+)
+
+type CacheConfig struct {
+	// How long the roles of a user are cached, defaults to 30 seconds.
+	TTL stdtime.Duration
+	// How long it is cached that a user is not a member of a board, defaults to 5 seconds.
+	NegativeTTL stdtime.Duration
+	// Maximum number of cached entries, the least recently used entries are evicted first.
+	// Defaults to 10000.
+	MaxEntries int
+}
+
+const defaultCacheTTL = 30 * stdtime.Second
+const defaultCacheNegativeTTL = 5 * stdtime.Second
+const defaultCacheMaxEntries = 10000
+
+// CachingAuthorizationStore wraps another AuthorizationStore and caches the roles of users, the custom roles and the visibility of boards.
+// Without a cache, every request that is authorized relative to a board reads the user from the data store.
+//
+// If a user is not a member of a board, i.e. the wrapped store returns an error with code PermissionDenied, the error is cached as well,
+// usually for a shorter time.
+// Other errors are not cached.
+//
+// Cached entries are invalidated when the users, custom roles or visibility of a board change, for that the store
+// has to receive the events of the boards component, it implements domain.EventPublisher.
+// Note that events are only received by the instance that made the change, if multiple instances of the application are running,
+// changes made by another instance are only visible to this instance after the cached entries expire.
+type CachingAuthorizationStore struct {
+	store  auth.AuthorizationStore
+	config CacheConfig
+
+	m       sync.Mutex
+	entries map[cacheKey]*list.Element
+	// Most recently used entries are at the front.
+	lru *list.List
+	// Incremented on every invalidation, values loaded while an invalidation happened are not cached,
+	// since they might have been read before the change.
+	generation uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+type cacheKey struct {
+	boardId string
+	// Empty unless kind is rolesEntry.
+	userId string
+	kind   cacheEntryKind
+}
+
+type cacheEntryKind int
+
+const (
+	rolesEntry cacheEntryKind = iota
+	customRolesEntry
+	visibilityEntry
+)
+
+type cacheEntry struct {
+	key         cacheKey
+	roles       []string
+	customRoles map[string][]auth.Scope
+	visible     bool
+	// Not nil for negative entries.
+	err     error
+	expires stdtime.Time
+}
+
+func NewCachingAuthorizationStore(store auth.AuthorizationStore, config CacheConfig) *CachingAuthorizationStore {
+	if config.TTL <= 0 {
+		config.TTL = defaultCacheTTL
+	}
+	if config.NegativeTTL <= 0 {
+		config.NegativeTTL = defaultCacheNegativeTTL
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultCacheMaxEntries
+	}
+	return &CachingAuthorizationStore{
+		store:   store,
+		config:  config,
+		entries: make(map[cacheKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *CachingAuthorizationStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	key := cacheKey{boardId: boardId, userId: userId}
+	if e, ok := c.get(key); ok {
+		return e.roles, e.err
+	}
+
+	generation := c.currentGeneration()
+	roles, err := c.store.Roles(ctx, boardId, userId)
+	if err != nil {
+		if errors.IsPermissionDeniedError(err) {
+			c.set(cacheEntry{key: key, err: err}, generation, c.config.NegativeTTL)
+		}
+		return nil, err
+	}
+	c.set(cacheEntry{key: key, roles: roles}, generation, c.config.TTL)
+	return roles, nil
+}
+
+// Implements auth.CustomRoleStore, if the wrapped store does not, boards have no custom roles.
+func (c *CachingAuthorizationStore) CustomRoles(ctx context.Context, boardId string) (map[string][]auth.Scope, error) {
+	crs, ok := c.store.(auth.CustomRoleStore)
+	if !ok {
+		return nil, nil
+	}
+
+	key := cacheKey{boardId: boardId, kind: customRolesEntry}
+	if e, ok := c.get(key); ok {
+		return e.customRoles, e.err
+	}
+
+	generation := c.currentGeneration()
+	customRoles, err := crs.CustomRoles(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	c.set(cacheEntry{key: key, customRoles: customRoles}, generation, c.config.TTL)
+	return customRoles, nil
+}
+
+// Implements auth.BoardVisibilityStore, if the wrapped store does not, boards are not visible to non-members.
+func (c *CachingAuthorizationStore) IsBoardVisible(ctx context.Context, boardId string) (bool, error) {
+	vs, ok := c.store.(auth.BoardVisibilityStore)
+	if !ok {
+		return false, nil
+	}
+
+	key := cacheKey{boardId: boardId, kind: visibilityEntry}
+	if e, ok := c.get(key); ok {
+		return e.visible, e.err
+	}
+
+	generation := c.currentGeneration()
+	visible, err := vs.IsBoardVisible(ctx, boardId)
+	if err != nil {
+		return false, err
+	}
+	c.set(cacheEntry{key: key, visible: visible}, generation, c.config.TTL)
+	return visible, nil
+}
+
+func (c *CachingAuthorizationStore) get(key cacheKey) (cacheEntry, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return cacheEntry{}, false
+	}
+	e := el.Value.(cacheEntry)
+	if !time.CurrTime().Before(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		c.misses++
+		return cacheEntry{}, false
+	}
+	c.lru.MoveToFront(el)
+	c.hits++
+	return e, true
+}
+
+func (c *CachingAuthorizationStore) currentGeneration() uint64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.generation
+}
+
+func (c *CachingAuthorizationStore) set(e cacheEntry, generation uint64, ttl stdtime.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	e.expires = time.CurrTime().Add(ttl)
+	if el, ok := c.entries[e.key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.config.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// Removes the cached roles of a user for a board.
+func (c *CachingAuthorizationStore) InvalidateUser(boardId string, userId string) {
+	c.invalidate(func(key cacheKey) bool {
+		return key.boardId == boardId && key.userId == userId && key.kind == rolesEntry
+	})
+}
+
+// Removes the cached custom roles of a board.
+func (c *CachingAuthorizationStore) InvalidateCustomRoles(boardId string) {
+	c.invalidate(func(key cacheKey) bool {
+		return key.boardId == boardId && key.kind == customRolesEntry
+	})
+}
+
+// Removes the cached visibility of a board.
+func (c *CachingAuthorizationStore) InvalidateVisibility(boardId string) {
+	c.invalidate(func(key cacheKey) bool {
+		return key.boardId == boardId && key.kind == visibilityEntry
+	})
+}
+
+// Removes all cached entries for a board.
+func (c *CachingAuthorizationStore) InvalidateBoard(boardId string) {
+	c.invalidate(func(key cacheKey) bool {
+		return key.boardId == boardId
+	})
+}
+
+func (c *CachingAuthorizationStore) invalidate(match func(key cacheKey) bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.generation++
+	for key, el := range c.entries {
+		if match(key) {
+			c.lru.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Implements domain.EventPublisher to invalidate cached entries when the users, custom roles or visibility of a board change.
+func (c *CachingAuthorizationStore) PublishEvent(ctx context.Context, event interface{}) {
+	switch e := event.(type) {
+	case domain.BoardUserAdded:
+		c.InvalidateUser(e.BoardId, e.User.User.UserId)
+	case domain.BoardUserEdited:
+		c.InvalidateUser(e.BoardId, e.User.User.UserId)
+	case domain.BoardUserRemoved:
+		c.InvalidateUser(e.BoardId, e.UserId)
+	case domain.BoardCustomRolesChanged:
+		c.InvalidateCustomRoles(e.BoardId)
+	case domain.BoardVisibilityChanged:
+		c.InvalidateVisibility(e.BoardId)
+	case domain.BoardDeleted:
+		c.InvalidateBoard(e.BoardId)
+	}
+}
+
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Number of entries currently cached, including expired entries that were not yet removed.
+	Entries int
+}
+
+// Returns the fraction of lookups that were answered from the cache, or 0 if there were no lookups.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+func (c *CachingAuthorizationStore) Stats() CacheStats {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   c.lru.Len(),
+	}
+}
@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/boards/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingStore struct {
+	roles       map[string][]string
+	customRoles map[string][]auth.Scope
+	err         error
+	calls       int
+	customCalls int
+	visible     map[string]bool
+	// called during Roles, e.g. to simulate a concurrent change
+	onRoles func()
+}
+
+func (s *countingStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	s.calls++
+	if s.onRoles != nil {
+		s.onRoles()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	roles, ok := s.roles[boardId+"/"+userId]
+	if !ok {
+		return nil, errors.New(nil, "test", errors.PermissionDenied)
+	}
+	return roles, nil
+}
+
+func (s *countingStore) CustomRoles(ctx context.Context, boardId string) (map[string][]auth.Scope, error) {
+	s.customCalls++
+	return s.customRoles, nil
+}
+
+func (s *countingStore) IsBoardVisible(ctx context.Context, boardId string) (bool, error) {
+	s.calls++
+	return s.visible[boardId], nil
+}
+
+func setTestTime(t stdtime.Time) {
+	time.TimeFunc = func() stdtime.Time {
+		return t
+	}
+}
+
+func TestCachingAuthorizationStore(t *testing.T) {
+	a := assert.New(t)
+	defer func() { time.TimeFunc = stdtime.Now }()
+
+	now := stdtime.Now()
+	setTestTime(now)
+
+	inner := &countingStore{roles: map[string][]string{"b-1/u-1": {auth.BoardRoleOwner}}}
+	cache := NewCachingAuthorizationStore(inner, CacheConfig{TTL: stdtime.Minute, NegativeTTL: 10 * stdtime.Second})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		roles, err := cache.Roles(ctx, "b-1", "u-1")
+		a.Nil(err)
+		a.Equal([]string{auth.BoardRoleOwner}, roles)
+	}
+	a.Equal(1, inner.calls)
+	a.Equal(CacheStats{Hits: 2, Misses: 1, Entries: 1}, cache.Stats())
+	a.InDelta(2.0/3.0, cache.Stats().HitRate(), 0.001)
+
+	// users that are not on a board are cached for a shorter time
+	for i := 0; i < 2; i++ {
+		_, err := cache.Roles(ctx, "b-1", "u-2")
+		a.NotNil(err)
+		a.True(errors.IsPermissionDeniedError(err))
+	}
+	a.Equal(2, inner.calls)
+
+	setTestTime(now.Add(30 * stdtime.Second))
+	_, err := cache.Roles(ctx, "b-1", "u-1")
+	a.Nil(err)
+	_, err = cache.Roles(ctx, "b-1", "u-2")
+	a.NotNil(err)
+	a.Equal(3, inner.calls)
+
+	setTestTime(now.Add(2 * stdtime.Minute))
+	_, err = cache.Roles(ctx, "b-1", "u-1")
+	a.Nil(err)
+	a.Equal(4, inner.calls)
+
+	// other errors are not cached
+	inner.err = errors.New(nil, "test", errors.Internal)
+	for i := 0; i < 2; i++ {
+		_, err = cache.Roles(ctx, "b-2", "u-1")
+		a.NotNil(err)
+		a.True(errors.IsInternalError(err))
+	}
+	a.Equal(6, inner.calls)
+}
+
+func TestCachingAuthorizationStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	a := assert.New(t)
+
+	inner := &countingStore{roles: map[string][]string{
+		"b-1/u-1": {auth.BoardRoleOwner},
+		"b-1/u-2": {auth.BoardRoleEditor},
+		"b-1/u-3": {auth.BoardRoleViewer},
+	}}
+	cache := NewCachingAuthorizationStore(inner, CacheConfig{MaxEntries: 2})
+	ctx := context.Background()
+
+	cache.Roles(ctx, "b-1", "u-1")
+	cache.Roles(ctx, "b-1", "u-2")
+	// u-1 is now the most recently used entry
+	cache.Roles(ctx, "b-1", "u-1")
+	cache.Roles(ctx, "b-1", "u-3")
+	a.Equal(3, inner.calls)
+	a.Equal(uint64(1), cache.Stats().Evictions)
+	a.Equal(2, cache.Stats().Entries)
+
+	cache.Roles(ctx, "b-1", "u-1")
+	a.Equal(3, inner.calls)
+	cache.Roles(ctx, "b-1", "u-2")
+	a.Equal(4, inner.calls)
+}
+
+func TestCachingAuthorizationStoreInvalidation(t *testing.T) {
+	a := assert.New(t)
+
+	inner := &countingStore{
+		roles: map[string][]string{
+			"b-1/u-1": {auth.BoardRoleOwner},
+			"b-1/u-2": {auth.BoardRoleViewer},
+		},
+		customRoles: map[string][]auth.Scope{"curator": {"links:delete"}},
+	}
+	cache := NewCachingAuthorizationStore(inner, CacheConfig{})
+	ctx := context.Background()
+
+	_, err := cache.Roles(ctx, "b-1", "u-3")
+	a.NotNil(err)
+	cache.Roles(ctx, "b-1", "u-2")
+	customRoles, err := cache.CustomRoles(ctx, "b-1")
+	a.Nil(err)
+	a.Equal(inner.customRoles, customRoles)
+	cache.CustomRoles(ctx, "b-1")
+	a.Equal(1, inner.customCalls)
+
+	// a user that joins the board is no longer denied
+	inner.roles["b-1/u-3"] = []string{"curator"}
+	cache.PublishEvent(ctx, domain.BoardUserAdded{BoardId: "b-1", User: domain.BoardUser{User: domain.User{UserId: "u-3"}}})
+	roles, err := cache.Roles(ctx, "b-1", "u-3")
+	a.Nil(err)
+	a.Equal([]string{"curator"}, roles)
+
+	inner.roles["b-1/u-2"] = []string{auth.BoardRoleEditor}
+	cache.PublishEvent(ctx, domain.BoardUserEdited{BoardId: "b-1", User: domain.BoardUser{User: domain.User{UserId: "u-2"}}})
+	roles, _ = cache.Roles(ctx, "b-1", "u-2")
+	a.Equal([]string{auth.BoardRoleEditor}, roles)
+
+	delete(inner.roles, "b-1/u-2")
+	cache.PublishEvent(ctx, domain.BoardUserRemoved{BoardId: "b-1", UserId: "u-2"})
+	_, err = cache.Roles(ctx, "b-1", "u-2")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	inner.customRoles = map[string][]auth.Scope{"curator": {"links:query"}}
+	cache.PublishEvent(ctx, domain.BoardCustomRolesChanged{BoardId: "b-1"})
+	customRoles, _ = cache.CustomRoles(ctx, "b-1")
+	a.Equal(inner.customRoles, customRoles)
+	a.Equal(2, inner.customCalls)
+
+	visible, err := cache.IsBoardVisible(ctx, "b-1")
+	a.Nil(err)
+	a.False(visible)
+	inner.visible = map[string]bool{"b-1": true}
+	visible, _ = cache.IsBoardVisible(ctx, "b-1")
+	a.False(visible)
+	cache.PublishEvent(ctx, domain.BoardVisibilityChanged{BoardId: "b-1", Visibility: domain.VisibilityLink})
+	visible, _ = cache.IsBoardVisible(ctx, "b-1")
+	a.True(visible)
+
+	calls := inner.calls
+	cache.PublishEvent(ctx, domain.BoardDeleted{BoardId: "b-1"})
+	a.Equal(0, cache.Stats().Entries)
+	cache.Roles(ctx, "b-1", "u-1")
+	a.Equal(calls+1, inner.calls)
+}
+
+func TestCachingAuthorizationStoreDoesNotCacheValuesReadDuringInvalidation(t *testing.T) {
+	a := assert.New(t)
+
+	inner := &countingStore{roles: map[string][]string{"b-1/u-1": {auth.BoardRoleViewer}}}
+	cache := NewCachingAuthorizationStore(inner, CacheConfig{})
+	ctx := context.Background()
+
+	// the role changes while the old value is being read
+	inner.onRoles = func() {
+		cache.InvalidateUser("b-1", "u-1")
+	}
+	roles, err := cache.Roles(ctx, "b-1", "u-1")
+	a.Nil(err)
+	a.Equal([]string{auth.BoardRoleViewer}, roles)
+	a.Equal(0, cache.Stats().Entries)
+
+	inner.onRoles = nil
+	inner.roles["b-1/u-1"] = []string{auth.BoardRoleEditor}
+	roles, _ = cache.Roles(ctx, "b-1", "u-1")
+	a.Equal([]string{auth.BoardRoleEditor}, roles)
+}
+
+func TestCachingAuthorizationStoreWithBoardsComponentEvents(t *testing.T) {
+	a := assert.New(t)
+
+	// Cached roles are invalidated by the events of a BoardService.
+	ds := inmem.NewInmemBoardDataStore()
+	err := ds.UpdateBoard(context.Background(), "b-123", domain.NewDatastoreBoardUpdate(nil).WithBoard(domain.Board{
+		BoardId: "b-123",
+	}).UpdateUser(domain.BoardUser{
+		User: domain.User{UserId: "u-1"},
+		Role: auth.BoardRoleOwner,
+	}).UpdateUser(domain.BoardUser{
+		User: domain.User{UserId: "u-2"},
+		Role: auth.BoardRoleViewer,
+	}))
+	a.Nil(err)
+	cache := NewCachingAuthorizationStore(NewDefaultAuthorizationStore(ds), CacheConfig{})
+	service := domain.NewBoardService(ds, cache, domain.LimitsConfig{}, nil)
+	checker := auth.NewAuthorizationChecker(map[string][]auth.Scope{
+		auth.BoardRoleOwner:  {"test:view", "test:invite"},
+		auth.BoardRoleViewer: {"test:view"},
+		auth.BoardRoleEditor: {"test:view", "test:invite"},
+	}, cache)
+	ctx := context.Background()
+
+	az, err := checker.GetAuthorization(ctx, "b-123", "u-2")
+	a.Nil(err)
+	a.False(az.HasScope("test:invite"))
+
+	_, err = service.EditBoardUser(ctx, "b-123", "u-2", domain.BoardUserEdit{UpdateRole: true, Role: auth.BoardRoleEditor}, domain.User{UserId: "u-1"})
+	a.Nil(err)
+	az, err = checker.GetAuthorization(ctx, "b-123", "u-2")
+	a.Nil(err)
+	a.True(az.HasScope("test:invite"))
+
+	err = service.RemoveUser(ctx, "b-123", "u-2")
+	a.Nil(err)
+	_, err = checker.GetAuthorization(ctx, "b-123", "u-2")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+}
@@ -30,3 +30,33 @@ func (d *DefaultAuthorizationStore) Roles(ctx context.Context, boardId string, u
 
 	return []string{user.Role}, nil
 }
+
+// Implements auth.CustomRoleStore, custom roles are stored with the board.
+func (d *DefaultAuthorizationStore) CustomRoles(ctx context.Context, boardId string) (map[string][]auth.Scope, error) {
+	boards, err := d.ds.Boards(ctx, []string{boardId})
+	if err != nil {
+		return nil, errors.New(err, "DefaultAuthorizationStore", errors.Internal)
+	}
+	// Depending on the data store, a board that does not exist is left out or returned as zero value.
+	if len(boards) == 0 || boards[0].BoardId != boardId {
+		return nil, errors.New(nil, "DefaultAuthorizationStore", errors.PermissionDenied)
+	}
+
+	result := make(map[string][]auth.Scope, len(boards[0].CustomRoles))
+	for _, role := range boards[0].CustomRoles {
+		result[role.Name] = role.Scopes
+	}
+	return result, nil
+}
+
+// Implements auth.BoardVisibilityStore, the visibility is stored with the board.
+func (d *DefaultAuthorizationStore) IsBoardVisible(ctx context.Context, boardId string) (bool, error) {
+	boards, err := d.ds.Boards(ctx, []string{boardId})
+	if err != nil {
+		return false, errors.New(err, "DefaultAuthorizationStore", errors.Internal)
+	}
+	if len(boards) == 0 || boards[0].BoardId != boardId {
+		return false, nil
+	}
+	return boards[0].IsVisibleToNonMembers(), nil
+}
@@ -56,3 +56,49 @@ func TestDefaultAuthorizationStore(t *testing.T) {
 	a.Len(roles, 1)
 	a.Contains(roles, auth.BoardRoleViewer)
 }
+
+func TestDefaultAuthorizationStoreCustomRoles(t *testing.T) {
+	a := assert.New(t)
+
+	bds := inmem.NewInmemBoardDataStore()
+	err := bds.UpdateBoard(context.Background(), "b-123", domain.NewDatastoreBoardUpdate(nil).WithBoard(domain.Board{
+		BoardId: "b-123",
+		CustomRoles: []domain.CustomRole{
+			{Name: "curator", Scopes: []auth.Scope{"links:delete"}},
+		},
+	}))
+	a.Nil(err)
+
+	store := NewDefaultAuthorizationStore(bds).(auth.CustomRoleStore)
+	ctx := context.Background()
+
+	_, err = store.CustomRoles(ctx, "b-456")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	roles, err := store.CustomRoles(ctx, "b-123")
+	a.Nil(err)
+	a.Equal(map[string][]auth.Scope{"curator": {"links:delete"}}, roles)
+}
+
+func TestDefaultAuthorizationStoreBoardVisibility(t *testing.T) {
+	a := assert.New(t)
+
+	bds := inmem.NewInmemBoardDataStore()
+	for boardId, visibility := range map[string]string{"b-1": "", "b-2": domain.VisibilityPrivate, "b-3": domain.VisibilityLink, "b-4": domain.VisibilityPublic} {
+		err := bds.UpdateBoard(context.Background(), boardId, domain.NewDatastoreBoardUpdate(nil).WithBoard(domain.Board{
+			BoardId:    boardId,
+			Visibility: visibility,
+		}))
+		a.Nil(err)
+	}
+
+	store := NewDefaultAuthorizationStore(bds).(auth.BoardVisibilityStore)
+	ctx := context.Background()
+
+	for boardId, expected := range map[string]bool{"b-1": false, "b-2": false, "b-3": true, "b-4": true, "b-5": false} {
+		visible, err := store.IsBoardVisible(ctx, boardId)
+		a.Nil(err)
+		a.Equal(expected, visible, boardId)
+	}
+}
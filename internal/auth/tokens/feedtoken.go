@@ -0,0 +1,72 @@
+package tokens
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dkinzler/kit/errors"
+	fs "github.com/dkinzler/kit/firebase/firestore"
+
+	"cloud.google.com/go/firestore"
+)
+
+// In-memory implementation of auth.FeedTokenVersionStore that can be used for development/testing.
+type InmemFeedTokenVersionStore struct {
+	m        sync.RWMutex
+	versions map[string]int64
+}
+
+func NewInmemFeedTokenVersionStore() *InmemFeedTokenVersionStore {
+	return &InmemFeedTokenVersionStore{
+		versions: make(map[string]int64),
+	}
+}
+
+func (s *InmemFeedTokenVersionStore) FeedTokenVersion(ctx context.Context, userId string) (int64, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return s.versions[userId], nil
+}
+
+func (s *InmemFeedTokenVersionStore) IncrementFeedTokenVersion(ctx context.Context, userId string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.versions[userId]++
+	return nil
+}
+
+const feedTokenVersionsCollectionName = "feedTokenVersions"
+
+// Implementation of auth.FeedTokenVersionStore using Firestore, the version of a user is stored in a document with the user id as document id.
+// Users that never incremented their version don't have a document.
+type FirestoreFeedTokenVersionStore struct {
+	versionsCollection *firestore.CollectionRef
+}
+
+func NewFirestoreFeedTokenVersionStore(client *firestore.Client) *FirestoreFeedTokenVersionStore {
+	return &FirestoreFeedTokenVersionStore{
+		versionsCollection: client.Collection(feedTokenVersionsCollectionName),
+	}
+}
+
+type fsFeedTokenVersion struct {
+	Version int64 `firestore:"version"`
+}
+
+func (s *FirestoreFeedTokenVersionStore) FeedTokenVersion(ctx context.Context, userId string) (int64, error) {
+	var v fsFeedTokenVersion
+	err := fs.GetDocumentById(ctx, s.versionsCollection, userId, &v)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return v.Version, nil
+}
+
+func (s *FirestoreFeedTokenVersionStore) IncrementFeedTokenVersion(ctx context.Context, userId string) error {
+	return fs.SetDocument(ctx, s.versionsCollection, userId, map[string]interface{}{
+		"version": firestore.Increment(1),
+	}, firestore.MergeAll)
+}
@@ -0,0 +1,114 @@
+package tokens
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+
+	fs "github.com/dkinzler/kit/firebase/firestore"
+
+	"cloud.google.com/go/firestore"
+)
+
+const tokensCollectionName = "accessTokens"
+
+// Implementation of Store using Firestore, every token is stored in its own document.
+type FirestoreStore struct {
+	tokensCollection *firestore.CollectionRef
+}
+
+func NewFirestoreStore(client *firestore.Client) *FirestoreStore {
+	return &FirestoreStore{
+		tokensCollection: client.Collection(tokensCollectionName),
+	}
+}
+
+type fsAccessToken struct {
+	TokenId     string   `firestore:"tokenId"`
+	UserId      string   `firestore:"userId"`
+	UserName    string   `firestore:"userName"`
+	Name        string   `firestore:"name"`
+	CreatedTime int64    `firestore:"createdTime"`
+	ExpiresTime int64    `firestore:"expiresTime"`
+	BoardIds    []string `firestore:"boardIds"`
+	Scopes      []string `firestore:"scopes"`
+	SecretHash  []byte   `firestore:"secretHash"`
+}
+
+func toFirestoreAccessToken(t AccessToken) fsAccessToken {
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = string(s)
+	}
+	return fsAccessToken{
+		TokenId:     t.TokenId,
+		UserId:      t.User.UserId,
+		UserName:    t.User.Name,
+		Name:        t.Name,
+		CreatedTime: t.CreatedTime,
+		ExpiresTime: t.ExpiresTime,
+		BoardIds:    t.BoardIds,
+		Scopes:      scopes,
+		SecretHash:  t.SecretHash,
+	}
+}
+
+func fromFirestoreAccessToken(t fsAccessToken) AccessToken {
+	var scopes []auth.Scope
+	for _, s := range t.Scopes {
+		scopes = append(scopes, auth.Scope(s))
+	}
+	var boardIds []string
+	if len(t.BoardIds) > 0 {
+		boardIds = t.BoardIds
+	}
+	return AccessToken{
+		TokenId:     t.TokenId,
+		User:        User{UserId: t.UserId, Name: t.UserName},
+		Name:        t.Name,
+		CreatedTime: t.CreatedTime,
+		ExpiresTime: t.ExpiresTime,
+		BoardIds:    boardIds,
+		Scopes:      scopes,
+		SecretHash:  t.SecretHash,
+	}
+}
+
+func (s *FirestoreStore) CreateToken(ctx context.Context, token AccessToken) error {
+	return fs.CreateDocument(ctx, s.tokensCollection, token.TokenId, toFirestoreAccessToken(token))
+}
+
+func (s *FirestoreStore) Token(ctx context.Context, tokenId string) (AccessToken, error) {
+	var token fsAccessToken
+	err := fs.GetDocumentById(ctx, s.tokensCollection, tokenId, &token)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	return fromFirestoreAccessToken(token), nil
+}
+
+// Tokens are sorted by creation time, newest first.
+func (s *FirestoreStore) TokensForUser(ctx context.Context, userId string) ([]AccessToken, error) {
+	query := s.tokensCollection.Where("userId", "==", userId).
+		OrderBy("createdTime", firestore.Desc).
+		Limit(maxTokensPerUser)
+
+	snaps, err := fs.GetDocumentsForQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]AccessToken, len(snaps))
+	for i, snap := range snaps {
+		var token fsAccessToken
+		if err := fs.UnmarshalDocSnapshot(snap, &token); err != nil {
+			return nil, err
+		}
+		result[i] = fromFirestoreAccessToken(token)
+	}
+	return result, nil
+}
+
+func (s *FirestoreStore) DeleteToken(ctx context.Context, tokenId string) error {
+	return fs.DeleteDocument(ctx, s.tokensCollection, tokenId)
+}
@@ -0,0 +1,68 @@
+package tokens
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+// In-memory implementation of Store that can be used for development/testing.
+type InmemStore struct {
+	m      sync.RWMutex
+	tokens map[string]AccessToken
+}
+
+func NewInmemStore() *InmemStore {
+	return &InmemStore{
+		tokens: make(map[string]AccessToken),
+	}
+}
+
+func (s *InmemStore) CreateToken(ctx context.Context, token AccessToken) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, ok := s.tokens[token.TokenId]; ok {
+		return errors.New(nil, "InmemTokenStore", errors.AlreadyExists)
+	}
+	s.tokens[token.TokenId] = token
+	return nil
+}
+
+func (s *InmemStore) Token(ctx context.Context, tokenId string) (AccessToken, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	token, ok := s.tokens[tokenId]
+	if !ok {
+		return AccessToken{}, errors.New(nil, "InmemTokenStore", errors.NotFound)
+	}
+	return token, nil
+}
+
+// Tokens are sorted by creation time, newest first.
+func (s *InmemStore) TokensForUser(ctx context.Context, userId string) ([]AccessToken, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []AccessToken
+	for _, token := range s.tokens {
+		if token.User.UserId == userId {
+			result = append(result, token)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedTime > result[j].CreatedTime
+	})
+	return result, nil
+}
+
+func (s *InmemStore) DeleteToken(ctx context.Context, tokenId string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.tokens, tokenId)
+	return nil
+}
@@ -0,0 +1,266 @@
+// Package tokens implements personal access tokens, that allow users to authenticate requests without obtaining a JWT,
+// e.g. from scripts or CI jobs.
+//
+// A token can be restricted to some boards and scopes, requests authenticated with it are then only authorized
+// for the intersection of these scopes and the scopes of the user's roles (see auth.Restrictions).
+// Only a hash of the secret part of a token is stored, the token itself is returned once when it is created.
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+	"github.com/dkinzler/kit/uuid"
+)
+
+const tokenPrefix = "lbp_"
+const secretLength = 32
+
+const maxTokensPerUser = 50
+const maxNameLength = 100
+const maxBoardIds = 50
+const defaultLifetime = 90 * 24 * stdtime.Hour
+const maxLifetime = 365 * 24 * stdtime.Hour
+
+// User a token belongs to, auth.User does not define the JSON field names used in API responses.
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+type AccessToken struct {
+	TokenId string `json:"tokenId"`
+	// User the token belongs to.
+	User User `json:"user"`
+	// Describes what the token is used for.
+	Name        string `json:"name"`
+	CreatedTime int64  `json:"createdTime"`
+	ExpiresTime int64  `json:"expiresTime"`
+	// If not empty, the token can only be used for these boards.
+	BoardIds []string `json:"boardIds,omitempty"`
+	// If not empty, the token can only be used for these scopes.
+	Scopes []auth.Scope `json:"scopes,omitempty"`
+	// SHA-256 hash of the secret part of the token.
+	SecretHash []byte `json:"-"`
+}
+
+func (t AccessToken) IsExpired() bool {
+	return time.CurrTimeUnixNano() >= t.ExpiresTime
+}
+
+func (t AccessToken) Restrictions() auth.Restrictions {
+	return auth.Restrictions{
+		BoardIds: t.BoardIds,
+		Scopes:   t.Scopes,
+	}
+}
+
+type Store interface {
+	CreateToken(ctx context.Context, token AccessToken) error
+	// Returns an error with code NotFound if the token does not exist.
+	Token(ctx context.Context, tokenId string) (AccessToken, error)
+	TokensForUser(ctx context.Context, userId string) ([]AccessToken, error)
+	DeleteToken(ctx context.Context, tokenId string) error
+}
+
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+type NewToken struct {
+	Name string `json:"name"`
+	// Time at which the token expires, as Unix time in nanoseconds.
+	// Defaults to 90 days after the token was created, must not be more than 365 days after.
+	ExpiresTime int64        `json:"expiresTime"`
+	BoardIds    []string     `json:"boardIds"`
+	Scopes      []auth.Scope `json:"scopes"`
+}
+
+type CreatedToken struct {
+	AccessToken
+	// Can be used to authenticate requests using the Authorization header, i.e. "Bearer <token>".
+	// The token is not stored and cannot be obtained again.
+	Token string `json:"token"`
+}
+
+func newError(inner error, code errors.ErrorCode) errors.Error {
+	return errors.New(inner, "AccessTokens", code)
+}
+
+// Access tokens can only be managed using other authentication mechanisms,
+// otherwise a restricted token could be used to create a token without restrictions.
+func userFromContext(ctx context.Context) (auth.User, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return auth.User{}, newError(nil, errors.Unauthenticated)
+	}
+	if _, ok := auth.RestrictionsFromContext(ctx); ok {
+		return auth.User{}, newError(nil, errors.PermissionDenied).WithPublicMessage("access tokens cannot be managed using an access token")
+	}
+	return user, nil
+}
+
+// Creates a new access token for the user making the request.
+func (s *Service) CreateToken(ctx context.Context, nt NewToken) (CreatedToken, error) {
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return CreatedToken{}, err
+	}
+
+	now := time.CurrTime()
+	expiresTime, err := validateNewToken(nt, now)
+	if err != nil {
+		return CreatedToken{}, err
+	}
+
+	existing, err := s.store.TokensForUser(ctx, user.UserId)
+	if err != nil {
+		return CreatedToken{}, err
+	}
+	if len(existing) >= maxTokensPerUser {
+		return CreatedToken{}, newError(nil, errors.FailedPrecondition).WithPublicMessage("too many access tokens")
+	}
+
+	tokenId, err := uuid.NewUUIDWithPrefix("t")
+	if err != nil {
+		return CreatedToken{}, newError(err, errors.Internal).WithInternalMessage("error creating uuid")
+	}
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return CreatedToken{}, newError(err, errors.Internal).WithInternalMessage("error creating secret")
+	}
+
+	token := AccessToken{
+		TokenId:     tokenId,
+		User:        User(user),
+		Name:        nt.Name,
+		CreatedTime: now.UnixNano(),
+		ExpiresTime: expiresTime,
+		BoardIds:    nt.BoardIds,
+		Scopes:      nt.Scopes,
+		SecretHash:  hashSecret(secret),
+	}
+	if err := s.store.CreateToken(ctx, token); err != nil {
+		return CreatedToken{}, err
+	}
+
+	return CreatedToken{
+		AccessToken: token,
+		Token:       tokenPrefix + tokenId + "." + base64.RawURLEncoding.EncodeToString(secret),
+	}, nil
+}
+
+// Returns the expiry time of the token.
+func validateNewToken(nt NewToken, now stdtime.Time) (int64, error) {
+	if nt.Name == "" || len(nt.Name) > maxNameLength {
+		return 0, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid name")
+	}
+
+	expiresTime := nt.ExpiresTime
+	if expiresTime == 0 {
+		expiresTime = now.Add(defaultLifetime).UnixNano()
+	}
+	if expiresTime <= now.UnixNano() || expiresTime > now.Add(maxLifetime).UnixNano() {
+		return 0, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid expiry time")
+	}
+
+	if len(nt.BoardIds) > maxBoardIds {
+		return 0, newError(nil, errors.InvalidArgument).WithPublicMessage("too many boards")
+	}
+	for _, boardId := range nt.BoardIds {
+		if boardId == "" {
+			return 0, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid board id")
+		}
+	}
+	for _, scope := range nt.Scopes {
+		if !auth.IsScopeRegistered(scope) {
+			return 0, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid scope: " + string(scope))
+		}
+	}
+	return expiresTime, nil
+}
+
+// Returns the access tokens of the user making the request, including expired ones.
+func (s *Service) Tokens(ctx context.Context) ([]AccessToken, error) {
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.TokensForUser(ctx, user.UserId)
+}
+
+// Deletes an access token of the user making the request, it can no longer be used to authenticate requests.
+func (s *Service) RevokeToken(ctx context.Context, tokenId string) error {
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.store.Token(ctx, tokenId)
+	if err != nil {
+		return err
+	}
+	// Don't reveal that the token exists.
+	if token.User.UserId != user.UserId {
+		return newError(nil, errors.NotFound)
+	}
+	return s.store.DeleteToken(ctx, tokenId)
+}
+
+// Returns true if the given string looks like an access token, i.e. has the correct prefix.
+// Can be used to decide which authentication mechanism to use for a bearer token.
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, tokenPrefix)
+}
+
+// Returns the access token for the given token string.
+// An error with code Unauthenticated is returned if the token is invalid, was revoked or is expired.
+func (s *Service) Authenticate(ctx context.Context, token string) (AccessToken, error) {
+	invalidTokenError := newError(nil, errors.Unauthenticated).WithPublicMessage("invalid access token")
+
+	if !IsAccessToken(token) {
+		return AccessToken{}, invalidTokenError
+	}
+	tokenId, encodedSecret, ok := strings.Cut(token[len(tokenPrefix):], ".")
+	if !ok || tokenId == "" {
+		return AccessToken{}, invalidTokenError
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(encodedSecret)
+	if err != nil || len(secret) != secretLength {
+		return AccessToken{}, invalidTokenError
+	}
+
+	at, err := s.store.Token(ctx, tokenId)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return AccessToken{}, invalidTokenError
+		}
+		return AccessToken{}, err
+	}
+	if subtle.ConstantTimeCompare(at.SecretHash, hashSecret(secret)) != 1 {
+		return AccessToken{}, invalidTokenError
+	}
+	if at.IsExpired() {
+		return AccessToken{}, newError(nil, errors.Unauthenticated).WithPublicMessage("access token expired")
+	}
+	return at, nil
+}
+
+// The secret is random and long enough, a fast hash function is sufficient.
+func hashSecret(secret []byte) []byte {
+	h := sha256.Sum256(secret)
+	return h[:]
+}
@@ -0,0 +1,187 @@
+package tokens
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	auth.RegisterScopes("links:create", "links:query")
+}
+
+var testUser = auth.User{UserId: "u-123", Name: "Peter"}
+
+func userContext(user auth.User) context.Context {
+	return auth.ContextWithUser(context.Background(), user)
+}
+
+func TestCreateAndAuthenticateToken(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewService(NewInmemStore())
+	ctx := userContext(testUser)
+
+	created, err := s.CreateToken(ctx, NewToken{
+		Name:     "ci",
+		BoardIds: []string{"b-123"},
+		Scopes:   []auth.Scope{"links:create"},
+	})
+	a.Nil(err)
+	a.True(strings.HasPrefix(created.Token, "lbp_"))
+	a.True(IsAccessToken(created.Token))
+	a.Equal(User(testUser), created.User)
+	a.NotEmpty(created.TokenId)
+	// default expiry
+	a.InDelta(time.CurrTime().Add(defaultLifetime).UnixNano(), created.ExpiresTime, float64(stdtime.Minute))
+
+	at, err := s.Authenticate(context.Background(), created.Token)
+	a.Nil(err)
+	a.Equal(created.AccessToken, at)
+	a.Equal(auth.Restrictions{BoardIds: []string{"b-123"}, Scopes: []auth.Scope{"links:create"}}, at.Restrictions())
+
+	// only a hash of the secret is stored
+	stored, err := s.store.Token(context.Background(), created.TokenId)
+	a.Nil(err)
+	secret, err := base64.RawURLEncoding.DecodeString(created.Token[strings.Index(created.Token, ".")+1:])
+	a.Nil(err)
+	a.Equal(hashSecret(secret), stored.SecretHash)
+	a.NotEqual(secret, stored.SecretHash)
+
+	// tampered or malformed tokens are not valid
+	other, err := s.CreateToken(ctx, NewToken{Name: "other"})
+	a.Nil(err)
+	invalidTokens := []string{
+		"",
+		"lbp_",
+		"lbp_.",
+		"lbp_" + created.TokenId,
+		created.Token[:len(created.Token)-2],
+		"lbp_" + created.TokenId + other.Token[strings.Index(other.Token, "."):],
+		"lbp_t-doesnotexist" + created.Token[strings.Index(created.Token, "."):],
+	}
+	for _, it := range invalidTokens {
+		_, err = s.Authenticate(context.Background(), it)
+		a.NotNil(err, it)
+		a.True(errors.IsUnauthenticatedError(err))
+	}
+}
+
+func TestExpiredTokensAreInvalid(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewService(NewInmemStore())
+	now := stdtime.Now()
+	created, err := s.CreateToken(userContext(testUser), NewToken{
+		Name:        "ci",
+		ExpiresTime: now.Add(stdtime.Hour).UnixNano(),
+	})
+	a.Nil(err)
+
+	_, err = s.Authenticate(context.Background(), created.Token)
+	a.Nil(err)
+
+	time.TimeFunc = func() stdtime.Time {
+		return now.Add(2 * stdtime.Hour)
+	}
+	defer func() { time.TimeFunc = stdtime.Now }()
+
+	_, err = s.Authenticate(context.Background(), created.Token)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+}
+
+func TestCreateTokenValidation(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewService(NewInmemStore())
+	ctx := userContext(testUser)
+	now := stdtime.Now()
+
+	_, err := s.CreateToken(context.Background(), NewToken{Name: "ci"})
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	invalid := []NewToken{
+		{},
+		{Name: strings.Repeat("a", maxNameLength+1)},
+		{Name: "ci", ExpiresTime: now.Add(-stdtime.Hour).UnixNano()},
+		{Name: "ci", ExpiresTime: now.Add(maxLifetime + stdtime.Hour).UnixNano()},
+		{Name: "ci", BoardIds: []string{""}},
+		{Name: "ci", Scopes: []auth.Scope{"notascope"}},
+	}
+	for _, nt := range invalid {
+		_, err := s.CreateToken(ctx, nt)
+		a.NotNil(err)
+		a.True(errors.IsInvalidArgumentError(err), nt)
+	}
+
+	for i := 0; i < maxTokensPerUser; i++ {
+		_, err := s.CreateToken(ctx, NewToken{Name: "ci"})
+		a.Nil(err)
+	}
+	_, err = s.CreateToken(ctx, NewToken{Name: "ci"})
+	a.NotNil(err)
+}
+
+func TestListAndRevokeTokens(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewService(NewInmemStore())
+	ctx := userContext(testUser)
+	otherCtx := userContext(auth.User{UserId: "u-456"})
+
+	t1, err := s.CreateToken(ctx, NewToken{Name: "t1"})
+	a.Nil(err)
+	t2, err := s.CreateToken(ctx, NewToken{Name: "t2"})
+	a.Nil(err)
+	_, err = s.CreateToken(otherCtx, NewToken{Name: "t3"})
+	a.Nil(err)
+
+	tokens, err := s.Tokens(ctx)
+	a.Nil(err)
+	a.ElementsMatch([]AccessToken{t1.AccessToken, t2.AccessToken}, tokens)
+
+	// users cannot revoke tokens of other users
+	err = s.RevokeToken(otherCtx, t1.TokenId)
+	a.NotNil(err)
+	a.True(errors.IsNotFoundError(err))
+
+	err = s.RevokeToken(ctx, t1.TokenId)
+	a.Nil(err)
+	_, err = s.Authenticate(context.Background(), t1.Token)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	tokens, err = s.Tokens(ctx)
+	a.Nil(err)
+	a.Equal([]AccessToken{t2.AccessToken}, tokens)
+}
+
+func TestTokensCannotBeManagedUsingAccessTokens(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewService(NewInmemStore())
+	ctx := auth.ContextWithRestrictions(userContext(testUser), auth.Restrictions{})
+
+	_, err := s.CreateToken(ctx, NewToken{Name: "ci"})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = s.Tokens(ctx)
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	err = s.RevokeToken(ctx, "t-123")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+}
@@ -0,0 +1,118 @@
+package tokens
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dkinzler/linkboards/internal/endpointname"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	e "github.com/dkinzler/kit/endpoint"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type HttpConfig struct {
+	// Middlewares that should be applied to all endpoints.
+	// They run after the authentication middleware and can get the name of the endpoint (e.g. "createToken") using package endpointname.
+	Middlewares []endpoint.Middleware
+	// Authentication middleware for the endpoints.
+	AuthMiddleware endpoint.Middleware
+	// If not nil, metrics are recorded for all endpoints.
+	Metrics *metrics.Metrics
+	// If not nil, spans are created for all endpoints, see package tracing.
+	TracerProvider trace.TracerProvider
+}
+
+func (c HttpConfig) buildMiddlewares(endpointName string) []endpoint.Middleware {
+	var mws []endpoint.Middleware
+	mws = append(mws, c.Middlewares...)
+	if c.AuthMiddleware != nil {
+		mws = append(mws, c.AuthMiddleware)
+	}
+	if c.Metrics != nil {
+		mws = append(mws, c.Metrics.EndpointMiddleware("tokens", endpointName))
+	}
+	if c.TracerProvider != nil {
+		mws = append(mws, tracing.EndpointMiddleware(c.TracerProvider, "tokens", endpointName))
+	}
+	// outermost middleware, makes the endpoint name available to all other middlewares
+	mws = append(mws, endpointname.Middleware(endpointName))
+	return mws
+}
+
+type RevokeTokenRequest struct {
+	TokenId string
+}
+
+func MakeCreateTokenEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(NewToken)
+		token, err := s.CreateToken(ctx, req)
+		return e.Response{
+			Err: err,
+			R:   token,
+		}, nil
+	}
+}
+
+func MakeTokensEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		tokens, err := s.Tokens(ctx)
+		return e.Response{
+			Err: err,
+			R:   tokens,
+		}, nil
+	}
+}
+
+func MakeRevokeTokenEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RevokeTokenRequest)
+		err := s.RevokeToken(ctx, req.TokenId)
+		return e.Response{
+			Err: err,
+		}, nil
+	}
+}
+
+func init() {
+	endpointname.Register("createToken", "getTokens", "revokeToken")
+}
+
+// Registers the handlers for "/me/tokens", that let users create, list and revoke their access tokens.
+func RegisterHttpHandlers(router *mux.Router, s *Service, config HttpConfig, opts []kithttp.ServerOption) {
+	createTokenHandler := kithttp.NewServer(e.ApplyMiddlewares(MakeCreateTokenEndpoint(s), config.buildMiddlewares("createToken")...), decodeHttpCreateTokenRequest, t.MakeGenericJSONEncodeFunc(201), opts...)
+	router.Handle("/me/tokens", createTokenHandler).Methods("POST", "OPTIONS")
+
+	tokensHandler := kithttp.NewServer(e.ApplyMiddlewares(MakeTokensEndpoint(s), config.buildMiddlewares("getTokens")...), decodeHttpTokensRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/me/tokens", tokensHandler).Methods("GET", "OPTIONS")
+
+	revokeTokenHandler := kithttp.NewServer(e.ApplyMiddlewares(MakeRevokeTokenEndpoint(s), config.buildMiddlewares("revokeToken")...), decodeHttpRevokeTokenRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/me/tokens/{tokenId}", revokeTokenHandler).Methods("DELETE", "OPTIONS")
+}
+
+func decodeHttpCreateTokenRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req NewToken
+	if err := t.DecodeJSONBody(r, &req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeHttpTokensRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func decodeHttpRevokeTokenRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	tokenId, err := t.DecodeURLParameter(r, "tokenId")
+	if err != nil {
+		return nil, err
+	}
+	return RevokeTokenRequest{TokenId: tokenId}, nil
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"sort"
+	stdtime "time"
 
 	"github.com/dkinzler/linkboards/internal/auth"
 	"github.com/dkinzler/linkboards/internal/boards/domain"
@@ -31,6 +32,7 @@ type BoardApplicationService interface {
 	//	"httpParams": ["url"],
 	//	"endpoints": [{"http": {"path":"/boards/{boardId}", "method":"GET"}}]
 	// }
+	// Can be used without authentication for boards that are visible to non-members.
 	Board(ctx context.Context, boardId string) (BoardWithUsersAndInvites, error)
 	// @Kit{
 	//	"httpParams": ["query"],
@@ -39,6 +41,12 @@ type BoardApplicationService interface {
 	// Return boards the user making the request is part of.
 	Boards(ctx context.Context, qp QueryParams) ([]Board, error)
 	// @Kit{
+	//	"httpParams": ["query"],
+	//	"endpoints": [{"http": {"path":"/public/boards", "method":"GET"}}]
+	// }
+	// Return boards with visibility "public", can be used without authentication.
+	PublicBoards(ctx context.Context, qp QueryParams) ([]Board, error)
+	// @Kit{
 	//	"httpParams": ["url", "json"],
 	//	"endpoints": [{"http": {"path":"/boards/{boardId}/invites", "method":"POST", "successCode": 201}}]
 	// }
@@ -61,6 +69,23 @@ type BoardApplicationService interface {
 	// Return invites for the user making the request.
 	Invites(ctx context.Context, qp QueryParams) ([]Invite, error)
 	// @Kit{
+	//	"httpParams": ["url"],
+	//	"endpoints": [{"http": {"path":"/boards/{boardId}/joinRequests", "method":"POST", "successCode": 201}}]
+	// }
+	// Creates a request of the user making the request to join the board.
+	CreateJoinRequest(ctx context.Context, boardId string) (JoinRequest, error)
+	// @Kit{
+	//	"httpParams": ["url"],
+	//	"endpoints": [{"http": {"path":"/boards/{boardId}/joinRequests", "method":"GET"}}]
+	// }
+	JoinRequests(ctx context.Context, boardId string) ([]JoinRequest, error)
+	// @Kit{
+	//	"httpParams": ["url", "url", "json"],
+	//	"endpoints": [{"http": {"path":"/boards/{boardId}/joinRequests/{joinRequestId}", "method":"POST"}}]
+	// }
+	// Used to either approve or reject a join request
+	RespondToJoinRequest(ctx context.Context, boardId string, joinRequestId string, jrr JoinRequestResponse) error
+	// @Kit{
 	//	"httpParams": ["url", "url"],
 	//	"endpoints": [{"http": {"path":"/boards/{boardId}/users/{userId}", "method":"DELETE"}}]
 	// }
@@ -70,9 +95,35 @@ type BoardApplicationService interface {
 	//	"endpoints": [{"http": {"path":"/boards/{boardId}/users/{userId}", "method":"PATCH"}}]
 	// }
 	EditBoardUser(ctx context.Context, boardId string, userId string, bue BoardUserEdit) (BoardUser, error)
-	// We don't actually expose this method, it is there to demonstrate how we can use go concurrency
-	// in service methods that assemble different pieces of data.
-	BoardsAndInvites(ctx context.Context) (BoardsAndInvites, error)
+	// @Kit{
+	//	"httpParams": ["url", "url", "json"],
+	//	"endpoints": [{"http": {"path":"/boards/{boardId}/roles/{role}", "method":"PUT"}}]
+	// }
+	// Creates a custom role or replaces the scopes of an existing one.
+	SetCustomRole(ctx context.Context, boardId string, role string, crs CustomRoleScopes) (Board, error)
+	// @Kit{
+	//	"httpParams": ["url", "url"],
+	//	"endpoints": [{"http": {"path":"/boards/{boardId}/roles/{role}", "method":"DELETE"}}]
+	// }
+	DeleteCustomRole(ctx context.Context, boardId string, role string) (Board, error)
+	// Returns both boards and invites for the user making the request, used by the dashboard (see package internal/dashboard).
+	// Boards and invites are loaded concurrently, if one of them cannot be loaded within sectionTimeout,
+	// the result contains the other one together with an error message.
+	BoardsAndInvites(ctx context.Context, sectionTimeout stdtime.Duration) (BoardsAndInvites, error)
+	// Returns the number of users of a board.
+	// Unlike the users themselves, the number of users is available to every user that can view the board.
+	UserCount(ctx context.Context, boardId string) (int, error)
+	// Returns the boards with the given ids that the user making the request is a member of, has been invited to
+	// or that are visible to non-members, boards that don't exist or that the user is not allowed to view are left out.
+	// Boards are loaded from the data store with a single call, which makes this method useful to batch board lookups,
+	// e.g. to resolve the boards of invites in a GraphQL query.
+	BoardsById(ctx context.Context, boardIds []string) (map[string]Board, error)
+}
+
+// Users are part of API responses, converted from domain.User to keep the JSON field names out of the domain layer.
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
 }
 
 type NewBoard struct {
@@ -85,11 +136,41 @@ type Board struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 
-	CreatedTime int64       `json:"createdTime"`
-	CreatedBy   domain.User `json:"createdBy"`
+	CreatedTime int64 `json:"createdTime"`
+	CreatedBy   User  `json:"createdBy"`
+
+	ModifiedTime int64 `json:"modifiedTime,omitempty"`
+	ModifiedBy   User  `json:"modifiedBy,omitempty"`
+
+	// One of "private", "link" and "public", determines who can view the board besides its members.
+	Visibility string `json:"visibility,omitempty"`
+
+	CustomRoles []CustomRole `json:"customRoles,omitempty"`
+}
+
+// A role defined by the owner of a board, that grants the given scopes.
+// Custom roles can be used like the built-in roles when inviting users or changing the role of a user.
+type CustomRole struct {
+	Name   string       `json:"name"`
+	Scopes []auth.Scope `json:"scopes"`
+}
+
+func customRolesFromDomainCustomRoles(roles []domain.CustomRole) []CustomRole {
+	if len(roles) == 0 {
+		return nil
+	}
+	result := make([]CustomRole, len(roles))
+	for i, role := range roles {
+		result[i] = CustomRole{
+			Name:   role.Name,
+			Scopes: role.Scopes,
+		}
+	}
+	return result
+}
 
-	ModifiedTime int64       `json:"modifiedTime,omitempty"`
-	ModifiedBy   domain.User `json:"modifiedBy,omitempty"`
+type CustomRoleScopes struct {
+	Scopes []auth.Scope `json:"scopes"`
 }
 
 type BoardEdit struct {
@@ -103,10 +184,12 @@ type BoardEdit struct {
 	// With pointers we don't have this problem. They will be nil if the user didn't provide a value.
 	Name        *string `json:"name"`
 	Description *string `json:"description"`
+	// Changing the visibility requires an additional scope, that only the owner of a board has.
+	Visibility *string `json:"visibility"`
 }
 
 func (b BoardEdit) IsEmpty() bool {
-	return b.Name == nil && b.Description == nil
+	return b.Name == nil && b.Description == nil && b.Visibility == nil
 }
 
 func (b BoardEdit) ToDomainBoardEdit() domain.BoardEdit {
@@ -122,6 +205,11 @@ func (b BoardEdit) ToDomainBoardEdit() domain.BoardEdit {
 		result.Description = *b.Description
 	}
 
+	if b.Visibility != nil {
+		result.UpdateVisibility = true
+		result.Visibility = *b.Visibility
+	}
+
 	return result
 }
 
@@ -130,36 +218,41 @@ type BoardWithUsersAndInvites struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 
-	CreatedTime int64       `json:"createdTime"`
-	CreatedBy   domain.User `json:"createdBy"`
+	CreatedTime int64 `json:"createdTime"`
+	CreatedBy   User  `json:"createdBy"`
 
-	ModifiedTime int64       `json:"modifiedTime,omitempty"`
-	ModifiedBy   domain.User `json:"modifiedBy,omitempty"`
+	ModifiedTime int64 `json:"modifiedTime,omitempty"`
+	ModifiedBy   User  `json:"modifiedBy,omitempty"`
+
+	Visibility string `json:"visibility,omitempty"`
+
+	CustomRoles []CustomRole `json:"customRoles,omitempty"`
 
 	// should only be included if user has required authorization
-	Users   []BoardUser `json:"users"`
-	Invites []Invite    `json:"invites"`
+	Users        []BoardUser   `json:"users"`
+	Invites      []Invite      `json:"invites"`
+	JoinRequests []JoinRequest `json:"joinRequests,omitempty"`
 }
 
 type BoardUser struct {
-	User domain.User `json:"user"`
-	Role string      `json:"role"`
+	User User   `json:"user"`
+	Role string `json:"role"`
 
-	CreatedTime int64       `json:"createdTime"`
-	InvitedBy   domain.User `json:"invitedBy"`
+	CreatedTime int64 `json:"createdTime"`
+	InvitedBy   User  `json:"invitedBy"`
 
-	ModifiedTime int64       `json:"modifiedTime"`
-	ModifiedBy   domain.User `json:"modifiedBy"`
+	ModifiedTime int64 `json:"modifiedTime"`
+	ModifiedBy   User  `json:"modifiedBy"`
 }
 
 func userFromDomainUser(u domain.BoardUser) BoardUser {
 	return BoardUser{
-		User:         u.User,
+		User:         User(u.User),
 		Role:         u.Role,
 		CreatedTime:  u.CreatedTime,
-		InvitedBy:    u.InvitedBy,
+		InvitedBy:    User(u.InvitedBy),
 		ModifiedTime: u.ModifiedTime,
-		ModifiedBy:   u.ModifiedBy,
+		ModifiedBy:   User(u.ModifiedBy),
 	}
 }
 
@@ -190,19 +283,19 @@ func (b BoardUserEdit) ToDomainBoardUserEdit() domain.BoardUserEdit {
 
 type NewInvite struct {
 	// can be empty
-	User domain.User `json:"user"`
-	Role string      `json:"role"`
+	User User   `json:"user"`
+	Role string `json:"role"`
 }
 
 type Invite struct {
 	BoardId  string `json:"boardId,omitempty"`
 	InviteId string `json:"inviteId"`
 
-	Role string      `json:"role"`
-	User domain.User `json:"user"`
+	Role string `json:"role"`
+	User User   `json:"user"`
 
-	CreatedTime int64       `json:"createdTime"`
-	CreatedBy   domain.User `json:"createdBy"`
+	CreatedTime int64 `json:"createdTime"`
+	CreatedBy   User  `json:"createdBy"`
 
 	ExpiresTime int64 `json:"expiresTime"`
 }
@@ -215,6 +308,40 @@ type InviteResponse struct {
 	Response string `json:"response"`
 }
 
+type JoinRequest struct {
+	BoardId       string `json:"boardId,omitempty"`
+	JoinRequestId string `json:"joinRequestId"`
+
+	User        User  `json:"user"`
+	CreatedTime int64 `json:"createdTime"`
+}
+
+func joinRequestsFromDomainJoinRequests(joinRequests []domain.BoardJoinRequest) []JoinRequest {
+	result := make([]JoinRequest, len(joinRequests))
+	for i, jr := range joinRequests {
+		result[i] = JoinRequest{
+			JoinRequestId: jr.JoinRequestId,
+			User:          User(jr.User),
+			CreatedTime:   jr.CreatedTime,
+		}
+	}
+	// sort result from newest to oldest
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedTime > result[j].CreatedTime
+	})
+	return result
+}
+
+const joinRequestResponseApprove = "approve"
+const joinRequestResponseReject = "reject"
+
+type JoinRequestResponse struct {
+	// Accepted values are "approve" and "reject"
+	Response string `json:"response"`
+	// Role the user is added to the board with if the join request is approved, defaults to viewer.
+	Role string `json:"role,omitempty"`
+}
+
 type QueryParams struct {
 	// Max number of results to return
 	Limit int
@@ -230,15 +357,20 @@ type BoardsAndInvites struct {
 	InvitesError string   `json:"invitesError,omitempty"`
 }
 
+// Max number of boards that can be requested at once using BoardsById.
+const maxBoardsById = 100
+
 type boardApplicationService struct {
 	boardService   *domain.BoardService
 	boardDataStore domain.BoardDataStore
 	authChecker    *auth.BoardAuthorizationChecker
 }
 
-func NewBoardApplicationService(boardDataStore domain.BoardDataStore, authorizationStore auth.AuthorizationStore) BoardApplicationService {
+// The EventPublisher can be nil, in which case events are not published.
+// Zero values of limits are replaced by the values of domain.DefaultLimits.
+func NewBoardApplicationService(boardDataStore domain.BoardDataStore, authorizationStore auth.AuthorizationStore, eventPublisher domain.EventPublisher, limits domain.LimitsConfig, quotas domain.QuotaStore) BoardApplicationService {
 	return &boardApplicationService{
-		boardService:   domain.NewBoardService(boardDataStore, nil),
+		boardService:   domain.NewBoardService(boardDataStore, eventPublisher, limits, quotas),
 		boardDataStore: boardDataStore,
 		authChecker:    NewAuthorizationChecker(authorizationStore),
 	}
@@ -262,9 +394,8 @@ func (bas *boardApplicationService) CreateBoard(ctx context.Context, nb NewBoard
 		return BoardWithUsersAndInvites{}, newUnauthenticatedError()
 	}
 
-	// This isn't really necessary since right now the scopes an authenticated user has are fixed.
-	// However in the future it might be possible to configure/change the scopes of authenticated users.
-	if !authenticatedScopes.HasScope(createBoardScope) {
+	// The scopes of authenticated users are fixed, but they can be restricted, e.g. if the request was authenticated using an access token.
+	if !authenticatedAuthorization(ctx).HasScope(createBoardScope) {
 		return BoardWithUsersAndInvites{}, newPermissionDeniedError()
 	}
 
@@ -279,9 +410,10 @@ func (bas *boardApplicationService) CreateBoard(ctx context.Context, nb NewBoard
 		Name:         board.Name,
 		Description:  board.Description,
 		CreatedTime:  board.CreatedTime,
-		CreatedBy:    board.CreatedBy,
+		CreatedBy:    User(board.CreatedBy),
 		ModifiedTime: board.ModifiedTime,
-		ModifiedBy:   board.ModifiedBy,
+		ModifiedBy:   User(board.ModifiedBy),
+		Visibility:   board.EffectiveVisibility(),
 		Users:        usersFromDomainUsers(boardWithOwner.Users),
 	}, nil
 }
@@ -321,6 +453,9 @@ func (bas *boardApplicationService) EditBoard(ctx context.Context, boardId strin
 	if !az.HasScope(editBoardScope) {
 		return Board{}, newPermissionDeniedError()
 	}
+	if be.Visibility != nil && !az.HasScope(editBoardVisibilityScope) {
+		return Board{}, newPermissionDeniedError()
+	}
 
 	if be.IsEmpty() {
 		return Board{}, newServiceError(nil, errors.InvalidArgument).WithPublicMessage("empty update")
@@ -331,25 +466,18 @@ func (bas *boardApplicationService) EditBoard(ctx context.Context, boardId strin
 		return Board{}, err
 	}
 
-	return Board{
-		BoardId:      boardId,
-		Name:         board.Name,
-		Description:  board.Description,
-		CreatedTime:  board.CreatedTime,
-		CreatedBy:    board.CreatedBy,
-		ModifiedTime: board.ModifiedTime,
-		ModifiedBy:   board.ModifiedBy,
-	}, nil
+	return boardFromDomainBoard(board), nil
 }
 
 func (bas *boardApplicationService) Board(ctx context.Context, boardId string) (BoardWithUsersAndInvites, error) {
+	// Unauthenticated users can view boards that are visible to non-members.
 	user, ok := userFromContext(ctx)
-	if !ok {
-		return BoardWithUsersAndInvites{}, newUnauthenticatedError()
-	}
 
 	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
 	if err != nil {
+		if !ok && errors.IsPermissionDeniedError(err) {
+			return BoardWithUsersAndInvites{}, newUnauthenticatedError()
+		}
 		return BoardWithUsersAndInvites{}, err
 	}
 	if !az.HasScope(viewBoardScope) {
@@ -368,24 +496,27 @@ func (bas *boardApplicationService) Board(ctx context.Context, boardId string) (
 		Name:        board.Name,
 		Description: board.Description,
 		CreatedTime: board.CreatedTime,
-		CreatedBy:   board.CreatedBy,
+		CreatedBy:   User(board.CreatedBy),
+		Visibility:  board.EffectiveVisibility(),
 	}
 
 	if az.HasScope(editBoardScope) {
 		result.ModifiedTime = board.ModifiedTime
-		result.ModifiedBy = board.ModifiedBy
+		result.ModifiedBy = User(board.ModifiedBy)
 	}
 
 	if az.HasScope(viewBoardUsersScope) {
+		result.CustomRoles = customRolesFromDomainCustomRoles(board.CustomRoles)
+
 		boardUsers := make([]BoardUser, len(b.Users))
 		for i, u := range b.Users {
 			boardUsers[i] = BoardUser{
-				User:         u.User,
+				User:         User(u.User),
 				Role:         u.Role,
 				CreatedTime:  u.CreatedTime,
-				InvitedBy:    u.InvitedBy,
+				InvitedBy:    User(u.InvitedBy),
 				ModifiedTime: u.ModifiedTime,
-				ModifiedBy:   u.ModifiedBy,
+				ModifiedBy:   User(u.ModifiedBy),
 			}
 		}
 		result.Users = boardUsers
@@ -397,15 +528,20 @@ func (bas *boardApplicationService) Board(ctx context.Context, boardId string) (
 			boardInvites[i] = Invite{
 				InviteId:    inv.InviteId,
 				Role:        inv.Role,
-				User:        inv.User,
+				User:        User(inv.User),
 				CreatedTime: inv.CreatedTime,
-				CreatedBy:   inv.CreatedBy,
+				CreatedBy:   User(inv.CreatedBy),
 				ExpiresTime: inv.ExpiresTime,
 			}
 		}
 		result.Invites = boardInvites
 	}
 
+	// join requests are only relevant to users that can add other users to the board
+	if az.HasScope(createInviteScope) {
+		result.JoinRequests = joinRequestsFromDomainJoinRequests(b.JoinRequests)
+	}
+
 	return result, nil
 }
 
@@ -415,7 +551,7 @@ func (bas *boardApplicationService) Boards(ctx context.Context, qp QueryParams)
 		return nil, newUnauthenticatedError()
 	}
 
-	if !authenticatedScopes.HasScope(listUserBoardsScope) {
+	if !authenticatedAuthorization(ctx).HasScope(listUserBoardsScope) {
 		return nil, newPermissionDeniedError()
 	}
 
@@ -432,6 +568,28 @@ func (bas *boardApplicationService) Boards(ctx context.Context, qp QueryParams)
 		return nil, err
 	}
 
+	return boardListFromDomainBoards(boards), nil
+}
+
+func (bas *boardApplicationService) PublicBoards(ctx context.Context, qp QueryParams) ([]Board, error) {
+	dqp := domain.NewQueryParams()
+	if qp.Limit != 0 {
+		dqp = dqp.WithLimit(qp.Limit)
+	}
+	if qp.Cursor != 0 {
+		dqp = dqp.WithCursor(qp.Cursor)
+	}
+
+	boards, err := bas.boardDataStore.PublicBoards(ctx, dqp)
+	if err != nil {
+		return nil, err
+	}
+
+	return boardListFromDomainBoards(boards), nil
+}
+
+// Only contains the fields of a board that are shown in lists of boards.
+func boardListFromDomainBoards(boards []domain.Board) []Board {
 	result := make([]Board, len(boards))
 	for i, b := range boards {
 		result[i] = Board{
@@ -439,10 +597,11 @@ func (bas *boardApplicationService) Boards(ctx context.Context, qp QueryParams)
 			Name:        b.Name,
 			Description: b.Description,
 			CreatedTime: b.CreatedTime,
-			CreatedBy:   b.CreatedBy,
+			CreatedBy:   User(b.CreatedBy),
+			Visibility:  b.EffectiveVisibility(),
 		}
 	}
-	return result, nil
+	return result
 }
 
 func (bas *boardApplicationService) CreateInvite(ctx context.Context, boardId string, ni NewInvite) (Invite, error) {
@@ -459,7 +618,7 @@ func (bas *boardApplicationService) CreateInvite(ctx context.Context, boardId st
 		return Invite{}, newPermissionDeniedError()
 	}
 
-	invite, err := bas.boardService.CreateInvite(ctx, boardId, ni.Role, ni.User, toDomainUser(user))
+	invite, err := bas.boardService.CreateInvite(ctx, boardId, ni.Role, domain.User(ni.User), toDomainUser(user))
 	if err != nil {
 		return Invite{}, err
 	}
@@ -468,9 +627,9 @@ func (bas *boardApplicationService) CreateInvite(ctx context.Context, boardId st
 		BoardId:     boardId,
 		InviteId:    invite.InviteId,
 		Role:        invite.Role,
-		User:        invite.User,
+		User:        User(invite.User),
 		CreatedTime: invite.CreatedTime,
-		CreatedBy:   invite.CreatedBy,
+		CreatedBy:   User(invite.CreatedBy),
 		ExpiresTime: invite.ExpiresTime,
 	}, nil
 }
@@ -481,7 +640,7 @@ func (bas *boardApplicationService) RespondToInvite(ctx context.Context, boardId
 		return newUnauthenticatedError()
 	}
 
-	if !authenticatedScopes.HasScope(respondToInviteScope) {
+	if !authenticatedAuthorization(ctx).HasScope(respondToInviteScope) {
 		return newPermissionDeniedError()
 	}
 
@@ -522,7 +681,7 @@ func (bas *boardApplicationService) Invites(ctx context.Context, qp QueryParams)
 		return nil, newUnauthenticatedError()
 	}
 
-	if !authenticatedScopes.HasScope(listUserInvitesScope) {
+	if !authenticatedAuthorization(ctx).HasScope(listUserInvitesScope) {
 		return nil, newPermissionDeniedError()
 	}
 
@@ -545,9 +704,9 @@ func (bas *boardApplicationService) Invites(ctx context.Context, qp QueryParams)
 			BoardId:     boardId,
 			InviteId:    invite.InviteId,
 			Role:        invite.Role,
-			User:        invite.User,
+			User:        User(invite.User),
 			CreatedTime: invite.CreatedTime,
-			CreatedBy:   invite.CreatedBy,
+			CreatedBy:   User(invite.CreatedBy),
 			ExpiresTime: invite.ExpiresTime,
 		})
 	}
@@ -563,6 +722,90 @@ func (bas *boardApplicationService) Invites(ctx context.Context, qp QueryParams)
 	return result, nil
 }
 
+func (bas *boardApplicationService) CreateJoinRequest(ctx context.Context, boardId string) (JoinRequest, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return JoinRequest{}, newUnauthenticatedError()
+	}
+
+	if !authenticatedAuthorization(ctx).HasScope(requestToJoinScope) {
+		return JoinRequest{}, newPermissionDeniedError()
+	}
+
+	// Users can only request to join boards they can view, i.e. boards that are visible to non-members.
+	// Otherwise join requests would reveal that a private board exists
+	// and could be used to fill up the board with requests of arbitrary users.
+	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return JoinRequest{}, err
+	}
+	if !az.HasScope(viewBoardScope) {
+		return JoinRequest{}, newPermissionDeniedError()
+	}
+
+	jr, err := bas.boardService.CreateJoinRequest(ctx, boardId, toDomainUser(user))
+	if err != nil {
+		return JoinRequest{}, err
+	}
+
+	return JoinRequest{
+		BoardId:       boardId,
+		JoinRequestId: jr.JoinRequestId,
+		User:          User(jr.User),
+		CreatedTime:   jr.CreatedTime,
+	}, nil
+}
+
+func (bas *boardApplicationService) JoinRequests(ctx context.Context, boardId string) ([]JoinRequest, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return nil, newUnauthenticatedError()
+	}
+
+	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if !az.HasScope(createInviteScope) {
+		return nil, newPermissionDeniedError()
+	}
+
+	b, _, err := bas.boardDataStore.Board(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	return joinRequestsFromDomainJoinRequests(b.JoinRequests), nil
+}
+
+func (bas *boardApplicationService) RespondToJoinRequest(ctx context.Context, boardId string, joinRequestId string, jrr JoinRequestResponse) error {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return newUnauthenticatedError()
+	}
+
+	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return err
+	}
+	if !az.HasScope(createInviteScope) {
+		return newPermissionDeniedError()
+	}
+
+	if jrr.Response == joinRequestResponseApprove {
+		role := jrr.Role
+		if role == "" {
+			role = auth.BoardRoleViewer
+		}
+		_, err = bas.boardService.ApproveJoinRequest(ctx, boardId, joinRequestId, role, toDomainUser(user))
+		return err
+	} else if jrr.Response == joinRequestResponseReject {
+		return bas.boardService.RejectJoinRequest(ctx, boardId, joinRequestId)
+	} else {
+		return newServiceError(nil, errors.InvalidArgument).WithPublicMessage("invalid join request response")
+	}
+}
+
 func (bas *boardApplicationService) RemoveUser(ctx context.Context, boardId string, userId string) error {
 	user, ok := userFromContext(ctx)
 	if !ok {
@@ -614,34 +857,100 @@ func (bas *boardApplicationService) EditBoardUser(ctx context.Context, boardId s
 	}
 
 	return BoardUser{
-		User:         boardUser.User,
+		User:         User(boardUser.User),
 		Role:         boardUser.Role,
 		CreatedTime:  boardUser.CreatedTime,
-		InvitedBy:    boardUser.InvitedBy,
+		InvitedBy:    User(boardUser.InvitedBy),
 		ModifiedTime: boardUser.ModifiedTime,
-		ModifiedBy:   boardUser.ModifiedBy,
+		ModifiedBy:   User(boardUser.ModifiedBy),
 	}, nil
 }
 
-// We don't actually expose this method, it is there do demonstrate how we can use go concurrency
-// in service methods that assemble different pieces of data.
+func (bas *boardApplicationService) SetCustomRole(ctx context.Context, boardId string, role string, crs CustomRoleScopes) (Board, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return Board{}, newUnauthenticatedError()
+	}
+
+	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return Board{}, err
+	}
+	if !az.HasScope(editCustomRolesScope) {
+		return Board{}, newPermissionDeniedError()
+	}
+
+	board, err := bas.boardService.SetCustomRole(ctx, boardId, domain.CustomRole{Name: role, Scopes: crs.Scopes}, toDomainUser(user))
+	if err != nil {
+		return Board{}, err
+	}
+	return boardFromDomainBoard(board), nil
+}
+
+func (bas *boardApplicationService) DeleteCustomRole(ctx context.Context, boardId string, role string) (Board, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return Board{}, newUnauthenticatedError()
+	}
+
+	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return Board{}, err
+	}
+	if !az.HasScope(editCustomRolesScope) {
+		return Board{}, newPermissionDeniedError()
+	}
+
+	board, err := bas.boardService.DeleteCustomRole(ctx, boardId, role, toDomainUser(user))
+	if err != nil {
+		return Board{}, err
+	}
+	return boardFromDomainBoard(board), nil
+}
+
+func boardFromDomainBoard(board domain.Board) Board {
+	return Board{
+		BoardId:      board.BoardId,
+		Name:         board.Name,
+		Description:  board.Description,
+		CreatedTime:  board.CreatedTime,
+		CreatedBy:    User(board.CreatedBy),
+		ModifiedTime: board.ModifiedTime,
+		ModifiedBy:   User(board.ModifiedBy),
+		Visibility:   board.EffectiveVisibility(),
+		CustomRoles:  customRolesFromDomainCustomRoles(board.CustomRoles),
+	}
+}
+
+// Demonstrates how we can use go concurrency in service methods that assemble different pieces of data.
 // Will return both boards and invites for the user making the request.
 // For some clients it might make more sense to retrieve the data together in one request instead of performing multiple.
-func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context) (BoardsAndInvites, error) {
+func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context, sectionTimeout stdtime.Duration) (BoardsAndInvites, error) {
 	user, ok := userFromContext(ctx)
 	if !ok {
 		return BoardsAndInvites{}, newUnauthenticatedError()
 	}
 
-	if !(authenticatedScopes.HasScope(listUserBoardsScope) && authenticatedScopes.HasScope(listUserInvitesScope)) {
+	if !(authenticatedAuthorization(ctx).HasScope(listUserBoardsScope) && authenticatedAuthorization(ctx).HasScope(listUserInvitesScope)) {
 		return BoardsAndInvites{}, newPermissionDeniedError()
 	}
 
 	// The following will start two goroutines to load boards and invites concurrently.
 	// If at least one of those operations succeed, we return the data that was available, so that clients get at least something.
+	// Both operations have to finish within sectionTimeout, such that a slow query doesn't prevent us from returning the result of the other one.
+	var timeout <-chan stdtime.Time
+	sectionCtx := ctx
+	if sectionTimeout > 0 {
+		var cancel context.CancelFunc
+		sectionCtx, cancel = context.WithTimeout(ctx, sectionTimeout)
+		defer cancel()
+		timer := stdtime.NewTimer(sectionTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
 
 	bc := runConcurrent(func() ([]Board, error) {
-		boards, err := bas.boardDataStore.BoardsForUser(ctx, user.UserId, domain.NewQueryParams())
+		boards, err := bas.boardDataStore.BoardsForUser(sectionCtx, user.UserId, domain.NewQueryParams())
 		if err != nil {
 			return nil, err
 		}
@@ -652,13 +961,13 @@ func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context) (Board
 				Name:        b.Name,
 				Description: b.Description,
 				CreatedTime: b.CreatedTime,
-				CreatedBy:   b.CreatedBy,
+				CreatedBy:   User(b.CreatedBy),
 			}
 		}
 		return result, nil
 	})
 	ic := runConcurrent(func() ([]Invite, error) {
-		invites, err := bas.boardDataStore.InvitesForUser(ctx, user.UserId, domain.NewQueryParams())
+		invites, err := bas.boardDataStore.InvitesForUser(sectionCtx, user.UserId, domain.NewQueryParams())
 		if err != nil {
 			return nil, err
 		}
@@ -668,9 +977,9 @@ func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context) (Board
 				BoardId:     boardId,
 				InviteId:    invite.InviteId,
 				Role:        invite.Role,
-				User:        invite.User,
+				User:        User(invite.User),
 				CreatedTime: invite.CreatedTime,
-				CreatedBy:   invite.CreatedBy,
+				CreatedBy:   User(invite.CreatedBy),
 				ExpiresTime: invite.ExpiresTime,
 			})
 		}
@@ -678,7 +987,8 @@ func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context) (Board
 	})
 
 	result := BoardsAndInvites{}
-	for i := 0; i < 2; i++ {
+	// Channels are set to nil once their result was received.
+	for bc != nil || ic != nil {
 		select {
 		case boards := <-bc:
 			if boards.err == nil {
@@ -686,12 +996,24 @@ func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context) (Board
 			} else {
 				result.BoardsError = "Could not load boards"
 			}
+			bc = nil
 		case invites := <-ic:
 			if invites.err == nil {
 				result.Invites = invites.result
 			} else {
 				result.InvitesError = "Could not load invites"
 			}
+			ic = nil
+		case <-timeout:
+			// Data store implementations might not respect the deadline of the context.
+			if bc != nil {
+				result.BoardsError = "Could not load boards"
+				bc = nil
+			}
+			if ic != nil {
+				result.InvitesError = "Could not load invites"
+				ic = nil
+			}
 		case <-ctx.Done():
 			// Note: this case is not strictly necessary
 			// because both goroutines receive the context and should return an error when the context is cancelled
@@ -706,6 +1028,84 @@ func (bas *boardApplicationService) BoardsAndInvites(ctx context.Context) (Board
 	return result, nil
 }
 
+func (bas *boardApplicationService) BoardsById(ctx context.Context, boardIds []string) (map[string]Board, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return nil, newUnauthenticatedError()
+	}
+
+	if len(boardIds) == 0 {
+		return map[string]Board{}, nil
+	}
+	if len(boardIds) > maxBoardsById {
+		return nil, newServiceError(nil, errors.InvalidArgument).WithPublicMessage("too many board ids")
+	}
+
+	// Users can view a board they have not joined yet if they have an invite for it.
+	invites, err := bas.boardDataStore.InvitesForUser(ctx, user.UserId, domain.NewQueryParams().WithLimit(100))
+	if err != nil {
+		return nil, err
+	}
+
+	boards, err := bas.boardDataStore.Boards(ctx, boardIds)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Board, len(boards))
+	for _, b := range boards {
+		// data store implementations might return empty boards for ids that don't exist
+		if b.BoardId == "" {
+			continue
+		}
+
+		if _, ok := invites[b.BoardId]; !ok {
+			az, err := bas.authChecker.GetAuthorization(ctx, b.BoardId, user.UserId)
+			if err != nil {
+				if errors.IsPermissionDeniedError(err) {
+					continue
+				}
+				return nil, err
+			}
+			if !az.HasScope(viewBoardScope) {
+				continue
+			}
+		}
+
+		result[b.BoardId] = Board{
+			BoardId:     b.BoardId,
+			Name:        b.Name,
+			Description: b.Description,
+			CreatedTime: b.CreatedTime,
+			CreatedBy:   User(b.CreatedBy),
+			Visibility:  b.EffectiveVisibility(),
+		}
+	}
+
+	return result, nil
+}
+
+func (bas *boardApplicationService) UserCount(ctx context.Context, boardId string) (int, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return 0, newUnauthenticatedError()
+	}
+
+	az, err := bas.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return 0, err
+	}
+	if !az.HasScope(viewBoardScope) {
+		return 0, newPermissionDeniedError()
+	}
+
+	b, _, err := bas.boardDataStore.Board(ctx, boardId)
+	if err != nil {
+		return 0, err
+	}
+	return b.UserCount(), nil
+}
+
 type result[T any] struct {
 	result T
 	err    error
@@ -34,7 +34,8 @@ func TestUnauthenticatedUsersDenied(t *testing.T) {
 
 	ctx := context.Background()
 
-	service := NewBoardApplicationService(newTestDatastores())
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
 
 	_, err := service.CreateBoard(ctx, NewBoard{})
 	a.NotNil(err)
@@ -79,6 +80,18 @@ func TestUnauthenticatedUsersDenied(t *testing.T) {
 	_, err = service.Invites(ctx, QueryParams{})
 	a.NotNil(err)
 	a.True(errors.IsUnauthenticatedError(err))
+
+	_, err = service.CreateJoinRequest(ctx, "abc")
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	_, err = service.JoinRequests(ctx, "abc")
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	err = service.RespondToJoinRequest(ctx, "abc", "j", JoinRequestResponse{})
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
 }
 
 func TestAuthorization(t *testing.T) {
@@ -107,7 +120,7 @@ func TestAuthorization(t *testing.T) {
 
 		authStore := store.NewDefaultAuthorizationStore(ds)
 		service := &boardApplicationService{
-			boardService:   domain.NewBoardService(ds, nil),
+			boardService:   domain.NewBoardService(ds, nil, domain.LimitsConfig{}, nil),
 			boardDataStore: ds,
 			authChecker:    auth.NewAuthorizationChecker(rts, authStore),
 		}
@@ -143,6 +156,243 @@ func TestAuthorization(t *testing.T) {
 	_, err = newService(editBoardUserScope).EditBoardUser(ctx, "b-123", "u-1", BoardUserEdit{})
 	a.NotNil(err)
 	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = newService(editCustomRolesScope).SetCustomRole(ctx, "b-123", "curator", CustomRoleScopes{})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = newService(editCustomRolesScope).DeleteCustomRole(ctx, "b-123", "curator")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = newService(editBoardVisibilityScope).EditBoard(ctx, "b-123", BoardEdit{Visibility: stringPtr(domain.VisibilityLink)})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = newService(createInviteScope).JoinRequests(ctx, "b-123")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	err = newService(createInviteScope).RespondToJoinRequest(ctx, "b-123", "j-1", JoinRequestResponse{Response: joinRequestResponseReject})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+}
+
+func TestCustomRoles(t *testing.T) {
+	a := assert.New(t)
+
+	// registered by the links component when running the app
+	auth.RegisterScopes("links:delete")
+
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
+	checker := NewAuthorizationChecker(as)
+
+	ownerCtx := auth.ContextWithUser(context.Background(), testUser1)
+	user2 := auth.User{UserId: "user-2", Name: "User Two"}
+	user2Ctx := auth.ContextWithUser(context.Background(), user2)
+
+	board, err := service.CreateBoard(ownerCtx, NewBoard{Name: "board"})
+	a.Nil(err)
+	boardId := board.BoardId
+
+	// owner-only scopes cannot be granted
+	_, err = service.SetCustomRole(ownerCtx, boardId, "curator", CustomRoleScopes{Scopes: []auth.Scope{viewBoardScope, deleteBoardScope}})
+	a.NotNil(err)
+	a.True(errors.IsInvalidArgumentError(err))
+	_, err = service.SetCustomRole(ownerCtx, boardId, "curator", CustomRoleScopes{Scopes: []auth.Scope{viewBoardScope, editCustomRolesScope}})
+	a.NotNil(err)
+	a.True(errors.IsInvalidArgumentError(err))
+
+	b, err := service.SetCustomRole(ownerCtx, boardId, "curator", CustomRoleScopes{Scopes: []auth.Scope{viewBoardScope, viewBoardUsersScope, "links:delete"}})
+	a.Nil(err)
+	a.Equal([]CustomRole{{Name: "curator", Scopes: []auth.Scope{viewBoardScope, viewBoardUsersScope, "links:delete"}}}, b.CustomRoles)
+
+	invite, err := service.CreateInvite(ownerCtx, boardId, NewInvite{Role: "curator", User: User(toDomainUser(user2))})
+	a.Nil(err)
+	a.Equal("curator", invite.Role)
+	err = service.RespondToInvite(user2Ctx, boardId, invite.InviteId, InviteResponse{Response: inviteResponseAccept})
+	a.Nil(err)
+
+	// the scopes of the custom role are merged into the authorization
+	az, err := checker.GetAuthorization(user2Ctx, boardId, user2.UserId)
+	a.Nil(err)
+	a.True(az.HasScope(viewBoardScope))
+	a.True(az.HasScope(viewBoardUsersScope))
+	a.True(az.HasScope("links:delete"))
+	a.False(az.HasScope(createInviteScope))
+
+	result, err := service.Board(user2Ctx, boardId)
+	a.Nil(err)
+	a.Len(result.Users, 2)
+	a.Len(result.CustomRoles, 1)
+	_, err = service.CreateInvite(user2Ctx, boardId, NewInvite{Role: auth.BoardRoleViewer})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	_, err = service.SetCustomRole(user2Ctx, boardId, "curator", CustomRoleScopes{Scopes: []auth.Scope{createInviteScope}})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	// changes to a role apply immediately, roles in use cannot be deleted
+	_, err = service.SetCustomRole(ownerCtx, boardId, "curator", CustomRoleScopes{Scopes: []auth.Scope{viewBoardScope}})
+	a.Nil(err)
+	az, err = checker.GetAuthorization(user2Ctx, boardId, user2.UserId)
+	a.Nil(err)
+	a.Equal(auth.Authorization{viewBoardScope: {}}, az)
+
+	_, err = service.DeleteCustomRole(ownerCtx, boardId, "curator")
+	a.NotNil(err)
+	a.True(errors.IsFailedPreconditionError(err))
+
+	_, err = service.EditBoardUser(ownerCtx, boardId, user2.UserId, BoardUserEdit{Role: stringPtr(auth.BoardRoleViewer)})
+	a.Nil(err)
+	b, err = service.DeleteCustomRole(ownerCtx, boardId, "curator")
+	a.Nil(err)
+	a.Empty(b.CustomRoles)
+}
+
+func TestBoardVisibility(t *testing.T) {
+	a := assert.New(t)
+
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
+
+	ownerCtx := auth.ContextWithUser(context.Background(), testUser1)
+	user2Ctx := auth.ContextWithUser(context.Background(), auth.User{UserId: "user-2"})
+	anonymousCtx := context.Background()
+
+	board, err := service.CreateBoard(ownerCtx, NewBoard{Name: "board"})
+	a.Nil(err)
+	a.Equal(domain.VisibilityPrivate, board.Visibility)
+	boardId := board.BoardId
+	_, err = service.SetCustomRole(ownerCtx, boardId, "curator", CustomRoleScopes{Scopes: []auth.Scope{viewBoardScope}})
+	a.Nil(err)
+
+	// private boards can only be viewed by members
+	_, err = service.Board(user2Ctx, boardId)
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	_, err = service.Board(anonymousCtx, boardId)
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	_, err = service.EditBoard(ownerCtx, boardId, BoardEdit{Visibility: stringPtr("secret")})
+	a.NotNil(err)
+	a.True(errors.IsInvalidArgumentError(err))
+	b, err := service.EditBoard(ownerCtx, boardId, BoardEdit{Visibility: stringPtr(domain.VisibilityLink)})
+	a.Nil(err)
+	a.Equal(domain.VisibilityLink, b.Visibility)
+
+	// anyone with the link can view the board, but not its users, invites or custom roles
+	for _, ctx := range []context.Context{user2Ctx, anonymousCtx} {
+		result, err := service.Board(ctx, boardId)
+		a.Nil(err)
+		a.Equal("board", result.Name)
+		a.Equal(domain.VisibilityLink, result.Visibility)
+		a.Nil(result.Users)
+		a.Nil(result.Invites)
+		a.Nil(result.CustomRoles)
+	}
+	_, err = service.EditBoard(user2Ctx, boardId, BoardEdit{Name: stringPtr("new name")})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	_, err = service.CreateInvite(user2Ctx, boardId, NewInvite{Role: auth.BoardRoleViewer})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	// only public boards are listed
+	boards, err := service.PublicBoards(anonymousCtx, QueryParams{})
+	a.Nil(err)
+	a.Empty(boards)
+	_, err = service.EditBoard(ownerCtx, boardId, BoardEdit{Visibility: stringPtr(domain.VisibilityPublic)})
+	a.Nil(err)
+	boards, err = service.PublicBoards(anonymousCtx, QueryParams{})
+	a.Nil(err)
+	a.Len(boards, 1)
+	a.Equal(boardId, boards[0].BoardId)
+}
+
+func TestJoinRequests(t *testing.T) {
+	a := assert.New(t)
+
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
+
+	ownerCtx := auth.ContextWithUser(context.Background(), testUser1)
+	user2Ctx := auth.ContextWithUser(context.Background(), auth.User{UserId: "user-2"})
+	user3Ctx := auth.ContextWithUser(context.Background(), auth.User{UserId: "user-3"})
+
+	board, err := service.CreateBoard(ownerCtx, NewBoard{Name: "board"})
+	a.Nil(err)
+	boardId := board.BoardId
+
+	// private boards cannot be joined by request
+	_, err = service.CreateJoinRequest(user2Ctx, boardId)
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	_, err = service.CreateJoinRequest(user2Ctx, "b-doesnotexist")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	// any authenticated user can request to join a board that is visible to non-members
+	_, err = service.EditBoard(ownerCtx, boardId, BoardEdit{Visibility: stringPtr(domain.VisibilityLink)})
+	a.Nil(err)
+	jr2, err := service.CreateJoinRequest(user2Ctx, boardId)
+	a.Nil(err)
+	a.Equal(boardId, jr2.BoardId)
+	a.Equal("user-2", jr2.User.UserId)
+	jr3, err := service.CreateJoinRequest(user3Ctx, boardId)
+	a.Nil(err)
+
+	_, err = service.CreateJoinRequest(user2Ctx, boardId)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, 20))
+
+	// but only users that can invite others can see and respond to join requests
+	_, err = service.JoinRequests(user2Ctx, boardId)
+	a.NotNil(err)
+	err = service.RespondToJoinRequest(user2Ctx, boardId, jr2.JoinRequestId, JoinRequestResponse{Response: joinRequestResponseApprove})
+	a.NotNil(err)
+
+	jrs, err := service.JoinRequests(ownerCtx, boardId)
+	a.Nil(err)
+	a.Len(jrs, 2)
+	b, err := service.Board(ownerCtx, boardId)
+	a.Nil(err)
+	a.Len(b.JoinRequests, 2)
+
+	err = service.RespondToJoinRequest(ownerCtx, boardId, jr2.JoinRequestId, JoinRequestResponse{Response: "maybe"})
+	a.NotNil(err)
+	a.True(errors.IsInvalidArgumentError(err))
+
+	// approved users are added with the viewer role by default
+	err = service.RespondToJoinRequest(ownerCtx, boardId, jr2.JoinRequestId, JoinRequestResponse{Response: joinRequestResponseApprove})
+	a.Nil(err)
+	b, err = service.Board(user2Ctx, boardId)
+	a.Nil(err)
+	a.Nil(b.JoinRequests)
+
+	err = service.RespondToJoinRequest(ownerCtx, boardId, jr3.JoinRequestId, JoinRequestResponse{Response: joinRequestResponseReject})
+	a.Nil(err)
+	_, err = service.EditBoard(ownerCtx, boardId, BoardEdit{Visibility: stringPtr(domain.VisibilityPrivate)})
+	a.Nil(err)
+	_, err = service.Board(user3Ctx, boardId)
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	b, err = service.Board(ownerCtx, boardId)
+	a.Nil(err)
+	a.Empty(b.JoinRequests)
+	a.Len(b.Users, 2)
+	for _, u := range b.Users {
+		if u.User.UserId == "user-2" {
+			a.Equal(auth.BoardRoleViewer, u.Role)
+		}
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
 }
 
 func TestBoardReturnsUsersAndInvitesOnlyIfAuthorized(t *testing.T) {
@@ -168,7 +418,7 @@ func TestBoardReturnsUsersAndInvitesOnlyIfAuthorized(t *testing.T) {
 
 		authStore := store.NewDefaultAuthorizationStore(ds)
 		service := &boardApplicationService{
-			boardService:   domain.NewBoardService(ds, nil),
+			boardService:   domain.NewBoardService(ds, nil, domain.LimitsConfig{}, nil),
 			boardDataStore: ds,
 			authChecker:    auth.NewAuthorizationChecker(rts, authStore),
 		}
@@ -181,7 +431,7 @@ func TestBoardReturnsUsersAndInvitesOnlyIfAuthorized(t *testing.T) {
 	b, err := newService([]auth.Scope{viewBoardScope}).Board(ctx, "b-123")
 	a.Nil(err)
 	a.Equal(board.BoardId, b.BoardId)
-	a.Equal(board.CreatedBy, b.CreatedBy)
+	a.Equal(User(board.CreatedBy), b.CreatedBy)
 	a.Equal(board.CreatedTime, b.CreatedTime)
 	a.Empty(b.Users)
 	a.Empty(b.Invites)
@@ -191,7 +441,7 @@ func TestBoardReturnsUsersAndInvitesOnlyIfAuthorized(t *testing.T) {
 	a.Nil(err)
 	a.NotEmpty(b.Users)
 	u := b.Users[0]
-	a.Equal(toDomainUser(testUser1), u.User)
+	a.Equal(User(toDomainUser(testUser1)), u.User)
 	a.Equal("testRole", u.Role)
 	a.Empty(b.Invites)
 
@@ -256,7 +506,8 @@ func TestCreateBoard(t *testing.T) {
 		UserId: "u-123",
 		Name:   "Testi Tester",
 	})
-	service := NewBoardApplicationService(newTestDatastores())
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
 
 	board, err := service.CreateBoard(ctx, NewBoard{
 		Name:        "Board name",
@@ -266,11 +517,11 @@ func TestCreateBoard(t *testing.T) {
 	a.NotEmpty(board.BoardId)
 	a.Equal("Board name", board.Name)
 	a.Equal("Board description", board.Description)
-	a.Equal(domain.User{
+	a.Equal(User{
 		UserId: "u-123",
 		Name:   "Testi Tester",
 	}, board.CreatedBy)
-	a.Equal(domain.User{
+	a.Equal(User{
 		UserId: "u-123",
 		Name:   "Testi Tester",
 	}, board.ModifiedBy)
@@ -279,7 +530,7 @@ func TestCreateBoard(t *testing.T) {
 	a.Len(board.Invites, 0)
 	a.Len(board.Users, 1)
 	creator := board.Users[0]
-	a.Equal(domain.User{
+	a.Equal(User{
 		UserId: "u-123",
 		Name:   "Testi Tester",
 	}, creator.User)
@@ -287,3 +538,94 @@ func TestCreateBoard(t *testing.T) {
 	a.Equal(defaultTimeUnix, creator.CreatedTime)
 	a.Equal(defaultTimeUnix, creator.ModifiedTime)
 }
+
+func TestBoardsById(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := context.Background()
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
+
+	// user is a member of b-1, has been invited to b-2 and has nothing to do with b-3
+	err := ds.UpdateBoard(ctx, "b-1", domain.NewDatastoreBoardUpdate(nil).
+		WithBoard(domain.Board{BoardId: "b-1", Name: "Board 1"}).
+		UpdateUser(domain.BoardUser{User: toDomainUser(testUser1), Role: auth.BoardRoleViewer}))
+	a.Nil(err)
+	err = ds.UpdateBoard(ctx, "b-2", domain.NewDatastoreBoardUpdate(nil).
+		WithBoard(domain.Board{BoardId: "b-2", Name: "Board 2"}).
+		UpdateInvite(domain.BoardInvite{InviteId: "i-1", Role: auth.BoardRoleViewer, User: toDomainUser(testUser1), ExpiresTime: defaultTimeUnix * 2}))
+	a.Nil(err)
+	err = ds.UpdateBoard(ctx, "b-3", domain.NewDatastoreBoardUpdate(nil).
+		WithBoard(domain.Board{BoardId: "b-3", Name: "Board 3"}))
+	a.Nil(err)
+
+	_, err = service.BoardsById(ctx, []string{"b-1"})
+	a.True(errors.IsUnauthenticatedError(err))
+
+	ctx = auth.ContextWithUser(ctx, testUser1)
+	boards, err := service.BoardsById(ctx, []string{"b-1", "b-2", "b-3", "b-4"})
+	a.Nil(err)
+	a.Len(boards, 2)
+	a.Equal("Board 1", boards["b-1"].Name)
+	a.Equal("Board 2", boards["b-2"].Name)
+
+	boards, err = service.BoardsById(ctx, nil)
+	a.Nil(err)
+	a.Empty(boards)
+
+	_, err = service.BoardsById(ctx, make([]string, maxBoardsById+1))
+	a.True(errors.IsInvalidArgumentError(err))
+}
+
+// Data store whose InvitesForUser method blocks until the context is done or fails.
+type slowInvitesDataStore struct {
+	domain.BoardDataStore
+	fail bool
+}
+
+func (s *slowInvitesDataStore) InvitesForUser(ctx context.Context, userId string, qp domain.QueryParams) (map[string]domain.BoardInvite, error) {
+	if s.fail {
+		return nil, errors.New(nil, "test", errors.Internal)
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBoardsAndInvitesReturnsPartialResults(t *testing.T) {
+	a := assert.New(t)
+
+	ds, as := newTestDatastores()
+	ctx := auth.ContextWithUser(context.Background(), testUser1)
+	_, err := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil).CreateBoard(ctx, NewBoard{Name: "Board"})
+	a.Nil(err)
+
+	for _, fail := range []bool{true, false} {
+		service := NewBoardApplicationService(&slowInvitesDataStore{BoardDataStore: ds, fail: fail}, as, nil, domain.LimitsConfig{}, nil)
+		result, err := service.BoardsAndInvites(ctx, 50*stdtime.Millisecond)
+		a.Nil(err)
+		a.Len(result.Boards, 1)
+		a.Empty(result.BoardsError)
+		a.Empty(result.Invites)
+		a.NotEmpty(result.InvitesError)
+	}
+
+	_, err = NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil).BoardsAndInvites(context.Background(), 0)
+	a.True(errors.IsUnauthenticatedError(err))
+}
+
+func TestUserCount(t *testing.T) {
+	a := assert.New(t)
+
+	ds, as := newTestDatastores()
+	service := NewBoardApplicationService(ds, as, nil, domain.LimitsConfig{}, nil)
+	ctx := auth.ContextWithUser(context.Background(), testUser1)
+	b, err := service.CreateBoard(ctx, NewBoard{Name: "Board"})
+	a.Nil(err)
+
+	count, err := service.UserCount(ctx, b.BoardId)
+	a.Nil(err)
+	a.Equal(1, count)
+
+	_, err = service.UserCount(auth.ContextWithUser(context.Background(), auth.User{UserId: "user-2"}), b.BoardId)
+	a.True(errors.IsPermissionDeniedError(err))
+}
@@ -32,6 +32,9 @@ const (
 	listUserInvitesScope                = "boards:listUserInvites"
 	removeUserFromBoardScope            = "boards:removeUser"
 	editBoardUserScope                  = "boards:editUsers"
+	editCustomRolesScope                = "boards:editRoles"
+	editBoardVisibilityScope            = "boards:editVisibility"
+	requestToJoinScope                  = "boards:requestToJoin"
 )
 
 func allScopes() []auth.Scope {
@@ -49,6 +52,9 @@ func allScopes() []auth.Scope {
 		listUserInvitesScope,
 		removeUserFromBoardScope,
 		editBoardUserScope,
+		editCustomRolesScope,
+		editBoardVisibilityScope,
+		requestToJoinScope,
 	}
 }
 
@@ -61,6 +67,8 @@ var roleToScopes = map[string][]auth.Scope{
 		viewBoardInvitesScope,
 		removeUserFromBoardScope,
 		editBoardUserScope,
+		editCustomRolesScope,
+		editBoardVisibilityScope,
 		createInviteScope,
 		deleteInviteScope,
 		respondToInviteScope,
@@ -80,6 +88,10 @@ var roleToScopes = map[string][]auth.Scope{
 		viewBoardScope,
 		respondToInviteScope,
 	},
+	// users that are not members of a board that is visible to non-members
+	auth.BoardRoleVisitor: {
+		viewBoardScope,
+	},
 }
 
 // scopes available to authenticated users
@@ -88,6 +100,17 @@ var authenticatedScopes auth.Authorization = map[auth.Scope]struct{}{
 	listUserBoardsScope:  {},
 	respondToInviteScope: {},
 	listUserInvitesScope: {},
+	requestToJoinScope:   {},
+}
+
+// Scopes of authenticated users that do not depend on a board, limited by the restrictions in the context, see auth.RestrictAuthorization.
+func authenticatedAuthorization(ctx context.Context) auth.Authorization {
+	return auth.RestrictAuthorization(ctx, "", authenticatedScopes)
+}
+
+func init() {
+	auth.RegisterScopes(allScopes()...)
+	auth.RegisterRoles(roleToScopes)
 }
 
 func NewAuthorizationChecker(as auth.AuthorizationStore) *auth.BoardAuthorizationChecker {
@@ -1,13 +1,20 @@
 package boards
 
 import (
+	"context"
+
 	"github.com/dkinzler/linkboards/internal/auth"
 	"github.com/dkinzler/linkboards/internal/auth/store"
 	"github.com/dkinzler/linkboards/internal/boards/application"
 	fs "github.com/dkinzler/linkboards/internal/boards/datastore/firestore"
 	"github.com/dkinzler/linkboards/internal/boards/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/boards/datastore/instrumented"
 	"github.com/dkinzler/linkboards/internal/boards/domain"
 	"github.com/dkinzler/linkboards/internal/boards/transport"
+	"github.com/dkinzler/linkboards/internal/boards/transport/pb"
+	"github.com/dkinzler/linkboards/internal/endpointname"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/tracing"
 
 	e "github.com/dkinzler/kit/endpoint"
 	"github.com/dkinzler/kit/errors"
@@ -15,14 +22,19 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
 // Contains all the necessary elements for running the boards service of the API.
 type Component struct {
 	ApplicationService application.BoardApplicationService
 	DataStore          domain.BoardDataStore
+	// AuthorizationStore used by the application service, can be shared with other components.
+	AuthorizationStore auth.AuthorizationStore
 	Endpoints          transport.EndpointSet
 }
 
@@ -35,12 +47,33 @@ type Config struct {
 	// Used to create a firestore data store if UseInmemDataStore is set to false.
 	FirestoreConfig *FirestoreConfig
 	// AuthorizationStore used to perform authorization in the application service.
+	// If nil, a store.DefaultAuthorizationStore that uses the data store of this component is created.
 	AuthorizationStore auth.AuthorizationStore
-
-	// Middlewares that should be applied to all endpoints
+	// If not nil and AuthorizationStore is nil, roles are cached using a store.CachingAuthorizationStore.
+	// Cached entries are invalidated by the events of this component.
+	AuthorizationCache *store.CacheConfig
+	// Optional, used to publish domain events, e.g. to push changes to clients.
+	EventPublisher domain.EventPublisher
+	// Limits for the number of users and invites of boards, zero values are replaced by the values of domain.DefaultLimits.
+	Limits domain.LimitsConfig
+	// Optional, used to enforce daily quotas, e.g. the number of boards a user can create per day.
+	// If nil, there are no quotas.
+	Quotas domain.QuotaStore
+
+	// Middlewares that should be applied to all endpoints.
+	// They run after the authentication middleware and can get the name of the endpoint using package endpointname,
+	// e.g. to limit the rate of requests per endpoint, see ratelimit.NewEndpointMiddleware.
 	Middlewares []endpoint.Middleware
 	// Authentication middleware for endpoints.
 	AuthMiddleware endpoint.Middleware
+	// Authentication middleware for endpoints that can also be used without authentication,
+	// e.g. to view boards that are visible to non-members, see middleware.NewOptionalAuthEndpointMiddleware.
+	// If nil, AuthMiddleware is used.
+	OptionalAuthMiddleware endpoint.Middleware
+	// If not nil, metrics are recorded for all endpoints and data store calls.
+	Metrics *metrics.Metrics
+	// If not nil, spans are created for all endpoints and data store calls, see package tracing.
+	TracerProvider trace.TracerProvider
 	// Whether to add the logging middleware from the "internal/pkg/endpoint" package to every endpoint.
 	// It logs errors from the underlying application service, not any errors produced by endpoint middlewares.
 	UseLoggingMiddleware bool
@@ -64,53 +97,135 @@ func NewComponent(config Config) (*Component, error) {
 		return nil, errors.New(nil, "boards", errors.InvalidArgument).WithInternalMessage("no datastore configured")
 	}
 
+	if config.Metrics != nil {
+		ds = instrumented.NewInstrumentedBoardDataStore(ds, config.Metrics)
+	}
+	if config.TracerProvider != nil {
+		ds = instrumented.NewTracingBoardDataStore(ds, config.TracerProvider)
+	}
+
+	if err := config.Limits.Validate(); err != nil {
+		return nil, errors.New(err, "boards", errors.InvalidArgument).WithInternalMessage("invalid limits")
+	}
+
 	var as auth.AuthorizationStore
+	ep := config.EventPublisher
 	if config.AuthorizationStore != nil {
 		as = config.AuthorizationStore
 	} else {
 		as = store.NewDefaultAuthorizationStore(ds)
+		if config.AuthorizationCache != nil {
+			cache := store.NewCachingAuthorizationStore(as, *config.AuthorizationCache)
+			// Invalidate cached entries before other publishers see the event.
+			ep = domain.CombineEventPublishers(cache, config.EventPublisher)
+			as = cache
+		}
 	}
 
-	applicationService := application.NewBoardApplicationService(ds, as)
+	applicationService := application.NewBoardApplicationService(ds, as, ep, config.Limits, config.Quotas)
 
 	mwBuilder := mwBuilder{config: config}
 	endpoints := transport.NewEndpoints(applicationService, transport.Middlewares{
-		CreateBoardEndpoint:     mwBuilder.buildMiddlewares("createBoard"),
-		DeleteBoardEndpoint:     mwBuilder.buildMiddlewares("deleteBoard"),
-		EditBoardEndpoint:       mwBuilder.buildMiddlewares("editBoard"),
-		BoardEndpoint:           mwBuilder.buildMiddlewares("getBoard"),
-		BoardsEndpoint:          mwBuilder.buildMiddlewares("getBoards"),
-		CreateInviteEndpoint:    mwBuilder.buildMiddlewares("createInvite"),
-		RespondToInviteEndpoint: mwBuilder.buildMiddlewares("respondToInvite"),
-		DeleteInviteEndpoint:    mwBuilder.buildMiddlewares("deleteInvite"),
-		InvitesEndpoint:         mwBuilder.buildMiddlewares("getInvites"),
-		RemoveUserEndpoint:      mwBuilder.buildMiddlewares("removeUser"),
-		EditBoardUserEndpoint:   mwBuilder.buildMiddlewares("editBoardUser"),
+		CreateBoardEndpoint:          mwBuilder.buildMiddlewares("createBoard"),
+		DeleteBoardEndpoint:          mwBuilder.buildMiddlewares("deleteBoard"),
+		EditBoardEndpoint:            mwBuilder.buildMiddlewares("editBoard"),
+		BoardEndpoint:                mwBuilder.buildMiddlewaresWithOptionalAuth("getBoard"),
+		BoardsEndpoint:               mwBuilder.buildMiddlewares("getBoards"),
+		PublicBoardsEndpoint:         mwBuilder.buildMiddlewaresWithOptionalAuth("getPublicBoards"),
+		CreateInviteEndpoint:         mwBuilder.buildMiddlewares("createInvite"),
+		RespondToInviteEndpoint:      mwBuilder.buildMiddlewares("respondToInvite"),
+		DeleteInviteEndpoint:         mwBuilder.buildMiddlewares("deleteInvite"),
+		InvitesEndpoint:              mwBuilder.buildMiddlewares("getInvites"),
+		CreateJoinRequestEndpoint:    mwBuilder.buildMiddlewares("createJoinRequest"),
+		JoinRequestsEndpoint:         mwBuilder.buildMiddlewares("getJoinRequests"),
+		RespondToJoinRequestEndpoint: mwBuilder.buildMiddlewares("respondToJoinRequest"),
+		RemoveUserEndpoint:           mwBuilder.buildMiddlewares("removeUser"),
+		EditBoardUserEndpoint:        mwBuilder.buildMiddlewares("editBoardUser"),
+		SetCustomRoleEndpoint:        mwBuilder.buildMiddlewares("setCustomRole"),
+		DeleteCustomRoleEndpoint:     mwBuilder.buildMiddlewares("deleteCustomRole"),
 	})
 
 	return &Component{
 		ApplicationService: applicationService,
 		DataStore:          ds,
+		AuthorizationStore: as,
 		Endpoints:          endpoints,
 	}, nil
 }
 
+// Returns an error if the data store of the component cannot be queried, can be used for readiness checks.
+func (c *Component) Ping(ctx context.Context) error {
+	// Queries the boards of a user that does not exist, this is cheap but still requires a round trip to the data store.
+	_, err := c.DataStore.BoardsForUser(ctx, pingUserId, domain.QueryParams{Limit: 1})
+	return err
+}
+
+const pingUserId = "healthcheck"
+
 func (c *Component) RegisterHttpHandlers(router *mux.Router, httpOpts []http.ServerOption) {
 	transport.RegisterHttpHandlers(c.Endpoints, router, httpOpts)
 }
 
+// Registers a gRPC service that uses the same endpoints as the http handlers.
+func (c *Component) RegisterGRPCServer(s *grpc.Server, opts []kitgrpc.ServerOption) {
+	pb.RegisterBoardServiceServer(s, transport.NewGRPCServer(c.Endpoints, opts))
+}
+
+// Names of the endpoints created in NewComponent, rate limits can only be configured for registered names.
+func init() {
+	endpointname.Register(
+		"createBoard",
+		"deleteBoard",
+		"editBoard",
+		"getBoard",
+		"getBoards",
+		"getPublicBoards",
+		"createInvite",
+		"respondToInvite",
+		"deleteInvite",
+		"getInvites",
+		"createJoinRequest",
+		"getJoinRequests",
+		"respondToJoinRequest",
+		"removeUser",
+		"editBoardUser",
+		"setCustomRole",
+		"deleteCustomRole",
+	)
+}
+
 type mwBuilder struct {
 	config Config
 }
 
 func (b mwBuilder) buildMiddlewares(endpointName string) []endpoint.Middleware {
+	return b.buildMiddlewaresWithAuth(endpointName, b.config.AuthMiddleware)
+}
+
+func (b mwBuilder) buildMiddlewaresWithOptionalAuth(endpointName string) []endpoint.Middleware {
+	if b.config.OptionalAuthMiddleware != nil {
+		return b.buildMiddlewaresWithAuth(endpointName, b.config.OptionalAuthMiddleware)
+	}
+	return b.buildMiddlewares(endpointName)
+}
+
+// Like buildMiddlewares but uses the given authentication middleware instead of the one from the config.
+func (b mwBuilder) buildMiddlewaresWithAuth(endpointName string, authMiddleware endpoint.Middleware) []endpoint.Middleware {
 	var mws []endpoint.Middleware
 	mws = append(mws, b.config.Middlewares...)
-	if b.config.AuthMiddleware != nil {
-		mws = append(mws, b.config.AuthMiddleware)
+	if authMiddleware != nil {
+		mws = append(mws, authMiddleware)
 	}
 	if b.config.UseLoggingMiddleware && b.config.Logger != nil {
 		mws = append(mws, e.ErrorLoggingMiddleware(b.config.Logger.With("component", "boards", "endpoint", endpointName)))
 	}
+	if b.config.Metrics != nil {
+		mws = append(mws, b.config.Metrics.EndpointMiddleware("boards", endpointName))
+	}
+	if b.config.TracerProvider != nil {
+		mws = append(mws, tracing.EndpointMiddleware(b.config.TracerProvider, "boards", endpointName))
+	}
+	// outermost middleware, makes the endpoint name available to all other middlewares
+	mws = append(mws, endpointname.Middleware(endpointName))
 	return mws
 }
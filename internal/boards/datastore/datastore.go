@@ -16,6 +16,8 @@ import (
 func DatastoreTest(ds domain.BoardDataStore, t *testing.T) {
 	GeneralTests(ds, t)
 	QueryCursorTest(ds, t)
+	PublicBoardsTest(ds, t)
+	JoinRequestTest(ds, t)
 }
 
 func GeneralTests(ds domain.BoardDataStore, t *testing.T) {
@@ -251,6 +253,106 @@ func QueryCursorTest(ds domain.BoardDataStore, t *testing.T) {
 	a.Contains(invites, board1.BoardId)
 }
 
+func PublicBoardsTest(ds domain.BoardDataStore, t *testing.T) {
+	a := assert.New(t)
+
+	boards := []domain.Board{
+		{BoardId: "b-public-1", CreatedTime: 1000, Visibility: domain.VisibilityPublic},
+		{BoardId: "b-public-2", CreatedTime: 2000, Visibility: domain.VisibilityLink},
+		{BoardId: "b-public-3", CreatedTime: 3000, Visibility: domain.VisibilityPublic},
+		{BoardId: "b-public-4", CreatedTime: 4000, Visibility: domain.VisibilityPrivate},
+		{BoardId: "b-public-5", CreatedTime: 5000, Visibility: domain.VisibilityPublic},
+	}
+
+	ctx, cancel := getContext()
+	defer cancel()
+
+	for _, board := range boards {
+		err := ds.UpdateBoard(ctx, board.BoardId, domain.NewDatastoreBoardUpdate(nil).WithBoard(board))
+		a.Nil(err)
+	}
+
+	result, err := ds.PublicBoards(ctx, domain.NewQueryParams())
+	a.Nil(err)
+	// only public boards are returned, newest first
+	a.Len(result, 3)
+	a.Equal(boards[4], result[0])
+	a.Equal(boards[2], result[1])
+	a.Equal(boards[0], result[2])
+
+	result, err = ds.PublicBoards(ctx, domain.NewQueryParams().WithLimit(1).WithCursor(4500))
+	a.Nil(err)
+	a.Len(result, 1)
+	a.Equal(boards[2].BoardId, result[0].BoardId)
+}
+
+func JoinRequestTest(ds domain.BoardDataStore, t *testing.T) {
+	a := assert.New(t)
+
+	jr1 := domain.BoardJoinRequest{JoinRequestId: "j-1", User: domain.User{UserId: "u-1"}, CreatedTime: 1000}
+	jr2 := domain.BoardJoinRequest{JoinRequestId: "j-2", User: domain.User{UserId: "u-2"}, CreatedTime: 2000}
+
+	ctx, cancel := getContext()
+	defer cancel()
+
+	err := ds.UpdateBoard(ctx, "b-join", domain.NewDatastoreBoardUpdate(nil).
+		WithBoard(domain.Board{BoardId: "b-join"}).
+		UpdateJoinRequest(jr1).
+		UpdateJoinRequest(jr2))
+	a.Nil(err)
+
+	b, te, err := ds.Board(ctx, "b-join")
+	a.Nil(err)
+	a.ElementsMatch([]domain.BoardJoinRequest{jr1, jr2}, b.JoinRequests)
+
+	// approving a join request, i.e. removing it and adding the user, happens in a single transaction
+	err = ds.UpdateBoard(ctx, "b-join", domain.NewDatastoreBoardUpdate(te).
+		RemoveJoinRequest(jr1.JoinRequestId).
+		UpdateUser(domain.BoardUser{User: jr1.User}))
+	a.Nil(err)
+
+	// the join requests were changed, the old transaction expectation can no longer be used
+	err = ds.UpdateBoard(ctx, "b-join", domain.NewDatastoreBoardUpdate(te).RemoveJoinRequest(jr2.JoinRequestId))
+	a.NotNil(err)
+	a.True(errors.IsFailedPreconditionError(err))
+
+	b, _, err = ds.Board(ctx, "b-join")
+	a.Nil(err)
+	a.Equal([]domain.BoardJoinRequest{jr2}, b.JoinRequests)
+	a.Equal(1, b.UserCount())
+
+	err = ds.DeleteBoard(ctx, "b-join")
+	a.Nil(err)
+}
+
+// Tests the methods of BoardAdminDataStore, expects an empty data store.
+func AdminDatastoreTest(ds domain.BoardAdminDataStore, t *testing.T) {
+	a := assert.New(t)
+
+	boards := []domain.Board{
+		{BoardId: "b-admin-1", CreatedTime: 1000, Visibility: domain.VisibilityPublic},
+		{BoardId: "b-admin-2", CreatedTime: 2000, Visibility: domain.VisibilityPrivate},
+		{BoardId: "b-admin-3", CreatedTime: 3000},
+	}
+
+	ctx, cancel := getContext()
+	defer cancel()
+
+	for _, board := range boards {
+		err := ds.UpdateBoard(ctx, board.BoardId, domain.NewDatastoreBoardUpdate(nil).WithBoard(board))
+		a.Nil(err)
+	}
+
+	// all boards are returned regardless of visibility, newest first
+	result, err := ds.AllBoards(ctx, domain.NewQueryParams())
+	a.Nil(err)
+	a.Equal([]domain.Board{boards[2], boards[1], boards[0]}, result)
+
+	result, err = ds.AllBoards(ctx, domain.NewQueryParams().WithLimit(1).WithCursor(2500))
+	a.Nil(err)
+	a.Equal([]domain.Board{boards[1]}, result)
+}
+
 func getContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 2*time.Second)
 }
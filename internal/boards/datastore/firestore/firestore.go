@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/docstore"
 
 	"github.com/dkinzler/kit/errors"
 	fs "github.com/dkinzler/kit/firebase/firestore"
@@ -17,60 +18,59 @@ const boardInviteCollectionName = "boardInvites"
 
 // We store a board and all its users and invites together in a single document,
 // since we often want to read all that data together.
-// The number of users and invites is limited, so we don't have to worry about a document
-// becoming too large (firestore 1MB document size limit).
+// The number of users and invites is limited (see domain.LimitsConfig, even limits configured for individual boards are bounded),
+// so we don't have to worry about a document becoming too large (firestore 1MB document size limit).
 // To make it easier to query and sort all the boards a user is a member of and all the invites for a user (across boards),
 // we also store board users and invites as separate documents in separate collections.
 //
 // The cost of this approach is that we have to perform multiple writes when updating a board or adding/removing users/invites.
 // However the number of times the board data is read is probably much higher than the number of changes to the board and its users/invites.
 type firestoreBoardDataStore struct {
-	client                *firestore.Client
-	boardCollection       *firestore.CollectionRef
-	boardUserCollection   *firestore.CollectionRef
-	boardInviteCollection *firestore.CollectionRef
+	store docstore.Store
 }
 
-func NewFirestoreBoardDataStore(client *firestore.Client) domain.BoardDataStore {
-	return &firestoreBoardDataStore{
-		client:                client,
-		boardCollection:       client.Collection(boardCollectionName),
-		boardUserCollection:   client.Collection(boardUserCollectionName),
-		boardInviteCollection: client.Collection(boardInviteCollectionName),
-	}
+func NewFirestoreBoardDataStore(client *firestore.Client) domain.BoardAdminDataStore {
+	return newFirestoreBoardDataStore(docstore.NewFirestore(client))
+}
+
+// Tests use an in-memory docstore.Fake instead of a firestore client.
+func newFirestoreBoardDataStore(store docstore.Store) *firestoreBoardDataStore {
+	return &firestoreBoardDataStore{store: store}
 }
 
-type transactionExpectation fs.TransactionExpectations
+type transactionExpectation docstore.Expectations
 
 func (te transactionExpectation) Combine(other domain.TransactionExpectation) domain.TransactionExpectation {
 	o, ok := other.(transactionExpectation)
 	if !ok {
 		return te
 	}
-	combined := transactionExpectation{}
-	for path, t := range te {
-		combined[path] = t
-	}
-	for path, t := range o {
-		existing, ok := combined[path]
-		// if there already exists an expectation for the same document
-		// choose the expecation with the later update time
-		if !ok || existing.UpdateTime.Before(t.UpdateTime) {
-			combined[path] = t
-		}
-	}
-	return combined
+	// if there already exists an expectation for the same document
+	// the expecation with the later update time is chosen
+	return transactionExpectation(docstore.Expectations(te).Combine(docstore.Expectations(o)))
 }
 
 func combinedId(boardId string, other string) string {
 	return boardId + "." + other
 }
 
+func boardRef(boardId string) docstore.DocRef {
+	return docstore.DocRef{Collection: boardCollectionName, Id: boardId}
+}
+
+func boardUserRef(boardId string, userId string) docstore.DocRef {
+	return docstore.DocRef{Collection: boardUserCollectionName, Id: combinedId(boardId, userId)}
+}
+
+func boardInviteRef(boardId string, inviteId string) docstore.DocRef {
+	return docstore.DocRef{Collection: boardInviteCollectionName, Id: combinedId(boardId, inviteId)}
+}
+
 func (f *firestoreBoardDataStore) UpdateBoard(ctx context.Context, boardId string, update *domain.DatastoreBoardUpdate) error {
 	if update == nil || update.IsEmpty() {
 		return fs.NewFirestoreError(nil, errors.InvalidArgument).WithInternalMessage("empty update, this might be a bug")
 	}
-	err := f.client.RunTransaction(ctx, func(c context.Context, t *firestore.Transaction) error {
+	err := f.store.RunTransaction(ctx, func(c context.Context, t docstore.Transaction) error {
 		// verify transaction expectations
 		te := update.TransactionExpecation
 		if te != nil {
@@ -78,23 +78,19 @@ func (f *firestoreBoardDataStore) UpdateBoard(ctx context.Context, boardId strin
 			if !ok {
 				return fs.NewFirestoreError(nil, errors.Internal).WithInternalMessage("passed transaction expectation of wrong type")
 			}
-			docRefs := fs.TransactionExpectations(lte).DocRefs()
-			snaps, err := t.GetAll(docRefs)
-			if err != nil {
-				return fs.NewFirestoreError(nil, errors.Internal)
-			}
-			err = fs.TransactionExpectations(lte).Verify(snaps)
+			err := docstore.Expectations(lte).Verify(t)
 			if err != nil {
 				return err
 			}
 		}
 
-		// We only want to update a small number of users/invites in a map of users/invites.
+		// We only want to update a small number of users/invites/join requests in a map of users/invites/join requests.
 		// And we also might want to remove a user/invite from the map of users/invites.
 		// This is only possible by using a map, not with a fsBoardWithUsersAndInvites struct.
 		u := map[string]interface{}{}
 		users := map[string]interface{}{}
 		invites := map[string]interface{}{}
+		joinRequests := map[string]interface{}{}
 
 		mergePaths := []firestore.FieldPath{}
 
@@ -109,7 +105,7 @@ func (f *firestoreBoardDataStore) UpdateBoard(ctx context.Context, boardId strin
 			users[userId] = fsu
 			mergePaths = append(mergePaths, firestore.FieldPath{"users", userId})
 
-			err := t.Set(f.boardUserCollection.Doc(combinedId(boardId, userId)), fsu)
+			err := t.Set(boardUserRef(boardId, userId), fsu)
 			if err != nil {
 				return err
 			}
@@ -119,7 +115,7 @@ func (f *firestoreBoardDataStore) UpdateBoard(ctx context.Context, boardId strin
 			users[userId] = firestore.Delete
 			mergePaths = append(mergePaths, firestore.FieldPath{"users", userId})
 
-			err := t.Delete(f.boardUserCollection.Doc(combinedId(boardId, userId)))
+			err := t.Delete(boardUserRef(boardId, userId))
 			if err != nil {
 				return err
 			}
@@ -131,7 +127,7 @@ func (f *firestoreBoardDataStore) UpdateBoard(ctx context.Context, boardId strin
 			invites[inviteId] = fsi
 			mergePaths = append(mergePaths, firestore.FieldPath{"invites", inviteId})
 
-			err := t.Set(f.boardInviteCollection.Doc(combinedId(boardId, inviteId)), fsi)
+			err := t.Set(boardInviteRef(boardId, inviteId), fsi)
 			if err != nil {
 				return err
 			}
@@ -141,73 +137,90 @@ func (f *firestoreBoardDataStore) UpdateBoard(ctx context.Context, boardId strin
 			invites[inviteId] = firestore.Delete
 			mergePaths = append(mergePaths, firestore.FieldPath{"invites", inviteId})
 
-			err := t.Delete(f.boardInviteCollection.Doc(combinedId(boardId, inviteId)))
+			err := t.Delete(boardInviteRef(boardId, inviteId))
 			if err != nil {
 				return err
 			}
 		}
 
+		for _, jr := range update.UpdateJoinRequests {
+			joinRequests[jr.JoinRequestId] = newFsBoardJoinRequest(jr)
+			mergePaths = append(mergePaths, firestore.FieldPath{"joinRequests", jr.JoinRequestId})
+		}
+
+		for _, joinRequestId := range update.RemoveJoinRequests {
+			joinRequests[joinRequestId] = firestore.Delete
+			mergePaths = append(mergePaths, firestore.FieldPath{"joinRequests", joinRequestId})
+		}
+
 		if len(users) > 0 {
 			u["users"] = users
 		}
 		if len(invites) > 0 {
 			u["invites"] = invites
 		}
+		if len(joinRequests) > 0 {
+			u["joinRequests"] = joinRequests
+		}
 
-		err := t.Set(f.boardCollection.Doc(boardId), u, firestore.Merge(mergePaths...))
+		err := t.MergeSet(boardRef(boardId), u, mergePaths...)
 		if err != nil {
 			return err
 		}
 
 		return nil
-	}, firestore.MaxAttempts(1))
+	})
 	return err
 }
 
 func (f *firestoreBoardDataStore) DeleteBoard(ctx context.Context, boardId string) error {
-	err := f.client.RunTransaction(ctx, func(c context.Context, t *firestore.Transaction) error {
+	err := f.store.RunTransaction(ctx, func(c context.Context, t docstore.Transaction) error {
 		var b fsBoardWithUsersAndInvites
-		snap, err := t.Get(f.boardCollection.Doc(boardId))
+		doc, err := t.Get(boardRef(boardId))
 		if err != nil {
 			return err
 		}
-		err = fs.UnmarshalDocSnapshot(snap, &b)
+		err = doc.DataTo(&b)
 		if err != nil {
 			return err
 		}
 
 		for userId := range b.Users {
-			err = t.Delete(f.boardUserCollection.Doc(combinedId(boardId, userId)))
+			err = t.Delete(boardUserRef(boardId, userId))
 			if err != nil {
 				return err
 			}
 		}
 
 		for inviteId := range b.Invites {
-			err = t.Delete(f.boardInviteCollection.Doc(combinedId(boardId, inviteId)))
+			err = t.Delete(boardInviteRef(boardId, inviteId))
 			if err != nil {
 				return err
 			}
 		}
 
-		err = t.Delete(f.boardCollection.Doc(boardId))
+		err = t.Delete(boardRef(boardId))
 		if err != nil {
 			return err
 		}
 
 		return nil
-	}, firestore.MaxAttempts(1))
+	})
 	return err
 }
 
 func (f *firestoreBoardDataStore) Board(ctx context.Context, boardId string) (domain.BoardWithUsersAndInvites, domain.TransactionExpectation, error) {
+	doc, err := f.store.Get(ctx, boardRef(boardId))
+	if err != nil {
+		return domain.BoardWithUsersAndInvites{}, nil, err
+	}
 	var board fsBoardWithUsersAndInvites
-	te, err := fs.GetDocumentByIdWithTE(ctx, f.boardCollection, boardId, &board)
+	err = doc.DataTo(&board)
 	if err != nil {
 		return domain.BoardWithUsersAndInvites{}, nil, err
 	}
 
-	return newDomainBoardWithUsersAndInvites(board), transactionExpectation(te), nil
+	return newDomainBoardWithUsersAndInvites(board), transactionExpectation(docstore.NewExpectations(doc)), nil
 }
 
 func (f *firestoreBoardDataStore) Boards(ctx context.Context, boardIds []string) ([]domain.Board, error) {
@@ -215,22 +228,22 @@ func (f *firestoreBoardDataStore) Boards(ctx context.Context, boardIds []string)
 
 	}
 
-	docRefs := make([]*firestore.DocumentRef, len(boardIds))
+	refs := make([]docstore.DocRef, len(boardIds))
 	for i, boardId := range boardIds {
-		docRefs[i] = f.boardCollection.Doc(boardId)
+		refs[i] = boardRef(boardId)
 	}
 
-	snaps, err := f.client.GetAll(ctx, docRefs)
+	docs, err := f.store.GetAll(ctx, refs)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]domain.Board, 0, len(snaps))
-	for _, snap := range snaps {
-		if snap.Exists() {
+	result := make([]domain.Board, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Exists() {
 			var board fsBoardWithUsersAndInvites
 			//TODO can this be optimized? we dont really need to unmarshal the users and invites, we just need the board
-			err := fs.UnmarshalDocSnapshot(snap, &board)
+			err := doc.DataTo(&board)
 			if err != nil {
 				return nil, err
 			}
@@ -242,25 +255,25 @@ func (f *firestoreBoardDataStore) Boards(ctx context.Context, boardIds []string)
 }
 
 func (f *firestoreBoardDataStore) BoardsForUser(ctx context.Context, userId string, qp domain.QueryParams) ([]domain.Board, error) {
-	query := f.boardUserCollection.Where("user.userId", "==", userId).Select("boardId").OrderBy("createdTime", firestore.Desc)
+	query := docstore.NewQuery(boardUserCollectionName).Where("user.userId", "==", userId).Select("boardId").OrderBy("createdTime", firestore.Desc)
 	if qp.Cursor != 0 {
 		query = query.StartAt(qp.Cursor)
 	}
 	if qp.Limit != 0 {
 		query = query.Limit(qp.Limit)
 	}
-	snaps, err := fs.GetDocumentsForQuery(ctx, query)
+	docs, err := f.store.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(snaps) == 0 {
+	if len(docs) == 0 {
 		return []domain.Board{}, nil
 	}
 
-	boardIds := make([]string, len(snaps))
-	for i, snap := range snaps {
-		v, err := snap.DataAt("boardId")
+	boardIds := make([]string, len(docs))
+	for i, doc := range docs {
+		v, err := doc.DataAt("boardId")
 		if err != nil {
 			return nil, err
 		}
@@ -273,9 +286,65 @@ func (f *firestoreBoardDataStore) BoardsForUser(ctx context.Context, userId stri
 	return f.Boards(ctx, boardIds)
 }
 
+func (f *firestoreBoardDataStore) PublicBoards(ctx context.Context, qp domain.QueryParams) ([]domain.Board, error) {
+	query := docstore.NewQuery(boardCollectionName).Where("board.visibility", "==", domain.VisibilityPublic).Select("board").OrderBy("board.createdTime", firestore.Desc)
+	if qp.Cursor != 0 {
+		query = query.StartAt(qp.Cursor)
+	}
+	if qp.Limit != 0 {
+		query = query.Limit(qp.Limit)
+	}
+	docs, err := f.store.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Board, len(docs))
+	for i, doc := range docs {
+		var board fsBoardWithUsersAndInvites
+		err := doc.DataTo(&board)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = newDomainBoard(board.Board)
+	}
+
+	return result, nil
+}
+
+func (f *firestoreBoardDataStore) AllBoards(ctx context.Context, qp domain.QueryParams) ([]domain.Board, error) {
+	query := docstore.NewQuery(boardCollectionName).Select("board").OrderBy("board.createdTime", firestore.Desc)
+	if qp.Cursor != 0 {
+		query = query.StartAt(qp.Cursor)
+	}
+	if qp.Limit != 0 {
+		query = query.Limit(qp.Limit)
+	}
+	docs, err := f.store.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Board, len(docs))
+	for i, doc := range docs {
+		var board fsBoardWithUsersAndInvites
+		err := doc.DataTo(&board)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = newDomainBoard(board.Board)
+	}
+
+	return result, nil
+}
+
 func (f *firestoreBoardDataStore) User(ctx context.Context, boardId string, userId string) (domain.BoardUser, error) {
+	doc, err := f.store.Get(ctx, boardUserRef(boardId, userId))
+	if err != nil {
+		return domain.BoardUser{}, err
+	}
 	var boardUser fsBoardUser
-	err := fs.GetDocumentById(ctx, f.boardUserCollection, combinedId(boardId, userId), &boardUser)
+	err = doc.DataTo(&boardUser)
 	if err != nil {
 		return domain.BoardUser{}, err
 	}
@@ -283,22 +352,22 @@ func (f *firestoreBoardDataStore) User(ctx context.Context, boardId string, user
 }
 
 func (f *firestoreBoardDataStore) InvitesForUser(ctx context.Context, userId string, qp domain.QueryParams) (map[string]domain.BoardInvite, error) {
-	query := f.boardInviteCollection.Where("user.userId", "==", userId).OrderBy("createdTime", firestore.Desc)
+	query := docstore.NewQuery(boardInviteCollectionName).Where("user.userId", "==", userId).OrderBy("createdTime", firestore.Desc)
 	if qp.Cursor != 0 {
 		query = query.StartAt(qp.Cursor)
 	}
 	if qp.Limit != 0 {
 		query = query.Limit(qp.Limit)
 	}
-	snaps, err := fs.GetDocumentsForQuery(ctx, query)
+	docs, err := f.store.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[string]domain.BoardInvite, len(snaps))
-	for _, snap := range snaps {
+	result := make(map[string]domain.BoardInvite, len(docs))
+	for _, doc := range docs {
 		var invite fsBoardInvite
-		err = fs.UnmarshalDocSnapshot(snap, &invite)
+		err = doc.DataTo(&invite)
 		if err != nil {
 			return nil, err
 		}
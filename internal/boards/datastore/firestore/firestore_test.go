@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/dkinzler/linkboards/internal/boards/datastore"
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/docstore"
 
 	"github.com/dkinzler/kit/firebase"
 	"github.com/dkinzler/kit/firebase/emulator"
@@ -64,6 +66,97 @@ func TestFirestoreBoardDataStore(t *testing.T) {
 	datastore.DatastoreTest(ds, t)
 }
 
+// This test requires a running firestore emulator.
+func TestFirestoreBoardAdminDataStore(t *testing.T) {
+	// Skip this test if the environment variable is not set.
+	if pid := os.Getenv("FIREBASE_PROJECT_ID"); pid == "" {
+		t.Skip("set FIREBASE_PROJECT_ID to run these tests")
+	}
+
+	a := assert.New(t)
+
+	client, err := initTest(t)
+	a.Nil(err)
+
+	ds := NewFirestoreBoardDataStore(client)
+	datastore.AdminDatastoreTest(ds, t)
+}
+
+// Runs the data store tests against an in-memory fake of firestore, no emulator required.
+func TestFirestoreBoardDataStoreWithFake(t *testing.T) {
+	datastore.DatastoreTest(newFirestoreBoardDataStore(docstore.NewFake()), t)
+}
+
+func TestFirestoreBoardAdminDataStoreWithFake(t *testing.T) {
+	datastore.AdminDatastoreTest(newFirestoreBoardDataStore(docstore.NewFake()), t)
+}
+
+// Verifies the layout of the documents, queries depend on the field names.
+func TestFirestoreBoardDocuments(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	fake := docstore.NewFake()
+	ds := newFirestoreBoardDataStore(fake)
+
+	user := domain.User{UserId: "u-1", Name: "User 1"}
+	board := domain.Board{BoardId: "b-1", Name: "Board", CreatedTime: 42, CreatedBy: user, Visibility: domain.VisibilityPublic}
+	err := ds.UpdateBoard(ctx, "b-1", &domain.DatastoreBoardUpdate{
+		UpdateBoard: true,
+		Board:       board,
+		UpdateUsers: []domain.BoardUser{{User: user, Role: "owner", CreatedTime: 42}},
+		UpdateInvites: []domain.BoardInvite{
+			{InviteId: "i-1", Role: "viewer", User: domain.User{UserId: "u-2"}, CreatedTime: 43, CreatedBy: user},
+		},
+	})
+	a.Nil(err)
+
+	doc, err := fake.Get(ctx, docstore.DocRef{Collection: boardCollectionName, Id: "b-1"})
+	a.Nil(err)
+	v, err := doc.DataAt("board.createdTime")
+	a.Nil(err)
+	a.Equal(int64(42), v)
+	v, err = doc.DataAt("board.visibility")
+	a.Nil(err)
+	a.Equal(string(domain.VisibilityPublic), v)
+	v, err = doc.DataAt("users.u-1.role")
+	a.Nil(err)
+	a.Equal("owner", v)
+	_, err = doc.DataAt("invites.i-1")
+	a.Nil(err)
+
+	doc, err = fake.Get(ctx, docstore.DocRef{Collection: boardUserCollectionName, Id: "b-1.u-1"})
+	a.Nil(err)
+	v, err = doc.DataAt("user.userId")
+	a.Nil(err)
+	a.Equal("u-1", v)
+	v, err = doc.DataAt("boardId")
+	a.Nil(err)
+	a.Equal("b-1", v)
+
+	doc, err = fake.Get(ctx, docstore.DocRef{Collection: boardInviteCollectionName, Id: "b-1.i-1"})
+	a.Nil(err)
+	v, err = doc.DataAt("user.userId")
+	a.Nil(err)
+	a.Equal("u-2", v)
+
+	// removing a user deletes the field of the board document and the board user document
+	err = ds.UpdateBoard(ctx, "b-1", &domain.DatastoreBoardUpdate{RemoveUsers: []string{"u-1"}, RemoveInvites: []string{"i-1"}})
+	a.Nil(err)
+	doc, err = fake.Get(ctx, docstore.DocRef{Collection: boardCollectionName, Id: "b-1"})
+	a.Nil(err)
+	_, err = doc.DataAt("users.u-1")
+	a.NotNil(err)
+	_, err = doc.DataAt("invites.i-1")
+	a.NotNil(err)
+	_, err = doc.DataAt("board.name")
+	a.Nil(err)
+	_, err = fake.Get(ctx, docstore.DocRef{Collection: boardUserCollectionName, Id: "b-1.u-1"})
+	a.NotNil(err)
+	_, err = fake.Get(ctx, docstore.DocRef{Collection: boardInviteCollectionName, Id: "b-1.i-1"})
+	a.NotNil(err)
+}
+
 func getContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 2*time.Second)
 }
@@ -1,6 +1,9 @@
 package firestore
 
-import "github.com/dkinzler/linkboards/internal/boards/domain"
+import (
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+)
 
 // We define separate types here, since the way we store the data in firestore
 // is slightly different from the domain types.
@@ -27,9 +30,16 @@ type fsBoard struct {
 	CreatedBy    fsUser `firestore:"createdBy"`
 	ModifiedTime int64  `firestore:"modifiedTime"`
 	ModifiedBy   fsUser `firestore:"modifiedBy"`
+
+	CustomRoles []fsCustomRole `firestore:"customRoles"`
+	Visibility  string         `firestore:"visibility"`
 }
 
 func newFsBoard(board domain.Board) fsBoard {
+	var customRoles []fsCustomRole
+	for _, role := range board.CustomRoles {
+		customRoles = append(customRoles, newFsCustomRole(role))
+	}
 	return fsBoard{
 		BoardId:      board.BoardId,
 		Name:         board.Name,
@@ -38,6 +48,24 @@ func newFsBoard(board domain.Board) fsBoard {
 		CreatedBy:    newFsUser(board.CreatedBy),
 		ModifiedTime: board.ModifiedTime,
 		ModifiedBy:   newFsUser(board.ModifiedBy),
+		CustomRoles:  customRoles,
+		Visibility:   board.Visibility,
+	}
+}
+
+type fsCustomRole struct {
+	Name   string   `firestore:"name"`
+	Scopes []string `firestore:"scopes"`
+}
+
+func newFsCustomRole(r domain.CustomRole) fsCustomRole {
+	scopes := make([]string, len(r.Scopes))
+	for i, scope := range r.Scopes {
+		scopes[i] = string(scope)
+	}
+	return fsCustomRole{
+		Name:   r.Name,
+		Scopes: scopes,
 	}
 }
 
@@ -85,11 +113,28 @@ func newFsBoardInvite(i domain.BoardInvite, boardId string) fsBoardInvite {
 	}
 }
 
+type fsBoardJoinRequest struct {
+	JoinRequestId string `firestore:"joinRequestId"`
+	User          fsUser `firestore:"user"`
+	CreatedTime   int64  `firestore:"createdTime"`
+}
+
+func newFsBoardJoinRequest(jr domain.BoardJoinRequest) fsBoardJoinRequest {
+	return fsBoardJoinRequest{
+		JoinRequestId: jr.JoinRequestId,
+		User:          newFsUser(jr.User),
+		CreatedTime:   jr.CreatedTime,
+	}
+}
+
 type fsBoardWithUsersAndInvites struct {
 	Board fsBoard `firestore:"board"`
 
 	Users   map[string]fsBoardUser   `firestore:"users"`
 	Invites map[string]fsBoardInvite `firestore:"invites"`
+	// Unlike users and invites, join requests are only stored with the board,
+	// since they are never queried across boards.
+	JoinRequests map[string]fsBoardJoinRequest `firestore:"joinRequests"`
 }
 
 func newDomainUser(fs fsUser) domain.User {
@@ -100,6 +145,10 @@ func newDomainUser(fs fsUser) domain.User {
 }
 
 func newDomainBoard(fs fsBoard) domain.Board {
+	var customRoles []domain.CustomRole
+	for _, role := range fs.CustomRoles {
+		customRoles = append(customRoles, newDomainCustomRole(role))
+	}
 	return domain.Board{
 		BoardId:      fs.BoardId,
 		Name:         fs.Name,
@@ -108,6 +157,19 @@ func newDomainBoard(fs fsBoard) domain.Board {
 		CreatedBy:    newDomainUser(fs.CreatedBy),
 		ModifiedTime: fs.ModifiedTime,
 		ModifiedBy:   newDomainUser(fs.ModifiedBy),
+		CustomRoles:  customRoles,
+		Visibility:   fs.Visibility,
+	}
+}
+
+func newDomainCustomRole(fs fsCustomRole) domain.CustomRole {
+	scopes := make([]auth.Scope, len(fs.Scopes))
+	for i, scope := range fs.Scopes {
+		scopes[i] = auth.Scope(scope)
+	}
+	return domain.CustomRole{
+		Name:   fs.Name,
+		Scopes: scopes,
 	}
 }
 
@@ -133,6 +195,14 @@ func newDomainBoardInvite(fs fsBoardInvite) domain.BoardInvite {
 	}
 }
 
+func newDomainBoardJoinRequest(fs fsBoardJoinRequest) domain.BoardJoinRequest {
+	return domain.BoardJoinRequest{
+		JoinRequestId: fs.JoinRequestId,
+		User:          newDomainUser(fs.User),
+		CreatedTime:   fs.CreatedTime,
+	}
+}
+
 func newDomainBoardWithUsersAndInvites(f fsBoardWithUsersAndInvites) domain.BoardWithUsersAndInvites {
 	result := domain.BoardWithUsersAndInvites{
 		Board: newDomainBoard(f.Board),
@@ -148,7 +218,13 @@ func newDomainBoardWithUsersAndInvites(f fsBoardWithUsersAndInvites) domain.Boar
 		invites = append(invites, newDomainBoardInvite(invite))
 	}
 
+	joinRequests := make([]domain.BoardJoinRequest, 0, len(f.JoinRequests))
+	for _, jr := range f.JoinRequests {
+		joinRequests = append(joinRequests, newDomainBoardJoinRequest(jr))
+	}
+
 	result.Users = users
 	result.Invites = invites
+	result.JoinRequests = joinRequests
 	return result
 }
@@ -3,6 +3,7 @@ package firestore
 import (
 	"testing"
 
+	"github.com/dkinzler/linkboards/internal/auth"
 	"github.com/dkinzler/linkboards/internal/boards/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -35,6 +36,13 @@ func TestFirestoreTypes(t *testing.T) {
 
 	a.Equal(board, newDomainBoard(newFsBoard(board)))
 
+	board.CustomRoles = []domain.CustomRole{
+		{Name: "curator", Scopes: []auth.Scope{"links:delete", "boards:view"}},
+		{Name: "guest", Scopes: []auth.Scope{"links:query"}},
+	}
+	board.Visibility = domain.VisibilityPublic
+	a.Equal(board, newDomainBoard(newFsBoard(board)))
+
 	boardUser1 := domain.BoardUser{
 		User:         user1,
 		Role:         "role1",
@@ -75,10 +83,19 @@ func TestFirestoreTypes(t *testing.T) {
 	a.Equal(boardInvite1, newDomainBoardInvite(newFsBoardInvite(boardInvite1, "b-123")))
 	a.Equal(boardInvite2, newDomainBoardInvite(newFsBoardInvite(boardInvite2, "b-123")))
 
+	joinRequest := domain.BoardJoinRequest{
+		JoinRequestId: "j-1",
+		User:          user2,
+		CreatedTime:   45,
+	}
+
+	a.Equal(joinRequest, newDomainBoardJoinRequest(newFsBoardJoinRequest(joinRequest)))
+
 	boardWithUAndI := domain.BoardWithUsersAndInvites{
-		Board:   board,
-		Users:   []domain.BoardUser{boardUser1, boardUser2},
-		Invites: []domain.BoardInvite{boardInvite1, boardInvite2},
+		Board:        board,
+		Users:        []domain.BoardUser{boardUser1, boardUser2},
+		Invites:      []domain.BoardInvite{boardInvite1, boardInvite2},
+		JoinRequests: []domain.BoardJoinRequest{joinRequest},
 	}
 
 	fsBoardWithUAndI := fsBoardWithUsersAndInvites{
@@ -91,10 +108,14 @@ func TestFirestoreTypes(t *testing.T) {
 			boardInvite1.InviteId: newFsBoardInvite(boardInvite1, "b-123"),
 			boardInvite2.InviteId: newFsBoardInvite(boardInvite2, "b-123"),
 		},
+		JoinRequests: map[string]fsBoardJoinRequest{
+			joinRequest.JoinRequestId: newFsBoardJoinRequest(joinRequest),
+		},
 	}
 
 	r := newDomainBoardWithUsersAndInvites(fsBoardWithUAndI)
 	a.Equal(boardWithUAndI.Board, r.Board)
 	a.ElementsMatch(boardWithUAndI.Users, r.Users)
 	a.ElementsMatch(boardWithUAndI.Invites, r.Invites)
+	a.ElementsMatch(boardWithUAndI.JoinRequests, r.JoinRequests)
 }
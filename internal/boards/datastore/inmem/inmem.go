@@ -21,6 +21,9 @@ type inmemBoardDataStore struct {
 	// maps from boardId to the set of invites of that board
 	// a set of invites is represented as a map from inviteId to invite
 	invites map[string]map[string]domain.BoardInvite
+	// maps from boardId to the set of join requests of that board
+	// a set of join requests is represented as a map from joinRequestId to join request
+	joinRequests map[string]map[string]domain.BoardJoinRequest
 	// maps from boardId to version
 	// the version of a board is increased every time it is modified
 	version map[string]int
@@ -31,12 +34,13 @@ type transactionExpectation struct {
 	version int
 }
 
-func NewInmemBoardDataStore() domain.BoardDataStore {
+func NewInmemBoardDataStore() domain.BoardAdminDataStore {
 	return &inmemBoardDataStore{
-		boards:  make(map[string]domain.Board),
-		users:   make(map[string]map[string]domain.BoardUser),
-		invites: make(map[string]map[string]domain.BoardInvite),
-		version: make(map[string]int),
+		boards:       make(map[string]domain.Board),
+		users:        make(map[string]map[string]domain.BoardUser),
+		invites:      make(map[string]map[string]domain.BoardInvite),
+		joinRequests: make(map[string]map[string]domain.BoardJoinRequest),
+		version:      make(map[string]int),
 	}
 }
 
@@ -113,6 +117,20 @@ func (s *inmemBoardDataStore) UpdateBoard(ctx context.Context, boardId string, u
 			delete(boardInvites, invite)
 		}
 	}
+	for _, jr := range update.UpdateJoinRequests {
+		boardJoinRequests, ok := s.joinRequests[boardId]
+		if !ok {
+			boardJoinRequests = make(map[string]domain.BoardJoinRequest)
+			s.joinRequests[boardId] = boardJoinRequests
+		}
+		boardJoinRequests[jr.JoinRequestId] = jr
+	}
+	for _, jr := range update.RemoveJoinRequests {
+		boardJoinRequests, ok := s.joinRequests[boardId]
+		if ok {
+			delete(boardJoinRequests, jr)
+		}
+	}
 	s.version[boardId] += 1
 	return nil
 }
@@ -123,6 +141,7 @@ func (s *inmemBoardDataStore) DeleteBoard(ctx context.Context, boardId string) e
 	delete(s.boards, boardId)
 	delete(s.users, boardId)
 	delete(s.invites, boardId)
+	delete(s.joinRequests, boardId)
 	delete(s.version, boardId)
 	return nil
 }
@@ -147,6 +166,10 @@ func (s *inmemBoardDataStore) Board(ctx context.Context, boardId string) (domain
 	if ok {
 		result.Invites = copyInvites(invites)
 	}
+	joinRequests, ok := s.joinRequests[boardId]
+	if ok {
+		result.JoinRequests = copyJoinRequests(joinRequests)
+	}
 
 	return result, te, nil
 }
@@ -210,6 +233,53 @@ func (s *inmemBoardDataStore) BoardsForUser(ctx context.Context, userId string,
 	return result, nil
 }
 
+func (s *inmemBoardDataStore) PublicBoards(ctx context.Context, qp domain.QueryParams) ([]domain.Board, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	result := make([]domain.Board, 0)
+	for _, board := range s.boards {
+		if board.Visibility != domain.VisibilityPublic {
+			continue
+		}
+		if qp.Cursor != 0 && board.CreatedTime > qp.Cursor {
+			continue
+		}
+		result = append(result, board)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedTime > result[j].CreatedTime
+	})
+
+	if qp.Limit > 0 && qp.Limit < len(result) {
+		result = result[0:qp.Limit]
+	}
+
+	return result, nil
+}
+
+func (s *inmemBoardDataStore) AllBoards(ctx context.Context, qp domain.QueryParams) ([]domain.Board, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	result := make([]domain.Board, 0, len(s.boards))
+	for _, board := range s.boards {
+		if qp.Cursor != 0 && board.CreatedTime > qp.Cursor {
+			continue
+		}
+		result = append(result, board)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedTime > result[j].CreatedTime
+	})
+
+	if qp.Limit > 0 && qp.Limit < len(result) {
+		result = result[0:qp.Limit]
+	}
+
+	return result, nil
+}
+
 func (s *inmemBoardDataStore) User(ctx context.Context, boardId string, userId string) (domain.BoardUser, error) {
 	s.m.RLock()
 	defer s.m.RUnlock()
@@ -293,3 +363,13 @@ func copyInvites(x map[string]domain.BoardInvite) []domain.BoardInvite {
 	}
 	return result
 }
+
+func copyJoinRequests(x map[string]domain.BoardJoinRequest) []domain.BoardJoinRequest {
+	result := make([]domain.BoardJoinRequest, len(x))
+	i := 0
+	for _, jr := range x {
+		result[i] = jr
+		i++
+	}
+	return result
+}
@@ -11,3 +11,7 @@ func TestInmemBoardDataStore(t *testing.T) {
 
 	datastore.DatastoreTest(s, t)
 }
+
+func TestInmemBoardAdminDataStore(t *testing.T) {
+	datastore.AdminDatastoreTest(NewInmemBoardDataStore(), t)
+}
@@ -0,0 +1,63 @@
+// Package instrumented provides decorators for BoardDataStore implementations,
+// that record metrics of calls (see package metrics) or create a span for every call (see package tracing).
+package instrumented
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/metrics"
+
+	"github.com/dkinzler/kit/time"
+)
+
+const dataStoreName = "boards"
+
+type instrumentedBoardDataStore struct {
+	ds domain.BoardDataStore
+	m  *metrics.Metrics
+}
+
+func NewInstrumentedBoardDataStore(ds domain.BoardDataStore, m *metrics.Metrics) domain.BoardDataStore {
+	return &instrumentedBoardDataStore{ds: ds, m: m}
+}
+
+func (i *instrumentedBoardDataStore) UpdateBoard(ctx context.Context, boardId string, update *domain.DatastoreBoardUpdate) (err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "UpdateBoard", time.CurrTime(), &err)
+	return i.ds.UpdateBoard(ctx, boardId, update)
+}
+
+func (i *instrumentedBoardDataStore) DeleteBoard(ctx context.Context, boardId string) (err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "DeleteBoard", time.CurrTime(), &err)
+	return i.ds.DeleteBoard(ctx, boardId)
+}
+
+func (i *instrumentedBoardDataStore) Board(ctx context.Context, boardId string) (_ domain.BoardWithUsersAndInvites, _ domain.TransactionExpectation, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "Board", time.CurrTime(), &err)
+	return i.ds.Board(ctx, boardId)
+}
+
+func (i *instrumentedBoardDataStore) Boards(ctx context.Context, boardIds []string) (_ []domain.Board, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "Boards", time.CurrTime(), &err)
+	return i.ds.Boards(ctx, boardIds)
+}
+
+func (i *instrumentedBoardDataStore) BoardsForUser(ctx context.Context, userId string, qp domain.QueryParams) (_ []domain.Board, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "BoardsForUser", time.CurrTime(), &err)
+	return i.ds.BoardsForUser(ctx, userId, qp)
+}
+
+func (i *instrumentedBoardDataStore) PublicBoards(ctx context.Context, qp domain.QueryParams) (_ []domain.Board, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "PublicBoards", time.CurrTime(), &err)
+	return i.ds.PublicBoards(ctx, qp)
+}
+
+func (i *instrumentedBoardDataStore) User(ctx context.Context, boardId string, userId string) (_ domain.BoardUser, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "User", time.CurrTime(), &err)
+	return i.ds.User(ctx, boardId, userId)
+}
+
+func (i *instrumentedBoardDataStore) InvitesForUser(ctx context.Context, userId string, qp domain.QueryParams) (_ map[string]domain.BoardInvite, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "InvitesForUser", time.CurrTime(), &err)
+	return i.ds.InvitesForUser(ctx, userId, qp)
+}
@@ -0,0 +1,73 @@
+package instrumented
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/boards/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionConflicts(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	reg := prometheus.NewRegistry()
+	m, err := metrics.New(reg)
+	a.Nil(err)
+	ds := NewInstrumentedBoardDataStore(inmem.NewInmemBoardDataStore(), m)
+
+	user := domain.User{UserId: "u-123"}
+	board, err := domain.NewBoard("Board", "", user, domain.DefaultLimits())
+	a.Nil(err)
+	boardUser, err := domain.NewBoardUser(user, auth.BoardRoleOwner, user, nil)
+	a.Nil(err)
+	a.Nil(ds.UpdateBoard(ctx, board.BoardId, domain.NewDatastoreBoardUpdate(nil).WithBoard(board).UpdateUser(boardUser)))
+
+	_, te, err := ds.Board(ctx, board.BoardId)
+	a.Nil(err)
+	board.Name = "New name"
+	a.Nil(ds.UpdateBoard(ctx, board.BoardId, domain.NewDatastoreBoardUpdate(te).WithBoard(board)))
+	// the board was modified since te was returned
+	a.NotNil(ds.UpdateBoard(ctx, board.BoardId, domain.NewDatastoreBoardUpdate(te).WithBoard(board)))
+
+	expected := `
+# HELP linkboards_datastore_transaction_conflicts_total Number of optimistic transactions that failed because the data was modified concurrently.
+# TYPE linkboards_datastore_transaction_conflicts_total counter
+linkboards_datastore_transaction_conflicts_total{datastore="boards",method="UpdateBoard"} 1
+`
+	a.Nil(testutil.GatherAndCompare(reg, strings.NewReader(expected), "linkboards_datastore_transaction_conflicts_total"))
+	count, err := testutil.GatherAndCount(reg, "linkboards_datastore_call_duration_seconds")
+	a.Nil(err)
+	// UpdateBoard succeeded and failed, Board succeeded
+	a.Equal(3, count)
+}
+
+func TestTracingBoardDataStore(t *testing.T) {
+	a := assert.New(t)
+	tp, exporter := tracing.NewInmemTracerProvider()
+	ds := NewTracingBoardDataStore(inmem.NewInmemBoardDataStore(), tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, _, err := ds.Board(ctx, "b-123")
+	a.NotNil(err)
+	_, err = ds.BoardsForUser(ctx, "u-123", domain.QueryParams{})
+	a.Nil(err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	a.Len(spans, 3)
+	a.Equal("boards.Board", spans[0].Name)
+	a.Equal("boards.BoardsForUser", spans[1].Name)
+	for _, s := range spans[:2] {
+		a.Equal(parent.SpanContext().SpanID(), s.Parent.SpanID())
+	}
+}
@@ -0,0 +1,68 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracingBoardDataStore struct {
+	ds     domain.BoardDataStore
+	tracer trace.Tracer
+}
+
+// Returns a BoardDataStore that creates a span for every call to the given data store.
+func NewTracingBoardDataStore(ds domain.BoardDataStore, tp trace.TracerProvider) domain.BoardDataStore {
+	return &tracingBoardDataStore{ds: ds, tracer: tracing.Tracer(tp)}
+}
+
+func (t *tracingBoardDataStore) UpdateBoard(ctx context.Context, boardId string, update *domain.DatastoreBoardUpdate) (err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "UpdateBoard")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.UpdateBoard(ctx, boardId, update)
+}
+
+func (t *tracingBoardDataStore) DeleteBoard(ctx context.Context, boardId string) (err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "DeleteBoard")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.DeleteBoard(ctx, boardId)
+}
+
+func (t *tracingBoardDataStore) Board(ctx context.Context, boardId string) (_ domain.BoardWithUsersAndInvites, _ domain.TransactionExpectation, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "Board")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.Board(ctx, boardId)
+}
+
+func (t *tracingBoardDataStore) Boards(ctx context.Context, boardIds []string) (_ []domain.Board, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "Boards")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.Boards(ctx, boardIds)
+}
+
+func (t *tracingBoardDataStore) BoardsForUser(ctx context.Context, userId string, qp domain.QueryParams) (_ []domain.Board, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "BoardsForUser")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.BoardsForUser(ctx, userId, qp)
+}
+
+func (t *tracingBoardDataStore) PublicBoards(ctx context.Context, qp domain.QueryParams) (_ []domain.Board, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "PublicBoards")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.PublicBoards(ctx, qp)
+}
+
+func (t *tracingBoardDataStore) User(ctx context.Context, boardId string, userId string) (_ domain.BoardUser, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "User")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.User(ctx, boardId, userId)
+}
+
+func (t *tracingBoardDataStore) InvitesForUser(ctx context.Context, userId string, qp domain.QueryParams) (_ map[string]domain.BoardInvite, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "InvitesForUser")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.InvitesForUser(ctx, userId, qp)
+}
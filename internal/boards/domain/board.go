@@ -46,13 +46,47 @@ type Board struct {
 	ModifiedTime int64
 	// User that performed the latest modification
 	ModifiedBy User
+
+	// Roles defined by the owner of the board, in addition to the built-in roles of the auth package.
+	CustomRoles []CustomRole
+
+	// Determines who can view the board and its links besides its members, see the Visibility constants.
+	// Boards created before visibility settings were introduced have an empty value, which is treated like VisibilityPrivate.
+	Visibility string
+}
+
+const (
+	// Only members can view the board.
+	VisibilityPrivate = "private"
+	// Anyone that knows the id of the board can view it, including unauthenticated users.
+	VisibilityLink = "link"
+	// Like VisibilityLink, but the board is also listed publicly.
+	VisibilityPublic = "public"
+)
+
+func isVisibilityValid(v string) bool {
+	return v == VisibilityPrivate || v == VisibilityLink || v == VisibilityPublic
+}
+
+// Returns the visibility of the board, boards without a value are private.
+func (b Board) EffectiveVisibility() string {
+	if b.Visibility == "" {
+		return VisibilityPrivate
+	}
+	return b.Visibility
+}
+
+// Returns true if users that are not members of the board can view it.
+func (b Board) IsVisibleToNonMembers() bool {
+	return b.Visibility == VisibilityLink || b.Visibility == VisibilityPublic
 }
 
 func newError(inner error, code errors.ErrorCode) errors.Error {
 	return errors.New(inner, "boards/domain", code)
 }
 
-func NewBoard(name, description string, user User) (Board, error) {
+// The name and description of the board are validated using the given limits.
+func NewBoard(name, description string, user User, l Limits) (Board, error) {
 	id, err := uuid.NewUUIDWithPrefix("b")
 	if err != nil {
 		return Board{}, newError(err, errors.Internal).WithInternalMessage("error creating random uuid")
@@ -68,9 +102,10 @@ func NewBoard(name, description string, user User) (Board, error) {
 		CreatedBy:    user,
 		ModifiedTime: timeNow,
 		ModifiedBy:   user,
+		Visibility:   VisibilityPrivate,
 	}
 
-	err = board.IsValid()
+	err = board.IsValid(l)
 	if err != nil {
 		return Board{}, err
 	}
@@ -78,42 +113,114 @@ func NewBoard(name, description string, user User) (Board, error) {
 	return board, nil
 }
 
-func (b *Board) IsValid() error {
-	if err := b.IsNameValid(); err != nil {
+func (b *Board) IsValid(l Limits) error {
+	if err := b.IsNameValid(l); err != nil {
 		return err
 	}
-	if err := b.IsDescriptionValid(); err != nil {
+	if err := b.IsDescriptionValid(l); err != nil {
 		return err
 	}
+	if b.Visibility != "" && !isVisibilityValid(b.Visibility) {
+		return newError(nil, errors.InvalidArgument).WithPublicMessage("invalid visibility").WithPublicCode(errInvalidVisibility)
+	}
 	return nil
 }
 
-const nameMaxLength = 100
-
-// A board name is valid if it is not empty and not longer than nameMaxLength bytes.
-func (b *Board) IsNameValid() error {
+// A board name is valid if it is not empty and not longer than l.NameMaxLength bytes.
+func (b *Board) IsNameValid(l Limits) error {
 	if len(b.Name) == 0 {
 		return newError(nil, errors.InvalidArgument).WithPublicMessage("empty name").WithPublicCode(errBoardNameEmpty)
 	}
-	if len(b.Name) > nameMaxLength {
+	if len(b.Name) > l.NameMaxLength {
 		return newError(nil, errors.InvalidArgument).WithPublicMessage("name too long").WithPublicCode(errBoardNameTooLong)
 	}
 	return nil
 }
 
-const descriptionMaxLength = 1000
-
-// A board description can be at most descriptionMaxLength bytes long.
-func (b *Board) IsDescriptionValid() error {
-	if len(b.Description) > descriptionMaxLength {
+// A board description can be at most l.DescriptionMaxLength bytes long.
+func (b *Board) IsDescriptionValid(l Limits) error {
+	if len(b.Description) > l.DescriptionMaxLength {
 		return newError(nil, errors.InvalidArgument).WithPublicMessage("description too long").WithPublicCode(errBoardDescriptionTooLong)
 	}
 	return nil
 }
 
+// Returns the custom role with the given name.
+func (b Board) CustomRole(name string) (CustomRole, bool) {
+	for _, role := range b.CustomRoles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return CustomRole{}, false
+}
+
+// A custom role grants a set of scopes, that can be chosen from the scopes registered by the components of the app.
+// E.g. a "curator" role could allow deleting links but not inviting other users.
+type CustomRole struct {
+	Name   string
+	Scopes []auth.Scope
+}
+
+const maxCustomRolesPerBoard = 10
+const customRoleNameMaxLength = 32
+const maxScopesPerCustomRole = 50
+
+// A custom role is valid if
+//   - the name consists of 1 to customRoleNameMaxLength lowercase letters, digits or "-" and is not reserved, see auth.IsRoleNameReserved
+//   - it grants at least one scope, all scopes are registered and none of them is owner-only.
+func (r CustomRole) IsValid() error {
+	if !isCustomRoleNameValid(r.Name) {
+		return newError(nil, errors.InvalidArgument).WithPublicMessage("invalid role name").WithPublicCode(errInvalidCustomRole)
+	}
+	if len(r.Scopes) == 0 || len(r.Scopes) > maxScopesPerCustomRole {
+		return newError(nil, errors.InvalidArgument).WithPublicMessage("invalid number of scopes").WithPublicCode(errInvalidCustomRole)
+	}
+	seen := make(map[auth.Scope]struct{}, len(r.Scopes))
+	for _, scope := range r.Scopes {
+		if !auth.IsScopeRegistered(scope) {
+			return newError(nil, errors.InvalidArgument).WithPublicMessage("invalid scope: " + string(scope)).WithPublicCode(errInvalidCustomRole)
+		}
+		if auth.IsOwnerOnlyScope(scope) {
+			return newError(nil, errors.InvalidArgument).WithPublicMessage("scope can only be granted to the owner: " + string(scope)).WithPublicCode(errInvalidCustomRole)
+		}
+		if _, ok := seen[scope]; ok {
+			return newError(nil, errors.InvalidArgument).WithPublicMessage("duplicate scope: " + string(scope)).WithPublicCode(errInvalidCustomRole)
+		}
+		seen[scope] = struct{}{}
+	}
+	return nil
+}
+
+func isCustomRoleNameValid(name string) bool {
+	if len(name) == 0 || len(name) > customRoleNameMaxLength || auth.IsRoleNameReserved(name) {
+		return false
+	}
+	for _, c := range name {
+		if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if role is a built-in role or one of the given custom roles.
+func isRoleValid(role string, customRoles []CustomRole) bool {
+	if auth.IsBoardRoleValid(role) {
+		return true
+	}
+	for _, r := range customRoles {
+		if r.Name == role {
+			return true
+		}
+	}
+	return false
+}
+
 type BoardUser struct {
 	User User
 	// Role the user has for the board, see the auth package for available roles.
+	// Can also be one of the custom roles of the board.
 	// Determines what the user can do on the board.
 	Role string
 	// Time the user joined the board as Unix time (nanoseconds).
@@ -125,12 +232,13 @@ type BoardUser struct {
 	ModifiedBy   User
 }
 
-func NewBoardUser(user User, role string, invitedBy User) (BoardUser, error) {
+// The role must be a built-in role or one of the given custom roles of the board.
+func NewBoardUser(user User, role string, invitedBy User, customRoles []CustomRole) (BoardUser, error) {
 	if user.UserId == "" {
 		return BoardUser{}, newError(nil, errors.InvalidArgument).WithInternalMessage("user id empty")
 	}
 
-	if !auth.IsBoardRoleValid(role) {
+	if !isRoleValid(role, customRoles) {
 		return BoardUser{}, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid role").WithPublicCode(errInvalidRole)
 	}
 
@@ -146,8 +254,9 @@ func NewBoardUser(user User, role string, invitedBy User) (BoardUser, error) {
 	}, nil
 }
 
-func (b *BoardUser) ChangeRole(role string, modifiedBy User) error {
-	if !auth.IsBoardRoleValid(role) {
+// The role must be a built-in role or one of the given custom roles of the board.
+func (b *BoardUser) ChangeRole(role string, modifiedBy User, customRoles []CustomRole) error {
+	if !isRoleValid(role, customRoles) {
 		return newError(nil, errors.InvalidArgument).WithPublicMessage("invalid role").WithPublicCode(errInvalidRole)
 	}
 	// If user has owner role, their role cannot be changed.
@@ -186,8 +295,9 @@ type BoardInvite struct {
 	ExpiresTime int64
 }
 
-func NewBoardInvite(role string, createdBy User, forUser User, expiryDuration stdtime.Duration) (BoardInvite, error) {
-	if !auth.IsBoardRoleValid(role) {
+// The role must be a built-in role or one of the given custom roles of the board.
+func NewBoardInvite(role string, createdBy User, forUser User, expiryDuration stdtime.Duration, customRoles []CustomRole) (BoardInvite, error) {
+	if !isRoleValid(role, customRoles) {
 		return BoardInvite{}, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid role").WithPublicCode(errInvalidRole)
 	}
 
@@ -221,9 +331,39 @@ func (i BoardInvite) IsExpired() bool {
 	return time.CurrTime().After(expiresTime)
 }
 
-// A board with all its users and invites is the unit of consistency of this component.
+// A request of a user to join a board, e.g. a board they discovered because it is public.
+// Users of the board that can invite other users can approve or reject the request.
+type BoardJoinRequest struct {
+	// Random UUIDv4 with prefix "j-"
+	JoinRequestId string
+
+	// User that wants to join the board.
+	User User
+
+	// Time the request was created as Unix time (nanoseconds).
+	CreatedTime int64
+}
+
+func NewBoardJoinRequest(user User) (BoardJoinRequest, error) {
+	if user.UserId == "" {
+		return BoardJoinRequest{}, newError(nil, errors.InvalidArgument).WithInternalMessage("user id empty")
+	}
+
+	id, err := uuid.NewUUIDWithPrefix("j")
+	if err != nil {
+		return BoardJoinRequest{}, newError(nil, errors.Internal).WithInternalMessage("error creating uuid")
+	}
+
+	return BoardJoinRequest{
+		JoinRequestId: id,
+		User:          user,
+		CreatedTime:   time.CurrTimeUnixNano(),
+	}, nil
+}
+
+// A board with all its users, invites and join requests is the unit of consistency of this component.
 // I.e. service methods and data stores should guarantee that
-// concurrent operations on a single board (including its users and invites) can't lead to data inconsistencies.
+// concurrent operations on a single board (including its users, invites and join requests) can't lead to data inconsistencies.
 //
 // This struct does not provide methods to manipulate the sets of users and invites,
 // instead the updates will be encoded using a DatastoreBoardUpdate value.
@@ -231,9 +371,37 @@ func (i BoardInvite) IsExpired() bool {
 // Note also that since the number of users and invites for a single board is limited,
 // we do not have to worry about the implications of loading all users/invites for performance and memory.
 type BoardWithUsersAndInvites struct {
-	Board   Board
-	Users   []BoardUser
-	Invites []BoardInvite
+	Board        Board
+	Users        []BoardUser
+	Invites      []BoardInvite
+	JoinRequests []BoardJoinRequest
+}
+
+func (b BoardWithUsersAndInvites) JoinRequest(joinRequestId string) (BoardJoinRequest, bool) {
+	for _, jr := range b.JoinRequests {
+		if jr.JoinRequestId == joinRequestId {
+			return jr, true
+		}
+	}
+	return BoardJoinRequest{}, false
+}
+
+func (b BoardWithUsersAndInvites) ContainsJoinRequestForUser(userId string) bool {
+	_, ok := b.JoinRequestForUser(userId)
+	return ok
+}
+
+func (b BoardWithUsersAndInvites) JoinRequestForUser(userId string) (BoardJoinRequest, bool) {
+	for _, jr := range b.JoinRequests {
+		if jr.User.UserId == userId {
+			return jr, true
+		}
+	}
+	return BoardJoinRequest{}, false
+}
+
+func (b BoardWithUsersAndInvites) JoinRequestCount() int {
+	return len(b.JoinRequests)
 }
 
 func (b BoardWithUsersAndInvites) ContainsInvite(inviteId string) bool {
@@ -297,3 +465,18 @@ func (b BoardWithUsersAndInvites) User(userId string) (BoardUser, bool) {
 func (b BoardWithUsersAndInvites) UserCount() int {
 	return len(b.Users)
 }
+
+// Returns true if a user or invite of the board has the given role.
+func (b BoardWithUsersAndInvites) IsRoleInUse(role string) bool {
+	for _, user := range b.Users {
+		if user.Role == role {
+			return true
+		}
+	}
+	for _, invite := range b.Invites {
+		if invite.Role == role {
+			return true
+		}
+	}
+	return false
+}
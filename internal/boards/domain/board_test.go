@@ -24,33 +24,33 @@ func TestBoardCreation(t *testing.T) {
 	}
 
 	//empty name
-	board, err := NewBoard("", "", User{})
+	board, err := NewBoard("", "", User{}, DefaultLimits())
 	a.Empty(board)
 	a.NotNil(err)
 	a.True(errors.HasPublicCode(err, errBoardNameEmpty))
 
 	//name too long
 	var sb strings.Builder
-	for i := 0; i <= descriptionMaxLength; i++ {
+	for i := 0; i <= DefaultLimits().DescriptionMaxLength; i++ {
 		sb.WriteString("a")
 	}
 
 	longString := sb.String()
 
-	board, err = NewBoard(longString, "", User{})
+	board, err = NewBoard(longString, "", User{}, DefaultLimits())
 	a.Empty(board)
 	a.NotNil(err)
 	a.True(errors.HasPublicCode(err, errBoardNameTooLong))
 
-	board, err = NewBoard("name", longString, User{})
+	board, err = NewBoard("name", longString, User{}, DefaultLimits())
 	a.Empty(board)
 	a.NotNil(err)
 	a.True(errors.HasPublicCode(err, errBoardDescriptionTooLong))
 
 	//this should work
-	board, err = NewBoard("name", "description", User{UserId: "abc", Name: "xyz"})
+	board, err = NewBoard("name", "description", User{UserId: "abc", Name: "xyz"}, DefaultLimits())
 	a.Nil(err)
-	a.Nil(board.IsValid())
+	a.Nil(board.IsValid(DefaultLimits()))
 	a.True(len(board.BoardId) > 10)
 	a.Equal("name", board.Name)
 	a.Equal("description", board.Description)
@@ -67,17 +67,17 @@ func TestBoardUserCreation(t *testing.T) {
 		return defaultTime
 	}
 
-	user, err := NewBoardUser(User{}, auth.BoardRoleOwner, User{})
+	user, err := NewBoardUser(User{}, auth.BoardRoleOwner, User{}, nil)
 	a.NotNil(err)
 	a.Empty(user)
 	a.True(errors.IsInvalidArgumentError(err))
 
-	user, err = NewBoardUser(User{UserId: "uid", Name: "Testi Tester"}, "invalidrole", User{UserId: "abc", Name: "xyz"})
+	user, err = NewBoardUser(User{UserId: "uid", Name: "Testi Tester"}, "invalidrole", User{UserId: "abc", Name: "xyz"}, nil)
 	a.NotNil(err)
 	a.Empty(user)
 	a.True(errors.HasPublicCode(err, errInvalidRole))
 
-	user, err = NewBoardUser(User{UserId: "uid", Name: "Testi Tester"}, auth.BoardRoleOwner, User{UserId: "abc", Name: "xyz"})
+	user, err = NewBoardUser(User{UserId: "uid", Name: "Testi Tester"}, auth.BoardRoleOwner, User{UserId: "abc", Name: "xyz"}, nil)
 	a.Nil(err)
 	a.Equal(User{UserId: "uid", Name: "Testi Tester"}, user.User)
 	a.Equal(auth.BoardRoleOwner, user.Role)
@@ -86,17 +86,17 @@ func TestBoardUserCreation(t *testing.T) {
 	a.Equal(defaultTimeUnix, user.CreatedTime)
 	a.Equal(defaultTimeUnix, user.ModifiedTime)
 
-	err = user.ChangeRole("invalidrole", User{})
+	err = user.ChangeRole("invalidrole", User{}, nil)
 	a.NotNil(err)
 	a.True(errors.HasPublicCode(err, errInvalidRole))
 
-	err = user.ChangeRole(auth.BoardRoleEditor, User{UserId: "123"})
+	err = user.ChangeRole(auth.BoardRoleEditor, User{UserId: "123"}, nil)
 	a.NotNil(err)
 	a.True(errors.HasPublicCode(err, errCannotChangeRoleOfOwner))
 
-	user, err = NewBoardUser(User{UserId: "uid", Name: "Testi Tester"}, auth.BoardRoleViewer, User{UserId: "abc", Name: "xyz"})
+	user, err = NewBoardUser(User{UserId: "uid", Name: "Testi Tester"}, auth.BoardRoleViewer, User{UserId: "abc", Name: "xyz"}, nil)
 	a.Nil(err)
-	err = user.ChangeRole(auth.BoardRoleEditor, User{UserId: "123"})
+	err = user.ChangeRole(auth.BoardRoleEditor, User{UserId: "123"}, nil)
 	a.Nil(err)
 	a.Equal(auth.BoardRoleEditor, user.Role)
 	a.Equal(User{UserId: "123"}, user.ModifiedBy)
@@ -109,12 +109,12 @@ func TestInviteCreation(t *testing.T) {
 		return defaultTime
 	}
 
-	invite, err := NewBoardInvite("invalidrole", User{}, User{}, 1000*stdtime.Second)
+	invite, err := NewBoardInvite("invalidrole", User{}, User{}, 1000*stdtime.Second, nil)
 	a.NotNil(err)
 	a.Empty(invite)
 	a.True(errors.HasPublicCode(err, errInvalidRole))
 
-	invite, err = NewBoardInvite(auth.BoardRoleEditor, User{UserId: "u-123"}, User{UserId: "u-456"}, 42*stdtime.Second)
+	invite, err = NewBoardInvite(auth.BoardRoleEditor, User{UserId: "u-123"}, User{UserId: "u-456"}, 42*stdtime.Second, nil)
 	a.Nil(err)
 	a.True(len(invite.InviteId) > 10)
 	a.Equal(auth.BoardRoleEditor, invite.Role)
@@ -16,7 +16,7 @@ type BoardDataStore interface {
 	// if the board wasn't changed (i.e. it is still in the same state represented by the TransactionExpectation).
 	UpdateBoard(ctx context.Context, boardId string, update *DatastoreBoardUpdate) error
 	DeleteBoard(ctx context.Context, boardId string) error
-	// Returns the board with the given id together with all its users and invites.
+	// Returns the board with the given id together with all its users, invites and join requests.
 	// There are (almost) no separate methods to query or retrieve specific users/invites.
 	// This is feasible since the number of users and invites per board is limited.
 	// If in the future we wanted to remove these limits, it would make sense to treat boards, board users and board invites
@@ -27,12 +27,22 @@ type BoardDataStore interface {
 	Board(ctx context.Context, boardId string) (BoardWithUsersAndInvites, TransactionExpectation, error)
 	Boards(ctx context.Context, boardIds []string) ([]Board, error)
 	BoardsForUser(ctx context.Context, userId string, qp QueryParams) ([]Board, error)
+	// Returns the boards with visibility VisibilityPublic, sorted by descending created time of the boards.
+	PublicBoards(ctx context.Context, qp QueryParams) ([]Board, error)
 
 	User(ctx context.Context, boardId string, userId string) (BoardUser, error)
 
 	InvitesForUser(ctx context.Context, userId string, qp QueryParams) (map[string]BoardInvite, error)
 }
 
+// Extends BoardDataStore with queries needed to administer the application, e.g. by the linkboards-admin command.
+// They are not used by BoardService.
+type BoardAdminDataStore interface {
+	BoardDataStore
+	// Returns all boards, regardless of their visibility, sorted by descending created time of the boards.
+	AllBoards(ctx context.Context, qp QueryParams) ([]Board, error)
+}
+
 // Defines an update to a single board and its users and/or invites.
 //
 // The set of changes to the users/invites are defined using update and remove lists, instead of just
@@ -53,6 +63,10 @@ type DatastoreBoardUpdate struct {
 	UpdateInvites []BoardInvite
 	// Invite ids of invites that should be removed
 	RemoveInvites []string
+
+	UpdateJoinRequests []BoardJoinRequest
+	// Join request ids of join requests that should be removed
+	RemoveJoinRequests []string
 }
 
 func NewDatastoreBoardUpdate(te TransactionExpectation) *DatastoreBoardUpdate {
@@ -85,8 +99,19 @@ func (u *DatastoreBoardUpdate) RemoveInvite(inviteId string) *DatastoreBoardUpda
 	return u
 }
 
+func (u *DatastoreBoardUpdate) UpdateJoinRequest(jr BoardJoinRequest) *DatastoreBoardUpdate {
+	u.UpdateJoinRequests = append(u.UpdateJoinRequests, jr)
+	return u
+}
+
+func (u *DatastoreBoardUpdate) RemoveJoinRequest(joinRequestId string) *DatastoreBoardUpdate {
+	u.RemoveJoinRequests = append(u.RemoveJoinRequests, joinRequestId)
+	return u
+}
+
 func (u *DatastoreBoardUpdate) IsEmpty() bool {
-	return !(u.UpdateBoard || len(u.UpdateUsers) > 0 || len(u.RemoveUsers) > 0 || len(u.UpdateInvites) > 0 || len(u.RemoveInvites) > 0)
+	return !(u.UpdateBoard || len(u.UpdateUsers) > 0 || len(u.RemoveUsers) > 0 || len(u.UpdateInvites) > 0 || len(u.RemoveInvites) > 0 ||
+		len(u.UpdateJoinRequests) > 0 || len(u.RemoveJoinRequests) > 0)
 }
 
 // A TransactionExpectation should contain information about when some piece of data
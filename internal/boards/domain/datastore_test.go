@@ -53,6 +53,14 @@ func TestDatastoreBoardUpdate(t *testing.T) {
 	a.ElementsMatch([]string{"u-1", "u-2"}, u.RemoveUsers)
 	a.ElementsMatch([]BoardInvite{bi1, bi2}, u.UpdateInvites)
 	a.ElementsMatch([]string{"i-1", "i-2"}, u.RemoveInvites)
+
+	jr := BoardJoinRequest{JoinRequestId: "j-1"}
+	u = NewDatastoreBoardUpdate(nil).UpdateJoinRequest(jr)
+	a.False(u.IsEmpty())
+	a.Equal([]BoardJoinRequest{jr}, u.UpdateJoinRequests)
+	u = NewDatastoreBoardUpdate(nil).RemoveJoinRequest(jr.JoinRequestId)
+	a.False(u.IsEmpty())
+	a.Equal([]string{"j-1"}, u.RemoveJoinRequests)
 }
 
 func TestDatastoreQueryParams(t *testing.T) {
@@ -14,7 +14,7 @@ type EventPublisher interface {
 	PublishEvent(ctx context.Context, event interface{})
 }
 
-// TODO could add more events like InviteCreated, InviteAccepted etc.
+// TODO could add more events like InviteCreated, InviteDeclined etc.
 
 type BoardCreated struct {
 	BoardId     string
@@ -29,6 +29,36 @@ type BoardDeleted struct {
 	DeletedBy User
 }
 
+// A user joined a board by accepting an invite or because their join request was approved.
+type BoardUserAdded struct {
+	BoardId string
+	User    BoardUser
+}
+
+type BoardUserRemoved struct {
+	BoardId string
+	UserId  string
+}
+
+// The user of a board was edited, e.g. their role changed.
+type BoardUserEdited struct {
+	BoardId string
+	User    BoardUser
+}
+
+// The custom roles of a board were created, changed or deleted.
+// CustomRoles contains all the custom roles the board has now.
+type BoardCustomRolesChanged struct {
+	BoardId     string
+	CustomRoles []CustomRole
+}
+
+// The visibility of a board changed such that users that are not members can now view it or no longer can.
+type BoardVisibilityChanged struct {
+	BoardId    string
+	Visibility string
+}
+
 /*
 Implements EventPublisher by wrapping another EventPublisher or nil.
 Forwards any calls to PublishEvent to the wrapped EventPublisher if it is not nil.
@@ -48,3 +78,22 @@ func (m *maybeEventPublisher) PublishEvent(ctx context.Context, event interface{
 		m.ep.PublishEvent(ctx, event)
 	}
 }
+
+// Returns an EventPublisher that publishes every event to all the given publishers in order, nil publishers are skipped.
+func CombineEventPublishers(eps ...EventPublisher) EventPublisher {
+	var result multiEventPublisher
+	for _, ep := range eps {
+		if ep != nil {
+			result = append(result, ep)
+		}
+	}
+	return result
+}
+
+type multiEventPublisher []EventPublisher
+
+func (m multiEventPublisher) PublishEvent(ctx context.Context, event interface{}) {
+	for _, ep := range m {
+		ep.PublishEvent(ctx, event)
+	}
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	stdtime "time"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+// Limits restrict the size of a board and its users and invites.
+// Zero values are replaced by the values of DefaultLimits.
+type Limits struct {
+	// Pending join requests count against this limit, since every one of them could be approved.
+	MaxUsersPerBoard     int
+	MaxInvitesPerBoard   int
+	InviteExpiryDuration stdtime.Duration
+	NameMaxLength        int
+	DescriptionMaxLength int
+	// Number of boards a user can create per day (UTC), only enforced if the BoardService has a quota store.
+	// Since new boards do not have overrides, only the default value is used.
+	MaxBoardsPerUserPerDay int
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		MaxUsersPerBoard:       32,
+		MaxInvitesPerBoard:     32,
+		InviteExpiryDuration:   3 * 24 * stdtime.Hour,
+		NameMaxLength:          100,
+		DescriptionMaxLength:   1000,
+		MaxBoardsPerUserPerDay: 50,
+	}
+}
+
+// Upper bounds for configurable limits.
+// A board is stored together with all its users, invites and join requests (see the comments on BoardService and in the firestore data store),
+// these bounds guarantee that this data stays small enough, e.g. to fit in a single firestore document (max 1MB).
+// With about 500 bytes per user/invite/join request, a board stays well below 1MB.
+const maxUsersAndInvitesPerBoard = 1000
+const maxNameLength = 1000
+const maxDescriptionLength = 10000
+
+// Returns a copy of l where zero values are replaced by the values of d.
+func (l Limits) withDefaults(d Limits) Limits {
+	if l.MaxUsersPerBoard == 0 {
+		l.MaxUsersPerBoard = d.MaxUsersPerBoard
+	}
+	if l.MaxInvitesPerBoard == 0 {
+		l.MaxInvitesPerBoard = d.MaxInvitesPerBoard
+	}
+	if l.InviteExpiryDuration == 0 {
+		l.InviteExpiryDuration = d.InviteExpiryDuration
+	}
+	if l.NameMaxLength == 0 {
+		l.NameMaxLength = d.NameMaxLength
+	}
+	if l.DescriptionMaxLength == 0 {
+		l.DescriptionMaxLength = d.DescriptionMaxLength
+	}
+	if l.MaxBoardsPerUserPerDay == 0 {
+		l.MaxBoardsPerUserPerDay = d.MaxBoardsPerUserPerDay
+	}
+	return l
+}
+
+func (l Limits) validate() error {
+	if l.MaxUsersPerBoard < 0 || l.MaxInvitesPerBoard < 0 || l.InviteExpiryDuration < 0 || l.NameMaxLength < 0 || l.DescriptionMaxLength < 0 || l.MaxBoardsPerUserPerDay < 0 {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("limits must not be negative")
+	}
+	if l.MaxUsersPerBoard+l.MaxInvitesPerBoard > maxUsersAndInvitesPerBoard {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("too many users and invites per board, board might not fit in a single document")
+	}
+	if l.NameMaxLength > maxNameLength {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("max name length too large")
+	}
+	if l.DescriptionMaxLength > maxDescriptionLength {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("max description length too large")
+	}
+	return nil
+}
+
+// LimitsConfig contains the limits used for all boards, together with overrides for individual boards, e.g. boards of premium teams.
+// Zero values of an override are replaced by the values of Default.
+type LimitsConfig struct {
+	Default Limits
+	// Maps board ids to limits.
+	BoardOverrides map[string]Limits
+}
+
+// Returns the limits for the board with the given id.
+// For new boards that do not have an id yet, pass the empty string to get the default limits.
+func (c LimitsConfig) ForBoard(boardId string) Limits {
+	d := c.Default.withDefaults(DefaultLimits())
+	if o, ok := c.BoardOverrides[boardId]; ok && boardId != "" {
+		return o.withDefaults(d)
+	}
+	return d
+}
+
+// Returns an error if the default limits or one of the overrides is invalid,
+// e.g. if it allows more users and invites than can be stored with a board.
+func (c LimitsConfig) Validate() error {
+	if err := c.ForBoard("").validate(); err != nil {
+		return err
+	}
+	for boardId := range c.BoardOverrides {
+		if err := c.ForBoard(boardId).validate(); err != nil {
+			return newError(err, errors.InvalidArgument).WithInternalMessage("invalid limits for board " + boardId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLimitsConfig(t *testing.T) {
+	a := assert.New(t)
+
+	// zero values are replaced by defaults
+	a.Equal(DefaultLimits(), LimitsConfig{}.ForBoard("b-123"))
+	a.Nil(LimitsConfig{}.Validate())
+
+	c := LimitsConfig{
+		Default: Limits{MaxUsersPerBoard: 10},
+		BoardOverrides: map[string]Limits{
+			"b-premium": {MaxUsersPerBoard: 100, InviteExpiryDuration: 7 * 24 * stdtime.Hour},
+		},
+	}
+	a.Nil(c.Validate())
+
+	l := c.ForBoard("b-123")
+	a.Equal(10, l.MaxUsersPerBoard)
+	a.Equal(DefaultLimits().MaxInvitesPerBoard, l.MaxInvitesPerBoard)
+
+	l = c.ForBoard("b-premium")
+	a.Equal(100, l.MaxUsersPerBoard)
+	a.Equal(7*24*stdtime.Hour, l.InviteExpiryDuration)
+	a.Equal(DefaultLimits().NameMaxLength, l.NameMaxLength)
+
+	// new boards always use the default limits
+	a.Equal(10, c.ForBoard("").MaxUsersPerBoard)
+
+	// a board together with its users and invites must fit in a single document
+	c.BoardOverrides["b-huge"] = Limits{MaxUsersPerBoard: 900, MaxInvitesPerBoard: 200}
+	a.NotNil(c.Validate())
+	a.NotNil(LimitsConfig{Default: Limits{NameMaxLength: -1}}.Validate())
+	a.NotNil(LimitsConfig{Default: Limits{DescriptionMaxLength: maxDescriptionLength + 1}}.Validate())
+}
+
+func TestBoardServiceUsesLimits(t *testing.T) {
+	a := assert.New(t)
+	initTestTime()
+	ctx := context.Background()
+
+	ds := &MockBoardDataStore{}
+	service := NewBoardService(ds, nil, LimitsConfig{
+		Default:        Limits{MaxUsersPerBoard: 2, NameMaxLength: 5},
+		BoardOverrides: map[string]Limits{"b-123": {MaxUsersPerBoard: 3, InviteExpiryDuration: stdtime.Hour}},
+	}, nil)
+
+	_, err := service.CreateBoard(ctx, "too long", "", user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errBoardNameTooLong))
+
+	// the default limits would not allow another user, the override does
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.Anything).Return(nil).Once()
+	invite, err := service.CreateInvite(ctx, "b-123", "viewer", user4, user1)
+	a.Nil(err)
+	a.Equal(testTimeUnix+stdtime.Hour.Nanoseconds(), invite.ExpiresTime)
+	ds.AssertExpectations(t)
+
+	ds.On("Board", "b-456").Return(exampleBoardWithUAndI, nil, nil).Once()
+	_, err = service.CreateInvite(ctx, "b-456", "viewer", user4, user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errMaxBoardUsersReached))
+	ds.AssertExpectations(t)
+}
+
+func TestBoardQuota(t *testing.T) {
+	a := assert.New(t)
+	initTestTime()
+	ctx := context.Background()
+
+	ds := &MockBoardDataStore{}
+	service := NewBoardService(ds, nil, LimitsConfig{Default: Limits{MaxBoardsPerUserPerDay: 2}}, ratelimit.NewInmemQuotaStore())
+
+	ds.On("UpdateBoard", mock.Anything, mock.Anything).Return(nil)
+	for i := 0; i < 2; i++ {
+		_, err := service.CreateBoard(ctx, "Board", "", user1)
+		a.Nil(err)
+	}
+	_, err := service.CreateBoard(ctx, "Board", "", user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errBoardQuotaReached))
+	_, ok := ratelimit.RetryAfter(err)
+	a.True(ok)
+
+	// other users can still create boards
+	_, err = service.CreateBoard(ctx, "Board", "", user2)
+	a.Nil(err)
+
+	// invalid boards don't count against the quota
+	_, err = service.CreateBoard(ctx, "", "", user2)
+	a.True(errors.HasPublicCode(err, errBoardNameEmpty))
+	_, err = service.CreateBoard(ctx, "Board", "", user2)
+	a.Nil(err)
+
+	// neither do boards that could not be written to the data store
+	quotas := ratelimit.NewInmemQuotaStore()
+	failingDs := &MockBoardDataStore{}
+	failingDs.On("UpdateBoard", mock.Anything, mock.Anything).Return(errors.New(nil, "test", errors.Internal))
+	service = NewBoardService(failingDs, nil, LimitsConfig{Default: Limits{MaxBoardsPerUserPerDay: 1}}, quotas)
+	_, err = service.CreateBoard(ctx, "Board", "", user1)
+	a.True(errors.IsInternalError(err))
+	service = NewBoardService(ds, nil, LimitsConfig{Default: Limits{MaxBoardsPerUserPerDay: 1}}, quotas)
+	_, err = service.CreateBoard(ctx, "Board", "", user1)
+	a.Nil(err)
+}
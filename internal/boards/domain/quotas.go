@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	stdtime "time"
+)
+
+// QuotaStore counts how often users performed an action on the current day (UTC), e.g. how many boards they created.
+// BoardService uses it to enforce the MaxBoardsPerUserPerDay limit, see ratelimit.InmemQuotaStore for an implementation.
+// Implementations must be safe for concurrent use.
+type QuotaStore interface {
+	// Increments the counter with the given key if it is below max.
+	// If the quota is used up, false is returned together with the duration after which it is available again.
+	Use(ctx context.Context, key string, max int) (bool, stdtime.Duration, error)
+	// Decrements the counter with the given key, used if the action could not be completed.
+	Refund(ctx context.Context, key string) error
+}
+
+// Inner error of the errors returned when a quota is used up.
+// Package ratelimit uses the RetryAfter method to return responses with status 429 and a Retry-After header.
+type quotaExceededError struct {
+	retryAfter stdtime.Duration
+}
+
+func (e quotaExceededError) Error() string {
+	return "quota exceeded, retry after " + e.retryAfter.String()
+}
+
+func (e quotaExceededError) RetryAfter() stdtime.Duration {
+	return e.retryAfter
+}
@@ -2,7 +2,6 @@ package domain
 
 import (
 	"context"
-	stdtime "time"
 
 	"github.com/dkinzler/linkboards/internal/auth"
 
@@ -26,6 +25,12 @@ const (
 	errBoardOwnerCannotBeRemoved
 	errOnlyCreatorCanBeOwner
 	errCannotChangeRoleOfOwner
+	errInvalidCustomRole
+	errMaxCustomRolesReached
+	errCustomRoleInUse
+	errInvalidVisibility
+	errUserAlreadyRequestedToJoin
+	errBoardQuotaReached
 )
 
 // BoardService provides operations on boards, users and invites.
@@ -47,15 +52,21 @@ const (
 // With optimistic transactions we can have many instances running at the same time
 // without any additional coordination required while still maintaining consistency.
 // (At least if the backing data store provides suitable consistency guarantees.)
+//
+// The number of users and invites of a board as well as the length of its name and description are limited, see LimitsConfig.
 type BoardService struct {
-	ds BoardDataStore
-	ep EventPublisher
+	ds     BoardDataStore
+	ep     EventPublisher
+	limits LimitsConfig
+	quotas QuotaStore
 }
 
 // The BoardDataStore passed must not be nil.
 // The EventPublisher can be, in which case the events will just end up nowhere.
-func NewBoardService(ds BoardDataStore, ep EventPublisher) *BoardService {
-	return &BoardService{ds: ds, ep: newMaybeEventPublisher(ep)}
+// Zero values of limits are replaced by the values of DefaultLimits, use LimitsConfig.Validate to check limits before.
+// The QuotaStore is used to limit the number of boards a user can create per day, if it is nil there is no such limit.
+func NewBoardService(ds BoardDataStore, ep EventPublisher, limits LimitsConfig, quotas QuotaStore) *BoardService {
+	return &BoardService{ds: ds, ep: newMaybeEventPublisher(ep), limits: limits, quotas: quotas}
 }
 
 func newServiceError(inner error, code errors.ErrorCode) errors.Error {
@@ -63,18 +74,37 @@ func newServiceError(inner error, code errors.ErrorCode) errors.Error {
 }
 
 func (bs *BoardService) CreateBoard(ctx context.Context, name, description string, user User) (BoardWithUsersAndInvites, error) {
-	board, err := NewBoard(name, description, user)
+	// a new board does not have any overrides yet
+	limits := bs.limits.ForBoard("")
+	board, err := NewBoard(name, description, user, limits)
 	if err != nil {
 		return BoardWithUsersAndInvites{}, err
 	}
 
-	boardUser, err := NewBoardUser(user, auth.BoardRoleOwner, user)
+	boardUser, err := NewBoardUser(user, auth.BoardRoleOwner, user, nil)
 	if err != nil {
 		return BoardWithUsersAndInvites{}, err
 	}
 
+	quotaKey := "createBoard:" + user.UserId
+	if bs.quotas != nil {
+		ok, retryAfter, err := bs.quotas.Use(ctx, quotaKey, limits.MaxBoardsPerUserPerDay)
+		if err != nil {
+			return BoardWithUsersAndInvites{}, newServiceError(err, errors.Internal).WithInternalMessage("could not check board quota")
+		}
+		if !ok {
+			return BoardWithUsersAndInvites{}, newServiceError(quotaExceededError{retryAfter: retryAfter}, errors.FailedPrecondition).
+				WithPublicCode(errBoardQuotaReached).
+				WithPublicMessage("daily board quota reached")
+		}
+	}
+
 	err = bs.ds.UpdateBoard(ctx, board.BoardId, NewDatastoreBoardUpdate(nil).WithBoard(board).UpdateUser(boardUser))
 	if err != nil {
+		// boards that could not be created don't count against the quota
+		if bs.quotas != nil {
+			bs.quotas.Refund(ctx, quotaKey)
+		}
 		return BoardWithUsersAndInvites{}, newServiceError(err, errors.Internal).WithInternalMessage("could not create board")
 	}
 
@@ -112,6 +142,8 @@ type BoardEdit struct {
 	Name              string
 	UpdateDescription bool
 	Description       string
+	UpdateVisibility  bool
+	Visibility        string
 }
 
 func (bs *BoardService) EditBoard(ctx context.Context, boardId string, be BoardEdit, user User) (Board, error) {
@@ -133,7 +165,14 @@ func (bs *BoardService) EditBoard(ctx context.Context, boardId string, be BoardE
 		board.Description = be.Description
 	}
 
-	err = board.IsValid()
+	if be.UpdateVisibility {
+		if !isVisibilityValid(be.Visibility) {
+			return Board{}, newServiceError(nil, errors.InvalidArgument).WithPublicMessage("invalid visibility").WithPublicCode(errInvalidVisibility)
+		}
+		board.Visibility = be.Visibility
+	}
+
+	err = board.IsValid(bs.limits.ForBoard(boardId))
 	if err != nil {
 		return Board{}, err
 	}
@@ -146,20 +185,17 @@ func (bs *BoardService) EditBoard(ctx context.Context, boardId string, be BoardE
 		return Board{}, newServiceError(err, errors.Internal).WithInternalMessage("could not edit board")
 	}
 
+	if be.UpdateVisibility && b.Board.IsVisibleToNonMembers() != board.IsVisibleToNonMembers() {
+		bs.ep.PublishEvent(ctx, BoardVisibilityChanged{
+			BoardId:    boardId,
+			Visibility: board.Visibility,
+		})
+	}
+
 	return board, nil
 }
 
-const maxInvitesPerBoard = 32
-const maxUsersPerBoard = 32
-const inviteExpiryDuration = 3 * 24 * stdtime.Hour
-
 func (bs *BoardService) CreateInvite(ctx context.Context, boardId string, role string, forUser User, fromUser User) (BoardInvite, error) {
-	// Check first if invite is even valid, if not we don't even have to perform any data store calls
-	invite, err := NewBoardInvite(role, fromUser, forUser, inviteExpiryDuration)
-	if err != nil {
-		return BoardInvite{}, err
-	}
-
 	board, te, err := bs.ds.Board(ctx, boardId)
 	if err != nil {
 		if errors.IsNotFoundError(err) {
@@ -168,12 +204,20 @@ func (bs *BoardService) CreateInvite(ctx context.Context, boardId string, role s
 		return BoardInvite{}, newServiceError(err, errors.Internal).WithInternalMessage("could not get board")
 	}
 
-	if board.InviteCount() >= maxInvitesPerBoard {
+	limits := bs.limits.ForBoard(boardId)
+
+	// The role can be one of the custom roles of the board, so the invite can only be validated after loading the board.
+	invite, err := NewBoardInvite(role, fromUser, forUser, limits.InviteExpiryDuration, board.Board.CustomRoles)
+	if err != nil {
+		return BoardInvite{}, err
+	}
+
+	if board.InviteCount() >= limits.MaxInvitesPerBoard {
 		return BoardInvite{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("maximum number of invites reached").WithPublicCode(errMaxInvitesReached)
 	}
 
 	// cannot create an invite if board is full
-	if board.UserCount() >= maxUsersPerBoard {
+	if board.UserCount() >= limits.MaxUsersPerBoard {
 		return BoardInvite{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("board already has maxiumum number of users").WithPublicCode(errMaxBoardUsersReached)
 	}
 
@@ -243,16 +287,26 @@ func (bs *BoardService) AcceptInvite(ctx context.Context, boardId string, invite
 		}
 	}
 
-	boardUser, err := NewBoardUser(user, invite.Role, invite.CreatedBy)
+	boardUser, err := NewBoardUser(user, invite.Role, invite.CreatedBy, board.Board.CustomRoles)
 	if err != nil {
 		return err
 	}
 
-	err = bs.ds.UpdateBoard(ctx, boardId, NewDatastoreBoardUpdate(te).RemoveInvite(inviteId).UpdateUser(boardUser))
+	// A pending join request of the user is removed, it would otherwise count against the maximum number of users.
+	update := NewDatastoreBoardUpdate(te).RemoveInvite(inviteId).UpdateUser(boardUser)
+	if jr, ok := board.JoinRequestForUser(user.UserId); ok {
+		update = update.RemoveJoinRequest(jr.JoinRequestId)
+	}
+	err = bs.ds.UpdateBoard(ctx, boardId, update)
 	if err != nil {
 		return newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
 	}
 
+	bs.ep.PublishEvent(ctx, BoardUserAdded{
+		BoardId: boardId,
+		User:    boardUser,
+	})
+
 	return nil
 }
 
@@ -306,6 +360,117 @@ func (bs *BoardService) RemoveUser(ctx context.Context, boardId string, userId s
 		return newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
 	}
 
+	bs.ep.PublishEvent(ctx, BoardUserRemoved{
+		BoardId: boardId,
+		UserId:  userId,
+	})
+
+	return nil
+}
+
+// Creates a request of the given user to join the board.
+// Pending join requests count against the maximum number of users of a board, since every one of them could be approved.
+func (bs *BoardService) CreateJoinRequest(ctx context.Context, boardId string, user User) (BoardJoinRequest, error) {
+	jr, err := NewBoardJoinRequest(user)
+	if err != nil {
+		return BoardJoinRequest{}, err
+	}
+
+	board, te, err := bs.ds.Board(ctx, boardId)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return BoardJoinRequest{}, newServiceError(err, errors.NotFound)
+		}
+		return BoardJoinRequest{}, newServiceError(err, errors.Internal).WithInternalMessage("could not get board")
+	}
+
+	if board.ContainsUser(user.UserId) {
+		return BoardJoinRequest{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("user is already on board").WithPublicCode(errUserAlreadyOnBoard)
+	}
+	if board.ContainsJoinRequestForUser(user.UserId) {
+		return BoardJoinRequest{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("user already requested to join").WithPublicCode(errUserAlreadyRequestedToJoin)
+	}
+	if board.UserCount()+board.JoinRequestCount() >= bs.limits.ForBoard(boardId).MaxUsersPerBoard {
+		return BoardJoinRequest{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("board already has maxiumum number of users").WithPublicCode(errMaxBoardUsersReached)
+	}
+
+	// As with invites, the transaction expectation guarantees that no other join request for the same user
+	// was created and that the user did not join the board in the meantime.
+	err = bs.ds.UpdateBoard(ctx, boardId, NewDatastoreBoardUpdate(te).UpdateJoinRequest(jr))
+	if err != nil {
+		return BoardJoinRequest{}, newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
+	}
+
+	return jr, nil
+}
+
+// Adds the user that created the join request to the board with the given role.
+// An invite for the user is removed, since it is no longer needed.
+func (bs *BoardService) ApproveJoinRequest(ctx context.Context, boardId string, joinRequestId string, role string, approvedBy User) (BoardUser, error) {
+	board, te, err := bs.ds.Board(ctx, boardId)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return BoardUser{}, newServiceError(err, errors.NotFound)
+		}
+		return BoardUser{}, newServiceError(err, errors.Internal).WithInternalMessage("could not get board")
+	}
+
+	jr, ok := board.JoinRequest(joinRequestId)
+	if !ok {
+		return BoardUser{}, newServiceError(nil, errors.NotFound)
+	}
+
+	// Same as for invites, only the creator of a board can be owner.
+	if role == auth.BoardRoleOwner {
+		return BoardUser{}, newServiceError(nil, errors.InvalidArgument).WithPublicMessage("only creator can be owner").WithPublicCode(errOnlyCreatorCanBeOwner)
+	}
+	if board.ContainsUser(jr.User.UserId) {
+		return BoardUser{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("user is already on board").WithPublicCode(errUserAlreadyOnBoard)
+	}
+	if board.UserCount() >= bs.limits.ForBoard(boardId).MaxUsersPerBoard {
+		return BoardUser{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("board already has maxiumum number of users").WithPublicCode(errMaxBoardUsersReached)
+	}
+
+	boardUser, err := NewBoardUser(jr.User, role, approvedBy, board.Board.CustomRoles)
+	if err != nil {
+		return BoardUser{}, err
+	}
+
+	update := NewDatastoreBoardUpdate(te).RemoveJoinRequest(joinRequestId).UpdateUser(boardUser)
+	if invite, ok := board.InviteForUser(jr.User.UserId); ok {
+		update = update.RemoveInvite(invite.InviteId)
+	}
+	err = bs.ds.UpdateBoard(ctx, boardId, update)
+	if err != nil {
+		return BoardUser{}, newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
+	}
+
+	bs.ep.PublishEvent(ctx, BoardUserAdded{
+		BoardId: boardId,
+		User:    boardUser,
+	})
+
+	return boardUser, nil
+}
+
+func (bs *BoardService) RejectJoinRequest(ctx context.Context, boardId string, joinRequestId string) error {
+	board, te, err := bs.ds.Board(ctx, boardId)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return newServiceError(err, errors.NotFound)
+		}
+		return newServiceError(err, errors.Internal).WithInternalMessage("could not get board")
+	}
+
+	if _, ok := board.JoinRequest(joinRequestId); !ok {
+		return newServiceError(nil, errors.NotFound)
+	}
+
+	err = bs.ds.UpdateBoard(ctx, boardId, NewDatastoreBoardUpdate(te).RemoveJoinRequest(joinRequestId))
+	if err != nil {
+		return newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
+	}
+
 	return nil
 }
 
@@ -330,7 +495,7 @@ func (bs *BoardService) EditBoardUser(ctx context.Context, boardId string, userI
 	u, _ := board.User(userId)
 
 	if bue.UpdateRole {
-		err = u.ChangeRole(bue.Role, user)
+		err = u.ChangeRole(bue.Role, user, board.Board.CustomRoles)
 		if err != nil {
 			return BoardUser{}, err
 		}
@@ -341,5 +506,100 @@ func (bs *BoardService) EditBoardUser(ctx context.Context, boardId string, userI
 		return BoardUser{}, newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
 	}
 
+	bs.ep.PublishEvent(ctx, BoardUserEdited{
+		BoardId: boardId,
+		User:    u,
+	})
+
 	return u, nil
 }
+
+// Creates a custom role for the board or replaces the scopes of an existing one.
+// Users and invites with this role are affected immediately.
+func (bs *BoardService) SetCustomRole(ctx context.Context, boardId string, role CustomRole, user User) (Board, error) {
+	if err := role.IsValid(); err != nil {
+		return Board{}, err
+	}
+
+	b, te, err := bs.ds.Board(ctx, boardId)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return Board{}, newServiceError(err, errors.NotFound)
+		}
+		return Board{}, newServiceError(err, errors.Internal).WithInternalMessage("could not get board")
+	}
+
+	board := b.Board
+	customRoles := make([]CustomRole, 0, len(board.CustomRoles)+1)
+	replaced := false
+	for _, r := range board.CustomRoles {
+		if r.Name == role.Name {
+			customRoles = append(customRoles, role)
+			replaced = true
+		} else {
+			customRoles = append(customRoles, r)
+		}
+	}
+	if !replaced {
+		if len(board.CustomRoles) >= maxCustomRolesPerBoard {
+			return Board{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("maximum number of custom roles reached").WithPublicCode(errMaxCustomRolesReached)
+		}
+		customRoles = append(customRoles, role)
+	}
+	board.CustomRoles = customRoles
+	board.ModifiedTime = time.CurrTimeUnixNano()
+	board.ModifiedBy = user
+
+	err = bs.ds.UpdateBoard(ctx, boardId, NewDatastoreBoardUpdate(te).WithBoard(board))
+	if err != nil {
+		return Board{}, newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
+	}
+
+	bs.ep.PublishEvent(ctx, BoardCustomRolesChanged{
+		BoardId:     boardId,
+		CustomRoles: board.CustomRoles,
+	})
+
+	return board, nil
+}
+
+// A custom role can only be deleted if no user or invite of the board has the role.
+func (bs *BoardService) DeleteCustomRole(ctx context.Context, boardId string, name string, user User) (Board, error) {
+	b, te, err := bs.ds.Board(ctx, boardId)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return Board{}, newServiceError(err, errors.NotFound)
+		}
+		return Board{}, newServiceError(err, errors.Internal).WithInternalMessage("could not get board")
+	}
+
+	board := b.Board
+	if _, ok := board.CustomRole(name); !ok {
+		return Board{}, newServiceError(nil, errors.NotFound)
+	}
+	if b.IsRoleInUse(name) {
+		return Board{}, newServiceError(nil, errors.FailedPrecondition).WithPublicMessage("role is used by a user or invite").WithPublicCode(errCustomRoleInUse)
+	}
+
+	customRoles := make([]CustomRole, 0, len(board.CustomRoles))
+	for _, r := range board.CustomRoles {
+		if r.Name != name {
+			customRoles = append(customRoles, r)
+		}
+	}
+	board.CustomRoles = customRoles
+	board.ModifiedTime = time.CurrTimeUnixNano()
+	board.ModifiedBy = user
+
+	err = bs.ds.UpdateBoard(ctx, boardId, NewDatastoreBoardUpdate(te).WithBoard(board))
+	if err != nil {
+		return Board{}, newServiceError(err, errors.Internal).WithInternalMessage("could not update board")
+	}
+
+	bs.ep.PublishEvent(ctx, BoardCustomRolesChanged{
+		BoardId:     boardId,
+		CustomRoles: board.CustomRoles,
+	})
+
+	return board, nil
+}
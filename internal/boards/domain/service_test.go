@@ -43,6 +43,11 @@ func (m *MockBoardDataStore) BoardsForUser(ctx context.Context, userId string, q
 	return args.Get(0).([]Board), args.Error(1)
 }
 
+func (m *MockBoardDataStore) PublicBoards(ctx context.Context, qp QueryParams) ([]Board, error) {
+	args := m.Called(qp)
+	return args.Get(0).([]Board), args.Error(1)
+}
+
 func (m *MockBoardDataStore) User(ctx context.Context, boardId string, userId string) (BoardUser, error) {
 	args := m.Called(boardId, userId)
 	return args.Get(0).(BoardUser), args.Error(1)
@@ -55,7 +60,7 @@ func (m *MockBoardDataStore) InvitesForUser(ctx context.Context, userId string,
 
 func newTestService() (BoardService, *MockBoardDataStore) {
 	ds := &MockBoardDataStore{}
-	return *NewBoardService(ds, nil), ds
+	return *NewBoardService(ds, nil, LimitsConfig{}, nil), ds
 }
 
 var testTime = stdtime.Date(2022, 1, 1, 0, 0, 0, 0, stdtime.UTC)
@@ -209,10 +214,7 @@ func TestEditBoard(t *testing.T) {
 		if update == nil || !update.UpdateBoard {
 			return false
 		}
-		if update.Board == expected {
-			return true
-		}
-		return false
+		return assert.ObjectsAreEqual(expected, update.Board)
 	})).Return(nil).Once()
 
 	board, err := service.EditBoard(ctx, "b-123", BoardEdit{
@@ -222,6 +224,40 @@ func TestEditBoard(t *testing.T) {
 	a.Nil(err)
 	a.Equal(expected, board)
 	ds.AssertExpectations(t)
+
+	// fails with invalid visibility
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	_, err = service.EditBoard(ctx, "b-123", BoardEdit{
+		UpdateVisibility: true,
+		Visibility:       "secret",
+	}, user4)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errInvalidVisibility))
+	ds.AssertExpectations(t)
+
+	// events are published when the visibility of a board changes
+	ep := &testEventPublisher{}
+	service = *NewBoardService(ds, ep, LimitsConfig{}, nil)
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && update.Board.Visibility == VisibilityLink
+	})).Return(nil).Once()
+	board, err = service.EditBoard(ctx, "b-123", BoardEdit{
+		UpdateVisibility: true,
+		Visibility:       VisibilityLink,
+	}, user4)
+	a.Nil(err)
+	a.Equal(VisibilityLink, board.Visibility)
+	a.Equal([]interface{}{BoardVisibilityChanged{BoardId: "b-123", Visibility: VisibilityLink}}, ep.events)
+	ds.AssertExpectations(t)
+}
+
+type testEventPublisher struct {
+	events []interface{}
+}
+
+func (t *testEventPublisher) PublishEvent(ctx context.Context, event interface{}) {
+	t.events = append(t.events, event)
 }
 
 func TestCreateInvite(t *testing.T) {
@@ -231,11 +267,14 @@ func TestCreateInvite(t *testing.T) {
 	ctx := context.Background()
 
 	// fails with invalid role
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
 	_, err := service.CreateInvite(ctx, "b-123", "invalidrole", User{}, User{})
 	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errInvalidRole))
+	ds.AssertExpectations(t)
 
 	// fails if max number of invites reached
-	invitesMax := make([]BoardInvite, maxInvitesPerBoard)
+	invitesMax := make([]BoardInvite, DefaultLimits().MaxInvitesPerBoard)
 	ds.On("Board", "b-123").Return(BoardWithUsersAndInvites{Invites: invitesMax}, nil, nil).Once()
 	_, err = service.CreateInvite(ctx, "b-123", auth.BoardRoleViewer, User{}, User{})
 	a.NotNil(err)
@@ -243,7 +282,7 @@ func TestCreateInvite(t *testing.T) {
 	ds.AssertExpectations(t)
 
 	// fails if max number of invites reached
-	usersMax := make([]BoardUser, maxUsersPerBoard)
+	usersMax := make([]BoardUser, DefaultLimits().MaxUsersPerBoard)
 	ds.On("Board", "b-123").Return(BoardWithUsersAndInvites{Users: usersMax}, nil, nil).Once()
 	_, err = service.CreateInvite(ctx, "b-123", auth.BoardRoleViewer, User{}, User{})
 	a.NotNil(err)
@@ -361,6 +400,17 @@ func TestAcceptInvite(t *testing.T) {
 	err = service.AcceptInvite(ctx, "b-123", "i-2", exampleBoardInvite2.User)
 	a.Nil(err)
 	ds.AssertExpectations(t)
+
+	// a pending join request of the user is removed
+	withRequest := exampleBoardWithUAndI
+	withRequest.JoinRequests = []BoardJoinRequest{{JoinRequestId: "j-1", User: exampleBoardInvite2.User}}
+	ds.On("Board", "b-123").Return(withRequest, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && len(update.RemoveJoinRequests) == 1 && update.RemoveJoinRequests[0] == "j-1" && len(update.UpdateUsers) == 1
+	})).Return(nil).Once()
+	err = service.AcceptInvite(ctx, "b-123", "i-2", exampleBoardInvite2.User)
+	a.Nil(err)
+	ds.AssertExpectations(t)
 }
 
 func TestDeclineInvite(t *testing.T) {
@@ -477,3 +527,217 @@ func TestEditBoardUser(t *testing.T) {
 	a.Nil(err)
 	ds.AssertExpectations(t)
 }
+
+func TestCustomRoles(t *testing.T) {
+	a := assert.New(t)
+	service, ds := newTestService()
+	ctx := context.Background()
+	initTestTime()
+
+	auth.RegisterScopes("test:view", "test:delete", "test:destroy")
+	auth.RegisterRoles(map[string][]auth.Scope{
+		auth.BoardRoleOwner:  {"test:view", "test:delete", "test:destroy"},
+		auth.BoardRoleEditor: {"test:view", "test:delete"},
+	})
+
+	// invalid roles are rejected without accessing the data store
+	invalidRoles := []CustomRole{
+		{Name: "", Scopes: []auth.Scope{"test:view"}},
+		{Name: auth.BoardRoleEditor, Scopes: []auth.Scope{"test:view"}},
+		{Name: auth.BoardRoleVisitor, Scopes: []auth.Scope{"test:view"}},
+		{Name: "Curator", Scopes: []auth.Scope{"test:view"}},
+		{Name: "curator"},
+		{Name: "curator", Scopes: []auth.Scope{"test:notregistered"}},
+		{Name: "curator", Scopes: []auth.Scope{"test:view", "test:destroy"}},
+		{Name: "curator", Scopes: []auth.Scope{"test:view", "test:view"}},
+	}
+	for _, role := range invalidRoles {
+		_, err := service.SetCustomRole(ctx, "b-123", role, user1)
+		a.NotNil(err)
+		a.True(errors.HasPublicCode(err, errInvalidCustomRole), role)
+	}
+
+	// cannot create more than maxCustomRolesPerBoard roles
+	full := exampleBoardWithUAndI
+	full.Board.CustomRoles = make([]CustomRole, maxCustomRolesPerBoard)
+	ds.On("Board", "b-123").Return(full, nil, nil).Once()
+	_, err := service.SetCustomRole(ctx, "b-123", CustomRole{Name: "curator", Scopes: []auth.Scope{"test:delete"}}, user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errMaxCustomRolesReached))
+	ds.AssertExpectations(t)
+
+	// create a role
+	curator := CustomRole{Name: "curator", Scopes: []auth.Scope{"test:view", "test:delete"}}
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && update.UpdateBoard && assert.ObjectsAreEqual([]CustomRole{curator}, update.Board.CustomRoles)
+	})).Return(nil).Once()
+	board, err := service.SetCustomRole(ctx, "b-123", curator, user1)
+	a.Nil(err)
+	a.Equal([]CustomRole{curator}, board.CustomRoles)
+	a.Equal(user1, board.ModifiedBy)
+	ds.AssertExpectations(t)
+
+	withRole := exampleBoardWithUAndI
+	withRole.Board.CustomRoles = []CustomRole{curator}
+
+	// replace the scopes of an existing role
+	changed := CustomRole{Name: "curator", Scopes: []auth.Scope{"test:view"}}
+	ds.On("Board", "b-123").Return(withRole, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && update.UpdateBoard && assert.ObjectsAreEqual([]CustomRole{changed}, update.Board.CustomRoles)
+	})).Return(nil).Once()
+	_, err = service.SetCustomRole(ctx, "b-123", changed, user1)
+	a.Nil(err)
+	ds.AssertExpectations(t)
+
+	// custom roles can be used for invites and users
+	ds.On("Board", "b-123").Return(withRole, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && len(update.UpdateInvites) == 1 && update.UpdateInvites[0].Role == "curator"
+	})).Return(nil).Once()
+	_, err = service.CreateInvite(ctx, "b-123", "curator", user4, user1)
+	a.Nil(err)
+	ds.AssertExpectations(t)
+
+	ds.On("Board", "b-123").Return(withRole, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && len(update.UpdateUsers) == 1 && update.UpdateUsers[0].Role == "curator"
+	})).Return(nil).Once()
+	_, err = service.EditBoardUser(ctx, "b-123", user2.UserId, BoardUserEdit{UpdateRole: true, Role: "curator"}, user1)
+	a.Nil(err)
+	ds.AssertExpectations(t)
+
+	// roles of other boards cannot be used
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	_, err = service.EditBoardUser(ctx, "b-123", user2.UserId, BoardUserEdit{UpdateRole: true, Role: "curator"}, user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errInvalidRole))
+	ds.AssertExpectations(t)
+
+	// cannot delete a role that does not exist or is in use
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	_, err = service.DeleteCustomRole(ctx, "b-123", "curator", user1)
+	a.NotNil(err)
+	a.True(errors.IsNotFoundError(err))
+	ds.AssertExpectations(t)
+
+	inUse := withRole
+	inUse.Users = []BoardUser{exampleBoardUser1, {User: user2, Role: "curator"}}
+	ds.On("Board", "b-123").Return(inUse, nil, nil).Once()
+	_, err = service.DeleteCustomRole(ctx, "b-123", "curator", user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errCustomRoleInUse))
+	ds.AssertExpectations(t)
+
+	ds.On("Board", "b-123").Return(withRole, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && update.UpdateBoard && len(update.Board.CustomRoles) == 0
+	})).Return(nil).Once()
+	board, err = service.DeleteCustomRole(ctx, "b-123", "curator", user1)
+	a.Nil(err)
+	a.Empty(board.CustomRoles)
+	ds.AssertExpectations(t)
+}
+
+func TestJoinRequests(t *testing.T) {
+	a := assert.New(t)
+	service, ds := newTestService()
+	initTestTime()
+	ctx := context.Background()
+
+	// fails if user is already on the board
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	_, err := service.CreateJoinRequest(ctx, "b-123", user2)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errUserAlreadyOnBoard))
+	ds.AssertExpectations(t)
+
+	withRequest := exampleBoardWithUAndI
+	withRequest.JoinRequests = []BoardJoinRequest{{JoinRequestId: "j-1", User: user4, CreatedTime: testTimeUnix}}
+
+	// fails if user already requested to join
+	ds.On("Board", "b-123").Return(withRequest, nil, nil).Once()
+	_, err = service.CreateJoinRequest(ctx, "b-123", user4)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errUserAlreadyRequestedToJoin))
+	ds.AssertExpectations(t)
+
+	// pending join requests count against the maximum number of users
+	full := BoardWithUsersAndInvites{
+		Users:        make([]BoardUser, DefaultLimits().MaxUsersPerBoard-1),
+		JoinRequests: []BoardJoinRequest{{JoinRequestId: "j-1", User: user3}},
+	}
+	ds.On("Board", "b-123").Return(full, nil, nil).Once()
+	_, err = service.CreateJoinRequest(ctx, "b-123", user4)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errMaxBoardUsersReached))
+	ds.AssertExpectations(t)
+
+	// happy path
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && len(update.UpdateJoinRequests) == 1 && update.UpdateJoinRequests[0].User == user4
+	})).Return(nil).Once()
+	jr, err := service.CreateJoinRequest(ctx, "b-123", user4)
+	a.Nil(err)
+	a.Equal(user4, jr.User)
+	a.Equal(testTimeUnix, jr.CreatedTime)
+	a.NotEmpty(jr.JoinRequestId)
+	ds.AssertExpectations(t)
+
+	// cannot approve a join request that does not exist
+	ds.On("Board", "b-123").Return(withRequest, nil, nil).Once()
+	_, err = service.ApproveJoinRequest(ctx, "b-123", "j-2", auth.BoardRoleViewer, user1)
+	a.NotNil(err)
+	a.True(errors.IsNotFoundError(err))
+	ds.AssertExpectations(t)
+
+	// cannot approve as owner
+	ds.On("Board", "b-123").Return(withRequest, nil, nil).Once()
+	_, err = service.ApproveJoinRequest(ctx, "b-123", "j-1", auth.BoardRoleOwner, user1)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errOnlyCreatorCanBeOwner))
+	ds.AssertExpectations(t)
+
+	// approving removes the join request and adds the user in a single update
+	ds.On("Board", "b-123").Return(withRequest, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		if update == nil || len(update.RemoveJoinRequests) != 1 || len(update.UpdateUsers) != 1 {
+			return false
+		}
+		return update.RemoveJoinRequests[0] == "j-1" && update.UpdateUsers[0].User == user4 && update.UpdateUsers[0].Role == auth.BoardRoleEditor
+	})).Return(nil).Once()
+	bu, err := service.ApproveJoinRequest(ctx, "b-123", "j-1", auth.BoardRoleEditor, user1)
+	a.Nil(err)
+	a.Equal(user4, bu.User)
+	a.Equal(user1, bu.InvitedBy)
+	ds.AssertExpectations(t)
+
+	// an invite for the same user is removed
+	withInvite := withRequest
+	withInvite.Invites = []BoardInvite{exampleBoardInvite1, exampleBoardInvite2}
+	withInvite.JoinRequests = []BoardJoinRequest{{JoinRequestId: "j-1", User: user3}}
+	ds.On("Board", "b-123").Return(withInvite, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && len(update.RemoveInvites) == 1 && update.RemoveInvites[0] == exampleBoardInvite2.InviteId
+	})).Return(nil).Once()
+	_, err = service.ApproveJoinRequest(ctx, "b-123", "j-1", auth.BoardRoleViewer, user1)
+	a.Nil(err)
+	ds.AssertExpectations(t)
+
+	// reject
+	ds.On("Board", "b-123").Return(exampleBoardWithUAndI, nil, nil).Once()
+	err = service.RejectJoinRequest(ctx, "b-123", "j-1")
+	a.NotNil(err)
+	a.True(errors.IsNotFoundError(err))
+	ds.AssertExpectations(t)
+
+	ds.On("Board", "b-123").Return(withRequest, nil, nil).Once()
+	ds.On("UpdateBoard", "b-123", mock.MatchedBy(func(update *DatastoreBoardUpdate) bool {
+		return update != nil && len(update.RemoveJoinRequests) == 1 && update.RemoveJoinRequests[0] == "j-1" && len(update.UpdateUsers) == 0
+	})).Return(nil).Once()
+	err = service.RejectJoinRequest(ctx, "b-123", "j-1")
+	a.Nil(err)
+	ds.AssertExpectations(t)
+}
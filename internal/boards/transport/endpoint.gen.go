@@ -85,6 +85,21 @@ func MakeBoardsEndpoint(svc application.BoardApplicationService) endpoint.Endpoi
 	}
 }
 
+type PublicBoardsRequest struct {
+	Qp application.QueryParams
+}
+
+func MakePublicBoardsEndpoint(svc application.BoardApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(PublicBoardsRequest)
+		r, err := svc.PublicBoards(ctx, req.Qp)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
 type CreateInviteRequest struct {
 	BoardId string
 	Ni      application.NewInvite
@@ -149,6 +164,53 @@ func MakeInvitesEndpoint(svc application.BoardApplicationService) endpoint.Endpo
 	}
 }
 
+type CreateJoinRequestRequest struct {
+	BoardId string
+}
+
+func MakeCreateJoinRequestEndpoint(svc application.BoardApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(CreateJoinRequestRequest)
+		r, err := svc.CreateJoinRequest(ctx, req.BoardId)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
+type JoinRequestsRequest struct {
+	BoardId string
+}
+
+func MakeJoinRequestsEndpoint(svc application.BoardApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(JoinRequestsRequest)
+		r, err := svc.JoinRequests(ctx, req.BoardId)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
+type RespondToJoinRequestRequest struct {
+	BoardId       string
+	JoinRequestId string
+	Jrr           application.JoinRequestResponse
+}
+
+func MakeRespondToJoinRequestEndpoint(svc application.BoardApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RespondToJoinRequestRequest)
+		err := svc.RespondToJoinRequest(ctx, req.BoardId, req.JoinRequestId, req.Jrr)
+		return e.Response{
+			Err: err,
+			R:   nil,
+		}, nil
+	}
+}
+
 type RemoveUserRequest struct {
 	BoardId string
 	UserId  string
@@ -182,32 +244,77 @@ func MakeEditBoardUserEndpoint(svc application.BoardApplicationService) endpoint
 	}
 }
 
+type SetCustomRoleRequest struct {
+	BoardId string
+	Role    string
+	Crs     application.CustomRoleScopes
+}
+
+func MakeSetCustomRoleEndpoint(svc application.BoardApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetCustomRoleRequest)
+		r, err := svc.SetCustomRole(ctx, req.BoardId, req.Role, req.Crs)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
+type DeleteCustomRoleRequest struct {
+	BoardId string
+	Role    string
+}
+
+func MakeDeleteCustomRoleEndpoint(svc application.BoardApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(DeleteCustomRoleRequest)
+		r, err := svc.DeleteCustomRole(ctx, req.BoardId, req.Role)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
 type EndpointSet struct {
-	CreateBoardEndpoint     endpoint.Endpoint
-	DeleteBoardEndpoint     endpoint.Endpoint
-	EditBoardEndpoint       endpoint.Endpoint
-	BoardEndpoint           endpoint.Endpoint
-	BoardsEndpoint          endpoint.Endpoint
-	CreateInviteEndpoint    endpoint.Endpoint
-	RespondToInviteEndpoint endpoint.Endpoint
-	DeleteInviteEndpoint    endpoint.Endpoint
-	InvitesEndpoint         endpoint.Endpoint
-	RemoveUserEndpoint      endpoint.Endpoint
-	EditBoardUserEndpoint   endpoint.Endpoint
+	CreateBoardEndpoint          endpoint.Endpoint
+	DeleteBoardEndpoint          endpoint.Endpoint
+	EditBoardEndpoint            endpoint.Endpoint
+	BoardEndpoint                endpoint.Endpoint
+	BoardsEndpoint               endpoint.Endpoint
+	PublicBoardsEndpoint         endpoint.Endpoint
+	CreateInviteEndpoint         endpoint.Endpoint
+	RespondToInviteEndpoint      endpoint.Endpoint
+	DeleteInviteEndpoint         endpoint.Endpoint
+	InvitesEndpoint              endpoint.Endpoint
+	CreateJoinRequestEndpoint    endpoint.Endpoint
+	JoinRequestsEndpoint         endpoint.Endpoint
+	RespondToJoinRequestEndpoint endpoint.Endpoint
+	RemoveUserEndpoint           endpoint.Endpoint
+	EditBoardUserEndpoint        endpoint.Endpoint
+	SetCustomRoleEndpoint        endpoint.Endpoint
+	DeleteCustomRoleEndpoint     endpoint.Endpoint
 }
 
 type Middlewares struct {
-	CreateBoardEndpoint     []endpoint.Middleware
-	DeleteBoardEndpoint     []endpoint.Middleware
-	EditBoardEndpoint       []endpoint.Middleware
-	BoardEndpoint           []endpoint.Middleware
-	BoardsEndpoint          []endpoint.Middleware
-	CreateInviteEndpoint    []endpoint.Middleware
-	RespondToInviteEndpoint []endpoint.Middleware
-	DeleteInviteEndpoint    []endpoint.Middleware
-	InvitesEndpoint         []endpoint.Middleware
-	RemoveUserEndpoint      []endpoint.Middleware
-	EditBoardUserEndpoint   []endpoint.Middleware
+	CreateBoardEndpoint          []endpoint.Middleware
+	DeleteBoardEndpoint          []endpoint.Middleware
+	EditBoardEndpoint            []endpoint.Middleware
+	BoardEndpoint                []endpoint.Middleware
+	BoardsEndpoint               []endpoint.Middleware
+	PublicBoardsEndpoint         []endpoint.Middleware
+	CreateInviteEndpoint         []endpoint.Middleware
+	RespondToInviteEndpoint      []endpoint.Middleware
+	DeleteInviteEndpoint         []endpoint.Middleware
+	InvitesEndpoint              []endpoint.Middleware
+	CreateJoinRequestEndpoint    []endpoint.Middleware
+	JoinRequestsEndpoint         []endpoint.Middleware
+	RespondToJoinRequestEndpoint []endpoint.Middleware
+	RemoveUserEndpoint           []endpoint.Middleware
+	EditBoardUserEndpoint        []endpoint.Middleware
+	SetCustomRoleEndpoint        []endpoint.Middleware
+	DeleteCustomRoleEndpoint     []endpoint.Middleware
 }
 
 func NewEndpoints(svc application.BoardApplicationService, mws Middlewares) EndpointSet {
@@ -241,6 +348,12 @@ func NewEndpoints(svc application.BoardApplicationService, mws Middlewares) Endp
 		boardsEndpoint = e.ApplyMiddlewares(boardsEndpoint, mws.BoardsEndpoint...)
 	}
 
+	var publicBoardsEndpoint endpoint.Endpoint
+	{
+		publicBoardsEndpoint = MakePublicBoardsEndpoint(svc)
+		publicBoardsEndpoint = e.ApplyMiddlewares(publicBoardsEndpoint, mws.PublicBoardsEndpoint...)
+	}
+
 	var createInviteEndpoint endpoint.Endpoint
 	{
 		createInviteEndpoint = MakeCreateInviteEndpoint(svc)
@@ -265,6 +378,24 @@ func NewEndpoints(svc application.BoardApplicationService, mws Middlewares) Endp
 		invitesEndpoint = e.ApplyMiddlewares(invitesEndpoint, mws.InvitesEndpoint...)
 	}
 
+	var createJoinRequestEndpoint endpoint.Endpoint
+	{
+		createJoinRequestEndpoint = MakeCreateJoinRequestEndpoint(svc)
+		createJoinRequestEndpoint = e.ApplyMiddlewares(createJoinRequestEndpoint, mws.CreateJoinRequestEndpoint...)
+	}
+
+	var joinRequestsEndpoint endpoint.Endpoint
+	{
+		joinRequestsEndpoint = MakeJoinRequestsEndpoint(svc)
+		joinRequestsEndpoint = e.ApplyMiddlewares(joinRequestsEndpoint, mws.JoinRequestsEndpoint...)
+	}
+
+	var respondToJoinRequestEndpoint endpoint.Endpoint
+	{
+		respondToJoinRequestEndpoint = MakeRespondToJoinRequestEndpoint(svc)
+		respondToJoinRequestEndpoint = e.ApplyMiddlewares(respondToJoinRequestEndpoint, mws.RespondToJoinRequestEndpoint...)
+	}
+
 	var removeUserEndpoint endpoint.Endpoint
 	{
 		removeUserEndpoint = MakeRemoveUserEndpoint(svc)
@@ -277,17 +408,35 @@ func NewEndpoints(svc application.BoardApplicationService, mws Middlewares) Endp
 		editBoardUserEndpoint = e.ApplyMiddlewares(editBoardUserEndpoint, mws.EditBoardUserEndpoint...)
 	}
 
+	var setCustomRoleEndpoint endpoint.Endpoint
+	{
+		setCustomRoleEndpoint = MakeSetCustomRoleEndpoint(svc)
+		setCustomRoleEndpoint = e.ApplyMiddlewares(setCustomRoleEndpoint, mws.SetCustomRoleEndpoint...)
+	}
+
+	var deleteCustomRoleEndpoint endpoint.Endpoint
+	{
+		deleteCustomRoleEndpoint = MakeDeleteCustomRoleEndpoint(svc)
+		deleteCustomRoleEndpoint = e.ApplyMiddlewares(deleteCustomRoleEndpoint, mws.DeleteCustomRoleEndpoint...)
+	}
+
 	return EndpointSet{
-		BoardEndpoint:           boardEndpoint,
-		BoardsEndpoint:          boardsEndpoint,
-		CreateBoardEndpoint:     createBoardEndpoint,
-		CreateInviteEndpoint:    createInviteEndpoint,
-		DeleteBoardEndpoint:     deleteBoardEndpoint,
-		DeleteInviteEndpoint:    deleteInviteEndpoint,
-		EditBoardEndpoint:       editBoardEndpoint,
-		EditBoardUserEndpoint:   editBoardUserEndpoint,
-		InvitesEndpoint:         invitesEndpoint,
-		RemoveUserEndpoint:      removeUserEndpoint,
-		RespondToInviteEndpoint: respondToInviteEndpoint,
+		BoardEndpoint:                boardEndpoint,
+		BoardsEndpoint:               boardsEndpoint,
+		CreateBoardEndpoint:          createBoardEndpoint,
+		CreateInviteEndpoint:         createInviteEndpoint,
+		CreateJoinRequestEndpoint:    createJoinRequestEndpoint,
+		DeleteBoardEndpoint:          deleteBoardEndpoint,
+		DeleteCustomRoleEndpoint:     deleteCustomRoleEndpoint,
+		DeleteInviteEndpoint:         deleteInviteEndpoint,
+		EditBoardEndpoint:            editBoardEndpoint,
+		EditBoardUserEndpoint:        editBoardUserEndpoint,
+		InvitesEndpoint:              invitesEndpoint,
+		JoinRequestsEndpoint:         joinRequestsEndpoint,
+		PublicBoardsEndpoint:         publicBoardsEndpoint,
+		RemoveUserEndpoint:           removeUserEndpoint,
+		RespondToInviteEndpoint:      respondToInviteEndpoint,
+		RespondToJoinRequestEndpoint: respondToJoinRequestEndpoint,
+		SetCustomRoleEndpoint:        setCustomRoleEndpoint,
 	}
 }
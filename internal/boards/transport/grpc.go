@@ -0,0 +1,556 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/boards/application"
+	"github.com/dkinzler/linkboards/internal/boards/transport/pb"
+	"github.com/dkinzler/linkboards/internal/grpcutil"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+)
+
+type grpcServer struct {
+	pb.UnimplementedBoardServiceServer
+
+	createBoard     kitgrpc.Handler
+	deleteBoard     kitgrpc.Handler
+	editBoard       kitgrpc.Handler
+	board           kitgrpc.Handler
+	boards          kitgrpc.Handler
+	publicBoards    kitgrpc.Handler
+	createInvite    kitgrpc.Handler
+	respondToInvite kitgrpc.Handler
+	deleteInvite    kitgrpc.Handler
+	invites         kitgrpc.Handler
+	removeUser      kitgrpc.Handler
+	editBoardUser   kitgrpc.Handler
+
+	createJoinRequest    kitgrpc.Handler
+	joinRequests         kitgrpc.Handler
+	respondToJoinRequest kitgrpc.Handler
+
+	setCustomRole    kitgrpc.Handler
+	deleteCustomRole kitgrpc.Handler
+}
+
+// Returns a gRPC server that uses the given endpoints, i.e. requests go through the same middlewares as http requests.
+func NewGRPCServer(endpoints EndpointSet, opts []kitgrpc.ServerOption) pb.BoardServiceServer {
+	return &grpcServer{
+		createBoard:     kitgrpc.NewServer(endpoints.CreateBoardEndpoint, decodeGRPCCreateBoardRequest, encodeGRPCBoardWithUsersAndInvitesResponse, opts...),
+		deleteBoard:     kitgrpc.NewServer(endpoints.DeleteBoardEndpoint, decodeGRPCDeleteBoardRequest, encodeGRPCDeleteBoardResponse, opts...),
+		editBoard:       kitgrpc.NewServer(endpoints.EditBoardEndpoint, decodeGRPCEditBoardRequest, encodeGRPCBoardResponse, opts...),
+		board:           kitgrpc.NewServer(endpoints.BoardEndpoint, decodeGRPCBoardRequest, encodeGRPCBoardWithUsersAndInvitesResponse, opts...),
+		boards:          kitgrpc.NewServer(endpoints.BoardsEndpoint, decodeGRPCBoardsRequest, encodeGRPCBoardsResponse, opts...),
+		publicBoards:    kitgrpc.NewServer(endpoints.PublicBoardsEndpoint, decodeGRPCPublicBoardsRequest, encodeGRPCBoardsResponse, opts...),
+		createInvite:    kitgrpc.NewServer(endpoints.CreateInviteEndpoint, decodeGRPCCreateInviteRequest, encodeGRPCInviteResponse, opts...),
+		respondToInvite: kitgrpc.NewServer(endpoints.RespondToInviteEndpoint, decodeGRPCRespondToInviteRequest, encodeGRPCRespondToInviteResponse, opts...),
+		deleteInvite:    kitgrpc.NewServer(endpoints.DeleteInviteEndpoint, decodeGRPCDeleteInviteRequest, encodeGRPCDeleteInviteResponse, opts...),
+		invites:         kitgrpc.NewServer(endpoints.InvitesEndpoint, decodeGRPCInvitesRequest, encodeGRPCInvitesResponse, opts...),
+		removeUser:      kitgrpc.NewServer(endpoints.RemoveUserEndpoint, decodeGRPCRemoveUserRequest, encodeGRPCRemoveUserResponse, opts...),
+		editBoardUser:   kitgrpc.NewServer(endpoints.EditBoardUserEndpoint, decodeGRPCEditBoardUserRequest, encodeGRPCBoardUserResponse, opts...),
+
+		createJoinRequest:    kitgrpc.NewServer(endpoints.CreateJoinRequestEndpoint, decodeGRPCCreateJoinRequestRequest, encodeGRPCJoinRequestResponse, opts...),
+		joinRequests:         kitgrpc.NewServer(endpoints.JoinRequestsEndpoint, decodeGRPCJoinRequestsRequest, encodeGRPCJoinRequestsResponse, opts...),
+		respondToJoinRequest: kitgrpc.NewServer(endpoints.RespondToJoinRequestEndpoint, decodeGRPCRespondToJoinRequestRequest, encodeGRPCRespondToJoinRequestResponse, opts...),
+
+		setCustomRole:    kitgrpc.NewServer(endpoints.SetCustomRoleEndpoint, decodeGRPCSetCustomRoleRequest, encodeGRPCBoardResponse, opts...),
+		deleteCustomRole: kitgrpc.NewServer(endpoints.DeleteCustomRoleEndpoint, decodeGRPCDeleteCustomRoleRequest, encodeGRPCBoardResponse, opts...),
+	}
+}
+
+func serveGRPC(ctx context.Context, h kitgrpc.Handler, req interface{}) (interface{}, error) {
+	_, resp, err := h.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.ErrorToStatus(err)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) CreateBoard(ctx context.Context, req *pb.CreateBoardRequest) (*pb.BoardWithUsersAndInvites, error) {
+	resp, err := serveGRPC(ctx, s.createBoard, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.BoardWithUsersAndInvites), nil
+}
+
+func (s *grpcServer) DeleteBoard(ctx context.Context, req *pb.DeleteBoardRequest) (*pb.DeleteBoardResponse, error) {
+	resp, err := serveGRPC(ctx, s.deleteBoard, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.DeleteBoardResponse), nil
+}
+
+func (s *grpcServer) EditBoard(ctx context.Context, req *pb.EditBoardRequest) (*pb.Board, error) {
+	resp, err := serveGRPC(ctx, s.editBoard, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Board), nil
+}
+
+func (s *grpcServer) GetBoard(ctx context.Context, req *pb.GetBoardRequest) (*pb.BoardWithUsersAndInvites, error) {
+	resp, err := serveGRPC(ctx, s.board, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.BoardWithUsersAndInvites), nil
+}
+
+func (s *grpcServer) ListBoards(ctx context.Context, req *pb.ListBoardsRequest) (*pb.ListBoardsResponse, error) {
+	resp, err := serveGRPC(ctx, s.boards, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListBoardsResponse), nil
+}
+
+func (s *grpcServer) ListPublicBoards(ctx context.Context, req *pb.ListPublicBoardsRequest) (*pb.ListBoardsResponse, error) {
+	resp, err := serveGRPC(ctx, s.publicBoards, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListBoardsResponse), nil
+}
+
+func (s *grpcServer) CreateInvite(ctx context.Context, req *pb.CreateInviteRequest) (*pb.Invite, error) {
+	resp, err := serveGRPC(ctx, s.createInvite, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Invite), nil
+}
+
+func (s *grpcServer) RespondToInvite(ctx context.Context, req *pb.RespondToInviteRequest) (*pb.RespondToInviteResponse, error) {
+	resp, err := serveGRPC(ctx, s.respondToInvite, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.RespondToInviteResponse), nil
+}
+
+func (s *grpcServer) DeleteInvite(ctx context.Context, req *pb.DeleteInviteRequest) (*pb.DeleteInviteResponse, error) {
+	resp, err := serveGRPC(ctx, s.deleteInvite, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.DeleteInviteResponse), nil
+}
+
+func (s *grpcServer) ListInvites(ctx context.Context, req *pb.ListInvitesRequest) (*pb.ListInvitesResponse, error) {
+	resp, err := serveGRPC(ctx, s.invites, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListInvitesResponse), nil
+}
+
+func (s *grpcServer) CreateJoinRequest(ctx context.Context, req *pb.CreateJoinRequestRequest) (*pb.JoinRequest, error) {
+	resp, err := serveGRPC(ctx, s.createJoinRequest, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.JoinRequest), nil
+}
+
+func (s *grpcServer) ListJoinRequests(ctx context.Context, req *pb.ListJoinRequestsRequest) (*pb.ListJoinRequestsResponse, error) {
+	resp, err := serveGRPC(ctx, s.joinRequests, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListJoinRequestsResponse), nil
+}
+
+func (s *grpcServer) RespondToJoinRequest(ctx context.Context, req *pb.RespondToJoinRequestRequest) (*pb.RespondToJoinRequestResponse, error) {
+	resp, err := serveGRPC(ctx, s.respondToJoinRequest, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.RespondToJoinRequestResponse), nil
+}
+
+func (s *grpcServer) RemoveUser(ctx context.Context, req *pb.RemoveUserRequest) (*pb.RemoveUserResponse, error) {
+	resp, err := serveGRPC(ctx, s.removeUser, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.RemoveUserResponse), nil
+}
+
+func (s *grpcServer) EditBoardUser(ctx context.Context, req *pb.EditBoardUserRequest) (*pb.BoardUser, error) {
+	resp, err := serveGRPC(ctx, s.editBoardUser, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.BoardUser), nil
+}
+
+func (s *grpcServer) SetCustomRole(ctx context.Context, req *pb.SetCustomRoleRequest) (*pb.Board, error) {
+	resp, err := serveGRPC(ctx, s.setCustomRole, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Board), nil
+}
+
+func (s *grpcServer) DeleteCustomRole(ctx context.Context, req *pb.DeleteCustomRoleRequest) (*pb.Board, error) {
+	resp, err := serveGRPC(ctx, s.deleteCustomRole, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Board), nil
+}
+
+func decodeGRPCCreateBoardRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.CreateBoardRequest)
+	return CreateBoardRequest{
+		Nb: application.NewBoard{
+			Name:        req.Name,
+			Description: req.Description,
+		},
+	}, nil
+}
+
+func decodeGRPCDeleteBoardRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.DeleteBoardRequest)
+	return DeleteBoardRequest{BoardId: req.BoardId}, nil
+}
+
+func decodeGRPCEditBoardRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.EditBoardRequest)
+	return EditBoardRequest{
+		BoardId: req.BoardId,
+		Be: application.BoardEdit{
+			Name:        req.Name,
+			Description: req.Description,
+			Visibility:  req.Visibility,
+		},
+	}, nil
+}
+
+func decodeGRPCBoardRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.GetBoardRequest)
+	return BoardRequest{BoardId: req.BoardId}, nil
+}
+
+func decodeGRPCBoardsRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.ListBoardsRequest)
+	return BoardsRequest{
+		Qp: application.QueryParams{
+			Limit:  int(req.Limit),
+			Cursor: req.Cursor,
+		},
+	}, nil
+}
+
+func decodeGRPCPublicBoardsRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.ListPublicBoardsRequest)
+	return PublicBoardsRequest{
+		Qp: application.QueryParams{
+			Limit:  int(req.Limit),
+			Cursor: req.Cursor,
+		},
+	}, nil
+}
+
+func decodeGRPCCreateInviteRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.CreateInviteRequest)
+	return CreateInviteRequest{
+		BoardId: req.BoardId,
+		Ni: application.NewInvite{
+			User: userFromPB(req.User),
+			Role: req.Role,
+		},
+	}, nil
+}
+
+func decodeGRPCRespondToInviteRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.RespondToInviteRequest)
+	return RespondToInviteRequest{
+		BoardId:  req.BoardId,
+		InviteId: req.InviteId,
+		Ir:       application.InviteResponse{Response: req.Response},
+	}, nil
+}
+
+func decodeGRPCDeleteInviteRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.DeleteInviteRequest)
+	return DeleteInviteRequest{
+		BoardId:  req.BoardId,
+		InviteId: req.InviteId,
+	}, nil
+}
+
+func decodeGRPCInvitesRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.ListInvitesRequest)
+	return InvitesRequest{
+		Qp: application.QueryParams{
+			Limit:  int(req.Limit),
+			Cursor: req.Cursor,
+		},
+	}, nil
+}
+
+func decodeGRPCCreateJoinRequestRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.CreateJoinRequestRequest)
+	return CreateJoinRequestRequest{BoardId: req.BoardId}, nil
+}
+
+func decodeGRPCJoinRequestsRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.ListJoinRequestsRequest)
+	return JoinRequestsRequest{BoardId: req.BoardId}, nil
+}
+
+func decodeGRPCRespondToJoinRequestRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.RespondToJoinRequestRequest)
+	return RespondToJoinRequestRequest{
+		BoardId:       req.BoardId,
+		JoinRequestId: req.JoinRequestId,
+		Jrr: application.JoinRequestResponse{
+			Response: req.Response,
+			Role:     req.Role,
+		},
+	}, nil
+}
+
+func decodeGRPCRemoveUserRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.RemoveUserRequest)
+	return RemoveUserRequest{
+		BoardId: req.BoardId,
+		UserId:  req.UserId,
+	}, nil
+}
+
+func decodeGRPCEditBoardUserRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.EditBoardUserRequest)
+	return EditBoardUserRequest{
+		BoardId: req.BoardId,
+		UserId:  req.UserId,
+		Bue:     application.BoardUserEdit{Role: req.Role},
+	}, nil
+}
+
+func decodeGRPCSetCustomRoleRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.SetCustomRoleRequest)
+	scopes := make([]auth.Scope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = auth.Scope(scope)
+	}
+	return SetCustomRoleRequest{
+		BoardId: req.BoardId,
+		Role:    req.Role,
+		Crs:     application.CustomRoleScopes{Scopes: scopes},
+	}, nil
+}
+
+func decodeGRPCDeleteCustomRoleRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.DeleteCustomRoleRequest)
+	return DeleteCustomRoleRequest{
+		BoardId: req.BoardId,
+		Role:    req.Role,
+	}, nil
+}
+
+func encodeGRPCBoardWithUsersAndInvitesResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	b := r.(application.BoardWithUsersAndInvites)
+	result := &pb.BoardWithUsersAndInvites{
+		BoardId:      b.BoardId,
+		Name:         b.Name,
+		Description:  b.Description,
+		CreatedTime:  b.CreatedTime,
+		CreatedBy:    userToPB(b.CreatedBy),
+		ModifiedTime: b.ModifiedTime,
+		ModifiedBy:   userToPB(b.ModifiedBy),
+		Visibility:   b.Visibility,
+	}
+	for _, u := range b.Users {
+		result.Users = append(result.Users, boardUserToPB(u))
+	}
+	for _, i := range b.Invites {
+		result.Invites = append(result.Invites, inviteToPB(i))
+	}
+	for _, jr := range b.JoinRequests {
+		result.JoinRequests = append(result.JoinRequests, joinRequestToPB(jr))
+	}
+	result.CustomRoles = customRolesToPB(b.CustomRoles)
+	return result, nil
+}
+
+func encodeGRPCDeleteBoardResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteBoardResponse{}, nil
+}
+
+func encodeGRPCBoardResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return boardToPB(r.(application.Board)), nil
+}
+
+func encodeGRPCBoardsResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	boards := r.([]application.Board)
+	result := &pb.ListBoardsResponse{Boards: make([]*pb.Board, len(boards))}
+	for i, b := range boards {
+		result.Boards[i] = boardToPB(b)
+	}
+	return result, nil
+}
+
+func encodeGRPCInviteResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return inviteToPB(r.(application.Invite)), nil
+}
+
+func encodeGRPCRespondToInviteResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.RespondToInviteResponse{}, nil
+}
+
+func encodeGRPCDeleteInviteResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteInviteResponse{}, nil
+}
+
+func encodeGRPCInvitesResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	invites := r.([]application.Invite)
+	result := &pb.ListInvitesResponse{Invites: make([]*pb.Invite, len(invites))}
+	for i, invite := range invites {
+		result.Invites[i] = inviteToPB(invite)
+	}
+	return result, nil
+}
+
+func encodeGRPCJoinRequestResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return joinRequestToPB(r.(application.JoinRequest)), nil
+}
+
+func encodeGRPCJoinRequestsResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	joinRequests := r.([]application.JoinRequest)
+	result := &pb.ListJoinRequestsResponse{JoinRequests: make([]*pb.JoinRequest, len(joinRequests))}
+	for i, jr := range joinRequests {
+		result.JoinRequests[i] = joinRequestToPB(jr)
+	}
+	return result, nil
+}
+
+func encodeGRPCRespondToJoinRequestResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.RespondToJoinRequestResponse{}, nil
+}
+
+func encodeGRPCRemoveUserResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.RemoveUserResponse{}, nil
+}
+
+func encodeGRPCBoardUserResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return boardUserToPB(r.(application.BoardUser)), nil
+}
+
+func userToPB(u application.User) *pb.User {
+	return &pb.User{UserId: u.UserId, Name: u.Name}
+}
+
+func userFromPB(u *pb.User) application.User {
+	return application.User{UserId: u.GetUserId(), Name: u.GetName()}
+}
+
+func boardToPB(b application.Board) *pb.Board {
+	return &pb.Board{
+		BoardId:      b.BoardId,
+		Name:         b.Name,
+		Description:  b.Description,
+		CreatedTime:  b.CreatedTime,
+		CreatedBy:    userToPB(b.CreatedBy),
+		ModifiedTime: b.ModifiedTime,
+		ModifiedBy:   userToPB(b.ModifiedBy),
+		Visibility:   b.Visibility,
+		CustomRoles:  customRolesToPB(b.CustomRoles),
+	}
+}
+
+func customRolesToPB(roles []application.CustomRole) []*pb.CustomRole {
+	var result []*pb.CustomRole
+	for _, role := range roles {
+		scopes := make([]string, len(role.Scopes))
+		for i, scope := range role.Scopes {
+			scopes[i] = string(scope)
+		}
+		result = append(result, &pb.CustomRole{Name: role.Name, Scopes: scopes})
+	}
+	return result
+}
+
+func boardUserToPB(u application.BoardUser) *pb.BoardUser {
+	return &pb.BoardUser{
+		User:         userToPB(u.User),
+		Role:         u.Role,
+		CreatedTime:  u.CreatedTime,
+		InvitedBy:    userToPB(u.InvitedBy),
+		ModifiedTime: u.ModifiedTime,
+		ModifiedBy:   userToPB(u.ModifiedBy),
+	}
+}
+
+func inviteToPB(i application.Invite) *pb.Invite {
+	return &pb.Invite{
+		BoardId:     i.BoardId,
+		InviteId:    i.InviteId,
+		Role:        i.Role,
+		User:        userToPB(i.User),
+		CreatedTime: i.CreatedTime,
+		CreatedBy:   userToPB(i.CreatedBy),
+		ExpiresTime: i.ExpiresTime,
+	}
+}
+
+func joinRequestToPB(jr application.JoinRequest) *pb.JoinRequest {
+	return &pb.JoinRequest{
+		BoardId:       jr.BoardId,
+		JoinRequestId: jr.JoinRequestId,
+		User:          userToPB(jr.User),
+		CreatedTime:   jr.CreatedTime,
+	}
+}
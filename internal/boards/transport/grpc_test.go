@@ -0,0 +1,184 @@
+package transport
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/auth/store"
+	"github.com/dkinzler/linkboards/internal/boards/application"
+	"github.com/dkinzler/linkboards/internal/boards/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/boards/domain"
+	"github.com/dkinzler/linkboards/internal/boards/transport/pb"
+
+	"github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	a := assert.New(t)
+
+	ds := inmem.NewInmemBoardDataStore()
+	svc := application.NewBoardApplicationService(ds, store.NewDefaultAuthorizationStore(ds), nil, domain.LimitsConfig{}, nil)
+	mws := []endpoint.Middleware{middleware.NewFakeAuthEndpointMiddleware()}
+	optionalAuthMws := []endpoint.Middleware{middleware.NewOptionalAuthEndpointMiddleware(middleware.NewFakeAuthEndpointMiddleware())}
+	endpoints := NewEndpoints(svc, Middlewares{
+		CreateBoardEndpoint:     mws,
+		DeleteBoardEndpoint:     mws,
+		EditBoardEndpoint:       mws,
+		BoardEndpoint:           optionalAuthMws,
+		BoardsEndpoint:          mws,
+		PublicBoardsEndpoint:    optionalAuthMws,
+		CreateInviteEndpoint:    mws,
+		RespondToInviteEndpoint: mws,
+		DeleteInviteEndpoint:    mws,
+		InvitesEndpoint:         mws,
+		RemoveUserEndpoint:      mws,
+		EditBoardUserEndpoint:   mws,
+
+		CreateJoinRequestEndpoint:    mws,
+		JoinRequestsEndpoint:         mws,
+		RespondToJoinRequestEndpoint: mws,
+
+		SetCustomRoleEndpoint:    mws,
+		DeleteCustomRoleEndpoint: mws,
+	})
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterBoardServiceServer(s, NewGRPCServer(endpoints, []kitgrpc.ServerOption{
+		kitgrpc.ServerBefore(middleware.GRPCAuthorizationToContext),
+	}))
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	a.Nil(err)
+	defer conn.Close()
+	client := pb.NewBoardServiceClient(conn)
+
+	withUser := func(userId string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userId+":pw")))
+	}
+	owner := withUser("u-1")
+	other := withUser("u-2")
+
+	_, err = client.CreateBoard(context.Background(), &pb.CreateBoardRequest{Name: "Board"})
+	a.Equal(codes.Unauthenticated, status.Code(err))
+
+	board, err := client.CreateBoard(owner, &pb.CreateBoardRequest{Name: "Board", Description: "Links"})
+	a.Nil(err)
+	a.Equal("Board", board.Name)
+	a.Len(board.Users, 1)
+	a.Equal("owner", board.Users[0].Role)
+
+	_, err = client.CreateBoard(owner, &pb.CreateBoardRequest{Name: ""})
+	a.Equal(codes.InvalidArgument, status.Code(err))
+
+	// only the fields that are set are changed
+	name := "New Board"
+	edited, err := client.EditBoard(owner, &pb.EditBoardRequest{BoardId: board.BoardId, Name: &name})
+	a.Nil(err)
+	a.Equal("New Board", edited.Name)
+	a.Equal("Links", edited.Description)
+
+	a.Equal("private", edited.Visibility)
+
+	_, err = client.GetBoard(other, &pb.GetBoardRequest{BoardId: board.BoardId})
+	a.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = client.GetBoard(context.Background(), &pb.GetBoardRequest{BoardId: board.BoardId})
+	a.Equal(codes.Unauthenticated, status.Code(err))
+
+	// public boards can be viewed and listed without authentication
+	visibility := "public"
+	edited, err = client.EditBoard(owner, &pb.EditBoardRequest{BoardId: board.BoardId, Visibility: &visibility})
+	a.Nil(err)
+	a.Equal("public", edited.Visibility)
+	publicBoard, err := client.GetBoard(context.Background(), &pb.GetBoardRequest{BoardId: board.BoardId})
+	a.Nil(err)
+	a.Equal("public", publicBoard.Visibility)
+	a.Empty(publicBoard.Users)
+	publicBoards, err := client.ListPublicBoards(context.Background(), &pb.ListPublicBoardsRequest{})
+	a.Nil(err)
+	a.Len(publicBoards.Boards, 1)
+	a.Equal(board.BoardId, publicBoards.Boards[0].BoardId)
+
+	// non-members can request to join boards that are visible to them
+	jr, err := client.CreateJoinRequest(withUser("u-3"), &pb.CreateJoinRequestRequest{BoardId: board.BoardId})
+	a.Nil(err)
+	a.Equal("u-3", jr.User.UserId)
+	_, err = client.ListJoinRequests(withUser("u-3"), &pb.ListJoinRequestsRequest{BoardId: board.BoardId})
+	a.Equal(codes.PermissionDenied, status.Code(err))
+	joinRequests, err := client.ListJoinRequests(owner, &pb.ListJoinRequestsRequest{BoardId: board.BoardId})
+	a.Nil(err)
+	a.Len(joinRequests.JoinRequests, 1)
+	a.Equal(jr.JoinRequestId, joinRequests.JoinRequests[0].JoinRequestId)
+	ownerBoard, err := client.GetBoard(owner, &pb.GetBoardRequest{BoardId: board.BoardId})
+	a.Nil(err)
+	a.Len(ownerBoard.JoinRequests, 1)
+	_, err = client.RespondToJoinRequest(owner, &pb.RespondToJoinRequestRequest{BoardId: board.BoardId, JoinRequestId: jr.JoinRequestId, Response: "approve"})
+	a.Nil(err)
+	joinedBoards, err := client.ListBoards(withUser("u-3"), &pb.ListBoardsRequest{})
+	a.Nil(err)
+	a.Len(joinedBoards.Boards, 1)
+
+	visibility = "private"
+	_, err = client.EditBoard(owner, &pb.EditBoardRequest{BoardId: board.BoardId, Visibility: &visibility})
+	a.Nil(err)
+
+	invite, err := client.CreateInvite(owner, &pb.CreateInviteRequest{BoardId: board.BoardId, Role: "editor", User: &pb.User{UserId: "u-2"}})
+	a.Nil(err)
+	invites, err := client.ListInvites(other, &pb.ListInvitesRequest{})
+	a.Nil(err)
+	a.Len(invites.Invites, 1)
+	a.Equal(invite.InviteId, invites.Invites[0].InviteId)
+
+	_, err = client.RespondToInvite(other, &pb.RespondToInviteRequest{BoardId: board.BoardId, InviteId: invite.InviteId, Response: "accept"})
+	a.Nil(err)
+	boards, err := client.ListBoards(other, &pb.ListBoardsRequest{})
+	a.Nil(err)
+	a.Len(boards.Boards, 1)
+
+	role := "viewer"
+	bu, err := client.EditBoardUser(owner, &pb.EditBoardUserRequest{BoardId: board.BoardId, UserId: "u-2", Role: &role})
+	a.Nil(err)
+	a.Equal("viewer", bu.Role)
+
+	withRole, err := client.SetCustomRole(owner, &pb.SetCustomRoleRequest{BoardId: board.BoardId, Role: "curator", Scopes: []string{"boards:view", "boards:viewUsers"}})
+	a.Nil(err)
+	a.Len(withRole.CustomRoles, 1)
+	_, err = client.SetCustomRole(other, &pb.SetCustomRoleRequest{BoardId: board.BoardId, Role: "curator", Scopes: []string{"boards:view"}})
+	a.Equal(codes.PermissionDenied, status.Code(err))
+	role = "curator"
+	bu, err = client.EditBoardUser(owner, &pb.EditBoardUserRequest{BoardId: board.BoardId, UserId: "u-2", Role: &role})
+	a.Nil(err)
+	a.Equal("curator", bu.Role)
+	ownerBoard, err = client.GetBoard(owner, &pb.GetBoardRequest{BoardId: board.BoardId})
+	a.Nil(err)
+	a.Len(ownerBoard.CustomRoles, 1)
+	a.Equal("curator", ownerBoard.CustomRoles[0].Name)
+	a.Equal([]string{"boards:view", "boards:viewUsers"}, ownerBoard.CustomRoles[0].Scopes)
+
+	_, err = client.RemoveUser(owner, &pb.RemoveUserRequest{BoardId: board.BoardId, UserId: "u-2"})
+	a.Nil(err)
+	withoutRole, err := client.DeleteCustomRole(owner, &pb.DeleteCustomRoleRequest{BoardId: board.BoardId, Role: "curator"})
+	a.Nil(err)
+	a.Empty(withoutRole.CustomRoles)
+
+	_, err = client.DeleteBoard(owner, &pb.DeleteBoardRequest{BoardId: board.BoardId})
+	a.Nil(err)
+	_, err = client.GetBoard(owner, &pb.GetBoardRequest{BoardId: board.BoardId})
+	a.NotNil(err)
+}
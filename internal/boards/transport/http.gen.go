@@ -67,6 +67,16 @@ func decodeHttpBoardsRequest(ctx context.Context, r *http.Request) (interface{},
 	return BoardsRequest{Qp: qp}, nil
 }
 
+func decodeHttpPublicBoardsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var qp application.QueryParams
+	err := t.DecodeQueryParameters(r, &qp)
+	if err != nil {
+		return nil, err
+	}
+
+	return PublicBoardsRequest{Qp: qp}, nil
+}
+
 func decodeHttpCreateInviteRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	boardId, err := t.DecodeURLParameter(r, "boardId")
 	if err != nil {
@@ -136,6 +146,48 @@ func decodeHttpInvitesRequest(ctx context.Context, r *http.Request) (interface{}
 	return InvitesRequest{Qp: qp}, nil
 }
 
+func decodeHttpCreateJoinRequestRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateJoinRequestRequest{BoardId: boardId}, nil
+}
+
+func decodeHttpJoinRequestsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	return JoinRequestsRequest{BoardId: boardId}, nil
+}
+
+func decodeHttpRespondToJoinRequestRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	joinRequestId, err := t.DecodeURLParameter(r, "joinRequestId")
+	if err != nil {
+		return nil, err
+	}
+
+	var jrr application.JoinRequestResponse
+	err = t.DecodeJSONBody(r, &jrr)
+	if err != nil {
+		return nil, err
+	}
+
+	return RespondToJoinRequestRequest{
+		BoardId:       boardId,
+		JoinRequestId: joinRequestId,
+		Jrr:           jrr,
+	}, nil
+}
+
 func decodeHttpRemoveUserRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	boardId, err := t.DecodeURLParameter(r, "boardId")
 	if err != nil {
@@ -177,13 +229,54 @@ func decodeHttpEditBoardUserRequest(ctx context.Context, r *http.Request) (inter
 	}, nil
 }
 
-func RegisterHttpHandlers(endpoints EndpointSet, router *mux.Router, opts []kithttp.ServerOption) {
-	createBoardHandler := kithttp.NewServer(endpoints.CreateBoardEndpoint, decodeHttpCreateBoardRequest, t.MakeGenericJSONEncodeFunc(201), opts...)
-	router.Handle("/boards", createBoardHandler).Methods("POST", "OPTIONS")
+func decodeHttpSetCustomRoleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := t.DecodeURLParameter(r, "role")
+	if err != nil {
+		return nil, err
+	}
+
+	var crs application.CustomRoleScopes
+	err = t.DecodeJSONBody(r, &crs)
+	if err != nil {
+		return nil, err
+	}
+
+	return SetCustomRoleRequest{
+		BoardId: boardId,
+		Crs:     crs,
+		Role:    role,
+	}, nil
+}
+
+func decodeHttpDeleteCustomRoleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := t.DecodeURLParameter(r, "role")
+	if err != nil {
+		return nil, err
+	}
 
+	return DeleteCustomRoleRequest{
+		BoardId: boardId,
+		Role:    role,
+	}, nil
+}
+
+func RegisterHttpHandlers(endpoints EndpointSet, router *mux.Router, opts []kithttp.ServerOption) {
 	boardsHandler := kithttp.NewServer(endpoints.BoardsEndpoint, decodeHttpBoardsRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
 	router.Handle("/boards", boardsHandler).Methods("GET", "OPTIONS")
 
+	createBoardHandler := kithttp.NewServer(endpoints.CreateBoardEndpoint, decodeHttpCreateBoardRequest, t.MakeGenericJSONEncodeFunc(201), opts...)
+	router.Handle("/boards", createBoardHandler).Methods("POST", "OPTIONS")
+
 	deleteBoardHandler := kithttp.NewServer(endpoints.DeleteBoardEndpoint, decodeHttpDeleteBoardRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
 	router.Handle("/boards/{boardId}", deleteBoardHandler).Methods("DELETE", "OPTIONS")
 
@@ -196,11 +289,26 @@ func RegisterHttpHandlers(endpoints EndpointSet, router *mux.Router, opts []kith
 	createInviteHandler := kithttp.NewServer(endpoints.CreateInviteEndpoint, decodeHttpCreateInviteRequest, t.MakeGenericJSONEncodeFunc(201), opts...)
 	router.Handle("/boards/{boardId}/invites", createInviteHandler).Methods("POST", "OPTIONS")
 
+	deleteInviteHandler := kithttp.NewServer(endpoints.DeleteInviteEndpoint, decodeHttpDeleteInviteRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/boards/{boardId}/invites/{inviteId}", deleteInviteHandler).Methods("DELETE", "OPTIONS")
+
 	respondToInviteHandler := kithttp.NewServer(endpoints.RespondToInviteEndpoint, decodeHttpRespondToInviteRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
 	router.Handle("/boards/{boardId}/invites/{inviteId}", respondToInviteHandler).Methods("POST", "OPTIONS")
 
-	deleteInviteHandler := kithttp.NewServer(endpoints.DeleteInviteEndpoint, decodeHttpDeleteInviteRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
-	router.Handle("/boards/{boardId}/invites/{inviteId}", deleteInviteHandler).Methods("DELETE", "OPTIONS")
+	createJoinRequestHandler := kithttp.NewServer(endpoints.CreateJoinRequestEndpoint, decodeHttpCreateJoinRequestRequest, t.MakeGenericJSONEncodeFunc(201), opts...)
+	router.Handle("/boards/{boardId}/joinRequests", createJoinRequestHandler).Methods("POST", "OPTIONS")
+
+	joinRequestsHandler := kithttp.NewServer(endpoints.JoinRequestsEndpoint, decodeHttpJoinRequestsRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/boards/{boardId}/joinRequests", joinRequestsHandler).Methods("GET", "OPTIONS")
+
+	respondToJoinRequestHandler := kithttp.NewServer(endpoints.RespondToJoinRequestEndpoint, decodeHttpRespondToJoinRequestRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/boards/{boardId}/joinRequests/{joinRequestId}", respondToJoinRequestHandler).Methods("POST", "OPTIONS")
+
+	setCustomRoleHandler := kithttp.NewServer(endpoints.SetCustomRoleEndpoint, decodeHttpSetCustomRoleRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/boards/{boardId}/roles/{role}", setCustomRoleHandler).Methods("PUT", "OPTIONS")
+
+	deleteCustomRoleHandler := kithttp.NewServer(endpoints.DeleteCustomRoleEndpoint, decodeHttpDeleteCustomRoleRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/boards/{boardId}/roles/{role}", deleteCustomRoleHandler).Methods("DELETE", "OPTIONS")
 
 	removeUserHandler := kithttp.NewServer(endpoints.RemoveUserEndpoint, decodeHttpRemoveUserRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
 	router.Handle("/boards/{boardId}/users/{userId}", removeUserHandler).Methods("DELETE", "OPTIONS")
@@ -210,4 +318,7 @@ func RegisterHttpHandlers(endpoints EndpointSet, router *mux.Router, opts []kith
 
 	invitesHandler := kithttp.NewServer(endpoints.InvitesEndpoint, decodeHttpInvitesRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
 	router.Handle("/invites", invitesHandler).Methods("GET", "OPTIONS")
+
+	publicBoardsHandler := kithttp.NewServer(endpoints.PublicBoardsEndpoint, decodeHttpPublicBoardsRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/public/boards", publicBoardsHandler).Methods("GET", "OPTIONS")
 }
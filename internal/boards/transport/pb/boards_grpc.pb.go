@@ -0,0 +1,699 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: boards/v1/boards.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BoardServiceClient is the client API for BoardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BoardServiceClient interface {
+	CreateBoard(ctx context.Context, in *CreateBoardRequest, opts ...grpc.CallOption) (*BoardWithUsersAndInvites, error)
+	DeleteBoard(ctx context.Context, in *DeleteBoardRequest, opts ...grpc.CallOption) (*DeleteBoardResponse, error)
+	EditBoard(ctx context.Context, in *EditBoardRequest, opts ...grpc.CallOption) (*Board, error)
+	GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*BoardWithUsersAndInvites, error)
+	// Returns the boards the user making the request is part of.
+	ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (*ListBoardsResponse, error)
+	// Returns boards with visibility "public", sorted from newest to oldest.
+	// Like GetBoard, it can also be called without authentication.
+	ListPublicBoards(ctx context.Context, in *ListPublicBoardsRequest, opts ...grpc.CallOption) (*ListBoardsResponse, error)
+	CreateInvite(ctx context.Context, in *CreateInviteRequest, opts ...grpc.CallOption) (*Invite, error)
+	// Accept or decline an invite.
+	RespondToInvite(ctx context.Context, in *RespondToInviteRequest, opts ...grpc.CallOption) (*RespondToInviteResponse, error)
+	DeleteInvite(ctx context.Context, in *DeleteInviteRequest, opts ...grpc.CallOption) (*DeleteInviteResponse, error)
+	// Returns the invites for the user making the request.
+	ListInvites(ctx context.Context, in *ListInvitesRequest, opts ...grpc.CallOption) (*ListInvitesResponse, error)
+	// Creates a request of the user making the request to join a board that is visible to non-members.
+	CreateJoinRequest(ctx context.Context, in *CreateJoinRequestRequest, opts ...grpc.CallOption) (*JoinRequest, error)
+	// Returns the join requests of a board, sorted from newest to oldest.
+	ListJoinRequests(ctx context.Context, in *ListJoinRequestsRequest, opts ...grpc.CallOption) (*ListJoinRequestsResponse, error)
+	// Approve or reject a join request.
+	RespondToJoinRequest(ctx context.Context, in *RespondToJoinRequestRequest, opts ...grpc.CallOption) (*RespondToJoinRequestResponse, error)
+	RemoveUser(ctx context.Context, in *RemoveUserRequest, opts ...grpc.CallOption) (*RemoveUserResponse, error)
+	EditBoardUser(ctx context.Context, in *EditBoardUserRequest, opts ...grpc.CallOption) (*BoardUser, error)
+	// Creates a custom role or replaces the scopes of an existing one, returns the board with its custom roles.
+	SetCustomRole(ctx context.Context, in *SetCustomRoleRequest, opts ...grpc.CallOption) (*Board, error)
+	DeleteCustomRole(ctx context.Context, in *DeleteCustomRoleRequest, opts ...grpc.CallOption) (*Board, error)
+}
+
+type boardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBoardServiceClient(cc grpc.ClientConnInterface) BoardServiceClient {
+	return &boardServiceClient{cc}
+}
+
+func (c *boardServiceClient) CreateBoard(ctx context.Context, in *CreateBoardRequest, opts ...grpc.CallOption) (*BoardWithUsersAndInvites, error) {
+	out := new(BoardWithUsersAndInvites)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/CreateBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) DeleteBoard(ctx context.Context, in *DeleteBoardRequest, opts ...grpc.CallOption) (*DeleteBoardResponse, error) {
+	out := new(DeleteBoardResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/DeleteBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) EditBoard(ctx context.Context, in *EditBoardRequest, opts ...grpc.CallOption) (*Board, error) {
+	out := new(Board)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/EditBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*BoardWithUsersAndInvites, error) {
+	out := new(BoardWithUsersAndInvites)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/GetBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (*ListBoardsResponse, error) {
+	out := new(ListBoardsResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/ListBoards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) ListPublicBoards(ctx context.Context, in *ListPublicBoardsRequest, opts ...grpc.CallOption) (*ListBoardsResponse, error) {
+	out := new(ListBoardsResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/ListPublicBoards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) CreateInvite(ctx context.Context, in *CreateInviteRequest, opts ...grpc.CallOption) (*Invite, error) {
+	out := new(Invite)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/CreateInvite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) RespondToInvite(ctx context.Context, in *RespondToInviteRequest, opts ...grpc.CallOption) (*RespondToInviteResponse, error) {
+	out := new(RespondToInviteResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/RespondToInvite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) DeleteInvite(ctx context.Context, in *DeleteInviteRequest, opts ...grpc.CallOption) (*DeleteInviteResponse, error) {
+	out := new(DeleteInviteResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/DeleteInvite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) ListInvites(ctx context.Context, in *ListInvitesRequest, opts ...grpc.CallOption) (*ListInvitesResponse, error) {
+	out := new(ListInvitesResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/ListInvites", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) CreateJoinRequest(ctx context.Context, in *CreateJoinRequestRequest, opts ...grpc.CallOption) (*JoinRequest, error) {
+	out := new(JoinRequest)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/CreateJoinRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) ListJoinRequests(ctx context.Context, in *ListJoinRequestsRequest, opts ...grpc.CallOption) (*ListJoinRequestsResponse, error) {
+	out := new(ListJoinRequestsResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/ListJoinRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) RespondToJoinRequest(ctx context.Context, in *RespondToJoinRequestRequest, opts ...grpc.CallOption) (*RespondToJoinRequestResponse, error) {
+	out := new(RespondToJoinRequestResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/RespondToJoinRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) RemoveUser(ctx context.Context, in *RemoveUserRequest, opts ...grpc.CallOption) (*RemoveUserResponse, error) {
+	out := new(RemoveUserResponse)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/RemoveUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) EditBoardUser(ctx context.Context, in *EditBoardUserRequest, opts ...grpc.CallOption) (*BoardUser, error) {
+	out := new(BoardUser)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/EditBoardUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) SetCustomRole(ctx context.Context, in *SetCustomRoleRequest, opts ...grpc.CallOption) (*Board, error) {
+	out := new(Board)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/SetCustomRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) DeleteCustomRole(ctx context.Context, in *DeleteCustomRoleRequest, opts ...grpc.CallOption) (*Board, error) {
+	out := new(Board)
+	err := c.cc.Invoke(ctx, "/linkboards.boards.v1.BoardService/DeleteCustomRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BoardServiceServer is the server API for BoardService service.
+// All implementations must embed UnimplementedBoardServiceServer
+// for forward compatibility
+type BoardServiceServer interface {
+	CreateBoard(context.Context, *CreateBoardRequest) (*BoardWithUsersAndInvites, error)
+	DeleteBoard(context.Context, *DeleteBoardRequest) (*DeleteBoardResponse, error)
+	EditBoard(context.Context, *EditBoardRequest) (*Board, error)
+	GetBoard(context.Context, *GetBoardRequest) (*BoardWithUsersAndInvites, error)
+	// Returns the boards the user making the request is part of.
+	ListBoards(context.Context, *ListBoardsRequest) (*ListBoardsResponse, error)
+	// Returns boards with visibility "public", sorted from newest to oldest.
+	// Like GetBoard, it can also be called without authentication.
+	ListPublicBoards(context.Context, *ListPublicBoardsRequest) (*ListBoardsResponse, error)
+	CreateInvite(context.Context, *CreateInviteRequest) (*Invite, error)
+	// Accept or decline an invite.
+	RespondToInvite(context.Context, *RespondToInviteRequest) (*RespondToInviteResponse, error)
+	DeleteInvite(context.Context, *DeleteInviteRequest) (*DeleteInviteResponse, error)
+	// Returns the invites for the user making the request.
+	ListInvites(context.Context, *ListInvitesRequest) (*ListInvitesResponse, error)
+	// Creates a request of the user making the request to join a board that is visible to non-members.
+	CreateJoinRequest(context.Context, *CreateJoinRequestRequest) (*JoinRequest, error)
+	// Returns the join requests of a board, sorted from newest to oldest.
+	ListJoinRequests(context.Context, *ListJoinRequestsRequest) (*ListJoinRequestsResponse, error)
+	// Approve or reject a join request.
+	RespondToJoinRequest(context.Context, *RespondToJoinRequestRequest) (*RespondToJoinRequestResponse, error)
+	RemoveUser(context.Context, *RemoveUserRequest) (*RemoveUserResponse, error)
+	EditBoardUser(context.Context, *EditBoardUserRequest) (*BoardUser, error)
+	// Creates a custom role or replaces the scopes of an existing one, returns the board with its custom roles.
+	SetCustomRole(context.Context, *SetCustomRoleRequest) (*Board, error)
+	DeleteCustomRole(context.Context, *DeleteCustomRoleRequest) (*Board, error)
+	mustEmbedUnimplementedBoardServiceServer()
+}
+
+// UnimplementedBoardServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBoardServiceServer struct {
+}
+
+func (UnimplementedBoardServiceServer) CreateBoard(context.Context, *CreateBoardRequest) (*BoardWithUsersAndInvites, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) DeleteBoard(context.Context, *DeleteBoardRequest) (*DeleteBoardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) EditBoard(context.Context, *EditBoardRequest) (*Board, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) GetBoard(context.Context, *GetBoardRequest) (*BoardWithUsersAndInvites, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) ListBoards(context.Context, *ListBoardsRequest) (*ListBoardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBoards not implemented")
+}
+func (UnimplementedBoardServiceServer) ListPublicBoards(context.Context, *ListPublicBoardsRequest) (*ListBoardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPublicBoards not implemented")
+}
+func (UnimplementedBoardServiceServer) CreateInvite(context.Context, *CreateInviteRequest) (*Invite, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInvite not implemented")
+}
+func (UnimplementedBoardServiceServer) RespondToInvite(context.Context, *RespondToInviteRequest) (*RespondToInviteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToInvite not implemented")
+}
+func (UnimplementedBoardServiceServer) DeleteInvite(context.Context, *DeleteInviteRequest) (*DeleteInviteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInvite not implemented")
+}
+func (UnimplementedBoardServiceServer) ListInvites(context.Context, *ListInvitesRequest) (*ListInvitesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvites not implemented")
+}
+func (UnimplementedBoardServiceServer) CreateJoinRequest(context.Context, *CreateJoinRequestRequest) (*JoinRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateJoinRequest not implemented")
+}
+func (UnimplementedBoardServiceServer) ListJoinRequests(context.Context, *ListJoinRequestsRequest) (*ListJoinRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJoinRequests not implemented")
+}
+func (UnimplementedBoardServiceServer) RespondToJoinRequest(context.Context, *RespondToJoinRequestRequest) (*RespondToJoinRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToJoinRequest not implemented")
+}
+func (UnimplementedBoardServiceServer) RemoveUser(context.Context, *RemoveUserRequest) (*RemoveUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveUser not implemented")
+}
+func (UnimplementedBoardServiceServer) EditBoardUser(context.Context, *EditBoardUserRequest) (*BoardUser, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditBoardUser not implemented")
+}
+func (UnimplementedBoardServiceServer) SetCustomRole(context.Context, *SetCustomRoleRequest) (*Board, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCustomRole not implemented")
+}
+func (UnimplementedBoardServiceServer) DeleteCustomRole(context.Context, *DeleteCustomRoleRequest) (*Board, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCustomRole not implemented")
+}
+func (UnimplementedBoardServiceServer) mustEmbedUnimplementedBoardServiceServer() {}
+
+// UnsafeBoardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BoardServiceServer will
+// result in compilation errors.
+type UnsafeBoardServiceServer interface {
+	mustEmbedUnimplementedBoardServiceServer()
+}
+
+func RegisterBoardServiceServer(s grpc.ServiceRegistrar, srv BoardServiceServer) {
+	s.RegisterService(&BoardService_ServiceDesc, srv)
+}
+
+func _BoardService_CreateBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).CreateBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/CreateBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).CreateBoard(ctx, req.(*CreateBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_DeleteBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).DeleteBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/DeleteBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).DeleteBoard(ctx, req.(*DeleteBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_EditBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).EditBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/EditBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).EditBoard(ctx, req.(*EditBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_GetBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).GetBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/GetBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).GetBoard(ctx, req.(*GetBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_ListBoards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBoardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).ListBoards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/ListBoards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).ListBoards(ctx, req.(*ListBoardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_ListPublicBoards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPublicBoardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).ListPublicBoards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/ListPublicBoards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).ListPublicBoards(ctx, req.(*ListPublicBoardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_CreateInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).CreateInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/CreateInvite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).CreateInvite(ctx, req.(*CreateInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_RespondToInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).RespondToInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/RespondToInvite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).RespondToInvite(ctx, req.(*RespondToInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_DeleteInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).DeleteInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/DeleteInvite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).DeleteInvite(ctx, req.(*DeleteInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_ListInvites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvitesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).ListInvites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/ListInvites",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).ListInvites(ctx, req.(*ListInvitesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_CreateJoinRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateJoinRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).CreateJoinRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/CreateJoinRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).CreateJoinRequest(ctx, req.(*CreateJoinRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_ListJoinRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJoinRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).ListJoinRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/ListJoinRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).ListJoinRequests(ctx, req.(*ListJoinRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_RespondToJoinRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToJoinRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).RespondToJoinRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/RespondToJoinRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).RespondToJoinRequest(ctx, req.(*RespondToJoinRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_RemoveUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).RemoveUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/RemoveUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).RemoveUser(ctx, req.(*RemoveUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_EditBoardUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditBoardUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).EditBoardUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/EditBoardUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).EditBoardUser(ctx, req.(*EditBoardUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_SetCustomRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCustomRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).SetCustomRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/SetCustomRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).SetCustomRole(ctx, req.(*SetCustomRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_DeleteCustomRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCustomRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).DeleteCustomRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkboards.boards.v1.BoardService/DeleteCustomRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).DeleteCustomRole(ctx, req.(*DeleteCustomRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BoardService_ServiceDesc is the grpc.ServiceDesc for BoardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BoardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "linkboards.boards.v1.BoardService",
+	HandlerType: (*BoardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBoard",
+			Handler:    _BoardService_CreateBoard_Handler,
+		},
+		{
+			MethodName: "DeleteBoard",
+			Handler:    _BoardService_DeleteBoard_Handler,
+		},
+		{
+			MethodName: "EditBoard",
+			Handler:    _BoardService_EditBoard_Handler,
+		},
+		{
+			MethodName: "GetBoard",
+			Handler:    _BoardService_GetBoard_Handler,
+		},
+		{
+			MethodName: "ListBoards",
+			Handler:    _BoardService_ListBoards_Handler,
+		},
+		{
+			MethodName: "ListPublicBoards",
+			Handler:    _BoardService_ListPublicBoards_Handler,
+		},
+		{
+			MethodName: "CreateInvite",
+			Handler:    _BoardService_CreateInvite_Handler,
+		},
+		{
+			MethodName: "RespondToInvite",
+			Handler:    _BoardService_RespondToInvite_Handler,
+		},
+		{
+			MethodName: "DeleteInvite",
+			Handler:    _BoardService_DeleteInvite_Handler,
+		},
+		{
+			MethodName: "ListInvites",
+			Handler:    _BoardService_ListInvites_Handler,
+		},
+		{
+			MethodName: "CreateJoinRequest",
+			Handler:    _BoardService_CreateJoinRequest_Handler,
+		},
+		{
+			MethodName: "ListJoinRequests",
+			Handler:    _BoardService_ListJoinRequests_Handler,
+		},
+		{
+			MethodName: "RespondToJoinRequest",
+			Handler:    _BoardService_RespondToJoinRequest_Handler,
+		},
+		{
+			MethodName: "RemoveUser",
+			Handler:    _BoardService_RemoveUser_Handler,
+		},
+		{
+			MethodName: "EditBoardUser",
+			Handler:    _BoardService_EditBoardUser_Handler,
+		},
+		{
+			MethodName: "SetCustomRole",
+			Handler:    _BoardService_SetCustomRole_Handler,
+		},
+		{
+			MethodName: "DeleteCustomRole",
+			Handler:    _BoardService_DeleteCustomRole_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "boards/v1/boards.proto",
+}
@@ -0,0 +1,256 @@
+// Package dashboard implements the "GET /me/dashboard" endpoint, that returns everything a client needs to render
+// the start page of a user in a single request: the boards of the user together with their top links and number of members,
+// and the open invites of the user.
+//
+// The data is assembled from the application services of the boards and links components.
+// Parts that cannot be loaded in time are left out and replaced by an error message,
+// such that clients can still show the rest.
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	stdtime "time"
+
+	boards "github.com/dkinzler/linkboards/internal/boards/application"
+	"github.com/dkinzler/linkboards/internal/endpointname"
+	links "github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name of the endpoint, e.g. to configure its rate limit.
+const endpointName = "getDashboard"
+
+func init() {
+	endpointname.Register(endpointName)
+}
+
+const defaultLinksPerBoard = 3
+const maxLinksPerBoard = 10
+const defaultMaxConcurrency = 4
+const defaultSectionTimeout = 3 * stdtime.Second
+
+type Config struct {
+	BoardApplicationService boards.BoardApplicationService
+	LinkApplicationService  links.LinkApplicationService
+
+	// Middlewares that should be applied to the endpoint.
+	// They run after the authentication middleware and can get the name of the endpoint ("getDashboard") using package endpointname.
+	Middlewares []endpoint.Middleware
+	// Authentication middleware for the endpoint.
+	AuthMiddleware endpoint.Middleware
+	// If not nil, metrics are recorded for the endpoint.
+	Metrics *metrics.Metrics
+	// If not nil, spans are created for the endpoint, see package tracing.
+	TracerProvider trace.TracerProvider
+
+	// Number of top links returned per board if the request does not specify it, defaults to 3.
+	LinksPerBoard int
+	// Max number of boards for which links and member counts are loaded at the same time, defaults to 4.
+	MaxConcurrency int
+	// Time after which a section of the dashboard (i.e. the boards, invites or the links and member counts of the boards)
+	// that has not been loaded yet is left out, defaults to 3s.
+	SectionTimeout stdtime.Duration
+}
+
+type Dashboard struct {
+	Boards       []Board         `json:"boards,omitempty"`
+	BoardsError  string          `json:"boardsError,omitempty"`
+	Invites      []boards.Invite `json:"invites,omitempty"`
+	InvitesError string          `json:"invitesError,omitempty"`
+}
+
+type Board struct {
+	boards.Board
+	MemberCount int `json:"memberCount,omitempty"`
+	// Links with the highest score.
+	TopLinks []links.Link `json:"topLinks,omitempty"`
+	// Set if the member count or links of the board could not be loaded.
+	Error string `json:"error,omitempty"`
+}
+
+type Service struct {
+	boards boards.BoardApplicationService
+	links  links.LinkApplicationService
+
+	linksPerBoard  int
+	maxConcurrency int
+	sectionTimeout stdtime.Duration
+}
+
+func NewService(config Config) (*Service, error) {
+	if config.BoardApplicationService == nil || config.LinkApplicationService == nil {
+		return nil, errors.New(nil, "dashboard", errors.InvalidArgument).WithInternalMessage("application services must not be nil")
+	}
+	s := &Service{
+		boards:         config.BoardApplicationService,
+		links:          config.LinkApplicationService,
+		linksPerBoard:  config.LinksPerBoard,
+		maxConcurrency: config.MaxConcurrency,
+		sectionTimeout: config.SectionTimeout,
+	}
+	if s.linksPerBoard <= 0 || s.linksPerBoard > maxLinksPerBoard {
+		s.linksPerBoard = defaultLinksPerBoard
+	}
+	if s.maxConcurrency <= 0 {
+		s.maxConcurrency = defaultMaxConcurrency
+	}
+	if s.sectionTimeout <= 0 {
+		s.sectionTimeout = defaultSectionTimeout
+	}
+	return s, nil
+}
+
+// Returns the dashboard of the user making the request.
+// If linksPerBoard is 0, the configured default is used.
+func (s *Service) Dashboard(ctx context.Context, linksPerBoard int) (Dashboard, error) {
+	if linksPerBoard < 0 || linksPerBoard > maxLinksPerBoard {
+		return Dashboard{}, errors.New(nil, "dashboard", errors.InvalidArgument).WithPublicMessage("invalid number of links")
+	}
+	if linksPerBoard == 0 {
+		linksPerBoard = s.linksPerBoard
+	}
+
+	bi, err := s.boards.BoardsAndInvites(ctx, s.sectionTimeout)
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	return Dashboard{
+		Boards:       s.loadBoardDetails(ctx, bi.Boards, linksPerBoard),
+		BoardsError:  bi.BoardsError,
+		Invites:      bi.Invites,
+		InvitesError: bi.InvitesError,
+	}, nil
+}
+
+const errBoardDetails = "Could not load board details"
+
+// Loads the member counts and top links of the boards, at most maxConcurrency boards at a time.
+// Boards whose details could not be loaded within the section timeout are returned with an error message.
+func (s *Service) loadBoardDetails(ctx context.Context, bs []boards.Board, linksPerBoard int) []Board {
+	ctx, cancel := context.WithTimeout(ctx, s.sectionTimeout)
+	defer cancel()
+
+	result := make([]Board, len(bs))
+	for i, b := range bs {
+		result[i] = Board{Board: b, Error: errBoardDetails}
+	}
+
+	type boardResult struct {
+		i     int
+		board Board
+	}
+	// Buffered, such that goroutines don't block if we stopped waiting for them.
+	results := make(chan boardResult, len(bs))
+	sem := make(chan struct{}, s.maxConcurrency)
+	go func() {
+		for i, b := range bs {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, b boards.Board) {
+				defer func() { <-sem }()
+				results <- boardResult{i: i, board: s.loadBoard(ctx, b, linksPerBoard)}
+			}(i, b)
+		}
+	}()
+
+	for received := 0; received < len(bs); received++ {
+		select {
+		case r := <-results:
+			result[r.i] = r.board
+		case <-ctx.Done():
+			return result
+		}
+	}
+	return result
+}
+
+func (s *Service) loadBoard(ctx context.Context, b boards.Board, linksPerBoard int) Board {
+	result := Board{Board: b}
+
+	count, err := s.boards.UserCount(ctx, b.BoardId)
+	if err != nil {
+		result.Error = errBoardDetails
+		return result
+	}
+	result.MemberCount = count
+
+	// The links application service returns at least 10 links.
+	ls, err := s.links.Links(ctx, b.BoardId, links.LinkQueryParams{Limit: 10, Sort: "top"})
+	if err != nil {
+		result.Error = errBoardDetails
+		return result
+	}
+	if len(ls) > linksPerBoard {
+		ls = ls[:linksPerBoard]
+	}
+	result.TopLinks = ls
+	return result
+}
+
+type DashboardRequest struct {
+	LinksPerBoard int
+}
+
+func MakeDashboardEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(DashboardRequest)
+		r, err := s.Dashboard(ctx, req.LinksPerBoard)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
+// Returns a http handler for the dashboard endpoint.
+// The number of top links per board can be set with the "links" query parameter.
+func NewHttpHandler(config Config, opts []kithttp.ServerOption) (http.Handler, error) {
+	s, err := NewService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var mws []endpoint.Middleware
+	mws = append(mws, config.Middlewares...)
+	if config.AuthMiddleware != nil {
+		mws = append(mws, config.AuthMiddleware)
+	}
+	if config.Metrics != nil {
+		mws = append(mws, config.Metrics.EndpointMiddleware("dashboard", endpointName))
+	}
+	if config.TracerProvider != nil {
+		mws = append(mws, tracing.EndpointMiddleware(config.TracerProvider, "dashboard", endpointName))
+	}
+	// outermost middleware, makes the endpoint name available to all other middlewares
+	mws = append(mws, endpointname.Middleware(endpointName))
+	ep := e.ApplyMiddlewares(MakeDashboardEndpoint(s), mws...)
+
+	return kithttp.NewServer(ep, decodeHttpDashboardRequest, t.MakeGenericJSONEncodeFunc(200), opts...), nil
+}
+
+func decodeHttpDashboardRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req DashboardRequest
+	if v := r.URL.Query().Get("links"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New(err, "dashboard", errors.InvalidArgument).WithPublicMessage("invalid number of links")
+		}
+		req.LinksPerBoard = n
+	}
+	return req, nil
+}
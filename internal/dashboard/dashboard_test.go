@@ -0,0 +1,211 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/auth/store"
+	boardscomponent "github.com/dkinzler/linkboards/internal/boards"
+	boards "github.com/dkinzler/linkboards/internal/boards/application"
+	linkscomponent "github.com/dkinzler/linkboards/internal/links"
+	links "github.com/dkinzler/linkboards/internal/links/application"
+
+	dhttp "github.com/dkinzler/kit/transport/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServices(t *testing.T) (boards.BoardApplicationService, links.LinkApplicationService) {
+	boardComponent, err := boardscomponent.NewComponent(boardscomponent.Config{UseInmemDataStore: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	linksComponent, err := linkscomponent.NewComponent(linkscomponent.Config{
+		UseInmemDataStore:  true,
+		AuthorizationStore: store.NewDefaultAuthorizationStore(boardComponent.DataStore),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return boardComponent.ApplicationService, linksComponent.ApplicationService
+}
+
+func userContext(userId string) context.Context {
+	return auth.ContextWithUser(context.Background(), auth.User{UserId: userId, Name: userId})
+}
+
+// Blocks queries for the links of the given board and records the max number of concurrent queries.
+type slowLinkService struct {
+	links.LinkApplicationService
+	slowBoardId string
+
+	m              sync.Mutex
+	running        int
+	maxConcurrency int
+}
+
+func (s *slowLinkService) Links(ctx context.Context, boardId string, qp links.LinkQueryParams) ([]links.Link, error) {
+	s.m.Lock()
+	s.running++
+	if s.running > s.maxConcurrency {
+		s.maxConcurrency = s.running
+	}
+	s.m.Unlock()
+	defer func() {
+		s.m.Lock()
+		s.running--
+		s.m.Unlock()
+	}()
+
+	if boardId == s.slowBoardId {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	// give other queries the chance to run at the same time
+	stdtime.Sleep(5 * stdtime.Millisecond)
+	return s.LinkApplicationService.Links(ctx, boardId, qp)
+}
+
+func TestDashboard(t *testing.T) {
+	a := assert.New(t)
+
+	bs, ls := newTestServices(t)
+
+	// u-1 owns three boards with 4 links each, u-2 owns a board that u-3 joined
+	for i := 0; i < 3; i++ {
+		b, err := bs.CreateBoard(userContext("u-1"), boards.NewBoard{Name: "Board"})
+		a.Nil(err)
+		for j := 0; j < 4; j++ {
+			_, err := ls.CreateLink(userContext("u-1"), b.BoardId, links.NewLink{Title: "Link", Url: "https://example.com"})
+			a.Nil(err)
+		}
+	}
+	b, err := bs.CreateBoard(userContext("u-2"), boards.NewBoard{Name: "Other board"})
+	a.Nil(err)
+	invite, err := bs.CreateInvite(userContext("u-2"), b.BoardId, boards.NewInvite{Role: auth.BoardRoleViewer})
+	a.Nil(err)
+	a.Nil(bs.RespondToInvite(userContext("u-3"), b.BoardId, invite.InviteId, boards.InviteResponse{Response: "accept"}))
+
+	s, err := NewService(Config{BoardApplicationService: bs, LinkApplicationService: ls})
+	a.Nil(err)
+
+	d, err := s.Dashboard(userContext("u-1"), 2)
+	a.Nil(err)
+	a.Empty(d.BoardsError)
+	a.Empty(d.InvitesError)
+	a.Len(d.Boards, 3)
+	for _, board := range d.Boards {
+		a.Empty(board.Error)
+		a.Equal(1, board.MemberCount)
+		a.Len(board.TopLinks, 2)
+	}
+
+	d, err = s.Dashboard(userContext("u-2"), 0)
+	a.Nil(err)
+	a.Len(d.Boards, 1)
+	a.Equal(2, d.Boards[0].MemberCount)
+	a.Empty(d.Boards[0].TopLinks)
+
+	_, err = s.Dashboard(userContext("u-1"), maxLinksPerBoard+1)
+	a.NotNil(err)
+
+	_, err = s.Dashboard(context.Background(), 0)
+	a.NotNil(err)
+}
+
+func TestDashboardReturnsPartialResults(t *testing.T) {
+	a := assert.New(t)
+
+	bs, ls := newTestServices(t)
+
+	var boardIds []string
+	for i := 0; i < 8; i++ {
+		b, err := bs.CreateBoard(userContext("u-1"), boards.NewBoard{Name: "Board"})
+		a.Nil(err)
+		boardIds = append(boardIds, b.BoardId)
+	}
+
+	sls := &slowLinkService{LinkApplicationService: ls, slowBoardId: boardIds[0]}
+	s, err := NewService(Config{
+		BoardApplicationService: bs,
+		LinkApplicationService:  sls,
+		MaxConcurrency:          2,
+		SectionTimeout:          200 * stdtime.Millisecond,
+	})
+	a.Nil(err)
+
+	d, err := s.Dashboard(userContext("u-1"), 0)
+	a.Nil(err)
+	a.Len(d.Boards, 8)
+	var failed int
+	for _, board := range d.Boards {
+		if board.Error != "" {
+			failed++
+			a.Equal(boardIds[0], board.BoardId)
+		}
+	}
+	a.Equal(1, failed)
+	a.LessOrEqual(sls.maxConcurrency, 2)
+}
+
+func TestHttpHandler(t *testing.T) {
+	a := assert.New(t)
+
+	bs, ls := newTestServices(t)
+	_, err := bs.CreateBoard(userContext("u-1"), boards.NewBoard{Name: "Board"})
+	a.Nil(err)
+
+	handler, err := NewHttpHandler(Config{
+		BoardApplicationService: bs,
+		LinkApplicationService:  ls,
+		AuthMiddleware:          middleware.NewFakeAuthEndpointMiddleware(),
+	}, []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext),
+		kithttp.ServerErrorEncoder(func(ctx context.Context, err error, w http.ResponseWriter) {
+			dhttp.EncodeError(ctx, err, w)
+		}),
+	})
+	a.Nil(err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	get := func(path string, userId string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if userId != "" {
+			req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userId+":pw")))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := get("/me/dashboard?links=5", "u-1")
+	defer resp.Body.Close()
+	a.Equal(http.StatusOK, resp.StatusCode)
+	var d Dashboard
+	a.Nil(json.NewDecoder(resp.Body).Decode(&d))
+	a.Len(d.Boards, 1)
+	a.Equal("Board", d.Boards[0].Name)
+
+	resp = get("/me/dashboard?links=abc", "u-1")
+	resp.Body.Close()
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp = get("/me/dashboard", "")
+	resp.Body.Close()
+	a.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
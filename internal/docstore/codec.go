@@ -0,0 +1,493 @@
+package docstore
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Converting Go values to and from the data of a document in the Fake.
+// Follows the rules of the firestore package for the cases used in this project:
+// struct fields are named by their firestore tag (or the field name if there is none), fields tagged "-" are skipped,
+// "omitempty" omits zero values, integers are stored as int64, floats as float64 and nil slices and maps as null.
+// When decoding, field names are matched case-insensitively and unknown fields are ignored.
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	deleteType = reflect.TypeOf(firestore.Delete)
+)
+
+// Encodes a struct or map into the data of a document.
+func encodeData(data interface{}) (map[string]interface{}, error) {
+	v, err := encodeValue(reflect.ValueOf(data))
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("document data must be a struct or map, got %T", data)
+	}
+	return m, nil
+}
+
+func encodeValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type() == deleteType {
+		return nil, fmt.Errorf("firestore.Delete can only be used in updates and merges")
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Truncate(time.Microsecond).UTC(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("uint value %d overflows int64", u)
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte{}, v.Bytes()...), nil
+		}
+		return encodeArray(v)
+	case reflect.Array:
+		return encodeArray(v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key must be a string, got %v", v.Type().Key())
+		}
+		if v.IsNil() {
+			return nil, nil
+		}
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			e, err := encodeValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			result[iter.Key().String()] = e
+		}
+		return result, nil
+	case reflect.Struct:
+		result := make(map[string]interface{})
+		for _, f := range structFields(v.Type()) {
+			fv := v.FieldByIndex(f.index)
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+			e, err := encodeValue(fv)
+			if err != nil {
+				return nil, err
+			}
+			result[f.name] = e
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("cannot encode value of type %v", v.Type())
+}
+
+func encodeArray(v reflect.Value) ([]interface{}, error) {
+	result := make([]interface{}, v.Len())
+	for i := range result {
+		e, err := encodeValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = e
+	}
+	return result, nil
+}
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+func structFields(t reflect.Type) []structField {
+	var result []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("firestore")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		// fields of embedded structs without a name are promoted
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for _, ef := range structFields(f.Type) {
+				ef.index = append([]int{i}, ef.index...)
+				result = append(result, ef)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		result = append(result, structField{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: options == "omitempty",
+		})
+	}
+	return result
+}
+
+// Decodes a value of document data into dst, which must be settable.
+func decodeValue(dst reflect.Value, src interface{}) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.Type() == timeType {
+		t, ok := src.(time.Time)
+		if !ok {
+			return decodeError(dst, src)
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return decodeError(dst, src)
+		}
+		dst.Set(reflect.ValueOf(copyValue(src)))
+		return nil
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeValue(dst.Elem(), src)
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return decodeError(dst, src)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return decodeError(dst, src)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := src.(int64)
+		if !ok || dst.OverflowInt(i) {
+			return decodeError(dst, src)
+		}
+		dst.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := src.(int64)
+		if !ok || i < 0 || dst.OverflowUint(uint64(i)) {
+			return decodeError(dst, src)
+		}
+		dst.SetUint(uint64(i))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch x := src.(type) {
+		case float64:
+			dst.SetFloat(x)
+		case int64:
+			dst.SetFloat(float64(x))
+		default:
+			return decodeError(dst, src)
+		}
+		return nil
+	case reflect.Slice:
+		if b, ok := src.([]byte); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes(append([]byte{}, b...))
+			return nil
+		}
+		a, ok := src.([]interface{})
+		if !ok {
+			return decodeError(dst, src)
+		}
+		s := reflect.MakeSlice(dst.Type(), len(a), len(a))
+		for i, e := range a {
+			if err := decodeValue(s.Index(i), e); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+	case reflect.Array:
+		a, ok := src.([]interface{})
+		if !ok {
+			return decodeError(dst, src)
+		}
+		for i := 0; i < dst.Len(); i++ {
+			if i >= len(a) {
+				dst.Index(i).Set(reflect.Zero(dst.Type().Elem()))
+			} else if err := decodeValue(dst.Index(i), a[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return decodeError(dst, src)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(m)))
+		}
+		for k, e := range m {
+			v := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(v, e); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), v)
+		}
+		return nil
+	case reflect.Struct:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			return decodeError(dst, src)
+		}
+		fields := structFields(dst.Type())
+		for k, e := range m {
+			f, ok := matchField(fields, k)
+			if !ok {
+				continue
+			}
+			fv, err := fieldByIndexAlloc(dst, f.index)
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(fv, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return decodeError(dst, src)
+}
+
+// Prefers an exact match of the name, like the firestore package.
+func matchField(fields []structField, name string) (structField, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
+
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	if !v.CanSet() {
+		return reflect.Value{}, fmt.Errorf("cannot set field of type %v", v.Type())
+	}
+	return v, nil
+}
+
+func decodeError(dst reflect.Value, src interface{}) error {
+	return fmt.Errorf("cannot decode value of type %T into %v", src, dst.Type())
+}
+
+// Returns a deep copy of a value of document data.
+func copyValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return copyMap(x)
+	case []interface{}:
+		result := make([]interface{}, len(x))
+		for i, e := range x {
+			result[i] = copyValue(e)
+		}
+		return result
+	case []byte:
+		return append([]byte{}, x...)
+	}
+	return v
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(m))
+	for k, e := range m {
+		result[k] = copyValue(e)
+	}
+	return result
+}
+
+// Returns the value at the given path, the second return value is false if there is none.
+func valueAt(data map[string]interface{}, path []string) (interface{}, bool) {
+	var v interface{} = data
+	for _, p := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok = m[p]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Sets the value at the given path, creating intermediate maps as needed.
+// Non-map values on the path are replaced.
+func setValueAt(data map[string]interface{}, path []string, v interface{}) {
+	m := data
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
+
+func deleteValueAt(data map[string]interface{}, path []string) {
+	m := data
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, path[len(path)-1])
+}
+
+// Values are ordered by type first, then by value, like in Firestore.
+// Integers and floats are compared as numbers.
+func compareValues(a, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return compareInts(ra, rb)
+	}
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		if x == y {
+			return 0
+		} else if !x {
+			return -1
+		}
+		return 1
+	case int64, float64:
+		// converting large integers like Unix times in nanoseconds to float64 would lose precision
+		if ia, ok := a.(int64); ok {
+			if ib, ok := b.(int64); ok {
+				return compareInts(ia, ib)
+			}
+		}
+		fa, fb := toFloat(a), toFloat(b)
+		if fa < fb {
+			return -1
+		} else if fa > fb {
+			return 1
+		}
+		return 0
+	case time.Time:
+		y := b.(time.Time)
+		if x.Before(y) {
+			return -1
+		} else if x.After(y) {
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(x, b.(string))
+	case []byte:
+		return strings.Compare(string(x), string(b.([]byte)))
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(x), len(y))
+	}
+	// maps are not ordered further, they are not used in order by clauses or cursors
+	return 0
+}
+
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int64, float64:
+		return 2
+	case time.Time:
+		return 3
+	case string:
+		return 4
+	case []byte:
+		return 5
+	case []interface{}:
+		return 6
+	}
+	return 7
+}
+
+func toFloat(v interface{}) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+func compareInts[T int | int64](a, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,210 @@
+// Package docstore provides the narrow subset of Firestore used by the firestore data stores of the boards and links components.
+//
+// Besides an implementation backed by a firestore.Client, there is an in-memory Fake that mimics the used features,
+// i.e. equality filters, ordering, start cursors, field selection and transactions.
+// This allows the data stores, including the mapping of domain types to documents, to be tested without the Firestore emulator.
+//
+// Value types of the firestore package like firestore.Update, firestore.FieldPath, firestore.Direction and firestore.Delete
+// are used as is, they don't depend on a client.
+// Errors returned are errors of package github.com/dkinzler/kit/errors, e.g. a document that does not exist results in a NotFound error.
+// The only exception are commit errors of transactions of the firestore client, which are returned unchanged
+// so that contention (grpc code Aborted) can still be detected.
+package docstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dkinzler/kit/errors"
+	fs "github.com/dkinzler/kit/firebase/firestore"
+
+	"cloud.google.com/go/firestore"
+)
+
+type Store interface {
+	// Fails if the document already exists.
+	Create(ctx context.Context, ref DocRef, data interface{}) error
+	// Returns a NotFound error if the document does not exist.
+	Get(ctx context.Context, ref DocRef) (Document, error)
+	// Returns a document for every ref in the same order, documents that don't exist are included, see Document.Exists.
+	GetAll(ctx context.Context, refs []DocRef) ([]Document, error)
+	// Deleting a document that does not exist is not an error.
+	Delete(ctx context.Context, ref DocRef) error
+	Query(ctx context.Context, q Query) ([]Document, error)
+	// Runs f in a transaction, all reads of a transaction must happen before its writes.
+	// Like the firestore data stores always did, a transaction is not retried if it fails because of contention.
+	RunTransaction(ctx context.Context, f func(ctx context.Context, t Transaction) error) error
+}
+
+// The writes of a transaction are applied atomically when the transaction function returns without error.
+type Transaction interface {
+	Get(ref DocRef) (Document, error)
+	GetAll(refs []DocRef) ([]Document, error)
+	// Replaces the document or creates it if it does not exist.
+	Set(ref DocRef, data interface{}) error
+	// Sets only the values of data at the given paths, a value of firestore.Delete removes the field at the path.
+	// Every path must be contained in data. Creates the document if it does not exist.
+	MergeSet(ref DocRef, data map[string]interface{}, paths ...firestore.FieldPath) error
+	// Fails if the document does not exist.
+	Update(ref DocRef, updates []firestore.Update) error
+	Delete(ref DocRef) error
+}
+
+type Document interface {
+	Ref() DocRef
+	Exists() bool
+	// Time of the last write to the document, zero if it does not exist.
+	UpdateTime() time.Time
+	// Decodes the document into a struct or map, using the same rules as firestore.DocumentSnapshot.DataTo.
+	DataTo(v interface{}) error
+	// Returns the value at a dot separated path.
+	DataAt(path string) (interface{}, error)
+}
+
+// Identifies a document in a top-level collection.
+type DocRef struct {
+	Collection string
+	Id         string
+}
+
+func (r DocRef) Path() string {
+	return r.Collection + "/" + r.Id
+}
+
+// A query of the documents of a collection, built like a firestore.Query.
+// Only equality filters are supported.
+type Query struct {
+	collection string
+	filters    []filter
+	// nil if all fields are returned
+	selectPaths []string
+	orders      []order
+	startAt     []interface{}
+	// If true, documents with values equal to startAt are not returned.
+	startAfter bool
+	limit      int
+}
+
+type filter struct {
+	path  string
+	op    string
+	value interface{}
+}
+
+type order struct {
+	path string
+	dir  firestore.Direction
+}
+
+func NewQuery(collection string) Query {
+	return Query{collection: collection}
+}
+
+// Paths are dot separated, e.g. "user.userId".
+func (q Query) Where(path string, op string, value interface{}) Query {
+	q.filters = append(append([]filter{}, q.filters...), filter{path: path, op: op, value: value})
+	return q
+}
+
+// Documents returned by the query only contain the values at the given paths.
+func (q Query) Select(paths ...string) Query {
+	q.selectPaths = append([]string{}, paths...)
+	return q
+}
+
+// Documents that don't have a value at the path are not returned.
+// Use firestore.DocumentID as path to order by the id of documents.
+func (q Query) OrderBy(path string, dir firestore.Direction) Query {
+	q.orders = append(append([]order{}, q.orders...), order{path: path, dir: dir})
+	return q
+}
+
+// Returns only documents at or after the given values of the fields the query is ordered by.
+func (q Query) StartAt(values ...interface{}) Query {
+	q.startAt = values
+	q.startAfter = false
+	return q
+}
+
+// Returns only documents after the given values of the fields the query is ordered by.
+func (q Query) StartAfter(values ...interface{}) Query {
+	q.startAt = values
+	q.startAfter = true
+	return q
+}
+
+func (q Query) Limit(n int) Query {
+	q.limit = n
+	return q
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// The state of a document when it was read.
+// Can be used to implement optimistic concurrency, i.e. to verify in a transaction that a document has not changed since it was read.
+type Expectation struct {
+	Ref        DocRef
+	Exists     bool
+	UpdateTime time.Time
+}
+
+// Expectations for a set of documents, by path of the document.
+type Expectations map[string]Expectation
+
+func NewExpectations(docs ...Document) Expectations {
+	result := Expectations{}
+	for _, doc := range docs {
+		result[doc.Ref().Path()] = Expectation{
+			Ref:        doc.Ref(),
+			Exists:     doc.Exists(),
+			UpdateTime: doc.UpdateTime(),
+		}
+	}
+	return result
+}
+
+// Combine two sets of expectations.
+// If both sets contain an expectation for the same document, the expectation with the more recent update time is used.
+func (e Expectations) Combine(other Expectations) Expectations {
+	combined := Expectations{}
+	for path, x := range e {
+		combined[path] = x
+	}
+	for path, x := range other {
+		existing, ok := combined[path]
+		if !ok || existing.UpdateTime.Before(x.UpdateTime) {
+			combined[path] = x
+		}
+	}
+	return combined
+}
+
+// Reads the documents in the transaction, returns a FailedPrecondition error if any of them changed.
+func (e Expectations) Verify(t Transaction) error {
+	if len(e) == 0 {
+		return nil
+	}
+	refs := make([]DocRef, 0, len(e))
+	for _, x := range e {
+		refs = append(refs, x.Ref)
+	}
+	docs, err := t.GetAll(refs)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		x := e[doc.Ref().Path()]
+		if x.Exists != doc.Exists() {
+			return fs.NewFirestoreError(nil, errors.FailedPrecondition).
+				WithInternalMessage("transaction expectation failed: document existence changed")
+		}
+		if x.Exists && !x.UpdateTime.Equal(doc.UpdateTime()) {
+			return fs.NewFirestoreError(nil, errors.FailedPrecondition).
+				WithInternalMessage("transaction expectation failed: document was updated")
+		}
+	}
+	return nil
+}
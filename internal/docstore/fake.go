@@ -0,0 +1,473 @@
+package docstore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dkinzler/kit/errors"
+	fs "github.com/dkinzler/kit/firebase/firestore"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// In-memory implementation of Store for tests.
+//
+// Mimics the Firestore features used by the data stores:
+//   - Values are stored like Firestore stores them, see codec.go. Decoding a document therefore exercises the same mapping code.
+//   - Every write sets the update time of a document, writes of the same transaction share an update time.
+//   - Queries support equality filters, ordering by multiple fields (documents without a value for an ordered field are excluded),
+//     start cursors, field selection and limits.
+//   - Transactions are serializable, writes are buffered and applied atomically on commit, reads after writes fail.
+//
+// Transactions hold a lock on the whole Fake, the transaction function must therefore not call methods of the Fake directly.
+type Fake struct {
+	mu sync.Mutex
+	// by document path
+	docs      map[string]fakeDoc
+	lastWrite time.Time
+}
+
+type fakeDoc struct {
+	ref        DocRef
+	data       map[string]interface{}
+	updateTime time.Time
+}
+
+func NewFake() *Fake {
+	return &Fake{docs: map[string]fakeDoc{}}
+}
+
+// A write that is applied to a copy of the documents, fails without effect if it returns an error.
+type fakeWrite func(docs map[string]fakeDoc, now time.Time) error
+
+// Applies the writes atomically. Must be called with the lock held.
+func (f *Fake) commit(writes []fakeWrite) error {
+	if len(writes) == 0 {
+		return nil
+	}
+	docs := make(map[string]fakeDoc, len(f.docs))
+	for path, doc := range f.docs {
+		docs[path] = doc
+	}
+	// update times are strictly increasing, so that every write can be detected by a transaction expectation
+	now := time.Now().Truncate(time.Microsecond).UTC()
+	if !now.After(f.lastWrite) {
+		now = f.lastWrite.Add(time.Microsecond)
+	}
+	for _, w := range writes {
+		if err := w(docs, now); err != nil {
+			return err
+		}
+	}
+	f.docs = docs
+	f.lastWrite = now
+	return nil
+}
+
+func (f *Fake) document(ref DocRef) fakeDocument {
+	doc, ok := f.docs[ref.Path()]
+	if !ok {
+		return fakeDocument{ref: ref}
+	}
+	return fakeDocument{ref: ref, exists: true, data: doc.data, updateTime: doc.updateTime}
+}
+
+func (f *Fake) Create(ctx context.Context, ref DocRef, data interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return fs.ParseFirestoreError(err)
+	}
+	w, err := createWrite(ref, data)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commit([]fakeWrite{w})
+}
+
+func (f *Fake) Get(ctx context.Context, ref DocRef) (Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fs.ParseFirestoreError(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.get(ref)
+}
+
+func (f *Fake) get(ref DocRef) (Document, error) {
+	doc := f.document(ref)
+	if !doc.exists {
+		return nil, notFoundError(ref)
+	}
+	return doc, nil
+}
+
+func (f *Fake) GetAll(ctx context.Context, refs []DocRef) ([]Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fs.ParseFirestoreError(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.getAll(refs), nil
+}
+
+func (f *Fake) getAll(refs []DocRef) []Document {
+	result := make([]Document, len(refs))
+	for i, ref := range refs {
+		result[i] = f.document(ref)
+	}
+	return result
+}
+
+func (f *Fake) Delete(ctx context.Context, ref DocRef) error {
+	if err := ctx.Err(); err != nil {
+		return fs.ParseFirestoreError(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commit([]fakeWrite{deleteWrite(ref)})
+}
+
+func (f *Fake) Query(ctx context.Context, q Query) ([]Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fs.ParseFirestoreError(err)
+	}
+	if len(q.startAt) > len(q.orders) {
+		return nil, invalidArgumentError("too many cursor values")
+	}
+	filters := make([]filter, len(q.filters))
+	for i, fl := range q.filters {
+		if fl.op != "==" {
+			return nil, invalidArgumentError(fmt.Sprintf("operator %q is not supported", fl.op))
+		}
+		v, err := encodeValue(reflect.ValueOf(fl.value))
+		if err != nil {
+			return nil, invalidArgumentError(err.Error())
+		}
+		filters[i] = filter{path: fl.path, op: fl.op, value: v}
+	}
+	cursor := make([]interface{}, len(q.startAt))
+	for i, c := range q.startAt {
+		v, err := encodeValue(reflect.ValueOf(c))
+		if err != nil {
+			return nil, invalidArgumentError(err.Error())
+		}
+		cursor[i] = v
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	type match struct {
+		doc fakeDoc
+		// values of the ordered fields
+		values []interface{}
+	}
+	var matches []match
+docs:
+	for _, doc := range f.docs {
+		if doc.ref.Collection != q.collection {
+			continue
+		}
+		for _, fl := range filters {
+			v, ok := valueAt(doc.data, splitPath(fl.path))
+			if !ok || compareValues(v, fl.value) != 0 {
+				continue docs
+			}
+		}
+		values := make([]interface{}, len(q.orders))
+		for i, o := range q.orders {
+			if o.path == firestore.DocumentID {
+				values[i] = doc.ref.Id
+				continue
+			}
+			v, ok := valueAt(doc.data, splitPath(o.path))
+			if !ok {
+				continue docs
+			}
+			values[i] = v
+		}
+		matches = append(matches, match{doc: doc, values: values})
+	}
+
+	// Like Firestore, documents with equal values are ordered by id, in the direction of the last order.
+	lastDir := firestore.Asc
+	if len(q.orders) > 0 {
+		lastDir = q.orders[len(q.orders)-1].dir
+	}
+	compare := func(values []interface{}, other []interface{}) int {
+		for i := range other {
+			c := compareValues(values[i], other[i])
+			if q.orders[i].dir == firestore.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if c := compare(matches[i].values, matches[j].values); c != 0 {
+			return c < 0
+		}
+		if lastDir == firestore.Desc {
+			return matches[i].doc.ref.Id > matches[j].doc.ref.Id
+		}
+		return matches[i].doc.ref.Id < matches[j].doc.ref.Id
+	})
+
+	var result []Document
+	for _, m := range matches {
+		if len(cursor) > 0 {
+			if c := compare(m.values, cursor); c < 0 || (c == 0 && q.startAfter) {
+				continue
+			}
+		}
+		if q.limit > 0 && len(result) >= q.limit {
+			break
+		}
+		data := m.doc.data
+		if q.selectPaths != nil {
+			data = map[string]interface{}{}
+			for _, p := range q.selectPaths {
+				path := splitPath(p)
+				if v, ok := valueAt(m.doc.data, path); ok {
+					setValueAt(data, path, v)
+				}
+			}
+		}
+		result = append(result, fakeDocument{ref: m.doc.ref, exists: true, data: data, updateTime: m.doc.updateTime})
+	}
+	return result, nil
+}
+
+func (f *Fake) RunTransaction(ctx context.Context, fn func(ctx context.Context, t Transaction) error) error {
+	if err := ctx.Err(); err != nil {
+		return fs.ParseFirestoreError(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTransaction{fake: f}
+	if err := fn(ctx, t); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fs.ParseFirestoreError(err)
+	}
+	return f.commit(t.writes)
+}
+
+type fakeTransaction struct {
+	fake   *Fake
+	writes []fakeWrite
+}
+
+func (t *fakeTransaction) checkRead() error {
+	if len(t.writes) > 0 {
+		return fs.NewFirestoreError(nil, errors.Internal).WithInternalMessage("read after write in transaction")
+	}
+	return nil
+}
+
+func (t *fakeTransaction) Get(ref DocRef) (Document, error) {
+	if err := t.checkRead(); err != nil {
+		return nil, err
+	}
+	return t.fake.get(ref)
+}
+
+func (t *fakeTransaction) GetAll(refs []DocRef) ([]Document, error) {
+	if err := t.checkRead(); err != nil {
+		return nil, err
+	}
+	return t.fake.getAll(refs), nil
+}
+
+func (t *fakeTransaction) Set(ref DocRef, data interface{}) error {
+	d, err := encodeData(data)
+	if err != nil {
+		return fs.NewFirestoreError(err, errors.Internal)
+	}
+	t.writes = append(t.writes, func(docs map[string]fakeDoc, now time.Time) error {
+		docs[ref.Path()] = fakeDoc{ref: ref, data: copyMap(d), updateTime: now}
+		return nil
+	})
+	return nil
+}
+
+func (t *fakeTransaction) MergeSet(ref DocRef, data map[string]interface{}, paths ...firestore.FieldPath) error {
+	type fieldValue struct {
+		path  []string
+		value interface{}
+		// true if the field is deleted
+		del bool
+	}
+	values := make([]fieldValue, len(paths))
+	for i, p := range paths {
+		v, ok := valueAt(data, p)
+		if !ok {
+			return fs.NewFirestoreError(nil, errors.Internal).WithInternalMessage(fmt.Sprintf("merge path %v not in data", p))
+		}
+		if isDelete(v) {
+			values[i] = fieldValue{path: p, del: true}
+			continue
+		}
+		e, err := encodeValue(reflect.ValueOf(v))
+		if err != nil {
+			return fs.NewFirestoreError(err, errors.Internal)
+		}
+		values[i] = fieldValue{path: p, value: e}
+	}
+	t.writes = append(t.writes, func(docs map[string]fakeDoc, now time.Time) error {
+		d := map[string]interface{}{}
+		if doc, ok := docs[ref.Path()]; ok {
+			d = copyMap(doc.data)
+		}
+		for _, v := range values {
+			if v.del {
+				deleteValueAt(d, v.path)
+			} else {
+				setValueAt(d, v.path, copyValue(v.value))
+			}
+		}
+		docs[ref.Path()] = fakeDoc{ref: ref, data: d, updateTime: now}
+		return nil
+	})
+	return nil
+}
+
+func (t *fakeTransaction) Update(ref DocRef, updates []firestore.Update) error {
+	type fieldValue struct {
+		path  []string
+		value interface{}
+		// true if the field is deleted
+		del bool
+	}
+	values := make([]fieldValue, len(updates))
+	for i, u := range updates {
+		path := []string(u.FieldPath)
+		if u.Path != "" {
+			path = splitPath(u.Path)
+		}
+		if len(path) == 0 {
+			return fs.NewFirestoreError(nil, errors.Internal).WithInternalMessage("update without path")
+		}
+		if isDelete(u.Value) {
+			values[i] = fieldValue{path: path, del: true}
+			continue
+		}
+		e, err := encodeValue(reflect.ValueOf(u.Value))
+		if err != nil {
+			return fs.NewFirestoreError(err, errors.Internal)
+		}
+		values[i] = fieldValue{path: path, value: e}
+	}
+	t.writes = append(t.writes, func(docs map[string]fakeDoc, now time.Time) error {
+		doc, ok := docs[ref.Path()]
+		if !ok {
+			return notFoundError(ref)
+		}
+		d := copyMap(doc.data)
+		for _, v := range values {
+			if v.del {
+				deleteValueAt(d, v.path)
+			} else {
+				setValueAt(d, v.path, copyValue(v.value))
+			}
+		}
+		docs[ref.Path()] = fakeDoc{ref: ref, data: d, updateTime: now}
+		return nil
+	})
+	return nil
+}
+
+func (t *fakeTransaction) Delete(ref DocRef) error {
+	t.writes = append(t.writes, deleteWrite(ref))
+	return nil
+}
+
+func createWrite(ref DocRef, data interface{}) (fakeWrite, error) {
+	d, err := encodeData(data)
+	if err != nil {
+		return nil, fs.NewFirestoreError(err, errors.Internal)
+	}
+	return func(docs map[string]fakeDoc, now time.Time) error {
+		if _, ok := docs[ref.Path()]; ok {
+			return fs.ParseFirestoreError(status.Errorf(codes.AlreadyExists, "document %v already exists", ref.Path()))
+		}
+		docs[ref.Path()] = fakeDoc{ref: ref, data: d, updateTime: now}
+		return nil
+	}, nil
+}
+
+// Values can be maps, which cannot be compared with ==.
+func isDelete(v interface{}) bool {
+	return reflect.TypeOf(v) == deleteType
+}
+
+func deleteWrite(ref DocRef) fakeWrite {
+	return func(docs map[string]fakeDoc, now time.Time) error {
+		delete(docs, ref.Path())
+		return nil
+	}
+}
+
+func notFoundError(ref DocRef) error {
+	return fs.ParseFirestoreError(status.Errorf(codes.NotFound, "document %v not found", ref.Path()))
+}
+
+func invalidArgumentError(msg string) error {
+	return fs.NewFirestoreError(nil, errors.InvalidArgument).WithInternalMessage(msg)
+}
+
+type fakeDocument struct {
+	ref        DocRef
+	exists     bool
+	data       map[string]interface{}
+	updateTime time.Time
+}
+
+func (d fakeDocument) Ref() DocRef {
+	return d.ref
+}
+
+func (d fakeDocument) Exists() bool {
+	return d.exists
+}
+
+func (d fakeDocument) UpdateTime() time.Time {
+	return d.updateTime
+}
+
+func (d fakeDocument) DataTo(v interface{}) error {
+	if !d.exists {
+		return fs.NewFirestoreErrorInternal(nil).WithInternalMessage("document does not exist")
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fs.NewFirestoreErrorInternal(nil).WithInternalMessage("DataTo requires a non-nil pointer")
+	}
+	if err := decodeValue(rv.Elem(), d.data); err != nil {
+		return fs.NewFirestoreErrorInternal(err).WithInternalMessage("could not unmarshal document snapshot")
+	}
+	return nil
+}
+
+func (d fakeDocument) DataAt(path string) (interface{}, error) {
+	if !d.exists {
+		return nil, fs.NewFirestoreErrorInternal(nil).WithInternalMessage("document does not exist")
+	}
+	v, ok := valueAt(d.data, splitPath(path))
+	if !ok {
+		return nil, fs.NewFirestoreErrorInternal(nil).WithInternalMessage(fmt.Sprintf("no field %q", path))
+	}
+	return copyValue(v), nil
+}
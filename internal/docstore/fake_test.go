@@ -0,0 +1,231 @@
+package docstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dkinzler/kit/errors"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+)
+
+type testItem struct {
+	Group   string   `firestore:"group"`
+	Score   int      `firestore:"score"`
+	Tags    []string `firestore:"tags"`
+	Note    string   `firestore:"note,omitempty"`
+	Ignored string   `firestore:"-"`
+	Nested  testNested
+}
+
+type testNested struct {
+	Value int
+}
+
+func TestFakeEncoding(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	fake := NewFake()
+
+	ref := DocRef{Collection: "items", Id: "i-1"}
+	item := testItem{Group: "g", Score: 3, Ignored: "x", Nested: testNested{Value: 7}}
+	a.Nil(fake.Create(ctx, ref, item))
+	err := fake.Create(ctx, ref, item)
+	a.NotNil(err)
+
+	doc, err := fake.Get(ctx, ref)
+	a.Nil(err)
+	a.True(doc.Exists())
+	a.False(doc.UpdateTime().IsZero())
+
+	v, err := doc.DataAt("score")
+	a.Nil(err)
+	a.Equal(int64(3), v)
+	v, err = doc.DataAt("tags")
+	a.Nil(err)
+	a.Nil(v)
+	v, err = doc.DataAt("Nested.Value")
+	a.Nil(err)
+	a.Equal(int64(7), v)
+	_, err = doc.DataAt("note")
+	a.NotNil(err)
+	_, err = doc.DataAt("Ignored")
+	a.NotNil(err)
+
+	var decoded testItem
+	a.Nil(doc.DataTo(&decoded))
+	item.Ignored = ""
+	a.Equal(item, decoded)
+
+	// field names are matched case-insensitively, unknown fields are ignored
+	var other struct {
+		GROUP   string
+		Unknown int
+	}
+	a.Nil(doc.DataTo(&other))
+	a.Equal("g", other.GROUP)
+
+	_, err = fake.Get(ctx, DocRef{Collection: "items", Id: "i-2"})
+	a.True(errors.IsNotFoundError(err))
+	docs, err := fake.GetAll(ctx, []DocRef{ref, {Collection: "items", Id: "i-2"}})
+	a.Nil(err)
+	a.Len(docs, 2)
+	a.True(docs[0].Exists())
+	a.False(docs[1].Exists())
+
+	a.Nil(fake.Delete(ctx, ref))
+	a.Nil(fake.Delete(ctx, ref))
+	_, err = fake.Get(ctx, ref)
+	a.True(errors.IsNotFoundError(err))
+}
+
+func TestFakeQuery(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	fake := NewFake()
+
+	items := map[string]testItem{
+		"i-1": {Group: "a", Score: 1, Nested: testNested{Value: 1}},
+		"i-2": {Group: "a", Score: 3, Nested: testNested{Value: 2}},
+		"i-3": {Group: "a", Score: 3, Nested: testNested{Value: 3}},
+		"i-4": {Group: "a", Score: 2, Nested: testNested{Value: 3}},
+		"i-5": {Group: "b", Score: 5, Nested: testNested{Value: 5}},
+	}
+	for id, item := range items {
+		a.Nil(fake.Create(ctx, DocRef{Collection: "items", Id: id}, item))
+	}
+	// documents of other collections and documents without the ordered field are not returned
+	a.Nil(fake.Create(ctx, DocRef{Collection: "other", Id: "o-1"}, items["i-1"]))
+	a.Nil(fake.Create(ctx, DocRef{Collection: "items", Id: "i-6"}, map[string]interface{}{"group": "a"}))
+
+	ids := func(docs []Document) []string {
+		result := make([]string, len(docs))
+		for i, doc := range docs {
+			result[i] = doc.Ref().Id
+		}
+		return result
+	}
+
+	q := NewQuery("items").Where("group", "==", "a")
+	docs, err := fake.Query(ctx, q)
+	a.Nil(err)
+	a.Equal([]string{"i-1", "i-2", "i-3", "i-4", "i-6"}, ids(docs))
+
+	q = q.OrderBy("score", firestore.Desc)
+	docs, err = fake.Query(ctx, q)
+	a.Nil(err)
+	a.Equal([]string{"i-3", "i-2", "i-4", "i-1"}, ids(docs))
+
+	docs, err = fake.Query(ctx, q.OrderBy("Nested.Value", firestore.Asc))
+	a.Nil(err)
+	a.Equal([]string{"i-2", "i-3", "i-4", "i-1"}, ids(docs))
+
+	// cursors are inclusive and can be used for a prefix of the ordered fields
+	docs, err = fake.Query(ctx, q.StartAt(2))
+	a.Nil(err)
+	a.Equal([]string{"i-4", "i-1"}, ids(docs))
+	docs, err = fake.Query(ctx, q.OrderBy("Nested.Value", firestore.Desc).StartAt(3, 2))
+	a.Nil(err)
+	a.Equal([]string{"i-2", "i-4", "i-1"}, ids(docs))
+
+	// documents can be ordered by id, cursors after a document exclude it
+	byId := q.OrderBy(firestore.DocumentID, firestore.Desc)
+	docs, err = fake.Query(ctx, byId)
+	a.Nil(err)
+	a.Equal([]string{"i-3", "i-2", "i-4", "i-1"}, ids(docs))
+	docs, err = fake.Query(ctx, byId.StartAfter(3, "i-3"))
+	a.Nil(err)
+	a.Equal([]string{"i-2", "i-4", "i-1"}, ids(docs))
+	docs, err = fake.Query(ctx, byId.StartAfter(2))
+	a.Nil(err)
+	a.Equal([]string{"i-1"}, ids(docs))
+
+	docs, err = fake.Query(ctx, q.Limit(2))
+	a.Nil(err)
+	a.Equal([]string{"i-3", "i-2"}, ids(docs))
+
+	docs, err = fake.Query(ctx, q.Select("group", "Nested.Value").Limit(1))
+	a.Nil(err)
+	a.Len(docs, 1)
+	var decoded testItem
+	a.Nil(docs[0].DataTo(&decoded))
+	a.Equal(testItem{Group: "a", Nested: testNested{Value: 3}}, decoded)
+
+	_, err = fake.Query(ctx, NewQuery("items").Where("score", ">", 1))
+	a.True(errors.IsInvalidArgumentError(err))
+	_, err = fake.Query(ctx, NewQuery("items").StartAt(1))
+	a.True(errors.IsInvalidArgumentError(err))
+}
+
+func TestFakeTransaction(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	fake := NewFake()
+
+	ref := DocRef{Collection: "items", Id: "i-1"}
+	a.Nil(fake.Create(ctx, ref, testItem{Group: "a", Score: 1}))
+	doc, err := fake.Get(ctx, ref)
+	a.Nil(err)
+	te := NewExpectations(doc)
+
+	err = fake.RunTransaction(ctx, func(ctx context.Context, t Transaction) error {
+		if err := te.Verify(t); err != nil {
+			return err
+		}
+		if err := t.Update(ref, []firestore.Update{{Path: "score", Value: 2}, {Path: "Nested.Value", Value: 4}}); err != nil {
+			return err
+		}
+		return t.MergeSet(ref, map[string]interface{}{
+			"group": firestore.Delete,
+			"tags":  []string{"x"},
+			"map":   map[string]interface{}{"k1": 1, "k2": 2},
+		}, firestore.FieldPath{"group"}, firestore.FieldPath{"tags"}, firestore.FieldPath{"map", "k1"})
+	})
+	a.Nil(err)
+
+	doc, err = fake.Get(ctx, ref)
+	a.Nil(err)
+	var data map[string]interface{}
+	a.Nil(doc.DataTo(&data))
+	a.Equal(map[string]interface{}{
+		"score":  int64(2),
+		"tags":   []interface{}{"x"},
+		"Nested": map[string]interface{}{"Value": int64(4)},
+		"map":    map[string]interface{}{"k1": int64(1)},
+	}, data)
+
+	// the document was updated since the expectations were created
+	err = fake.RunTransaction(ctx, func(ctx context.Context, t Transaction) error {
+		return te.Verify(t)
+	})
+	a.True(errors.IsFailedPreconditionError(err))
+	err = fake.RunTransaction(ctx, func(ctx context.Context, t Transaction) error {
+		return NewExpectations(doc).Verify(t)
+	})
+	a.Nil(err)
+
+	// the writes of a failed transaction are not applied
+	err = fake.RunTransaction(ctx, func(ctx context.Context, t Transaction) error {
+		if err := t.Delete(ref); err != nil {
+			return err
+		}
+		return t.Update(DocRef{Collection: "items", Id: "i-2"}, []firestore.Update{{Path: "score", Value: 1}})
+	})
+	a.True(errors.IsNotFoundError(err))
+	_, err = fake.Get(ctx, ref)
+	a.Nil(err)
+
+	// reads must happen before writes
+	err = fake.RunTransaction(ctx, func(ctx context.Context, t Transaction) error {
+		if err := t.Set(ref, testItem{}); err != nil {
+			return err
+		}
+		_, err := t.Get(ref)
+		return err
+	})
+	a.NotNil(err)
+	doc2, err := fake.Get(ctx, ref)
+	a.Nil(err)
+	a.Equal(doc.UpdateTime(), doc2.UpdateTime())
+}
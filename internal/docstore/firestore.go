@@ -0,0 +1,172 @@
+package docstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkinzler/kit/errors"
+	fs "github.com/dkinzler/kit/firebase/firestore"
+
+	"cloud.google.com/go/firestore"
+)
+
+type firestoreStore struct {
+	client *firestore.Client
+}
+
+// Returns a Store backed by the given client.
+func NewFirestore(client *firestore.Client) Store {
+	return &firestoreStore{client: client}
+}
+
+func (s *firestoreStore) doc(ref DocRef) *firestore.DocumentRef {
+	return s.client.Collection(ref.Collection).Doc(ref.Id)
+}
+
+func (s *firestoreStore) docs(refs []DocRef) []*firestore.DocumentRef {
+	result := make([]*firestore.DocumentRef, len(refs))
+	for i, ref := range refs {
+		result[i] = s.doc(ref)
+	}
+	return result
+}
+
+func (s *firestoreStore) Create(ctx context.Context, ref DocRef, data interface{}) error {
+	return fs.CreateDocument(ctx, s.client.Collection(ref.Collection), ref.Id, data)
+}
+
+func (s *firestoreStore) Get(ctx context.Context, ref DocRef) (Document, error) {
+	snap, err := fs.GetDocumentSnapshotById(ctx, s.client.Collection(ref.Collection), ref.Id)
+	if err != nil {
+		return nil, err
+	}
+	return firestoreDocument{snap: snap}, nil
+}
+
+func (s *firestoreStore) GetAll(ctx context.Context, refs []DocRef) ([]Document, error) {
+	snaps, err := s.client.GetAll(ctx, s.docs(refs))
+	if err != nil {
+		return nil, fs.ParseFirestoreError(err)
+	}
+	return documents(snaps), nil
+}
+
+func (s *firestoreStore) Delete(ctx context.Context, ref DocRef) error {
+	return fs.DeleteDocument(ctx, s.client.Collection(ref.Collection), ref.Id)
+}
+
+func (s *firestoreStore) Query(ctx context.Context, q Query) ([]Document, error) {
+	query := s.client.Collection(q.collection).Query
+	for _, f := range q.filters {
+		query = query.Where(f.path, f.op, f.value)
+	}
+	if q.selectPaths != nil {
+		query = query.Select(q.selectPaths...)
+	}
+	for _, o := range q.orders {
+		query = query.OrderBy(o.path, o.dir)
+	}
+	if len(q.startAt) > 0 {
+		if q.startAfter {
+			query = query.StartAfter(q.startAt...)
+		} else {
+			query = query.StartAt(q.startAt...)
+		}
+	}
+	if q.limit > 0 {
+		query = query.Limit(q.limit)
+	}
+
+	snaps, err := fs.GetDocumentsForQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return documents(snaps), nil
+}
+
+func (s *firestoreStore) RunTransaction(ctx context.Context, f func(ctx context.Context, t Transaction) error) error {
+	return s.client.RunTransaction(ctx, func(c context.Context, t *firestore.Transaction) error {
+		return f(c, &firestoreTransaction{store: s, t: t})
+	}, firestore.MaxAttempts(1))
+}
+
+type firestoreTransaction struct {
+	store *firestoreStore
+	t     *firestore.Transaction
+}
+
+func (t *firestoreTransaction) Get(ref DocRef) (Document, error) {
+	snap, err := t.t.Get(t.store.doc(ref))
+	if err != nil {
+		return nil, fs.ParseFirestoreError(err)
+	}
+	return firestoreDocument{snap: snap}, nil
+}
+
+func (t *firestoreTransaction) GetAll(refs []DocRef) ([]Document, error) {
+	snaps, err := t.t.GetAll(t.store.docs(refs))
+	if err != nil {
+		return nil, fs.ParseFirestoreError(err)
+	}
+	return documents(snaps), nil
+}
+
+func (t *firestoreTransaction) Set(ref DocRef, data interface{}) error {
+	return writeError(t.t.Set(t.store.doc(ref), data))
+}
+
+func (t *firestoreTransaction) MergeSet(ref DocRef, data map[string]interface{}, paths ...firestore.FieldPath) error {
+	return writeError(t.t.Set(t.store.doc(ref), data, firestore.Merge(paths...)))
+}
+
+func (t *firestoreTransaction) Update(ref DocRef, updates []firestore.Update) error {
+	return writeError(t.t.Update(t.store.doc(ref), updates))
+}
+
+func (t *firestoreTransaction) Delete(ref DocRef) error {
+	return writeError(t.t.Delete(t.store.doc(ref)))
+}
+
+// Writes in a transaction are only sent when the transaction commits, an error here means the write is invalid.
+func writeError(err error) error {
+	if err != nil {
+		return fs.NewFirestoreError(err, errors.Internal)
+	}
+	return nil
+}
+
+type firestoreDocument struct {
+	snap *firestore.DocumentSnapshot
+}
+
+func documents(snaps []*firestore.DocumentSnapshot) []Document {
+	result := make([]Document, len(snaps))
+	for i, snap := range snaps {
+		result[i] = firestoreDocument{snap: snap}
+	}
+	return result
+}
+
+func (d firestoreDocument) Ref() DocRef {
+	return DocRef{Collection: d.snap.Ref.Parent.ID, Id: d.snap.Ref.ID}
+}
+
+func (d firestoreDocument) Exists() bool {
+	return d.snap.Exists()
+}
+
+func (d firestoreDocument) UpdateTime() time.Time {
+	return d.snap.UpdateTime
+}
+
+func (d firestoreDocument) DataTo(v interface{}) error {
+	return fs.UnmarshalDocSnapshot(d.snap, v)
+}
+
+func (d firestoreDocument) DataAt(path string) (interface{}, error) {
+	v, err := d.snap.DataAt(path)
+	if err != nil {
+		return nil, fs.NewFirestoreErrorInternal(err)
+	}
+	return v, nil
+}
@@ -0,0 +1,70 @@
+// Package endpointname stores the name of the endpoint that handles a request in the context.
+// This allows middlewares that are applied to all endpoints of a component, e.g. to limit the rate of requests,
+// to behave differently for different endpoints.
+package endpointname
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type contextKey string
+
+const endpointNameContextKey contextKey = "endpointName"
+
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, endpointNameContextKey, name)
+}
+
+// Returns the name of the endpoint stored in the context, or false if there is none.
+func FromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(endpointNameContextKey).(string)
+	return name, ok
+}
+
+// Returns a middleware that stores the given name in the context.
+// It must be applied outside of the middlewares that use the name.
+func Middleware(name string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			return next(NewContext(ctx, name), request)
+		}
+	}
+}
+
+var registeredNames = struct {
+	sync.RWMutex
+	names map[string]struct{}
+}{names: make(map[string]struct{})}
+
+// Components register the names of their endpoints, such that names provided by users,
+// e.g. when configuring rate limits per endpoint, can be validated.
+func Register(names ...string) {
+	registeredNames.Lock()
+	defer registeredNames.Unlock()
+	for _, name := range names {
+		registeredNames.names[name] = struct{}{}
+	}
+}
+
+func IsRegistered(name string) bool {
+	registeredNames.RLock()
+	defer registeredNames.RUnlock()
+	_, ok := registeredNames.names[name]
+	return ok
+}
+
+// Returns the registered names in sorted order.
+func Registered() []string {
+	registeredNames.RLock()
+	defer registeredNames.RUnlock()
+	names := make([]string, 0, len(registeredNames.names))
+	for name := range registeredNames.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
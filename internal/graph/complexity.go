@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Number of elements assumed for list fields without a "limit" argument,
+// or if the limit is not valid.
+// Matches the default number of results returned by the http API.
+const defaultListSize = 20
+const maxListSize = 100
+
+// Estimates the cost of executing an operation before any resolvers are run.
+//
+// Every field costs 1, the cost of the fields selected on a list is multiplied by the
+// expected number of elements in the list.
+// E.g. the query "{ boards(limit: 10) { name links { title } } }" has a complexity of 1 + 10 * (1 + 1 + 20 * 1) = 221.
+//
+// The operation must have been validated against the schema, such that the definitions of fields and fragments are set.
+func complexity(op *ast.OperationDefinition, vars map[string]interface{}) int {
+	return selectionSetComplexity(op.SelectionSet, vars)
+}
+
+func selectionSetComplexity(set ast.SelectionSet, vars map[string]interface{}) int {
+	result := 0
+	for _, selection := range set {
+		switch s := selection.(type) {
+		case *ast.Field:
+			result = addCapped(result, fieldComplexity(s, vars))
+		case *ast.InlineFragment:
+			result = addCapped(result, selectionSetComplexity(s.SelectionSet, vars))
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				result = addCapped(result, selectionSetComplexity(s.Definition.SelectionSet, vars))
+			}
+		}
+	}
+	return result
+}
+
+func fieldComplexity(field *ast.Field, vars map[string]interface{}) int {
+	children := selectionSetComplexity(field.SelectionSet, vars)
+	if field.Definition != nil && field.Definition.Type.Elem != nil {
+		children = mulCapped(children, listSize(field, vars))
+	}
+	return addCapped(1, children)
+}
+
+func listSize(field *ast.Field, vars map[string]interface{}) int {
+	arg := field.Arguments.ForName("limit")
+	if arg == nil {
+		return defaultListSize
+	}
+	v, err := arg.Value.Value(vars)
+	if err != nil {
+		return defaultListSize
+	}
+
+	var limit int
+	switch l := v.(type) {
+	case int64:
+		limit = int(l)
+	case float64:
+		limit = int(l)
+	default:
+		return defaultListSize
+	}
+	if limit < 1 || limit > maxListSize {
+		return defaultListSize
+	}
+	return limit
+}
+
+// Deeply nested lists could overflow otherwise.
+func addCapped(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+	return a + b
+}
+
+func mulCapped(a, b int) int {
+	if a != 0 && b > math.MaxInt32/a {
+		return math.MaxInt32
+	}
+	return a * b
+}
@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestComplexity(t *testing.T) {
+	a := assert.New(t)
+
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: schema})
+
+	queryComplexity := func(query string, vars map[string]interface{}) int {
+		doc := gqlparser.MustLoadQuery(s, query)
+		return complexity(doc.Operations[0], vars)
+	}
+
+	a.Equal(3, queryComplexity(`{ board(boardId: "b-1") { name description } }`, nil))
+	a.Equal(221, queryComplexity(`{ boards(limit: 10) { name links { title } } }`, nil))
+	// limit given as variable, invalid limits use the default
+	a.Equal(1+5*2, queryComplexity(`query q($limit: Int) { boards(limit: $limit) { name boardId } }`, map[string]interface{}{"limit": float64(5)}))
+	a.Equal(1+20*2, queryComplexity(`{ boards(limit: 1000) { name boardId } }`, nil))
+	// fragments count like the fields they contain
+	a.Equal(1+20*(1+1+1+20*(1+1+1)), queryComplexity(`
+		{ invites { ...invite } }
+		fragment invite on Invite { inviteId board { ... on Board { members { role user { name } } } } }
+	`, nil))
+}
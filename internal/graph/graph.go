@@ -0,0 +1,251 @@
+// Package graph exposes the boards and links application services using GraphQL.
+//
+// The schema (see schema.go) lets clients fetch a board together with its members, invites and links using a single request.
+// Resolvers only call the application services, all authorization happens there, exactly like for the http and gRPC transports.
+// Mutations are executed using the endpoints of the components, such that their middlewares (e.g. rate limits) apply the same way as for http requests.
+//
+// To protect the data stores from expensive queries, the depth and estimated complexity of queries are limited.
+// Boards referenced by multiple objects in a query (e.g. the boards of a user's invites) are loaded in batches.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+
+	boards "github.com/dkinzler/linkboards/internal/boards/application"
+	boardstransport "github.com/dkinzler/linkboards/internal/boards/transport"
+	"github.com/dkinzler/linkboards/internal/endpointname"
+	links "github.com/dkinzler/linkboards/internal/links/application"
+	linkstransport "github.com/dkinzler/linkboards/internal/links/transport"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/log"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name of the endpoint, e.g. to configure its rate limit.
+const endpointName = "graphql"
+
+func init() {
+	endpointname.Register(endpointName)
+}
+
+const defaultMaxDepth = 8
+const defaultMaxComplexity = 5000
+
+type Config struct {
+	BoardApplicationService boards.BoardApplicationService
+	LinkApplicationService  links.LinkApplicationService
+	// Endpoints of the boards and links components used to execute mutations.
+	// Since the endpoints authenticate requests again, the context of a GraphQL request must contain the same values as for the endpoints' own transport,
+	// e.g. the authentication token.
+	BoardEndpoints *boardstransport.EndpointSet
+	LinkEndpoints  *linkstransport.EndpointSet
+
+	// Middlewares that should be applied to the endpoint.
+	// They run after the authentication middleware and can get the name of the endpoint ("graphql") using package endpointname,
+	// e.g. to limit the rate of requests, see ratelimit.NewEndpointMiddleware.
+	Middlewares []endpoint.Middleware
+	// Authentication middleware for the endpoint.
+	// Should be the same as the one used by the components, since the application services expect the user in the context.
+	AuthMiddleware endpoint.Middleware
+	// If not nil, used instead of AuthMiddleware, such that boards that are visible to non-members and their links
+	// can be queried without authentication, see middleware.NewOptionalAuthEndpointMiddleware.
+	// Fields that require authentication then fail with status 401 for unauthenticated requests.
+	OptionalAuthMiddleware endpoint.Middleware
+	// If not nil, metrics are recorded for the endpoint.
+	Metrics *metrics.Metrics
+	// If not nil, spans are created for the endpoint, see package tracing.
+	TracerProvider trace.TracerProvider
+
+	// Max depth of queries, defaults to 8.
+	MaxDepth int
+	// Max estimated complexity of queries, defaults to 5000.
+	// Every field counts as 1, fields selected on lists are multiplied by the number of elements requested (see complexity.go).
+	MaxComplexity int
+
+	// Used to log errors of resolvers that are not caused by the request, e.g. if a data store is unavailable.
+	Logger *log.Logger
+}
+
+// Body of a GraphQL request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type executor struct {
+	schema        *graphql.Schema
+	astSchema     *ast.Schema
+	resolver      *resolver
+	maxComplexity int
+	logger        *log.Logger
+}
+
+func newExecutor(config Config) (*executor, error) {
+	if config.BoardApplicationService == nil || config.LinkApplicationService == nil {
+		return nil, errors.New(nil, "graph", errors.InvalidArgument).WithInternalMessage("application services must not be nil")
+	}
+	if config.BoardEndpoints == nil || config.LinkEndpoints == nil {
+		return nil, errors.New(nil, "graph", errors.InvalidArgument).WithInternalMessage("endpoints must not be nil")
+	}
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = defaultMaxDepth
+	}
+	if config.MaxComplexity <= 0 {
+		config.MaxComplexity = defaultMaxComplexity
+	}
+
+	r := &resolver{
+		boards:         config.BoardApplicationService,
+		links:          config.LinkApplicationService,
+		boardEndpoints: *config.BoardEndpoints,
+		linkEndpoints:  *config.LinkEndpoints,
+	}
+	s, err := graphql.ParseSchema(schema, r, graphql.MaxDepth(config.MaxDepth))
+	if err != nil {
+		return nil, errors.New(err, "graph", errors.Internal).WithInternalMessage("invalid schema")
+	}
+	// The schema is parsed a second time to compute the complexity of queries,
+	// since graphql-go does not expose the queries it parses.
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schema})
+	if err != nil {
+		return nil, errors.New(err, "graph", errors.Internal).WithInternalMessage("invalid schema")
+	}
+
+	return &executor{
+		schema:        s,
+		astSchema:     astSchema,
+		resolver:      r,
+		maxComplexity: config.MaxComplexity,
+		logger:        config.Logger,
+	}, nil
+}
+
+func (ex *executor) exec(ctx context.Context, req Request) *graphql.Response {
+	doc, errs := gqlparser.LoadQuery(ex.astSchema, req.Query)
+	if len(errs) > 0 {
+		result := make([]*gqlerrors.QueryError, len(errs))
+		for i, err := range errs {
+			qe := &gqlerrors.QueryError{Message: err.Message}
+			for _, l := range err.Locations {
+				qe.Locations = append(qe.Locations, gqlerrors.Location{Line: l.Line, Column: l.Column})
+			}
+			result[i] = qe
+		}
+		return &graphql.Response{Errors: result}
+	}
+
+	// If the operation does not exist, graphql-go will return an error.
+	if op := doc.Operations.ForName(req.OperationName); op != nil {
+		if c := complexity(op, req.Variables); c > ex.maxComplexity {
+			return &graphql.Response{Errors: []*gqlerrors.QueryError{
+				gqlerrors.Errorf("query has complexity %v, the maximum allowed complexity is %v", c, ex.maxComplexity),
+			}}
+		}
+	}
+
+	resp := ex.schema.Exec(ex.resolver.withLoaders(ctx), req.Query, req.OperationName, req.Variables)
+	for _, qe := range resp.Errors {
+		ex.toPublicError(qe)
+	}
+	return resp
+}
+
+// Like the error responses of the HTTP API, only the public code and message of an error returned by a resolver are sent to the client.
+// The http status code that would have been returned by the HTTP API is added as the "status" extension, the public code as the "code" extension.
+// For exceeded rate limits and quotas, the "retryAfter" extension contains the number of seconds after which the mutation can be retried.
+func (ex *executor) toPublicError(qe *gqlerrors.QueryError) {
+	if qe.ResolverError == nil {
+		return
+	}
+	err := qe.ResolverError
+	status := t.ErrToCode(err)
+	retryAfter, limitExceeded := ratelimit.RetryAfter(err)
+	if limitExceeded {
+		status = http.StatusTooManyRequests
+	}
+	if status == http.StatusInternalServerError && ex.logger != nil {
+		ex.logger.Log("msg", "graphql resolver error", "path", fmt.Sprint(qe.Path), "error", err)
+	}
+
+	qe.Message = http.StatusText(status)
+	qe.Extensions = map[string]interface{}{"status": status}
+	if limitExceeded {
+		// in whole seconds, like the Retry-After header
+		qe.Extensions["retryAfter"] = int(math.Ceil(retryAfter.Seconds()))
+	}
+	if e, ok := err.(errors.Error); ok {
+		if e.PublicMessage != "" {
+			qe.Message = e.PublicMessage
+		}
+		if e.PublicCode != 0 {
+			qe.Extensions["code"] = e.PublicCode
+		}
+	}
+}
+
+// Returns an endpoint that executes GraphQL requests.
+// Errors of resolvers are contained in the returned graphql.Response, the endpoint itself only fails
+// if one of its middlewares does, e.g. if the request is not authenticated.
+func makeEndpoint(ex *executor) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+		return e.Response{
+			R: ex.exec(ctx, req),
+		}, nil
+	}
+}
+
+// Returns a http handler that serves GraphQL requests sent as POST requests with a JSON body.
+func NewHttpHandler(config Config, opts []kithttp.ServerOption) (http.Handler, error) {
+	ex, err := newExecutor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var mws []endpoint.Middleware
+	mws = append(mws, config.Middlewares...)
+	if config.OptionalAuthMiddleware != nil {
+		mws = append(mws, config.OptionalAuthMiddleware)
+	} else if config.AuthMiddleware != nil {
+		mws = append(mws, config.AuthMiddleware)
+	}
+	if config.Metrics != nil {
+		mws = append(mws, config.Metrics.EndpointMiddleware("graph", endpointName))
+	}
+	if config.TracerProvider != nil {
+		mws = append(mws, tracing.EndpointMiddleware(config.TracerProvider, "graph", endpointName))
+	}
+	// outermost middleware, makes the endpoint name available to all other middlewares
+	mws = append(mws, endpointname.Middleware(endpointName))
+	ep := e.ApplyMiddlewares(makeEndpoint(ex), mws...)
+
+	return kithttp.NewServer(ep, decodeHttpRequest, t.MakeGenericJSONEncodeFunc(200), opts...), nil
+}
+
+func decodeHttpRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req Request
+	if err := t.DecodeJSONBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Query == "" {
+		return nil, errors.New(nil, "graph", errors.InvalidArgument).WithPublicMessage("query must not be empty")
+	}
+	return req, nil
+}
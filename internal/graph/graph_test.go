@@ -0,0 +1,358 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/auth/store"
+	"github.com/dkinzler/linkboards/internal/boards"
+	boardsapp "github.com/dkinzler/linkboards/internal/boards/application"
+	"github.com/dkinzler/linkboards/internal/links"
+	linksapp "github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// Counts the calls to BoardsById, to check that boards are loaded in batches.
+type countingBoardService struct {
+	boardsapp.BoardApplicationService
+	m     sync.Mutex
+	calls int
+}
+
+func (s *countingBoardService) BoardsById(ctx context.Context, boardIds []string) (map[string]boardsapp.Board, error) {
+	s.m.Lock()
+	s.calls++
+	s.m.Unlock()
+	return s.BoardApplicationService.BoardsById(ctx, boardIds)
+}
+
+type testEnv struct {
+	server *httptest.Server
+	boards *countingBoardService
+	links  linksapp.LinkApplicationService
+}
+
+// The given middlewares are added to the endpoints of the components.
+func newTestEnv(t *testing.T, config Config, mws ...endpoint.Middleware) *testEnv {
+	boardComponent, err := boards.NewComponent(boards.Config{UseInmemDataStore: true, Middlewares: mws})
+	if err != nil {
+		t.Fatal(err)
+	}
+	linksComponent, err := links.NewComponent(links.Config{
+		UseInmemDataStore:  true,
+		AuthorizationStore: store.NewDefaultAuthorizationStore(boardComponent.DataStore),
+		Middlewares:        mws,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs := &countingBoardService{BoardApplicationService: boardComponent.ApplicationService}
+	config.BoardApplicationService = bs
+	config.LinkApplicationService = linksComponent.ApplicationService
+	config.BoardEndpoints = &boardComponent.Endpoints
+	config.LinkEndpoints = &linksComponent.Endpoints
+	config.AuthMiddleware = middleware.NewFakeAuthEndpointMiddleware()
+	handler, err := NewHttpHandler(config, []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext),
+		kithttp.ServerErrorEncoder(func(ctx context.Context, err error, w http.ResponseWriter) {
+			ratelimit.EncodeError(ctx, err, w)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &testEnv{server: srv, boards: bs, links: linksComponent.ApplicationService}
+}
+
+type testResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (env *testEnv) query(t *testing.T, userId string, query string, vars map[string]interface{}) (int, testResponse) {
+	body, err := json.Marshal(Request{Query: query, Variables: vars})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, env.server.URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userId != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userId+":pw")))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result testResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+func userContext(userId string) context.Context {
+	return auth.ContextWithUser(context.Background(), auth.User{UserId: userId, Name: userId})
+}
+
+func boardsUser(userId string) boardsapp.User {
+	return boardsapp.User{UserId: userId, Name: userId}
+}
+
+func TestQueries(t *testing.T) {
+	a := assert.New(t)
+
+	env := newTestEnv(t, Config{})
+
+	// u-1 creates two boards with a link each and invites u-2 to both of them
+	var boardIds []string
+	for _, name := range []string{"Board 1", "Board 2"} {
+		b, err := env.boards.CreateBoard(userContext("u-1"), boardsapp.NewBoard{Name: name})
+		a.Nil(err)
+		boardIds = append(boardIds, b.BoardId)
+		_, err = env.boards.CreateInvite(userContext("u-1"), b.BoardId, boardsapp.NewInvite{Role: auth.BoardRoleViewer, User: boardsUser("u-2")})
+		a.Nil(err)
+		_, err = env.links.CreateLink(userContext("u-1"), b.BoardId, linksapp.NewLink{Title: "Link of " + name, Url: "https://example.com"})
+		a.Nil(err)
+	}
+	_, err := env.boards.SetCustomRole(userContext("u-1"), boardIds[0], "curator", boardsapp.CustomRoleScopes{Scopes: []auth.Scope{"boards:view", "links:delete"}})
+	a.Nil(err)
+
+	status, resp := env.query(t, "u-1", `query q($boardId: ID!) {
+		board(boardId: $boardId) {
+			name
+			members { role user { userId } }
+			invites { role user { userId } }
+			customRoles { name scopes }
+			links(sort: TOP) { title score userRating createdBy { userId } }
+		}
+	}`, map[string]interface{}{"boardId": boardIds[0]})
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	board := resp.Data["board"].(map[string]interface{})
+	a.Equal("Board 1", board["name"])
+	a.Equal([]interface{}{map[string]interface{}{"role": "owner", "user": map[string]interface{}{"userId": "u-1"}}}, board["members"])
+	a.Equal([]interface{}{map[string]interface{}{"role": "viewer", "user": map[string]interface{}{"userId": "u-2"}}}, board["invites"])
+	a.Equal([]interface{}{map[string]interface{}{"name": "curator", "scopes": []interface{}{"boards:view", "links:delete"}}}, board["customRoles"])
+	a.Equal([]interface{}{map[string]interface{}{
+		"title": "Link of Board 1", "score": float64(0), "userRating": float64(0), "createdBy": map[string]interface{}{"userId": "u-1"},
+	}}, board["links"])
+
+	// lists of boards can be queried with their members
+	status, resp = env.query(t, "u-1", `{ boards { name members { role } } }`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	a.Len(resp.Data["boards"], 2)
+
+	// u-2 is not a member yet, but can see the boards of the invites, which are loaded in a single batch
+	status, resp = env.query(t, "u-2", `{
+		board1: board(boardId: "`+boardIds[0]+`") { name }
+		invites { role board { name } }
+	}`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	a.Nil(resp.Data["board1"])
+	invites := resp.Data["invites"].([]interface{})
+	a.Len(invites, 2)
+	var names []interface{}
+	for _, invite := range invites {
+		names = append(names, invite.(map[string]interface{})["board"].(map[string]interface{})["name"])
+	}
+	a.ElementsMatch([]interface{}{"Board 1", "Board 2"}, names)
+	a.Equal(1, env.boards.calls)
+
+	// boards the user is not allowed to view resolve to null
+	status, resp = env.query(t, "u-2", `query q($boardId: ID!) { boards { name } board(boardId: $boardId) { links { title } } }`, map[string]interface{}{"boardId": boardIds[0]})
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	a.Nil(resp.Data["board"])
+
+	status, resp = env.query(t, "u-2", `mutation { createLink(boardId: "`+boardIds[0]+`", link: {title: "abc", url: "https://example.com"}) { linkId } }`, nil)
+	// errors of the application services only contain public information
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Equal("Forbidden", resp.Errors[0].Message)
+	a.Equal(float64(http.StatusForbidden), resp.Errors[0].Extensions["status"])
+
+	// unauthenticated requests are rejected
+	status, _ = env.query(t, "", `{ boards { name } }`, nil)
+	a.Equal(http.StatusUnauthorized, status)
+}
+
+func TestPublicBoardQueries(t *testing.T) {
+	a := assert.New(t)
+
+	env := newTestEnv(t, Config{
+		OptionalAuthMiddleware: middleware.NewOptionalAuthEndpointMiddleware(middleware.NewFakeAuthEndpointMiddleware()),
+	})
+
+	b, err := env.boards.CreateBoard(userContext("u-1"), boardsapp.NewBoard{Name: "Board"})
+	a.Nil(err)
+	_, err = env.links.CreateLink(userContext("u-1"), b.BoardId, linksapp.NewLink{Title: "Link", Url: "https://example.com"})
+	a.Nil(err)
+
+	query := `query q($boardId: ID!) {
+		board(boardId: $boardId) { name visibility members { role } links { title } }
+		publicBoards { name }
+	}`
+	vars := map[string]interface{}{"boardId": b.BoardId}
+
+	// private boards can't be viewed without authentication
+	status, resp := env.query(t, "", query, vars)
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Equal(float64(http.StatusUnauthorized), resp.Errors[0].Extensions["status"])
+	a.Empty(resp.Data["publicBoards"])
+
+	visibility := "public"
+	_, err = env.boards.EditBoard(userContext("u-1"), b.BoardId, boardsapp.BoardEdit{Visibility: &visibility})
+	a.Nil(err)
+
+	status, resp = env.query(t, "", query, vars)
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	a.Equal(map[string]interface{}{
+		"name":       "Board",
+		"visibility": "PUBLIC",
+		"members":    []interface{}{},
+		"links":      []interface{}{map[string]interface{}{"title": "Link"}},
+	}, resp.Data["board"])
+	a.Equal([]interface{}{map[string]interface{}{"name": "Board"}}, resp.Data["publicBoards"])
+
+	// fields that require authentication fail, mutations are still authenticated by the endpoints
+	status, resp = env.query(t, "", `{ boards { name } }`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Equal(float64(http.StatusUnauthorized), resp.Errors[0].Extensions["status"])
+	status, resp = env.query(t, "", `mutation m($boardId: ID!) {
+		createLink(boardId: $boardId, link: {title: "abc", url: "https://example.com"}) { linkId }
+	}`, vars)
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Equal(float64(http.StatusUnauthorized), resp.Errors[0].Extensions["status"])
+}
+
+func TestMutations(t *testing.T) {
+	a := assert.New(t)
+
+	env := newTestEnv(t, Config{})
+
+	b, err := env.boards.CreateBoard(userContext("u-1"), boardsapp.NewBoard{Name: "Board"})
+	a.Nil(err)
+	invite, err := env.boards.CreateInvite(userContext("u-1"), b.BoardId, boardsapp.NewInvite{Role: auth.BoardRoleEditor})
+	a.Nil(err)
+
+	status, resp := env.query(t, "u-2", `mutation m($boardId: ID!, $inviteId: ID!) {
+		respondToInvite(boardId: $boardId, inviteId: $inviteId, response: ACCEPT)
+	}`, map[string]interface{}{"boardId": b.BoardId, "inviteId": invite.InviteId})
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	a.Equal(true, resp.Data["respondToInvite"])
+
+	status, resp = env.query(t, "u-2", `mutation m($boardId: ID!) {
+		createLink(boardId: $boardId, link: {title: "Example", url: "https://example.com", tags: ["a"]}) { linkId tags }
+	}`, map[string]interface{}{"boardId": b.BoardId})
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	link := resp.Data["createLink"].(map[string]interface{})
+	a.Equal([]interface{}{"a"}, link["tags"])
+
+	status, resp = env.query(t, "u-1", `mutation m($boardId: ID!, $linkId: ID!) {
+		rateLink(boardId: $boardId, linkId: $linkId, rating: 1) { score upvotes userRating }
+	}`, map[string]interface{}{"boardId": b.BoardId, "linkId": link["linkId"]})
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+	a.Equal(map[string]interface{}{"score": float64(1), "upvotes": float64(1), "userRating": float64(1)}, resp.Data["rateLink"])
+
+	// invalid ratings are rejected with the public error code of the application service
+	status, resp = env.query(t, "u-1", `mutation m($boardId: ID!, $linkId: ID!) {
+		rateLink(boardId: $boardId, linkId: $linkId, rating: 5) { score }
+	}`, map[string]interface{}{"boardId": b.BoardId, "linkId": link["linkId"]})
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Equal(float64(http.StatusBadRequest), resp.Errors[0].Extensions["status"])
+	a.NotNil(resp.Errors[0].Extensions["code"])
+}
+
+func TestMutationRateLimits(t *testing.T) {
+	a := assert.New(t)
+
+	// mutations use the endpoints of the components and are therefore limited like http requests
+	env := newTestEnv(t, Config{}, ratelimit.NewEndpointMiddleware(ratelimit.NewInmemStore(), ratelimit.Limits{
+		"createLink": ratelimit.PerMinute(1),
+	}))
+
+	b, err := env.boards.CreateBoard(userContext("u-1"), boardsapp.NewBoard{Name: "Board"})
+	a.Nil(err)
+
+	createLink := `mutation m($boardId: ID!) {
+		createLink(boardId: $boardId, link: {title: "Example", url: "https://example.com"}) { linkId }
+	}`
+	status, resp := env.query(t, "u-1", createLink, map[string]interface{}{"boardId": b.BoardId})
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+
+	status, resp = env.query(t, "u-1", createLink, map[string]interface{}{"boardId": b.BoardId})
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Equal(float64(http.StatusTooManyRequests), resp.Errors[0].Extensions["status"])
+	a.Equal(float64(60), resp.Errors[0].Extensions["retryAfter"])
+
+	// the GraphQL endpoint itself can be limited as well
+	env = newTestEnv(t, Config{Middlewares: []endpoint.Middleware{
+		ratelimit.NewEndpointMiddleware(ratelimit.NewInmemStore(), ratelimit.Limits{"graphql": ratelimit.PerMinute(1)}),
+	}})
+	status, _ = env.query(t, "u-1", `{ boards(limit: 1) { name } }`, nil)
+	a.Equal(http.StatusOK, status)
+	status, _ = env.query(t, "u-1", `{ boards(limit: 1) { name } }`, nil)
+	a.Equal(http.StatusTooManyRequests, status)
+}
+
+func TestQueryLimits(t *testing.T) {
+	a := assert.New(t)
+
+	env := newTestEnv(t, Config{MaxDepth: 3, MaxComplexity: 100})
+
+	status, resp := env.query(t, "u-1", `{ boards(limit: 2) { invites { board { name } } } }`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Nil(resp.Data)
+
+	status, resp = env.query(t, "u-1", `{ boards(limit: 10) { links(limit: 10) { title } } }`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+	a.Contains(resp.Errors[0].Message, "complexity")
+
+	status, resp = env.query(t, "u-1", `{ boards(limit: 5) { links(limit: 10) { title } } }`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Empty(resp.Errors)
+
+	// invalid queries are rejected before any resolvers run
+	status, resp = env.query(t, "u-1", `{ boards { doesNotExist } }`, nil)
+	a.Equal(http.StatusOK, status)
+	a.Len(resp.Errors, 1)
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	stdtime "time"
+)
+
+// Resolvers of a query are run concurrently, a loader collects the keys they request
+// during a short time window and loads them with a single call to the fetch function.
+// Results are cached, i.e. a key is loaded at most once.
+//
+// A loader should only be used for a single request, since the cached results
+// depend on the user making the request.
+type loader[K comparable, V any] struct {
+	// Returns the values for the given keys, keys without a value are left out.
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     stdtime.Duration
+	maxBatch int
+
+	m       sync.Mutex
+	results map[K]*loaderResult[V]
+	batch   *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+	timer   *stdtime.Timer
+}
+
+const defaultLoaderWait = 2 * stdtime.Millisecond
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error), maxBatch int) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:    fetch,
+		wait:     defaultLoaderWait,
+		maxBatch: maxBatch,
+		results:  make(map[K]*loaderResult[V]),
+	}
+}
+
+// Returns the value for the given key, the returned bool is false if there is none.
+// Blocks until the batch containing the key was loaded.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.m.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &loaderResult[V]{done: make(chan struct{})}
+		l.results[key] = r
+		l.addToBatch(ctx, key, r)
+	}
+	l.m.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.found, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// Caller must hold the lock.
+func (l *loader[K, V]) addToBatch(ctx context.Context, key K, r *loaderResult[V]) {
+	if l.batch == nil {
+		b := &loaderBatch[K, V]{}
+		b.timer = stdtime.AfterFunc(l.wait, func() {
+			l.m.Lock()
+			if l.batch != b {
+				// batch was already started because it was full
+				l.m.Unlock()
+				return
+			}
+			l.batch = nil
+			l.m.Unlock()
+			l.run(ctx, b)
+		})
+		l.batch = b
+	}
+
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		b.timer.Stop()
+		l.batch = nil
+		go l.run(ctx, b)
+	}
+}
+
+func (l *loader[K, V]) run(ctx context.Context, b *loaderBatch[K, V]) {
+	values, err := l.fetch(ctx, b.keys)
+	for i, key := range b.keys {
+		r := b.results[i]
+		if err != nil {
+			r.err = err
+		} else {
+			r.value, r.found = values[key]
+		}
+		close(r.done)
+	}
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fetchRecorder struct {
+	m       sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (f *fetchRecorder) fetch(ctx context.Context, keys []string) (map[string]int, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.batches = append(f.batches, keys)
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make(map[string]int)
+	for _, key := range keys {
+		if key != "missing" {
+			result[key] = len(key)
+		}
+	}
+	return result, nil
+}
+
+// Loads the keys concurrently, like the resolvers of a query would.
+func loadConcurrently(l *loader[string, int], keys []string) ([]int, []bool, []error) {
+	values := make([]int, len(keys))
+	found := make([]bool, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			values[i], found[i], errs[i] = l.Load(context.Background(), key)
+		}(i, key)
+	}
+	wg.Wait()
+	return values, found, errs
+}
+
+func TestLoaderBatchesAndCachesKeys(t *testing.T) {
+	a := assert.New(t)
+
+	f := &fetchRecorder{}
+	l := newLoader(f.fetch, 100)
+
+	values, found, errs := loadConcurrently(l, []string{"a", "bb", "ccc", "missing", "bb"})
+	a.Equal([]int{1, 2, 3, 0, 2}, values)
+	a.Equal([]bool{true, true, true, false, true}, found)
+	for _, err := range errs {
+		a.Nil(err)
+	}
+	a.Len(f.batches, 1)
+	a.ElementsMatch([]string{"a", "bb", "ccc", "missing"}, f.batches[0])
+
+	// cached keys are not loaded again
+	values, _, _ = loadConcurrently(l, []string{"a", "dddd"})
+	a.Equal([]int{1, 4}, values)
+	a.Len(f.batches, 2)
+	a.Equal([]string{"dddd"}, f.batches[1])
+}
+
+func TestLoaderLimitsBatchSize(t *testing.T) {
+	a := assert.New(t)
+
+	f := &fetchRecorder{}
+	l := newLoader(f.fetch, 2)
+
+	_, found, _ := loadConcurrently(l, []string{"a", "b", "c", "d", "e"})
+	a.Equal([]bool{true, true, true, true, true}, found)
+	a.Len(f.batches, 3)
+	for _, b := range f.batches {
+		a.LessOrEqual(len(b), 2)
+	}
+}
+
+func TestLoaderReturnsFetchError(t *testing.T) {
+	a := assert.New(t)
+
+	f := &fetchRecorder{err: errors.New(nil, "test", errors.Internal)}
+	l := newLoader(f.fetch, 100)
+
+	_, found, errs := loadConcurrently(l, []string{"a", "b"})
+	a.Equal([]bool{false, false}, found)
+	for _, err := range errs {
+		a.True(errors.IsInternalError(err))
+	}
+}
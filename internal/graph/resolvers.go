@@ -0,0 +1,596 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	boards "github.com/dkinzler/linkboards/internal/boards/application"
+	boardstransport "github.com/dkinzler/linkboards/internal/boards/transport"
+	links "github.com/dkinzler/linkboards/internal/links/application"
+	linkstransport "github.com/dkinzler/linkboards/internal/links/transport"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// The resolvers don't perform any authorization themselves, they only call the application services
+// which check the scopes of the user making the request.
+// Mutations use the endpoints of the components instead, see callEndpoint.
+type resolver struct {
+	boards         boards.BoardApplicationService
+	links          links.LinkApplicationService
+	boardEndpoints boardstransport.EndpointSet
+	linkEndpoints  linkstransport.EndpointSet
+}
+
+// Calls an endpoint of a component and returns the result of the application service,
+// errors of the application service and of the middlewares of the endpoint are returned the same way.
+func callEndpoint(ctx context.Context, ep endpoint.Endpoint, request interface{}) (interface{}, error) {
+	response, err := ep(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := response.(e.Responder); ok {
+		return r.Response(), r.Error()
+	}
+	return response, nil
+}
+
+// Unix time in nanoseconds, implements the Timestamp scalar of the schema.
+type Timestamp int64
+
+func (Timestamp) ImplementsGraphQLType(name string) bool {
+	return name == "Timestamp"
+}
+
+func (t *Timestamp) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return errors.New(err, "graph", errors.InvalidArgument).WithPublicMessage("invalid timestamp")
+		}
+		*t = Timestamp(i)
+	case int32:
+		*t = Timestamp(v)
+	case float64:
+		*t = Timestamp(v)
+	default:
+		return errors.New(nil, "graph", errors.InvalidArgument).WithPublicMessage("invalid timestamp")
+	}
+	return nil
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, strconv.FormatInt(int64(t), 10)), nil
+}
+
+// Returns nil for the zero value, to resolve optional timestamps to null.
+func optionalTimestamp(t int64) *Timestamp {
+	if t == 0 {
+		return nil
+	}
+	ts := Timestamp(t)
+	return &ts
+}
+
+// Loaders are created for every request and stored in its context.
+type loaders struct {
+	// Batches lookups of boards, e.g. for the invites of a user.
+	boards *loader[string, boards.Board]
+	// Boards with their users and invites can only be loaded one at a time,
+	// the loader runs these lookups concurrently and makes sure every board is loaded at most once.
+	boardDetails *loader[string, boards.BoardWithUsersAndInvites]
+}
+
+type loadersContextKey struct{}
+
+// Max number of keys loaded at once.
+const maxBatchSize = 100
+
+func (r *resolver) withLoaders(ctx context.Context) context.Context {
+	l := &loaders{
+		boards: newLoader(r.boards.BoardsById, maxBatchSize),
+		boardDetails: newLoader(func(ctx context.Context, boardIds []string) (map[string]boards.BoardWithUsersAndInvites, error) {
+			var m sync.Mutex
+			var wg sync.WaitGroup
+			var firstErr error
+			result := make(map[string]boards.BoardWithUsersAndInvites, len(boardIds))
+			for _, boardId := range boardIds {
+				wg.Add(1)
+				go func(boardId string) {
+					defer wg.Done()
+					b, err := r.boards.Board(ctx, boardId)
+					m.Lock()
+					defer m.Unlock()
+					if err != nil {
+						if firstErr == nil {
+							firstErr = err
+						}
+						return
+					}
+					result[boardId] = b
+				}(boardId)
+			}
+			wg.Wait()
+			if firstErr != nil {
+				return nil, firstErr
+			}
+			return result, nil
+		}, maxBatchSize),
+	}
+	return context.WithValue(ctx, loadersContextKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersContextKey{}).(*loaders)
+}
+
+// Returns true if the error means that a requested object does not exist or the user is not allowed to view it.
+// Query fields then resolve to null.
+func isNotAvailableError(err error) bool {
+	return errors.IsNotFoundError(err) || errors.IsPermissionDeniedError(err)
+}
+
+func (r *resolver) Board(ctx context.Context, args struct{ BoardId graphql.ID }) (*boardResolver, error) {
+	b, err := r.boards.Board(ctx, string(args.BoardId))
+	if err != nil {
+		if isNotAvailableError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return newBoardDetailsResolver(r, b), nil
+}
+
+type pageArgs struct {
+	Limit  *int32
+	Cursor *Timestamp
+}
+
+func (a pageArgs) queryParams() boards.QueryParams {
+	var qp boards.QueryParams
+	if a.Limit != nil {
+		qp.Limit = int(*a.Limit)
+	}
+	if a.Cursor != nil {
+		qp.Cursor = int64(*a.Cursor)
+	}
+	return qp
+}
+
+func (r *resolver) Boards(ctx context.Context, args pageArgs) ([]*boardResolver, error) {
+	bs, err := r.boards.Boards(ctx, args.queryParams())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*boardResolver, len(bs))
+	for i, b := range bs {
+		result[i] = &boardResolver{r: r, board: b}
+	}
+	return result, nil
+}
+
+func (r *resolver) PublicBoards(ctx context.Context, args pageArgs) ([]*boardResolver, error) {
+	bs, err := r.boards.PublicBoards(ctx, args.queryParams())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*boardResolver, len(bs))
+	for i, b := range bs {
+		result[i] = &boardResolver{r: r, board: b}
+	}
+	return result, nil
+}
+
+func (r *resolver) Invites(ctx context.Context, args pageArgs) ([]*inviteResolver, error) {
+	invites, err := r.boards.Invites(ctx, args.queryParams())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*inviteResolver, len(invites))
+	for i, invite := range invites {
+		result[i] = &inviteResolver{r: r, boardId: invite.BoardId, invite: invite}
+	}
+	return result, nil
+}
+
+func (r *resolver) Link(ctx context.Context, args struct{ BoardId, LinkId graphql.ID }) (*linkResolver, error) {
+	l, err := r.links.Link(ctx, string(args.BoardId), string(args.LinkId))
+	if err != nil {
+		if isNotAvailableError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &linkResolver{link: l}, nil
+}
+
+type newLinkInput struct {
+	Title string
+	Url   string
+	Tags  *[]string
+}
+
+func (r *resolver) CreateLink(ctx context.Context, args struct {
+	BoardId graphql.ID
+	Link    newLinkInput
+}) (*linkResolver, error) {
+	nl := links.NewLink{
+		Title: args.Link.Title,
+		Url:   args.Link.Url,
+	}
+	if args.Link.Tags != nil {
+		nl.Tags = *args.Link.Tags
+	}
+	l, err := callEndpoint(ctx, r.linkEndpoints.CreateLinkEndpoint, linkstransport.CreateLinkRequest{
+		BoardId: string(args.BoardId),
+		Nl:      nl,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &linkResolver{link: l.(links.Link)}, nil
+}
+
+func (r *resolver) DeleteLink(ctx context.Context, args struct{ BoardId, LinkId graphql.ID }) (bool, error) {
+	_, err := callEndpoint(ctx, r.linkEndpoints.DeleteLinkEndpoint, linkstransport.DeleteLinkRequest{
+		BoardId: string(args.BoardId),
+		LinkId:  string(args.LinkId),
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Returns the link with the updated score.
+func (r *resolver) RateLink(ctx context.Context, args struct {
+	BoardId graphql.ID
+	LinkId  graphql.ID
+	Rating  int32
+}) (*linkResolver, error) {
+	boardId, linkId := string(args.BoardId), string(args.LinkId)
+	_, err := callEndpoint(ctx, r.linkEndpoints.RateLinkEndpoint, linkstransport.RateLinkRequest{
+		BoardId: boardId,
+		LinkId:  linkId,
+		Lr:      links.LinkRating{Rating: int(args.Rating)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.links.Link(ctx, boardId, linkId)
+	if err != nil {
+		return nil, err
+	}
+	return &linkResolver{link: l}, nil
+}
+
+func (r *resolver) RespondToInvite(ctx context.Context, args struct {
+	BoardId  graphql.ID
+	InviteId graphql.ID
+	Response string
+}) (bool, error) {
+	_, err := callEndpoint(ctx, r.boardEndpoints.RespondToInviteEndpoint, boardstransport.RespondToInviteRequest{
+		BoardId:  string(args.BoardId),
+		InviteId: string(args.InviteId),
+		Ir: boards.InviteResponse{
+			// enum values are the uppercase versions of the values used by the application service
+			Response: strings.ToLower(args.Response),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type userResolver struct {
+	userId string
+	name   string
+}
+
+// Returns nil if userId is empty, to resolve optional users to null.
+func newUserResolver(userId string, name string) *userResolver {
+	if userId == "" {
+		return nil
+	}
+	return &userResolver{userId: userId, name: name}
+}
+
+func (u *userResolver) UserId() graphql.ID {
+	return graphql.ID(u.userId)
+}
+
+func (u *userResolver) Name() string {
+	return u.name
+}
+
+type boardResolver struct {
+	r     *resolver
+	board boards.Board
+	// Set if the board was loaded together with its users and invites.
+	details *boards.BoardWithUsersAndInvites
+}
+
+func newBoardDetailsResolver(r *resolver, b boards.BoardWithUsersAndInvites) *boardResolver {
+	return &boardResolver{
+		r: r,
+		board: boards.Board{
+			BoardId:      b.BoardId,
+			Name:         b.Name,
+			Description:  b.Description,
+			CreatedTime:  b.CreatedTime,
+			CreatedBy:    b.CreatedBy,
+			ModifiedTime: b.ModifiedTime,
+			ModifiedBy:   b.ModifiedBy,
+			Visibility:   b.Visibility,
+		},
+		details: &b,
+	}
+}
+
+func (b *boardResolver) loadDetails(ctx context.Context) (boards.BoardWithUsersAndInvites, error) {
+	if b.details != nil {
+		return *b.details, nil
+	}
+	d, ok, err := loadersFromContext(ctx).boardDetails.Load(ctx, b.board.BoardId)
+	if err != nil {
+		return boards.BoardWithUsersAndInvites{}, err
+	}
+	if !ok {
+		return boards.BoardWithUsersAndInvites{}, errors.New(nil, "graph", errors.NotFound)
+	}
+	return d, nil
+}
+
+func (b *boardResolver) BoardId() graphql.ID {
+	return graphql.ID(b.board.BoardId)
+}
+
+func (b *boardResolver) Name() string {
+	return b.board.Name
+}
+
+func (b *boardResolver) Description() string {
+	return b.board.Description
+}
+
+func (b *boardResolver) CreatedTime() Timestamp {
+	return Timestamp(b.board.CreatedTime)
+}
+
+func (b *boardResolver) CreatedBy() *userResolver {
+	return &userResolver{userId: b.board.CreatedBy.UserId, name: b.board.CreatedBy.Name}
+}
+
+// Enum values are the uppercase versions of the values used by the application service.
+func (b *boardResolver) Visibility() string {
+	return strings.ToUpper(b.board.Visibility)
+}
+
+// Lists of boards don't contain modification data, therefore it is only resolved for single boards.
+func (b *boardResolver) ModifiedTime() *Timestamp {
+	return optionalTimestamp(b.board.ModifiedTime)
+}
+
+func (b *boardResolver) ModifiedBy() *userResolver {
+	return newUserResolver(b.board.ModifiedBy.UserId, b.board.ModifiedBy.Name)
+}
+
+func (b *boardResolver) Members(ctx context.Context) ([]*memberResolver, error) {
+	d, err := b.loadDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*memberResolver, len(d.Users))
+	for i, u := range d.Users {
+		result[i] = &memberResolver{user: u}
+	}
+	return result, nil
+}
+
+func (b *boardResolver) Invites(ctx context.Context) ([]*inviteResolver, error) {
+	d, err := b.loadDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*inviteResolver, len(d.Invites))
+	for i, invite := range d.Invites {
+		result[i] = &inviteResolver{r: b.r, boardId: b.board.BoardId, invite: invite}
+	}
+	return result, nil
+}
+
+func (b *boardResolver) CustomRoles(ctx context.Context) ([]*customRoleResolver, error) {
+	d, err := b.loadDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*customRoleResolver, len(d.CustomRoles))
+	for i, role := range d.CustomRoles {
+		result[i] = &customRoleResolver{role: role}
+	}
+	return result, nil
+}
+
+type linksArgs struct {
+	Limit             *int32
+	Sort              *string
+	CursorScore       *int32
+	CursorCreatedTime *Timestamp
+}
+
+func (b *boardResolver) Links(ctx context.Context, args linksArgs) ([]*linkResolver, error) {
+	var qp links.LinkQueryParams
+	if args.Limit != nil {
+		qp.Limit = int(*args.Limit)
+	}
+	if args.Sort != nil {
+		qp.Sort = strings.ToLower(*args.Sort)
+	}
+	if args.CursorScore != nil {
+		score := int(*args.CursorScore)
+		qp.CursorScore = &score
+	}
+	if args.CursorCreatedTime != nil {
+		createdTime := int64(*args.CursorCreatedTime)
+		qp.CursorCreatedTime = &createdTime
+	}
+
+	ls, err := b.r.links.Links(ctx, b.board.BoardId, qp)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*linkResolver, len(ls))
+	for i, l := range ls {
+		result[i] = &linkResolver{link: l}
+	}
+	return result, nil
+}
+
+type memberResolver struct {
+	user boards.BoardUser
+}
+
+func (m *memberResolver) User() *userResolver {
+	return &userResolver{userId: m.user.User.UserId, name: m.user.User.Name}
+}
+
+func (m *memberResolver) Role() string {
+	return m.user.Role
+}
+
+func (m *memberResolver) CreatedTime() Timestamp {
+	return Timestamp(m.user.CreatedTime)
+}
+
+func (m *memberResolver) InvitedBy() *userResolver {
+	return newUserResolver(m.user.InvitedBy.UserId, m.user.InvitedBy.Name)
+}
+
+func (m *memberResolver) ModifiedTime() *Timestamp {
+	return optionalTimestamp(m.user.ModifiedTime)
+}
+
+func (m *memberResolver) ModifiedBy() *userResolver {
+	return newUserResolver(m.user.ModifiedBy.UserId, m.user.ModifiedBy.Name)
+}
+
+type customRoleResolver struct {
+	role boards.CustomRole
+}
+
+func (c *customRoleResolver) Name() string {
+	return c.role.Name
+}
+
+func (c *customRoleResolver) Scopes() []string {
+	result := make([]string, len(c.role.Scopes))
+	for i, scope := range c.role.Scopes {
+		result[i] = string(scope)
+	}
+	return result
+}
+
+type inviteResolver struct {
+	r *resolver
+	// The invites of a board don't contain the board id.
+	boardId string
+	invite  boards.Invite
+}
+
+func (i *inviteResolver) InviteId() graphql.ID {
+	return graphql.ID(i.invite.InviteId)
+}
+
+func (i *inviteResolver) BoardId() graphql.ID {
+	return graphql.ID(i.boardId)
+}
+
+func (i *inviteResolver) Board(ctx context.Context) (*boardResolver, error) {
+	b, ok, err := loadersFromContext(ctx).boards.Load(ctx, i.boardId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &boardResolver{r: i.r, board: b}, nil
+}
+
+func (i *inviteResolver) Role() string {
+	return i.invite.Role
+}
+
+func (i *inviteResolver) User() *userResolver {
+	return newUserResolver(i.invite.User.UserId, i.invite.User.Name)
+}
+
+func (i *inviteResolver) CreatedTime() Timestamp {
+	return Timestamp(i.invite.CreatedTime)
+}
+
+func (i *inviteResolver) CreatedBy() *userResolver {
+	return &userResolver{userId: i.invite.CreatedBy.UserId, name: i.invite.CreatedBy.Name}
+}
+
+func (i *inviteResolver) ExpiresTime() Timestamp {
+	return Timestamp(i.invite.ExpiresTime)
+}
+
+type linkResolver struct {
+	link links.Link
+}
+
+func (l *linkResolver) LinkId() graphql.ID {
+	return graphql.ID(l.link.LinkId)
+}
+
+func (l *linkResolver) BoardId() graphql.ID {
+	return graphql.ID(l.link.BoardId)
+}
+
+func (l *linkResolver) Title() string {
+	return l.link.Title
+}
+
+func (l *linkResolver) Url() string {
+	return l.link.Url
+}
+
+func (l *linkResolver) Tags() []string {
+	if l.link.Tags == nil {
+		return []string{}
+	}
+	return l.link.Tags
+}
+
+func (l *linkResolver) CreatedTime() Timestamp {
+	return Timestamp(l.link.CreatedTime)
+}
+
+func (l *linkResolver) CreatedBy() *userResolver {
+	return &userResolver{userId: l.link.CreatedBy.UserId, name: l.link.CreatedBy.Name}
+}
+
+func (l *linkResolver) Score() int32 {
+	return int32(l.link.Score)
+}
+
+func (l *linkResolver) Upvotes() int32 {
+	return int32(l.link.Upvotes)
+}
+
+func (l *linkResolver) Downvotes() int32 {
+	return int32(l.link.Downvotes)
+}
+
+func (l *linkResolver) UserRating() int32 {
+	return int32(l.link.UserRating)
+}
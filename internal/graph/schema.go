@@ -0,0 +1,127 @@
+package graph
+
+// GraphQL schema of the API.
+//
+// Fields that are not available to the user making the request, e.g. the members of a board for users
+// without the necessary scopes, resolve to empty values like they do in the http API.
+const schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+# Unix time in nanoseconds.
+# Encoded as a string, since the values don't fit into the 32-bit Int type of GraphQL.
+scalar Timestamp
+
+type Query {
+	# Returns null if the board does not exist or the user is not allowed to view it.
+	# Boards that are visible to non-members (see Board.visibility) and their links can be queried without authentication.
+	board(boardId: ID!): Board
+	# Boards the user making the request is a member of, sorted from newest to oldest.
+	boards(limit: Int, cursor: Timestamp): [Board!]!
+	# Boards that are visible to everyone, sorted from newest to oldest.
+	# Can be queried without authentication.
+	publicBoards(limit: Int, cursor: Timestamp): [Board!]!
+	# Invites for the user making the request, sorted from newest to oldest.
+	invites(limit: Int, cursor: Timestamp): [Invite!]!
+	link(boardId: ID!, linkId: ID!): Link
+}
+
+type Mutation {
+	createLink(boardId: ID!, link: NewLink!): Link!
+	deleteLink(boardId: ID!, linkId: ID!): Boolean!
+	# Rating must be 1 for an upvote or -1 for a downvote.
+	rateLink(boardId: ID!, linkId: ID!, rating: Int!): Link!
+	respondToInvite(boardId: ID!, inviteId: ID!, response: InviteResponse!): Boolean!
+}
+
+type User {
+	userId: ID!
+	name: String!
+}
+
+type Board {
+	boardId: ID!
+	name: String!
+	description: String!
+	createdTime: Timestamp!
+	createdBy: User!
+	visibility: BoardVisibility!
+	# Only set for users that are allowed to edit the board.
+	modifiedTime: Timestamp
+	modifiedBy: User
+	members: [Member!]!
+	invites: [Invite!]!
+	# Roles defined by the owner of the board in addition to the built-in roles, available to the same users as the members.
+	customRoles: [CustomRole!]!
+	links(limit: Int, sort: LinkSort, cursorScore: Int, cursorCreatedTime: Timestamp): [Link!]!
+}
+
+type Member {
+	user: User!
+	role: String!
+	createdTime: Timestamp!
+	invitedBy: User
+	modifiedTime: Timestamp
+	modifiedBy: User
+}
+
+type CustomRole {
+	name: String!
+	# e.g. "links:delete"
+	scopes: [String!]!
+}
+
+type Invite {
+	inviteId: ID!
+	boardId: ID!
+	# Null if the user making the request is not allowed to view the board.
+	board: Board
+	role: String!
+	# Null if any user can accept the invite.
+	user: User
+	createdTime: Timestamp!
+	createdBy: User!
+	expiresTime: Timestamp!
+}
+
+type Link {
+	linkId: ID!
+	boardId: ID!
+	title: String!
+	url: String!
+	tags: [String!]!
+	createdTime: Timestamp!
+	createdBy: User!
+	score: Int!
+	upvotes: Int!
+	downvotes: Int!
+	# Rating of the user making the request, 1, -1 or 0 if the user has not rated the link.
+	userRating: Int!
+}
+
+input NewLink {
+	title: String!
+	url: String!
+	tags: [String!]
+}
+
+enum LinkSort {
+	NEWEST
+	TOP
+}
+
+# PRIVATE boards can only be viewed by members, LINK boards by everyone who knows the board id
+# and PUBLIC boards are additionally listed by publicBoards.
+enum BoardVisibility {
+	PRIVATE
+	LINK
+	PUBLIC
+}
+
+enum InviteResponse {
+	ACCEPT
+	DECLINE
+}
+`
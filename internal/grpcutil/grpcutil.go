@@ -0,0 +1,129 @@
+// Package grpcutil contains helpers to expose go-kit endpoints using gRPC.
+package grpcutil
+
+import (
+	"strconv"
+
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain of the ErrorInfo detail added to status errors.
+const ErrorDomain = "linkboards"
+
+// Key of the ErrorInfo metadata that contains the public error code of an error, see api/openapi.yaml for the possible values.
+const ErrorCodeKey = "code"
+
+// Returns the result of an endpoint, or the error if the result is a Responder that contains an error.
+// Can be used in the EncodeResponseFuncs of gRPC servers, since unlike the http servers,
+// the gRPC servers of go-kit don't have an error encoder.
+func UnwrapResponse(response interface{}) (interface{}, error) {
+	if r, ok := response.(e.Responder); ok {
+		if err := r.Error(); err != nil {
+			return nil, err
+		}
+		return r.Response(), nil
+	}
+	return response, nil
+}
+
+// Converts an error to a gRPC status error.
+// Like the error responses of the HTTP API, only the public message and code of an error are included.
+// The public code is added as an ErrorInfo detail with ErrorCodeKey in its metadata.
+// Errors caused by exceeded rate limits or quotas are converted to ResourceExhausted errors with a RetryInfo detail.
+func ErrorToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	e, ok := err.(errors.Error)
+	if !ok {
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	code := toGRPCCode(e.Code)
+	retryAfter, limitExceeded := ratelimit.RetryAfter(e)
+	if limitExceeded {
+		code = codes.ResourceExhausted
+	}
+	message := e.PublicMessage
+	if message == "" {
+		message = code.String()
+	}
+	s := status.New(code, message)
+	if e.PublicCode != 0 {
+		withDetails, err := s.WithDetails(&errdetails.ErrorInfo{
+			Reason:   "ERROR_CODE",
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{ErrorCodeKey: strconv.Itoa(e.PublicCode)},
+		})
+		if err == nil {
+			s = withDetails
+		}
+	}
+	if limitExceeded {
+		withDetails, err := s.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+		if err == nil {
+			s = withDetails
+		}
+	}
+	return s.Err()
+}
+
+// Returns the public error code contained in a status error returned by ErrorToStatus, or 0 if there is none.
+func PublicCode(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		return 0
+	}
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+			code, _ := strconv.Atoi(info.Metadata[ErrorCodeKey])
+			return code
+		}
+	}
+	return 0
+}
+
+func toGRPCCode(code errors.ErrorCode) codes.Code {
+	switch code {
+	case errors.Cancelled:
+		return codes.Canceled
+	case errors.InvalidArgument:
+		return codes.InvalidArgument
+	case errors.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case errors.NotFound:
+		return codes.NotFound
+	case errors.AlreadyExists:
+		return codes.AlreadyExists
+	case errors.PermissionDenied:
+		return codes.PermissionDenied
+	case errors.Unauthenticated:
+		return codes.Unauthenticated
+	case errors.FailedPrecondition:
+		return codes.FailedPrecondition
+	case errors.Aborted:
+		return codes.Aborted
+	case errors.OutOfRange:
+		return codes.OutOfRange
+	case errors.Unimplemented:
+		return codes.Unimplemented
+	case errors.Unavailable:
+		return codes.Unavailable
+	case errors.Internal:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,84 @@
+package grpcutil
+
+import (
+	"testing"
+	"time"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorToStatus(t *testing.T) {
+	a := assert.New(t)
+
+	a.Nil(ErrorToStatus(nil))
+
+	err := ErrorToStatus(errors.New(nil, "test", errors.InvalidArgument).WithPublicCode(3).WithPublicMessage("invalid url").WithInternalMessage("secret"))
+	s, ok := status.FromError(err)
+	a.True(ok)
+	a.Equal(codes.InvalidArgument, s.Code())
+	a.Equal("invalid url", s.Message())
+	a.Equal(3, PublicCode(err))
+
+	err = ErrorToStatus(errors.New(nil, "test", errors.PermissionDenied))
+	s, _ = status.FromError(err)
+	a.Equal(codes.PermissionDenied, s.Code())
+	a.Equal(0, PublicCode(err))
+
+	err = ErrorToStatus(errors.New(nil, "test", errors.Unauthenticated))
+	s, _ = status.FromError(err)
+	a.Equal(codes.Unauthenticated, s.Code())
+
+	// internal details of other errors are not exposed
+	s, _ = status.FromError(ErrorToStatus(assert.AnError))
+	a.Equal(codes.Internal, s.Code())
+	a.Equal("internal error", s.Message())
+
+	// exceeded rate limits and quotas
+	quotaErr := errors.New(quotaExceededError{}, "test", errors.FailedPrecondition).WithPublicMessage("daily link quota reached")
+	s, _ = status.FromError(ErrorToStatus(quotaErr))
+	a.Equal(codes.ResourceExhausted, s.Code())
+	a.Equal("daily link quota reached", s.Message())
+	hasRetryInfo := false
+	for _, d := range s.Details() {
+		if _, ok := d.(*errdetails.RetryInfo); ok {
+			hasRetryInfo = true
+		}
+	}
+	a.True(hasRetryInfo)
+
+	// status errors are returned unchanged
+	statusErr := status.Error(codes.NotFound, "not found")
+	a.Equal(statusErr, ErrorToStatus(statusErr))
+}
+
+func TestUnwrapResponse(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := UnwrapResponse(e.Response{R: "abc"})
+	a.Nil(err)
+	a.Equal("abc", r)
+
+	_, err = UnwrapResponse(e.Response{Err: assert.AnError})
+	a.Equal(assert.AnError, err)
+
+	r, err = UnwrapResponse("abc")
+	a.Nil(err)
+	a.Equal("abc", r)
+}
+
+// Like the errors returned by the domain services for exceeded quotas, see ratelimit.RetryAfter.
+type quotaExceededError struct{}
+
+func (e quotaExceededError) Error() string {
+	return "quota exceeded"
+}
+
+func (e quotaExceededError) RetryAfter() time.Duration {
+	return time.Hour
+}
@@ -0,0 +1,148 @@
+// Package health provides HTTP handlers that can be used by load balancers and orchestrators like Cloud Run or Kubernetes
+// to check whether the application is running and ready to handle requests.
+//
+//   - /healthz returns status 200 as long as the application is running (liveness probe).
+//   - /readyz runs all configured checks, e.g. whether the data stores can be reached, and returns status 200 only if all of them succeed (readiness probe).
+//   - /version returns the version and commit of the application and the backends it uses.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dkinzler/kit/log"
+
+	"github.com/gorilla/mux"
+)
+
+// A Check returns an error if a dependency of the application, e.g. a data store, is not available.
+type Check func(ctx context.Context) error
+
+// Information returned by the /version endpoint.
+type VersionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	// Maps kinds of backends to the implementation used, e.g. "dataStore" to "firestore" or "auth" to "jwt".
+	Backends map[string]string `json:"backends"`
+}
+
+type Config struct {
+	// Checks run by the /readyz endpoint, identified by name.
+	Checks map[string]Check
+	// Maximum duration of a single check, a check that takes longer fails.
+	// Defaults to 2 seconds.
+	CheckTimeout time.Duration
+	Version      VersionInfo
+	// Optional, used to log failed checks.
+	Logger *log.Logger
+}
+
+const defaultCheckTimeout = 2 * time.Second
+
+// Statuses returned by the endpoints.
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// Response of the /healthz and /readyz endpoints.
+type statusResponse struct {
+	Status string `json:"status"`
+	// Maps the name of every check to its result, only set for /readyz.
+	// To not expose any internal details, the errors of failed checks are only logged.
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func RegisterHttpHandlers(router *mux.Router, config Config) {
+	router.Methods(http.MethodGet).Path("/healthz").HandlerFunc(healthz)
+	router.Methods(http.MethodGet).Path("/readyz").Handler(NewReadyHandler(config))
+	router.Methods(http.MethodGet).Path("/version").Handler(NewVersionHandler(config.Version))
+}
+
+func healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, statusResponse{Status: statusOK})
+}
+
+// Returns a handler that runs all checks concurrently and responds with status 200 if all of them succeed,
+// and status 503 otherwise.
+func NewReadyHandler(config Config) http.Handler {
+	timeout := config.CheckTimeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := runChecks(r.Context(), config.Checks, timeout)
+
+		response := statusResponse{Status: statusOK, Checks: make(map[string]string, len(results))}
+		for name, err := range results {
+			if err != nil {
+				response.Status = statusUnavailable
+				response.Checks[name] = statusUnavailable
+				if config.Logger != nil {
+					config.Logger.Warn().Log("message", "readiness check failed", "check", name, "error", err)
+				}
+			} else {
+				response.Checks[name] = statusOK
+			}
+		}
+
+		code := http.StatusOK
+		if response.Status != statusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, response)
+	})
+}
+
+// Runs the checks concurrently, each with the given timeout, and returns their results by name.
+func runChecks(ctx context.Context, checks map[string]Check, timeout time.Duration) map[string]error {
+	var m sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]error, len(checks))
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			// A check might not respect the deadline of the context, in that case we stop waiting for it.
+			done := make(chan error, 1)
+			go func() {
+				done <- check(ctx)
+			}()
+			var err error
+			select {
+			case err = <-done:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			m.Lock()
+			results[name] = err
+			m.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func NewVersionHandler(info VersionInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, info)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	// probes should always see the current state
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
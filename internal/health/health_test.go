@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func get(router *mux.Router, path string) (int, map[string]interface{}) {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&body)
+	return w.Code, body
+}
+
+func TestHandlers(t *testing.T) {
+	a := assert.New(t)
+
+	var boardsErr error
+	router := mux.NewRouter()
+	RegisterHttpHandlers(router, Config{
+		Checks: map[string]Check{
+			"boards": func(ctx context.Context) error { return boardsErr },
+			"links":  func(ctx context.Context) error { return nil },
+		},
+		Version: VersionInfo{
+			Version:  "1.0.0",
+			Commit:   "abc123",
+			Backends: map[string]string{"dataStore": "inmem"},
+		},
+	})
+
+	code, body := get(router, "/healthz")
+	a.Equal(200, code)
+	a.Equal("ok", body["status"])
+
+	code, body = get(router, "/readyz")
+	a.Equal(200, code)
+	a.Equal(map[string]interface{}{"status": "ok", "checks": map[string]interface{}{"boards": "ok", "links": "ok"}}, body)
+
+	boardsErr = assert.AnError
+	code, body = get(router, "/readyz")
+	a.Equal(503, code)
+	a.Equal(map[string]interface{}{"status": "unavailable", "checks": map[string]interface{}{"boards": "unavailable", "links": "ok"}}, body)
+
+	code, body = get(router, "/version")
+	a.Equal(200, code)
+	a.Equal(map[string]interface{}{"version": "1.0.0", "commit": "abc123", "backends": map[string]interface{}{"dataStore": "inmem"}}, body)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	a := assert.New(t)
+
+	block := make(chan struct{})
+	defer close(block)
+
+	results := runChecks(context.Background(), map[string]Check{
+		// respects the deadline of the context
+		"slow": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		// ignores the deadline
+		"stuck": func(ctx context.Context) error {
+			<-block
+			return nil
+		},
+		"fast": func(ctx context.Context) error { return nil },
+	}, 10*time.Millisecond)
+
+	a.Len(results, 3)
+	a.Equal(context.DeadlineExceeded, results["slow"])
+	a.Equal(context.DeadlineExceeded, results["stuck"])
+	a.Nil(results["fast"])
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dkinzler/linkboards/internal/auth"
 	"github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/paging"
 
 	"github.com/dkinzler/kit/errors"
 )
@@ -30,17 +31,39 @@ type LinkApplicationService interface {
 	//	"httpParams": ["url", "url"],
 	//	"endpoints": [{"http": {"path":"/boards/{boardId}/links/{linkId}", "method":"GET"}}]
 	// }
+	// Link and Links can be used without authentication for boards that are visible to non-members.
 	Link(ctx context.Context, boardId string, linkId string) (Link, error)
 	// @Kit{
 	//	"httpParams": ["url", "query"],
 	//	"endpoints": [{"http": {"path":"/boards/{boardId}/links", "method":"GET"}}]
 	// }
 	Links(ctx context.Context, boardId string, qp LinkQueryParams) ([]Link, error)
+
+	// Create many links at once, e.g. from an imported bookmark file.
+	// Every link is validated separately, links that are invalid will be reported in the result,
+	// the other links will still be created.
+	ImportLinks(ctx context.Context, boardId string, nls []NewLink) (LinkImportResult, error)
+	// Returns the newest links of a board, up to maxExportLinks.
+	// If the board has more links, the result is marked as truncated.
+	ExportLinks(ctx context.Context, boardId string) (LinkExport, error)
+
+	// Returns nil if the user making the request is allowed to query the links of the board and is a member of it.
+	// Can be used by transports that push links to clients, e.g. a stream of link events.
+	// Visitors of boards that are visible to non-members are not allowed, since streams also contain events
+	// that only members can see, e.g. changes to the users of a board and the ratings of individual users.
+	AuthorizeLinkQueries(ctx context.Context, boardId string) error
+}
+
+// See the User type of the boards application service.
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
 }
 
 type NewLink struct {
-	Title string `json:"title"`
-	Url   string `json:"url"`
+	Title string   `json:"title"`
+	Url   string   `json:"url"`
+	Tags  []string `json:"tags"`
 }
 
 type Link struct {
@@ -50,8 +73,10 @@ type Link struct {
 	Title string `json:"title"`
 	Url   string `json:"url"`
 
-	CreatedTime int64       `json:"createdTime"`
-	CreatedBy   domain.User `json:"createdBy"`
+	CreatedTime int64 `json:"createdTime"`
+	CreatedBy   User  `json:"createdBy"`
+
+	Tags []string `json:"tags"`
 
 	Score     int `json:"score"`
 	Upvotes   int `json:"upvotes"`
@@ -68,7 +93,8 @@ func linkFromDomainLink(l domain.LinkWithRating) Link {
 		Title:       l.Link.Title,
 		Url:         l.Link.Url,
 		CreatedTime: l.Link.CreatedTime,
-		CreatedBy:   l.Link.CreatedBy,
+		CreatedBy:   User(l.Link.CreatedBy),
+		Tags:        l.Link.Tags,
 		Score:       l.Rating.Score,
 		Upvotes:     l.Rating.Upvotes,
 		Downvotes:   l.Rating.Downvotes,
@@ -127,9 +153,11 @@ type linkApplicationService struct {
 	authChecker   *auth.BoardAuthorizationChecker
 }
 
-func NewLinkApplicationService(linkDataStore domain.LinkDataStore, authorizationStore auth.AuthorizationStore) LinkApplicationService {
+// The EventPublisher can be nil, in which case events are not published.
+// Zero values of limits are replaced by the values of domain.DefaultLimits.
+func NewLinkApplicationService(linkDataStore domain.LinkDataStore, authorizationStore auth.AuthorizationStore, eventPublisher domain.EventPublisher, limits domain.LimitsConfig, quotas domain.QuotaStore) LinkApplicationService {
 	return &linkApplicationService{
-		linkService:   domain.NewLinkService(linkDataStore),
+		linkService:   domain.NewLinkService(linkDataStore, eventPublisher, limits, quotas),
 		linkDataStore: linkDataStore,
 		authChecker:   NewAuthorizationChecker(authorizationStore),
 	}
@@ -162,19 +190,25 @@ func (svc *linkApplicationService) CreateLink(ctx context.Context, boardId strin
 		return Link{}, newPermissionDeniedError()
 	}
 
-	link, err := svc.linkService.CreateLink(ctx, boardId, nl.Title, nl.Url, toDomainUser(user))
+	link, err := svc.linkService.CreateLink(ctx, boardId, nl.Title, nl.Url, toDomainUser(user), nl.Tags...)
+	return linkFromNewDomainLink(link), err
+}
+
+// A newly created link does not have any ratings yet.
+func linkFromNewDomainLink(link domain.Link) Link {
 	return Link{
 		BoardId:     link.BoardId,
 		LinkId:      link.LinkId,
 		Title:       link.Title,
 		Url:         link.Url,
 		CreatedTime: link.CreatedTime,
-		CreatedBy:   link.CreatedBy,
+		CreatedBy:   User(link.CreatedBy),
+		Tags:        link.Tags,
 		Score:       0,
 		Downvotes:   0,
 		Upvotes:     0,
 		UserRating:  0,
-	}, err
+	}
 }
 
 func (svc *linkApplicationService) DeleteLink(ctx context.Context, boardId string, linkId string) error {
@@ -207,12 +241,7 @@ func (svc *linkApplicationService) DeleteLink(ctx context.Context, boardId strin
 		}
 	}
 
-	err = svc.linkDataStore.DeleteLink(ctx, boardId, linkId)
-	if err != nil {
-		return newServiceError(nil, errors.Internal).WithInternalMessage("could not delete link")
-	}
-
-	return nil
+	return svc.linkService.DeleteLink(ctx, boardId, linkId, toDomainUser(user))
 }
 
 func (svc *linkApplicationService) RateLink(ctx context.Context, boardId string, linkId string, lr LinkRating) error {
@@ -235,13 +264,14 @@ func (svc *linkApplicationService) RateLink(ctx context.Context, boardId string,
 }
 
 func (svc *linkApplicationService) Link(ctx context.Context, boardId string, linkId string) (Link, error) {
+	// Unauthenticated users can query the links of boards that are visible to non-members.
 	user, ok := auth.UserFromContext(ctx)
-	if !ok {
-		return Link{}, newUnauthenticatedError()
-	}
 
 	az, err := svc.authChecker.GetAuthorization(ctx, boardId, user.UserId)
 	if err != nil {
+		if !ok && errors.IsPermissionDeniedError(err) {
+			return Link{}, newUnauthenticatedError()
+		}
 		return Link{}, err
 	}
 
@@ -264,13 +294,14 @@ func (svc *linkApplicationService) Link(ctx context.Context, boardId string, lin
 }
 
 func (svc *linkApplicationService) Links(ctx context.Context, boardId string, qp LinkQueryParams) ([]Link, error) {
+	// Unauthenticated users can query the links of boards that are visible to non-members.
 	user, ok := auth.UserFromContext(ctx)
-	if !ok {
-		return nil, newUnauthenticatedError()
-	}
 
 	az, err := svc.authChecker.GetAuthorization(ctx, boardId, user.UserId)
 	if err != nil {
+		if !ok && errors.IsPermissionDeniedError(err) {
+			return nil, newUnauthenticatedError()
+		}
 		return nil, err
 	}
 
@@ -293,3 +324,141 @@ func (svc *linkApplicationService) Links(ctx context.Context, boardId string, qp
 
 	return result, nil
 }
+
+type LinkImportResult struct {
+	Imported []Link              `json:"imported"`
+	Failed   []LinkImportFailure `json:"failed"`
+}
+
+// Describes why a link could not be imported.
+type LinkImportFailure struct {
+	// Position of the link in the import request.
+	Index int    `json:"index"`
+	Title string `json:"title"`
+	Url   string `json:"url"`
+	// Public error code and message of the error that occurred.
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Maximum number of links that can be imported at once.
+const MaxImportLinks = 200
+
+func (svc *linkApplicationService) ImportLinks(ctx context.Context, boardId string, nls []NewLink) (LinkImportResult, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return LinkImportResult{}, newUnauthenticatedError()
+	}
+
+	az, err := svc.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return LinkImportResult{}, err
+	}
+
+	if !az.HasScope(createLinkScope) {
+		return LinkImportResult{}, newPermissionDeniedError()
+	}
+
+	if len(nls) > MaxImportLinks {
+		return LinkImportResult{}, newServiceError(nil, errors.InvalidArgument).WithPublicMessage("too many links")
+	}
+
+	result := LinkImportResult{
+		Imported: []Link{},
+		Failed:   []LinkImportFailure{},
+	}
+	for i, nl := range nls {
+		link, err := svc.linkService.CreateLink(ctx, boardId, nl.Title, nl.Url, toDomainUser(user), nl.Tags...)
+		if err != nil {
+			// Stop if the error was not caused by the link itself or an exhausted quota, e.g. if the data store is not available,
+			// it is very likely that the other links will fail too.
+			// Links that exceed the daily quota are reported like invalid links, so that the links imported before are returned.
+			if !errors.IsInvalidArgumentError(err) && !domain.IsQuotaExceededError(err) {
+				return LinkImportResult{}, err
+			}
+			failure := LinkImportFailure{
+				Index: i,
+				Title: nl.Title,
+				Url:   nl.Url,
+			}
+			if e, ok := err.(errors.Error); ok {
+				failure.Code = int(e.PublicCode)
+				failure.Message = e.PublicMessage
+			}
+			result.Failed = append(result.Failed, failure)
+			continue
+		}
+		result.Imported = append(result.Imported, linkFromNewDomainLink(link))
+	}
+
+	return result, nil
+}
+
+const maxExportLinks = 1000
+const exportPageSize = 50
+
+type LinkExport struct {
+	// Newest links of the board, sorted by created time in descending order.
+	Links []Link `json:"links"`
+	// True if the board has more than maxExportLinks links and older links were left out.
+	Truncated bool `json:"truncated"`
+}
+
+func (svc *linkApplicationService) ExportLinks(ctx context.Context, boardId string) (LinkExport, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return LinkExport{}, newUnauthenticatedError()
+	}
+
+	az, err := svc.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return LinkExport{}, err
+	}
+
+	if !az.HasScope(queryLinksScope) {
+		return LinkExport{}, newPermissionDeniedError()
+	}
+
+	result := LinkExport{Links: []Link{}}
+	query := domain.AllLinksQuery(svc.linkDataStore, boardId, domain.LinkReturnFields{}, exportPageSize)
+	err = paging.Each(ctx, exportPageSize, query, domain.LinkWithRatingId, func(link domain.LinkWithRating) bool {
+		// the link after the last one that fits into the export only tells us that the result is truncated
+		if len(result.Links) == maxExportLinks {
+			result.Truncated = true
+			return false
+		}
+		result.Links = append(result.Links, linkFromDomainLink(link))
+		return true
+	})
+	if err != nil {
+		return LinkExport{}, newServiceError(err, errors.Internal)
+	}
+
+	return result, nil
+}
+
+func (svc *linkApplicationService) AuthorizeLinkQueries(ctx context.Context, boardId string) error {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return newUnauthenticatedError()
+	}
+
+	az, err := svc.authChecker.GetAuthorization(ctx, boardId, user.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !az.HasScope(queryLinksScope) {
+		return newPermissionDeniedError()
+	}
+
+	member, err := svc.authChecker.IsMember(ctx, boardId, user.UserId)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return newPermissionDeniedError()
+	}
+
+	return nil
+}
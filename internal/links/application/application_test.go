@@ -2,12 +2,14 @@ package application
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	stdtime "time"
 
 	"github.com/dkinzler/linkboards/internal/auth"
 	"github.com/dkinzler/linkboards/internal/links/datastore/inmem"
 	"github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/ratelimit"
 
 	"github.com/dkinzler/kit/errors"
 	"github.com/dkinzler/kit/time"
@@ -57,7 +59,7 @@ func TestUnauthenticatedUsersDenied(t *testing.T) {
 
 	// Uauthenticated users are denied
 	ctx := context.Background()
-	service := NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{})
+	service := NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
 
 	_, err := service.CreateLink(ctx, "b-123", NewLink{})
 	a.NotNil(err)
@@ -78,6 +80,14 @@ func TestUnauthenticatedUsersDenied(t *testing.T) {
 	_, err = service.Links(ctx, "b-123", LinkQueryParams{})
 	a.NotNil(err)
 	a.True(errors.IsUnauthenticatedError(err))
+
+	_, err = service.ImportLinks(ctx, "b-123", []NewLink{})
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
+
+	_, err = service.ExportLinks(ctx, "b-123")
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
 }
 
 func TestAuthorization(t *testing.T) {
@@ -101,7 +111,7 @@ func TestAuthorization(t *testing.T) {
 		}
 
 		service := &linkApplicationService{
-			linkService:   domain.NewLinkService(ds),
+			linkService:   domain.NewLinkService(ds, nil, domain.LimitsConfig{}, nil),
 			linkDataStore: ds,
 			authChecker:   auth.NewAuthorizationChecker(rts, &testAuthorizationStore{}),
 		}
@@ -140,6 +150,102 @@ func TestAuthorization(t *testing.T) {
 	_, err = newService(queryLinksScope).Links(ctx, "b-123", LinkQueryParams{})
 	a.NotNil(err)
 	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = newService(createLinkScope).ImportLinks(ctx, "b-123", []NewLink{})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = newService(queryLinksScope).ExportLinks(ctx, "b-123")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+}
+
+func TestRestrictedAuthorization(t *testing.T) {
+	a := assert.New(t)
+
+	service := NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+
+	// testUser2 is an editor, but the request is restricted to some scopes of a single board
+	ctx := auth.ContextWithUser(context.Background(), auth.User{
+		UserId: testUser2.UserId,
+		Name:   testUser2.Name,
+	})
+	ctx = auth.ContextWithRestrictions(ctx, auth.Restrictions{
+		BoardIds: []string{"b-123"},
+		Scopes:   []auth.Scope{createLinkScope, queryLinksScope},
+	})
+
+	link, err := service.CreateLink(ctx, "b-123", NewLink{Title: "Example", Url: "https://example.com"})
+	a.Nil(err)
+
+	_, err = service.Links(ctx, "b-123", LinkQueryParams{})
+	a.Nil(err)
+
+	err = service.RateLink(ctx, "b-123", link.LinkId, LinkRating{Rating: 1})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	_, err = service.CreateLink(ctx, "b-456", NewLink{Title: "Example", Url: "https://example.com"})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+}
+
+type testVisibilityStore struct {
+	testAuthorizationStore
+}
+
+// Like auth/store.DefaultAuthorizationStore, returns PermissionDenied for users that are not members of a board.
+func (t *testVisibilityStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	roles, err := t.testAuthorizationStore.Roles(ctx, boardId, userId)
+	if err != nil {
+		return nil, errors.New(err, "test", errors.PermissionDenied)
+	}
+	return roles, nil
+}
+
+func (t *testVisibilityStore) IsBoardVisible(ctx context.Context, boardId string) (bool, error) {
+	return boardId == "b-visible", nil
+}
+
+func TestVisitorAuthorization(t *testing.T) {
+	a := assert.New(t)
+
+	ds := newTestLinkDatastore()
+	service := NewLinkApplicationService(ds, &testVisibilityStore{}, nil, domain.LimitsConfig{}, nil)
+	err := ds.CreateLink(context.Background(), "b-visible", domain.Link{LinkId: "l-123"})
+	a.Nil(err)
+
+	// unauthenticated users and users that are not members of the board can query links of visible boards
+	for _, ctx := range []context.Context{
+		context.Background(),
+		auth.ContextWithUser(context.Background(), auth.User{UserId: "user-3"}),
+	} {
+		links, err := service.Links(ctx, "b-visible", LinkQueryParams{})
+		a.Nil(err)
+		a.Len(links, 1)
+		_, err = service.Link(ctx, "b-visible", "l-123")
+		a.Nil(err)
+	}
+
+	// but they cannot create or rate links
+	ctx := auth.ContextWithUser(context.Background(), auth.User{UserId: "user-3"})
+	_, err = service.CreateLink(ctx, "b-visible", NewLink{Title: "Example", Url: "https://example.com"})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	err = service.RateLink(ctx, "b-visible", "l-123", LinkRating{Rating: 1})
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+
+	// streams of board events are only available to members
+	err = service.AuthorizeLinkQueries(ctx, "b-visible")
+	a.NotNil(err)
+	a.True(errors.IsPermissionDeniedError(err))
+	err = service.AuthorizeLinkQueries(auth.ContextWithUser(context.Background(), auth.User{UserId: testUser1.UserId}), "b-visible")
+	a.Nil(err)
+
+	_, err = service.Links(context.Background(), "b-private", LinkQueryParams{})
+	a.NotNil(err)
+	a.True(errors.IsUnauthenticatedError(err))
 }
 
 var defaultTime = stdtime.Date(2022, 04, 04, 0, 0, 0, 0, stdtime.UTC)
@@ -156,7 +262,7 @@ func TestLinks(t *testing.T) {
 		UserId: testUser1.UserId,
 		Name:   testUser1.Name,
 	})
-	svc := NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{})
+	svc := NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
 
 	link, err := svc.CreateLink(ctx, "b-123", NewLink{Title: "Link title", Url: "https://abc.com/xyz"})
 	a.Nil(err)
@@ -205,6 +311,104 @@ func TestLinks(t *testing.T) {
 	a.True(errors.IsNotFoundError(err))
 }
 
+func TestImportAndExportLinks(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := auth.ContextWithUser(context.Background(), auth.User{
+		UserId: testUser2.UserId,
+		Name:   testUser2.Name,
+	})
+	svc := NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+
+	result, err := svc.ImportLinks(ctx, "b-123", []NewLink{
+		{Title: "Go", Url: "https://go.dev", Tags: []string{"Programming", "Go"}},
+		{Title: "Insecure", Url: "http://example.com"},
+		{Title: "", Url: "https://example.com"},
+		{Title: "Example", Url: "https://example.com"},
+	})
+	a.Nil(err)
+	a.Len(result.Imported, 2)
+	a.Equal("Go", result.Imported[0].Title)
+	a.Equal([]string{"Programming", "Go"}, result.Imported[0].Tags)
+	a.Equal("Example", result.Imported[1].Title)
+	a.Len(result.Failed, 2)
+	a.Equal(1, result.Failed[0].Index)
+	a.Equal("http://example.com", result.Failed[0].Url)
+	a.NotZero(result.Failed[0].Code)
+	a.Equal(2, result.Failed[1].Index)
+
+	export, err := svc.ExportLinks(ctx, "b-123")
+	a.Nil(err)
+	a.False(export.Truncated)
+	a.Len(export.Links, 2)
+	urls := []string{export.Links[0].Url, export.Links[1].Url}
+	a.ElementsMatch([]string{"https://go.dev", "https://example.com"}, urls)
+
+	// too many links
+	_, err = svc.ImportLinks(ctx, "b-123", make([]NewLink, MaxImportLinks+1))
+	a.NotNil(err)
+	a.True(errors.IsInvalidArgumentError(err))
+
+	// links created at the same time are exported even if they are split across pages
+	ds := newTestLinkDatastore()
+	for i := 0; i < exportPageSize+10; i++ {
+		createdTime := int64(1000 + i)
+		if i < 20 {
+			createdTime = 1000
+		}
+		err = ds.CreateLink(context.Background(), "b-456", domain.Link{LinkId: fmt.Sprintf("l-%v", i), CreatedTime: createdTime})
+		a.Nil(err)
+	}
+	svc = NewLinkApplicationService(ds, &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+	export, err = svc.ExportLinks(ctx, "b-456")
+	a.Nil(err)
+	a.Len(export.Links, exportPageSize+10)
+
+	// more links than fit on a page share the same created time
+	ds = newTestLinkDatastore()
+	for i := 0; i < exportPageSize+5; i++ {
+		err = ds.CreateLink(context.Background(), "b-456", domain.Link{LinkId: fmt.Sprintf("l-%v", i), CreatedTime: 1000})
+		a.Nil(err)
+	}
+	svc = NewLinkApplicationService(ds, &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+	export, err = svc.ExportLinks(ctx, "b-456")
+	a.Nil(err)
+	a.Len(export.Links, exportPageSize+5)
+	ids := make(map[string]struct{})
+	for _, link := range export.Links {
+		ids[link.LinkId] = struct{}{}
+	}
+	a.Len(ids, exportPageSize+5)
+
+	// only the newest links are exported if the board has too many
+	ds = newTestLinkDatastore()
+	for i := 0; i < maxExportLinks+1; i++ {
+		err = ds.CreateLink(context.Background(), "b-456", domain.Link{LinkId: fmt.Sprintf("l-%v", i), CreatedTime: int64(1000 + i)})
+		a.Nil(err)
+	}
+	svc = NewLinkApplicationService(ds, &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+	export, err = svc.ExportLinks(ctx, "b-456")
+	a.Nil(err)
+	a.True(export.Truncated)
+	a.Len(export.Links, maxExportLinks)
+	a.Equal("l-1000", export.Links[0].LinkId)
+	a.Equal("l-1", export.Links[maxExportLinks-1].LinkId)
+
+	// links that exceed the daily quota are reported as failed
+	svc = NewLinkApplicationService(newTestLinkDatastore(), &testAuthorizationStore{}, nil, domain.LimitsConfig{
+		Default: domain.Limits{MaxLinksPerUserPerDay: 1},
+	}, ratelimit.NewInmemQuotaStore())
+	result, err = svc.ImportLinks(ctx, "b-123", []NewLink{
+		{Title: "Go", Url: "https://go.dev"},
+		{Title: "Example", Url: "https://example.com"},
+	})
+	a.Nil(err)
+	a.Len(result.Imported, 1)
+	a.Len(result.Failed, 1)
+	a.Equal(1, result.Failed[0].Index)
+	a.Equal("daily link quota reached", result.Failed[0].Message)
+}
+
 func TestLinkQueryParam(t *testing.T) {
 	a := assert.New(t)
 
@@ -46,10 +46,16 @@ var roleToScopes = map[string][]auth.Scope{
 		rateLinkScope,
 		queryLinksScope,
 	},
+	// users that are not members of a board that is visible to non-members, they can view links but not create or rate them
+	auth.BoardRoleVisitor: {
+		queryLinksScope,
+	},
 }
 
-// scopes available to authenticated users that are not a member of a board
-var authenticatedScopes auth.Authorization = map[auth.Scope]struct{}{}
+func init() {
+	auth.RegisterScopes(allScopes()...)
+	auth.RegisterRoles(roleToScopes)
+}
 
 func NewAuthorizationChecker(as auth.AuthorizationStore) *auth.BoardAuthorizationChecker {
 	return auth.NewAuthorizationChecker(roleToScopes, as)
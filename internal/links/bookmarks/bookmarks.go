@@ -0,0 +1,219 @@
+// Package bookmarks reads and writes the Netscape bookmark file format.
+// All major browsers can import and export bookmarks in this format, it is
+// a loosely defined HTML document where folders are nested <DL> lists, e.g.:
+//
+//	<!DOCTYPE NETSCAPE-Bookmark-file-1>
+//	<TITLE>Bookmarks</TITLE>
+//	<H1>Bookmarks</H1>
+//	<DL><p>
+//	    <DT><H3>Go</H3>
+//	    <DL><p>
+//	        <DT><A HREF="https://go.dev" ADD_DATE="1660000000">The Go Programming Language</A>
+//	    </DL><p>
+//	</DL><p>
+package bookmarks
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dkinzler/kit/errors"
+
+	xhtml "golang.org/x/net/html"
+)
+
+type Bookmark struct {
+	Title string
+	Url   string
+	// Names of the folders containing the bookmark, outermost folder first.
+	Folders []string
+	// Additional tags, some browsers (e.g. Firefox) support tagging bookmarks.
+	Tags []string
+	// Time the bookmark was added as Unix time (seconds), 0 if unknown.
+	AddDate int64
+}
+
+func newError(inner error, code errors.ErrorCode) errors.Error {
+	return errors.New(inner, "bookmarks", code)
+}
+
+// Parse reads the bookmarks of a document in the Netscape bookmark file format.
+// Bookmarks are returned in the order they appear in the document.
+// If max is greater than 0, parsing stops after max bookmarks were read and the rest of the document is ignored.
+// Since browsers are not very strict when writing these files, Parse is lenient too,
+// e.g. unclosed tags are accepted.
+func Parse(r io.Reader, max int) ([]Bookmark, error) {
+	z := xhtml.NewTokenizer(r)
+
+	var result []Bookmark
+	// folders that are currently open, an element is empty for a list that does not belong to a folder (e.g. the top-level list)
+	var folders []string
+	// name of the last folder heading, will be pushed onto the folder stack when the next list starts
+	var pendingFolder string
+	var inFolderHeading bool
+	// the bookmark whose title is currently being read
+	var current *Bookmark
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			if z.Err() == io.EOF {
+				return result, nil
+			}
+			return nil, newError(z.Err(), errors.InvalidArgument).WithPublicMessage("could not parse bookmark file")
+		case xhtml.StartTagToken:
+			token := z.Token()
+			switch token.Data {
+			case "h3":
+				inFolderHeading = true
+				pendingFolder = ""
+			case "dl":
+				folders = append(folders, pendingFolder)
+				pendingFolder = ""
+			case "a":
+				b := Bookmark{Folders: nonEmpty(folders)}
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "href":
+						b.Url = strings.TrimSpace(attr.Val)
+					case "add_date":
+						b.AddDate, _ = strconv.ParseInt(attr.Val, 10, 64)
+					case "tags":
+						b.Tags = splitTags(attr.Val)
+					}
+				}
+				current = &b
+			}
+		case xhtml.EndTagToken:
+			token := z.Token()
+			switch token.Data {
+			case "h3":
+				inFolderHeading = false
+			case "dl":
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			case "a":
+				if current != nil {
+					current.Title = strings.TrimSpace(current.Title)
+					if current.Title == "" {
+						current.Title = current.Url
+					}
+					result = append(result, *current)
+					current = nil
+					if max > 0 && len(result) >= max {
+						return result, nil
+					}
+				}
+			}
+		case xhtml.TextToken:
+			text := string(z.Text())
+			if current != nil {
+				current.Title += text
+			} else if inFolderHeading {
+				pendingFolder += strings.TrimSpace(text)
+			}
+		}
+	}
+}
+
+func nonEmpty(x []string) []string {
+	var result []string
+	for _, s := range x {
+		if s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func splitTags(s string) []string {
+	var result []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// A folder of the document written by Write.
+type folder struct {
+	name      string
+	bookmarks []Bookmark
+	// keep subfolders in order of first appearance, so that the output is deterministic
+	subfolders []*folder
+}
+
+func (f *folder) subfolder(name string) *folder {
+	for _, sf := range f.subfolders {
+		if sf.name == name {
+			return sf
+		}
+	}
+	sf := &folder{name: name}
+	f.subfolders = append(f.subfolders, sf)
+	return sf
+}
+
+// Write writes the given bookmarks as a document in the Netscape bookmark file format.
+// The folders of a bookmark are written as nested folders, i.e. the result of reading the document
+// with Parse will contain the same bookmarks.
+func Write(w io.Writer, title string, bookmarks []Bookmark) error {
+	root := &folder{}
+	for _, b := range bookmarks {
+		f := root
+		for _, name := range b.Folders {
+			f = f.subfolder(name)
+		}
+		f.bookmarks = append(f.bookmarks, b)
+	}
+
+	bw := &errWriter{w: w}
+	bw.printf("<!DOCTYPE NETSCAPE-Bookmark-file-1>\n")
+	bw.printf("<META HTTP-EQUIV=\"Content-Type\" CONTENT=\"text/html; charset=UTF-8\">\n")
+	bw.printf("<TITLE>%v</TITLE>\n", html.EscapeString(title))
+	bw.printf("<H1>%v</H1>\n", html.EscapeString(title))
+	writeFolder(bw, root, 0)
+	if bw.err != nil {
+		return newError(bw.err, errors.Internal).WithInternalMessage("could not write bookmarks")
+	}
+	return nil
+}
+
+func writeFolder(w *errWriter, f *folder, depth int) {
+	indent := strings.Repeat("    ", depth)
+	w.printf("%v<DL><p>\n", indent)
+	for _, sf := range f.subfolders {
+		w.printf("%v    <DT><H3>%v</H3>\n", indent, html.EscapeString(sf.name))
+		writeFolder(w, sf, depth+1)
+	}
+	for _, b := range f.bookmarks {
+		w.printf("%v    <DT><A HREF=\"%v\"", indent, html.EscapeString(b.Url))
+		if b.AddDate > 0 {
+			w.printf(" ADD_DATE=\"%v\"", b.AddDate)
+		}
+		if len(b.Tags) > 0 {
+			w.printf(" TAGS=\"%v\"", html.EscapeString(strings.Join(b.Tags, ",")))
+		}
+		w.printf(">%v</A>\n", html.EscapeString(b.Title))
+	}
+	w.printf("%v</DL><p>\n", indent)
+}
+
+// Remembers the first error that occurred, so that we don't have to check for errors after every write.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...interface{}) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
@@ -0,0 +1,95 @@
+package bookmarks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Similar to a file exported by Firefox/Chrome.
+const testFile = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1660000000" LAST_MODIFIED="1660000001">Programming</H3>
+    <DL><p>
+        <DT><H3>Go</H3>
+        <DL><p>
+            <DT><A HREF="https://go.dev" ADD_DATE="1660000002" TAGS="golang,docs">The Go Programming Language</A>
+            <DT><A HREF="https://pkg.go.dev/std">Standard library &amp; more</A>
+        </DL><p>
+        <DT><A HREF="https://www.rust-lang.org" ADD_DATE="abc">Rust</A>
+    </DL><p>
+    <DT><A HREF="https://example.com"></A>
+</DL><p>
+`
+
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+
+	bms, err := Parse(strings.NewReader(testFile), 0)
+	a.Nil(err)
+	a.Len(bms, 4)
+
+	a.Equal(Bookmark{
+		Title:   "The Go Programming Language",
+		Url:     "https://go.dev",
+		Folders: []string{"Programming", "Go"},
+		Tags:    []string{"golang", "docs"},
+		AddDate: 1660000002,
+	}, bms[0])
+
+	a.Equal(Bookmark{
+		Title:   "Standard library & more",
+		Url:     "https://pkg.go.dev/std",
+		Folders: []string{"Programming", "Go"},
+	}, bms[1])
+
+	// invalid add date is ignored
+	a.Equal(Bookmark{
+		Title:   "Rust",
+		Url:     "https://www.rust-lang.org",
+		Folders: []string{"Programming"},
+	}, bms[2])
+
+	// url is used as title if there is none
+	a.Equal(Bookmark{
+		Title: "https://example.com",
+		Url:   "https://example.com",
+	}, bms[3])
+
+	bms, err = Parse(strings.NewReader(""), 0)
+	a.Nil(err)
+	a.Empty(bms)
+
+	// parsing stops after max bookmarks
+	bms, err = Parse(strings.NewReader(testFile), 2)
+	a.Nil(err)
+	a.Len(bms, 2)
+	a.Equal("https://pkg.go.dev/std", bms[1].Url)
+}
+
+func TestWriteAndParse(t *testing.T) {
+	a := assert.New(t)
+
+	bms := []Bookmark{
+		{Title: "A <b>", Url: "https://a.com?x=1&y=2", Folders: []string{"X", "Y"}, AddDate: 1660000000},
+		{Title: "B", Url: "https://b.com", Folders: []string{"X"}, Tags: []string{"t1", "t2"}},
+		{Title: "C", Url: "https://c.com"},
+		{Title: "D", Url: "https://d.com", Folders: []string{"X", "Y"}},
+	}
+
+	var buf bytes.Buffer
+	err := Write(&buf, "Board & Bookmarks", bms)
+	a.Nil(err)
+	a.Contains(buf.String(), "<TITLE>Board &amp; Bookmarks</TITLE>")
+
+	parsed, err := Parse(&buf, 0)
+	a.Nil(err)
+	// bookmarks in the same folder are grouped together
+	a.Equal([]Bookmark{bms[0], bms[3], bms[1], bms[2]}, parsed)
+}
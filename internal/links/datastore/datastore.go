@@ -163,6 +163,31 @@ func DatastoreTest(ds domain.LinkDataStore, t *testing.T) {
 	a.Equal("3", result[2].Link.LinkId)
 	a.Equal("1", result[3].Link.LinkId)
 
+	// when sorting by top, the created time cursor only applies to links with the same score as the score cursor
+	result, err = ds.Links(ctx, "1", domain.LinkReturnFields{}, domain.NewLinkQueryParams().WithLimit(5).SortByTop().WithScoreCursor(3).WithCreatedTimeCursor(links[1].CreatedTime-1))
+	a.Nil(err)
+	a.Len(result, 3)
+	a.Equal("4", result[0].Link.LinkId)
+	a.Equal("3", result[1].Link.LinkId)
+	a.Equal("1", result[2].Link.LinkId)
+
+	// links created at the same time are sorted by descending id, a link cursor continues after the given link
+	sameTime := ctime.CurrTimeUnixNano()
+	for _, linkId := range []string{"6", "7", "8"} {
+		a.Nil(ds.CreateLink(ctx, "3", domain.Link{BoardId: "3", LinkId: linkId, CreatedTime: sameTime}))
+	}
+	a.Nil(ds.CreateLink(ctx, "3", domain.Link{BoardId: "3", LinkId: "9", CreatedTime: sameTime - 1}))
+	result, err = ds.Links(ctx, "3", domain.LinkReturnFields{}, domain.NewLinkQueryParams().WithLimit(2).SortByNewest())
+	a.Nil(err)
+	a.Len(result, 2)
+	a.Equal("8", result[0].Link.LinkId)
+	a.Equal("7", result[1].Link.LinkId)
+	result, err = ds.Links(ctx, "3", domain.LinkReturnFields{}, domain.NewLinkQueryParams().WithLimit(2).SortByNewest().WithLinkCursor(result[1].Link))
+	a.Nil(err)
+	a.Len(result, 2)
+	a.Equal("6", result[0].Link.LinkId)
+	a.Equal("9", result[1].Link.LinkId)
+
 	err = ds.DeleteLink(ctx, "1", "3")
 	a.Nil(err)
 	link, err = ds.Link(ctx, "1", "3", domain.LinkReturnFields{
@@ -173,6 +198,59 @@ func DatastoreTest(ds domain.LinkDataStore, t *testing.T) {
 	a.True(errors.IsNotFoundError(err))
 }
 
+// Tests the methods of LinkAdminDataStore, expects an empty data store.
+func AdminDatastoreTest(ds domain.LinkAdminDataStore, t *testing.T) {
+	a := assert.New(t)
+
+	ctx, cancel := getContext()
+	defer cancel()
+
+	boardIds, err := ds.BoardIdsWithLinks(ctx)
+	a.Nil(err)
+	a.Empty(boardIds)
+
+	for _, l := range []domain.Link{
+		{BoardId: "b-2", LinkId: "l-1", CreatedTime: 1000},
+		{BoardId: "b-1", LinkId: "l-2", CreatedTime: 2000},
+		{BoardId: "b-2", LinkId: "l-3", CreatedTime: 3000},
+	} {
+		a.Nil(ds.CreateLink(ctx, l.BoardId, l))
+	}
+
+	boardIds, err = ds.BoardIdsWithLinks(ctx)
+	a.Nil(err)
+	a.Equal([]string{"b-1", "b-2"}, boardIds)
+
+	for _, r := range []domain.UserLinkRating{
+		{UserId: "u-1", Rating: 1},
+		{UserId: "u-2", Rating: -1},
+		{UserId: "u-3", Rating: 1},
+		// changes the rating of u-2
+		{UserId: "u-2", Rating: 1},
+	} {
+		a.Nil(ds.UpdateRating(ctx, "b-2", "l-1", r))
+	}
+
+	// the rating is consistent with the user ratings, nothing changes
+	old, new, err := ds.RecomputeRating(ctx, "b-2", "l-1")
+	a.Nil(err)
+	a.Equal(domain.Rating{Score: 3, Upvotes: 3}, old)
+	a.Equal(old, new)
+
+	link, err := ds.Link(ctx, "b-2", "l-1", domain.LinkReturnFields{IncludeRating: true})
+	a.Nil(err)
+	a.Equal(domain.Rating{Score: 3, Upvotes: 3}, link.Rating)
+
+	old, new, err = ds.RecomputeRating(ctx, "b-2", "l-3")
+	a.Nil(err)
+	a.Equal(domain.Rating{}, old)
+	a.Equal(domain.Rating{}, new)
+
+	_, _, err = ds.RecomputeRating(ctx, "b-2", "l-4")
+	a.NotNil(err)
+	a.True(errors.IsNotFoundError(err))
+}
+
 func getContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 2*time.Second)
 }
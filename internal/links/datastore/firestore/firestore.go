@@ -2,27 +2,31 @@ package firestore
 
 import (
 	"context"
+	"sort"
 
+	"github.com/dkinzler/linkboards/internal/docstore"
 	"github.com/dkinzler/linkboards/internal/links/domain"
 
-	"github.com/dkinzler/kit/errors"
-	fs "github.com/dkinzler/kit/firebase/firestore"
-
 	"cloud.google.com/go/firestore"
 )
 
 const linksCollectionName = "links"
 
 type FirestoreLinkDataStore struct {
-	client          *firestore.Client
-	linksCollection *firestore.CollectionRef
+	store docstore.Store
 }
 
 func NewFirestoreLinkDataStore(client *firestore.Client) *FirestoreLinkDataStore {
-	return &FirestoreLinkDataStore{
-		client:          client,
-		linksCollection: client.Collection(linksCollectionName),
-	}
+	return newFirestoreLinkDataStore(docstore.NewFirestore(client))
+}
+
+// Tests use an in-memory docstore.Fake instead of a firestore client.
+func newFirestoreLinkDataStore(store docstore.Store) *FirestoreLinkDataStore {
+	return &FirestoreLinkDataStore{store: store}
+}
+
+func linkRef(linkId string) docstore.DocRef {
+	return docstore.DocRef{Collection: linksCollectionName, Id: linkId}
 }
 
 // Since the number of users of a board is limited, so is the number of ratings for a link.
@@ -80,7 +84,7 @@ func (ds *FirestoreLinkDataStore) CreateLink(ctx context.Context, boardId string
 		UserRatings: map[string]domain.UserLinkRating{},
 	}
 
-	err := fs.CreateDocument(ctx, ds.linksCollection, link.LinkId, fsLink)
+	err := ds.store.Create(ctx, linkRef(link.LinkId), fsLink)
 	if err != nil {
 		return err
 	}
@@ -88,18 +92,18 @@ func (ds *FirestoreLinkDataStore) CreateLink(ctx context.Context, boardId string
 }
 
 func (ds *FirestoreLinkDataStore) DeleteLink(ctx context.Context, boardId string, linkId string) error {
-	err := fs.DeleteDocument(ctx, ds.linksCollection, linkId)
+	err := ds.store.Delete(ctx, linkRef(linkId))
 	return err
 }
 
 func (ds *FirestoreLinkDataStore) UpdateRating(ctx context.Context, boardId string, linkId string, userRating domain.UserLinkRating) error {
-	err := ds.client.RunTransaction(ctx, func(c context.Context, t *firestore.Transaction) error {
-		snap, err := t.Get(ds.linksCollection.Doc(linkId))
+	err := ds.store.RunTransaction(ctx, func(c context.Context, t docstore.Transaction) error {
+		doc, err := t.Get(linkRef(linkId))
 		if err != nil {
-			return fs.ParseFirestoreError(err)
+			return err
 		}
 		var link fsLink
-		err = fs.UnmarshalDocSnapshot(snap, &link)
+		err = doc.DataTo(&link)
 		if err != nil {
 			return err
 		}
@@ -123,19 +127,23 @@ func (ds *FirestoreLinkDataStore) UpdateRating(ctx context.Context, boardId stri
 			{Path: "userRatings." + userRating.UserId, Value: userRating},
 			{Path: "rating", Value: newRating},
 		}
-		err = t.Update(ds.linksCollection.Doc(linkId), updates)
+		err = t.Update(linkRef(linkId), updates)
 		if err != nil {
-			return fs.NewFirestoreError(err, errors.Internal)
+			return err
 		}
 
 		return nil
-	}, firestore.MaxAttempts(1))
+	})
 	return err
 }
 
 func (ds *FirestoreLinkDataStore) Link(ctx context.Context, boardId string, linkId string, rf domain.LinkReturnFields) (domain.LinkWithRating, error) {
+	doc, err := ds.store.Get(ctx, linkRef(linkId))
+	if err != nil {
+		return domain.LinkWithRating{}, err
+	}
 	var link fsLink
-	err := fs.GetDocumentById(ctx, ds.linksCollection, linkId, &link)
+	err = doc.DataTo(&link)
 	if err != nil {
 		return domain.LinkWithRating{}, err
 	}
@@ -144,7 +152,7 @@ func (ds *FirestoreLinkDataStore) Link(ctx context.Context, boardId string, link
 }
 
 func (ds *FirestoreLinkDataStore) Links(ctx context.Context, boardId string, rf domain.LinkReturnFields, qp domain.LinkQueryParams) ([]domain.LinkWithRating, error) {
-	query := ds.linksCollection.Where("boardId", "==", boardId)
+	query := docstore.NewQuery(linksCollectionName).Where("boardId", "==", boardId)
 
 	pathsToSelect := []string{"boardId", "linkId", "link"}
 	if rf.IncludeRating {
@@ -156,8 +164,11 @@ func (ds *FirestoreLinkDataStore) Links(ctx context.Context, boardId string, rf
 	query = query.Select(pathsToSelect...)
 
 	if qp.SortOrder == domain.SortOrderNewest {
-		query = query.OrderBy("link.CreatedTime", firestore.Desc)
-		if qp.CursorCreatedTime != 0 {
+		// Link ids are the ids of the documents, ordering by them does not require an additional index.
+		query = query.OrderBy("link.CreatedTime", firestore.Desc).OrderBy(firestore.DocumentID, firestore.Desc)
+		if qp.CursorCreatedTime != 0 && qp.CursorLinkId != "" {
+			query = query.StartAfter(qp.CursorCreatedTime, qp.CursorLinkId)
+		} else if qp.CursorCreatedTime != 0 {
 			query = query.StartAt(qp.CursorCreatedTime)
 		}
 	} else if qp.SortOrder == domain.SortOrderTop {
@@ -178,15 +189,15 @@ func (ds *FirestoreLinkDataStore) Links(ctx context.Context, boardId string, rf
 		query = query.Limit(20)
 	}
 
-	snaps, err := fs.GetDocumentsForQuery(ctx, query)
+	docs, err := ds.store.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]domain.LinkWithRating, len(snaps))
-	for i, snap := range snaps {
+	result := make([]domain.LinkWithRating, len(docs))
+	for i, doc := range docs {
 		var link fsLink
-		err = fs.UnmarshalDocSnapshot(snap, &link)
+		err = doc.DataTo(&link)
 		if err != nil {
 			return nil, err
 		}
@@ -195,3 +206,62 @@ func (ds *FirestoreLinkDataStore) Links(ctx context.Context, boardId string, rf
 
 	return result, nil
 }
+
+func (ds *FirestoreLinkDataStore) BoardIdsWithLinks(ctx context.Context) ([]string, error) {
+	// Firestore cannot query distinct values, therefore we have to read the board id of every link.
+	docs, err := ds.store.Query(ctx, docstore.NewQuery(linksCollectionName).Select("boardId"))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, doc := range docs {
+		v, err := doc.DataAt("boardId")
+		if err != nil {
+			return nil, err
+		}
+		boardId, ok := v.(string)
+		if ok && !seen[boardId] {
+			seen[boardId] = true
+			result = append(result, boardId)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (ds *FirestoreLinkDataStore) RecomputeRating(ctx context.Context, boardId string, linkId string) (domain.Rating, domain.Rating, error) {
+	var oldRating, newRating domain.Rating
+	err := ds.store.RunTransaction(ctx, func(c context.Context, t docstore.Transaction) error {
+		doc, err := t.Get(linkRef(linkId))
+		if err != nil {
+			return err
+		}
+		var link fsLink
+		err = doc.DataTo(&link)
+		if err != nil {
+			return err
+		}
+
+		ratings := make([]domain.UserLinkRating, 0, len(link.UserRatings))
+		for _, r := range link.UserRatings {
+			ratings = append(ratings, r)
+		}
+		oldRating = link.Rating
+		newRating = domain.AggregateRatings(ratings)
+		if oldRating == newRating {
+			return nil
+		}
+
+		err = t.Update(linkRef(linkId), []firestore.Update{{Path: "rating", Value: newRating}})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Rating{}, domain.Rating{}, err
+	}
+	return oldRating, newRating, nil
+}
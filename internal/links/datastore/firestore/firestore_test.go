@@ -2,12 +2,13 @@ package firestore
 
 import (
 	"context"
-	"log"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/dkinzler/linkboards/internal/docstore"
 	"github.com/dkinzler/linkboards/internal/links/datastore"
+	"github.com/dkinzler/linkboards/internal/links/domain"
 
 	"github.com/dkinzler/kit/firebase"
 	"github.com/dkinzler/kit/firebase/emulator"
@@ -16,18 +17,6 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// These tests require a running firestore emulator.
-func TestMain(m *testing.M) {
-	// Skip these tests if the environment variable is not set.
-	if pid := os.Getenv("FIREBASE_PROJECT_ID"); pid == "" {
-		log.Println("set FIREBASE_PROJECT_ID to run these tests")
-		os.Exit(0)
-	}
-
-	exitCode := m.Run()
-	os.Exit(exitCode)
-}
-
 // Creates a firestore emulator client that can be used to reset the emulator after each test and an instance of firestore.Client to implement the tests.
 func initTest(t *testing.T) (*firestore.Client, error) {
 	firestoreEmulatorClient, err := emulator.NewFirestoreEmulatorClient()
@@ -56,7 +45,18 @@ func initTest(t *testing.T) (*firestore.Client, error) {
 	return firestore, nil
 }
 
-func TestFirestoreBoardDataStore(t *testing.T) {
+func TestMain(m *testing.M) {
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+// This test requires a running firestore emulator.
+func TestFirestoreLinkDataStore(t *testing.T) {
+	// Skip this test if the environment variable is not set.
+	if pid := os.Getenv("FIREBASE_PROJECT_ID"); pid == "" {
+		t.Skip("set FIREBASE_PROJECT_ID to run these tests")
+	}
+
 	a := assert.New(t)
 
 	client, err := initTest(t)
@@ -66,6 +66,66 @@ func TestFirestoreBoardDataStore(t *testing.T) {
 	datastore.DatastoreTest(ds, t)
 }
 
+// This test requires a running firestore emulator.
+func TestFirestoreLinkAdminDataStore(t *testing.T) {
+	// Skip this test if the environment variable is not set.
+	if pid := os.Getenv("FIREBASE_PROJECT_ID"); pid == "" {
+		t.Skip("set FIREBASE_PROJECT_ID to run these tests")
+	}
+
+	a := assert.New(t)
+
+	client, err := initTest(t)
+	a.Nil(err)
+
+	ds := NewFirestoreLinkDataStore(client)
+	datastore.AdminDatastoreTest(ds, t)
+}
+
+// Runs the data store tests against an in-memory fake of firestore, no emulator required.
+func TestFirestoreLinkDataStoreWithFake(t *testing.T) {
+	datastore.DatastoreTest(newFirestoreLinkDataStore(docstore.NewFake()), t)
+}
+
+func TestFirestoreLinkAdminDataStoreWithFake(t *testing.T) {
+	datastore.AdminDatastoreTest(newFirestoreLinkDataStore(docstore.NewFake()), t)
+}
+
+// Verifies the layout of link documents, queries order by fields named after the domain types.
+func TestFirestoreLinkDocuments(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	fake := docstore.NewFake()
+	ds := newFirestoreLinkDataStore(fake)
+
+	err := ds.CreateLink(ctx, "b-1", domain.Link{LinkId: "l-1", Title: "title", CreatedTime: 42})
+	a.Nil(err)
+	err = ds.UpdateRating(ctx, "b-1", "l-1", domain.UserLinkRating{UserId: "u-1", Rating: 1})
+	a.Nil(err)
+
+	doc, err := fake.Get(ctx, docstore.DocRef{Collection: linksCollectionName, Id: "l-1"})
+	a.Nil(err)
+	for path, expected := range map[string]interface{}{
+		"boardId":                "b-1",
+		"link.CreatedTime":       int64(42),
+		"rating.Score":           int64(1),
+		"rating.Upvotes":         int64(1),
+		"userRatings.u-1.Rating": int64(1),
+	} {
+		v, err := doc.DataAt(path)
+		a.Nil(err, path)
+		a.Equal(expected, v, path)
+	}
+
+	// the rating of another user is not selected
+	links, err := ds.Links(ctx, "b-1", domain.LinkReturnFields{IncludeRating: true, IncludeUserRatingFor: "u-2"}, domain.LinkQueryParams{SortOrder: domain.SortOrderTop})
+	a.Nil(err)
+	a.Len(links, 1)
+	a.Equal(domain.UserLinkRating{}, links[0].UserRating)
+	a.Equal(1, links[0].Rating.Score)
+}
+
 func getContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 2*time.Second)
 }
@@ -74,7 +74,7 @@ func (ds *InmemLinkDataStore) UpdateRating(ctx context.Context, boardId string,
 		if oldRating.Rating > 0 {
 			aggregate.Upvotes -= oldRating.Rating
 		} else {
-			aggregate.Downvotes += oldRating.Rating
+			aggregate.Downvotes -= oldRating.Rating
 		}
 	}
 
@@ -107,14 +107,20 @@ func (ds *InmemLinkDataStore) Links(ctx context.Context, boardId string, rf doma
 		if link.boardId == boardId {
 			match := true
 
-			if qp.CursorCreatedTime != 0 {
+			// Like the cursors of the firestore data store, when sorting by top the created time
+			// only applies to links with a score equal to the score cursor.
+			if qp.SortOrder == domain.SortOrderTop {
+				if qp.CursorScore != nil {
+					if link.rating.Score > *qp.CursorScore {
+						match = false
+					} else if link.rating.Score == *qp.CursorScore && qp.CursorCreatedTime != 0 && link.link.CreatedTime > qp.CursorCreatedTime {
+						match = false
+					}
+				}
+			} else if qp.CursorCreatedTime != 0 {
 				if link.link.CreatedTime > qp.CursorCreatedTime {
 					match = false
-				}
-			}
-
-			if qp.CursorScore != nil {
-				if link.rating.Score > *qp.CursorScore {
+				} else if link.link.CreatedTime == qp.CursorCreatedTime && qp.CursorLinkId != "" && link.link.LinkId >= qp.CursorLinkId {
 					match = false
 				}
 			}
@@ -127,10 +133,10 @@ func (ds *InmemLinkDataStore) Links(ctx context.Context, boardId string, rf doma
 
 	if qp.SortOrder == domain.SortOrderNewest {
 		sort.Slice(links, func(i int, j int) bool {
-			if links[i].link.CreatedTime > links[j].link.CreatedTime {
-				return true
+			if links[i].link.CreatedTime == links[j].link.CreatedTime {
+				return links[i].link.LinkId > links[j].link.LinkId
 			}
-			return false
+			return links[i].link.CreatedTime > links[j].link.CreatedTime
 		})
 	} else if qp.SortOrder == domain.SortOrderTop {
 		sort.Slice(links, func(i int, j int) bool {
@@ -155,6 +161,40 @@ func (ds *InmemLinkDataStore) Links(ctx context.Context, boardId string, rf doma
 	return result, nil
 }
 
+func (ds *InmemLinkDataStore) BoardIdsWithLinks(ctx context.Context) ([]string, error) {
+	ds.m.RLock()
+	defer ds.m.RUnlock()
+
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, l := range ds.links {
+		if !seen[l.boardId] {
+			seen[l.boardId] = true
+			result = append(result, l.boardId)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (ds *InmemLinkDataStore) RecomputeRating(ctx context.Context, boardId string, linkId string) (domain.Rating, domain.Rating, error) {
+	ds.m.Lock()
+	defer ds.m.Unlock()
+
+	l, ok := ds.links[linkId]
+	if !ok {
+		return domain.Rating{}, domain.Rating{}, newError(nil, errors.NotFound)
+	}
+
+	ratings := make([]domain.UserLinkRating, 0, len(l.userRatings))
+	for _, r := range l.userRatings {
+		ratings = append(ratings, r)
+	}
+	old := l.rating
+	l.rating = domain.AggregateRatings(ratings)
+	return old, l.rating, nil
+}
+
 func linkWithRatingFromReturnFields(l *linkWithRatings, rf domain.LinkReturnFields) domain.LinkWithRating {
 	result := domain.LinkWithRating{
 		Link: l.link,
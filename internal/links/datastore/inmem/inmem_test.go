@@ -11,3 +11,7 @@ func TestInmemLinkDataStore(t *testing.T) {
 
 	datastore.DatastoreTest(s, t)
 }
+
+func TestInmemLinkAdminDataStore(t *testing.T) {
+	datastore.AdminDatastoreTest(NewInmemLinkDataStore(), t)
+}
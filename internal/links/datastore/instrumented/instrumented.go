@@ -0,0 +1,48 @@
+// Package instrumented provides decorators for LinkDataStore implementations,
+// that record metrics of calls (see package metrics) or create a span for every call (see package tracing).
+package instrumented
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/metrics"
+
+	"github.com/dkinzler/kit/time"
+)
+
+const dataStoreName = "links"
+
+type instrumentedLinkDataStore struct {
+	ds domain.LinkDataStore
+	m  *metrics.Metrics
+}
+
+func NewInstrumentedLinkDataStore(ds domain.LinkDataStore, m *metrics.Metrics) domain.LinkDataStore {
+	return &instrumentedLinkDataStore{ds: ds, m: m}
+}
+
+func (i *instrumentedLinkDataStore) CreateLink(ctx context.Context, boardId string, link domain.Link) (err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "CreateLink", time.CurrTime(), &err)
+	return i.ds.CreateLink(ctx, boardId, link)
+}
+
+func (i *instrumentedLinkDataStore) DeleteLink(ctx context.Context, boardId string, linkId string) (err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "DeleteLink", time.CurrTime(), &err)
+	return i.ds.DeleteLink(ctx, boardId, linkId)
+}
+
+func (i *instrumentedLinkDataStore) UpdateRating(ctx context.Context, boardId string, linkId string, rating domain.UserLinkRating) (err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "UpdateRating", time.CurrTime(), &err)
+	return i.ds.UpdateRating(ctx, boardId, linkId, rating)
+}
+
+func (i *instrumentedLinkDataStore) Link(ctx context.Context, boardId string, linkId string, rf domain.LinkReturnFields) (_ domain.LinkWithRating, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "Link", time.CurrTime(), &err)
+	return i.ds.Link(ctx, boardId, linkId, rf)
+}
+
+func (i *instrumentedLinkDataStore) Links(ctx context.Context, boardId string, rf domain.LinkReturnFields, qp domain.LinkQueryParams) (_ []domain.LinkWithRating, err error) {
+	defer i.m.ObserveDataStoreCall(dataStoreName, "Links", time.CurrTime(), &err)
+	return i.ds.Links(ctx, boardId, rf, qp)
+}
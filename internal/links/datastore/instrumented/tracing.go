@@ -0,0 +1,50 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/tracing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracingLinkDataStore struct {
+	ds     domain.LinkDataStore
+	tracer trace.Tracer
+}
+
+// Returns a LinkDataStore that creates a span for every call to the given data store.
+func NewTracingLinkDataStore(ds domain.LinkDataStore, tp trace.TracerProvider) domain.LinkDataStore {
+	return &tracingLinkDataStore{ds: ds, tracer: tracing.Tracer(tp)}
+}
+
+func (t *tracingLinkDataStore) CreateLink(ctx context.Context, boardId string, link domain.Link) (err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "CreateLink")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.CreateLink(ctx, boardId, link)
+}
+
+func (t *tracingLinkDataStore) DeleteLink(ctx context.Context, boardId string, linkId string) (err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "DeleteLink")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.DeleteLink(ctx, boardId, linkId)
+}
+
+func (t *tracingLinkDataStore) UpdateRating(ctx context.Context, boardId string, linkId string, rating domain.UserLinkRating) (err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "UpdateRating")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.UpdateRating(ctx, boardId, linkId, rating)
+}
+
+func (t *tracingLinkDataStore) Link(ctx context.Context, boardId string, linkId string, rf domain.LinkReturnFields) (_ domain.LinkWithRating, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "Link")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.Link(ctx, boardId, linkId, rf)
+}
+
+func (t *tracingLinkDataStore) Links(ctx context.Context, boardId string, rf domain.LinkReturnFields, qp domain.LinkQueryParams) (_ []domain.LinkWithRating, err error) {
+	ctx, span := tracing.StartDataStoreSpan(ctx, t.tracer, dataStoreName, "Links")
+	defer tracing.EndSpan(span, &err)
+	return t.ds.Links(ctx, boardId, rf, qp)
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/paging"
+)
 
 // Any type implementing this interface can be used as the link and rating data store for this application.
 //
@@ -19,6 +23,18 @@ type LinkDataStore interface {
 	Links(ctx context.Context, boardId string, rf LinkReturnFields, qp LinkQueryParams) ([]LinkWithRating, error)
 }
 
+// Extends LinkDataStore with operations needed to administer the application, e.g. by the linkboards-admin command.
+// They are not used by LinkService.
+type LinkAdminDataStore interface {
+	LinkDataStore
+	// Returns the ids of all boards that have at least one link.
+	// Can be used to find links of boards that no longer exist.
+	BoardIdsWithLinks(ctx context.Context) ([]string, error)
+	// Recomputes the rating of a link from the ratings of all users (see AggregateRatings) and returns the old and new rating.
+	// Can be used to repair ratings that are inconsistent with the user ratings.
+	RecomputeRating(ctx context.Context, boardId string, linkId string) (Rating, Rating, error)
+}
+
 // For convenience, LinkDataStore should be able to return a link with its rating (i.e. the summary/aggregate of all user ratings) and possibly
 // the individual rating of a given user.
 // A LinkReturnFields value can be used to configure what should be returned.
@@ -62,6 +78,10 @@ type LinkQueryParams struct {
 	CursorScore *int
 	// Return only links that were created at or before the given time (Unix nanoseconds).
 	CursorCreatedTime int64
+	// Only used when sort order is "newest", links created at the same time are sorted by descending link id.
+	// If set, links created at CursorCreatedTime are only returned if their id is less than CursorLinkId,
+	// i.e. the cursor continues after the link with the given created time and id, see WithLinkCursor.
+	CursorLinkId string
 }
 
 func NewLinkQueryParams() LinkQueryParams {
@@ -99,3 +119,30 @@ func (l LinkQueryParams) WithCreatedTimeCursor(t int64) LinkQueryParams {
 	}
 	return l
 }
+
+// Returns only the links that follow the given link when sorting by newest.
+// Unlike a created time cursor, links created at the same time as the given link are neither returned again nor skipped.
+func (l LinkQueryParams) WithLinkCursor(link Link) LinkQueryParams {
+	l.CursorCreatedTime = link.CreatedTime
+	l.CursorLinkId = link.LinkId
+	return l
+}
+
+// Returns a query that reads the links of a board from newest to oldest, pageSize links at a time, to be used with paging.Each.
+// Pages continue after the last link of the previous page (see WithLinkCursor), such that links created at the same time are not skipped,
+// no matter how many there are.
+func AllLinksQuery(ds LinkDataStore, boardId string, rf LinkReturnFields, pageSize int) paging.Query[LinkWithRating] {
+	return func(ctx context.Context, last *LinkWithRating) ([]LinkWithRating, error) {
+		qp := NewLinkQueryParams().SortByNewest()
+		qp.Limit = pageSize
+		if last != nil {
+			qp = qp.WithLinkCursor(last.Link)
+		}
+		return ds.Links(ctx, boardId, rf, qp)
+	}
+}
+
+// Identifies links returned by AllLinksQuery.
+func LinkWithRatingId(l LinkWithRating) string {
+	return l.Link.LinkId
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "context"
+
+// Any type implementing this interface can be used by LinkService to publish events,
+// see the EventPublisher interface of the boards component for more details.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, event interface{})
+}
+
+type LinkCreated struct {
+	Link Link
+}
+
+type LinkDeleted struct {
+	BoardId   string
+	LinkId    string
+	DeletedBy User
+}
+
+type LinkRated struct {
+	BoardId string
+	LinkId  string
+	Rating  UserLinkRating
+}
+
+// Implements EventPublisher by wrapping another EventPublisher or nil.
+// Forwards any calls to PublishEvent to the wrapped EventPublisher if it is not nil.
+type maybeEventPublisher struct {
+	ep EventPublisher
+}
+
+func newMaybeEventPublisher(ep EventPublisher) EventPublisher {
+	return &maybeEventPublisher{ep: ep}
+}
+
+func (m *maybeEventPublisher) PublishEvent(ctx context.Context, event interface{}) {
+	if m.ep != nil {
+		m.ep.PublishEvent(ctx, event)
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"github.com/dkinzler/kit/errors"
+)
+
+// Limits restrict the size of links.
+// Zero values are replaced by the values of DefaultLimits.
+type Limits struct {
+	MaxTitleLength int
+	MaxTagsPerLink int
+	MaxTagLength   int
+	// Number of links a user can create per day (UTC) on a board, only enforced if the LinkService has a quota store.
+	MaxLinksPerUserPerDay int
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		MaxTitleLength:        200,
+		MaxTagsPerLink:        10,
+		MaxTagLength:          50,
+		MaxLinksPerUserPerDay: 1000,
+	}
+}
+
+// Upper bounds for configurable limits, a link should stay small since links are queried in batches.
+const maxTitleLength = 2000
+const maxTagsPerLink = 100
+const maxTagLength = 200
+
+// Returns a copy of l where zero values are replaced by the values of d.
+func (l Limits) withDefaults(d Limits) Limits {
+	if l.MaxTitleLength == 0 {
+		l.MaxTitleLength = d.MaxTitleLength
+	}
+	if l.MaxTagsPerLink == 0 {
+		l.MaxTagsPerLink = d.MaxTagsPerLink
+	}
+	if l.MaxTagLength == 0 {
+		l.MaxTagLength = d.MaxTagLength
+	}
+	if l.MaxLinksPerUserPerDay == 0 {
+		l.MaxLinksPerUserPerDay = d.MaxLinksPerUserPerDay
+	}
+	return l
+}
+
+func (l Limits) validate() error {
+	if l.MaxTitleLength < 0 || l.MaxTagsPerLink < 0 || l.MaxTagLength < 0 || l.MaxLinksPerUserPerDay < 0 {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("limits must not be negative")
+	}
+	if l.MaxTitleLength > maxTitleLength || l.MaxTagsPerLink > maxTagsPerLink || l.MaxTagLength > maxTagLength {
+		return newError(nil, errors.InvalidArgument).WithInternalMessage("limits too large")
+	}
+	return nil
+}
+
+// LimitsConfig contains the limits used for the links of all boards, together with overrides for individual boards, e.g. boards of premium teams.
+// Zero values of an override are replaced by the values of Default.
+type LimitsConfig struct {
+	Default Limits
+	// Maps board ids to limits.
+	BoardOverrides map[string]Limits
+}
+
+// Returns the limits for links of the board with the given id.
+func (c LimitsConfig) ForBoard(boardId string) Limits {
+	d := c.Default.withDefaults(DefaultLimits())
+	if o, ok := c.BoardOverrides[boardId]; ok {
+		return o.withDefaults(d)
+	}
+	return d
+}
+
+// Returns an error if the default limits or one of the overrides is invalid.
+func (c LimitsConfig) Validate() error {
+	if err := c.ForBoard("").validate(); err != nil {
+		return err
+	}
+	for boardId := range c.BoardOverrides {
+		if err := c.ForBoard(boardId).validate(); err != nil {
+			return newError(err, errors.InvalidArgument).WithInternalMessage("invalid limits for board " + boardId)
+		}
+	}
+	return nil
+}
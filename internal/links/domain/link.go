@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"net/url"
+	"strings"
 
 	"github.com/dkinzler/kit/errors"
 	"github.com/dkinzler/kit/time"
@@ -16,6 +17,9 @@ const (
 	errUrlInvalid
 	errUrlInsecure
 	errInvalidRating
+	errTooManyTags
+	errTagInvalid
+	errLinkQuotaReached
 )
 
 type User struct {
@@ -32,16 +36,17 @@ type Link struct {
 
 	CreatedTime int64
 	CreatedBy   User
+
+	// Optional labels to group links, e.g. the folders of an imported browser bookmark.
+	Tags []string
 }
 
 func newError(inner error, code errors.ErrorCode) errors.Error {
 	return errors.New(inner, "links/domain", code)
 }
 
-const maxTitleLength = 200
-
-func (l *Link) IsValid() error {
-	if err := l.isTitleValid(); err != nil {
+func (l *Link) IsValid(limits Limits) error {
+	if err := l.isTitleValid(limits); err != nil {
 		return err
 	}
 
@@ -49,15 +54,19 @@ func (l *Link) IsValid() error {
 		return err
 	}
 
+	if err := l.areTagsValid(limits); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (l *Link) isTitleValid() error {
+func (l *Link) isTitleValid(limits Limits) error {
 	if len(l.Title) == 0 {
 		return newError(nil, errors.InvalidArgument).WithPublicMessage("title cannot be empty").WithPublicCode(errTitleEmpty)
 	}
 
-	if len(l.Title) > maxTitleLength {
+	if len(l.Title) > limits.MaxTitleLength {
 		return newError(nil, errors.InvalidArgument).WithPublicMessage("title too long").WithPublicCode(errTitleTooLong)
 	}
 
@@ -83,7 +92,41 @@ func (l *Link) isUrlValid() error {
 	return nil
 }
 
-func NewLink(boardId, title, url string, user User) (Link, error) {
+// A link can have at most limits.MaxTagsPerLink tags, each tag must be non-empty and not longer than limits.MaxTagLength bytes.
+func (l *Link) areTagsValid(limits Limits) error {
+	if len(l.Tags) > limits.MaxTagsPerLink {
+		return newError(nil, errors.InvalidArgument).WithPublicMessage("too many tags").WithPublicCode(errTooManyTags)
+	}
+
+	for _, tag := range l.Tags {
+		if len(tag) == 0 || len(tag) > limits.MaxTagLength {
+			return newError(nil, errors.InvalidArgument).WithPublicMessage("invalid tag").WithPublicCode(errTagInvalid)
+		}
+	}
+
+	return nil
+}
+
+// Removes surrounding whitespace from tags and drops empty and duplicate ones.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// The title and tags of the link are validated using the given limits.
+func NewLink(boardId, title, url string, user User, limits Limits, tags ...string) (Link, error) {
 	id, err := uuid.NewUUIDWithPrefix("l")
 	if err != nil {
 		return Link{}, newError(err, errors.Internal).WithInternalMessage("could not create link uuid")
@@ -98,9 +141,10 @@ func NewLink(boardId, title, url string, user User) (Link, error) {
 		Url:         url,
 		CreatedTime: timeNow,
 		CreatedBy:   user,
+		Tags:        normalizeTags(tags),
 	}
 
-	err = link.IsValid()
+	err = link.IsValid(limits)
 	if err != nil {
 		return Link{}, err
 	}
@@ -126,6 +170,21 @@ type UserLinkRating struct {
 	ModifiedTime int64
 }
 
+// Returns the rating of a link with the given user ratings.
+// Data stores that keep the rating of a link up to date when user ratings change must arrive at the same value.
+func AggregateRatings(ratings []UserLinkRating) Rating {
+	var r Rating
+	for _, ur := range ratings {
+		if ur.Rating > 0 {
+			r.Upvotes += ur.Rating
+		} else {
+			r.Downvotes += ur.Rating
+		}
+	}
+	r.Score = r.Upvotes + r.Downvotes
+	return r
+}
+
 func NewUserLinkRating(rating int, userId string) (UserLinkRating, error) {
 	if !(rating == 1 || rating == -1) {
 		return UserLinkRating{}, newError(nil, errors.InvalidArgument).WithPublicMessage("invalid rating").WithPublicCode(errInvalidRating)
@@ -140,32 +199,77 @@ func NewUserLinkRating(rating int, userId string) (UserLinkRating, error) {
 	}, nil
 }
 
+// LinkService provides operations on links and ratings.
+// It uses an implementation of LinkDataStore to read and persist links,
+// and an implementation of EventPublisher to make events available to other components/systems.
 type LinkService struct {
-	ds LinkDataStore
+	ds     LinkDataStore
+	ep     EventPublisher
+	limits LimitsConfig
+	quotas QuotaStore
 }
 
-func NewLinkService(ds LinkDataStore) *LinkService {
-	return &LinkService{ds: ds}
+// The LinkDataStore passed must not be nil.
+// The EventPublisher can be, in which case the events will just end up nowhere.
+// Zero values of limits are replaced by the values of DefaultLimits, use LimitsConfig.Validate to check limits before.
+// The QuotaStore is used to limit the number of links a user can create per day on a board, if it is nil there is no such limit.
+func NewLinkService(ds LinkDataStore, ep EventPublisher, limits LimitsConfig, quotas QuotaStore) *LinkService {
+	return &LinkService{ds: ds, ep: newMaybeEventPublisher(ep), limits: limits, quotas: quotas}
 }
 
 func newServiceError(inner error, code errors.ErrorCode) errors.Error {
 	return errors.New(inner, "LinkService", code)
 }
 
-func (ls *LinkService) CreateLink(ctx context.Context, boardId string, title string, url string, user User) (Link, error) {
-	link, err := NewLink(boardId, title, url, user)
+func (ls *LinkService) CreateLink(ctx context.Context, boardId string, title string, url string, user User, tags ...string) (Link, error) {
+	limits := ls.limits.ForBoard(boardId)
+	link, err := NewLink(boardId, title, url, user, limits, tags...)
 	if err != nil {
 		return Link{}, err
 	}
 
+	quotaKey := "createLink:" + boardId + ":" + user.UserId
+	if ls.quotas != nil {
+		ok, retryAfter, err := ls.quotas.Use(ctx, quotaKey, limits.MaxLinksPerUserPerDay)
+		if err != nil {
+			return Link{}, newServiceError(err, errors.Internal).WithInternalMessage("could not check link quota")
+		}
+		if !ok {
+			return Link{}, newServiceError(quotaExceededError{retryAfter: retryAfter}, errors.FailedPrecondition).
+				WithPublicCode(errLinkQuotaReached).
+				WithPublicMessage("daily link quota reached")
+		}
+	}
+
 	err = ls.ds.CreateLink(ctx, boardId, link)
 	if err != nil {
+		// links that could not be created don't count against the quota
+		if ls.quotas != nil {
+			ls.quotas.Refund(ctx, quotaKey)
+		}
 		return Link{}, newServiceError(err, errors.Internal).WithInternalMessage("could not create link")
 	}
 
+	ls.ep.PublishEvent(ctx, LinkCreated{Link: link})
+
 	return link, nil
 }
 
+func (ls *LinkService) DeleteLink(ctx context.Context, boardId string, linkId string, user User) error {
+	err := ls.ds.DeleteLink(ctx, boardId, linkId)
+	if err != nil {
+		return newServiceError(err, errors.Internal).WithInternalMessage("could not delete link")
+	}
+
+	ls.ep.PublishEvent(ctx, LinkDeleted{
+		BoardId:   boardId,
+		LinkId:    linkId,
+		DeletedBy: user,
+	})
+
+	return nil
+}
+
 // Creates or changes the rating of a user for a link.
 func (ls *LinkService) UpdateUserRating(ctx context.Context, boardId string, linkId string, rating int, user User) (UserLinkRating, error) {
 	r, err := NewUserLinkRating(rating, user.UserId)
@@ -178,5 +282,11 @@ func (ls *LinkService) UpdateUserRating(ctx context.Context, boardId string, lin
 		return UserLinkRating{}, newServiceError(err, errors.Internal).WithInternalMessage("could not update link rating")
 	}
 
+	ls.ep.PublishEvent(ctx, LinkRated{
+		BoardId: boardId,
+		LinkId:  linkId,
+		Rating:  r,
+	})
+
 	return r, nil
 }
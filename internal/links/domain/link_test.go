@@ -2,8 +2,12 @@ package domain
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/dkinzler/linkboards/internal/ratelimit"
+
 	"github.com/dkinzler/kit/errors"
 	"github.com/stretchr/testify/assert"
 	mock "github.com/stretchr/testify/mock"
@@ -42,7 +46,7 @@ func TestLinkCreation(t *testing.T) {
 	a := assert.New(t)
 
 	ds := &MockLinkDataStore{}
-	svc := NewLinkService(ds)
+	svc := NewLinkService(ds, nil, LimitsConfig{}, nil)
 	ctx := context.Background()
 
 	// empty title shouldn't work
@@ -97,11 +101,38 @@ func TestLinkCreation(t *testing.T) {
 	ds.AssertExpectations(t)
 }
 
+func TestLinkTags(t *testing.T) {
+	a := assert.New(t)
+
+	user := User{UserId: "u-123"}
+
+	// tags are trimmed, empty and duplicate tags are dropped
+	link, err := NewLink("b-123", "A title", "https://example.com", user, DefaultLimits(), " news ", "", "tech", "news")
+	a.Nil(err)
+	a.Equal([]string{"news", "tech"}, link.Tags)
+
+	link, err = NewLink("b-123", "A title", "https://example.com", user, DefaultLimits())
+	a.Nil(err)
+	a.Empty(link.Tags)
+
+	tooMany := make([]string, DefaultLimits().MaxTagsPerLink+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%v", i)
+	}
+	_, err = NewLink("b-123", "A title", "https://example.com", user, DefaultLimits(), tooMany...)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errTooManyTags))
+
+	_, err = NewLink("b-123", "A title", "https://example.com", user, DefaultLimits(), strings.Repeat("x", DefaultLimits().MaxTagLength+1))
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errTagInvalid))
+}
+
 func TestRating(t *testing.T) {
 	a := assert.New(t)
 
 	ds := &MockLinkDataStore{}
-	svc := NewLinkService(ds)
+	svc := NewLinkService(ds, nil, LimitsConfig{}, nil)
 	ctx := context.Background()
 
 	// invalid rating
@@ -132,3 +163,127 @@ func TestRating(t *testing.T) {
 	a.Equal(1, rating.Rating)
 	a.NotZero(rating.ModifiedTime)
 }
+
+func TestAggregateRatings(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(Rating{}, AggregateRatings(nil))
+	a.Equal(Rating{Score: 1, Upvotes: 3, Downvotes: -2}, AggregateRatings([]UserLinkRating{
+		{UserId: "u-1", Rating: 1},
+		{UserId: "u-2", Rating: -1},
+		{UserId: "u-3", Rating: 1},
+		{UserId: "u-4", Rating: -1},
+		{UserId: "u-5", Rating: 1},
+	}))
+}
+
+type recordingEventPublisher struct {
+	events []interface{}
+}
+
+func (r *recordingEventPublisher) PublishEvent(ctx context.Context, event interface{}) {
+	r.events = append(r.events, event)
+}
+
+func TestEventsPublished(t *testing.T) {
+	a := assert.New(t)
+
+	ds := &MockLinkDataStore{}
+	ep := &recordingEventPublisher{}
+	svc := NewLinkService(ds, ep, LimitsConfig{}, nil)
+	ctx := context.Background()
+	user := User{UserId: "u-123"}
+
+	ds.On("CreateLink", "b-123", mock.Anything).Return(nil).Once()
+	link, err := svc.CreateLink(ctx, "b-123", "A title", "https://example.com", user)
+	a.Nil(err)
+
+	ds.On("UpdateRating", "b-123", link.LinkId, mock.Anything).Return(nil).Once()
+	_, err = svc.UpdateUserRating(ctx, "b-123", link.LinkId, 1, user)
+	a.Nil(err)
+
+	ds.On("DeleteLink", "b-123", link.LinkId).Return(nil).Once()
+	err = svc.DeleteLink(ctx, "b-123", link.LinkId, user)
+	a.Nil(err)
+
+	// no event is published if an operation fails
+	ds.On("DeleteLink", "b-123", "l-1").Return(errors.New(nil, "test", errors.Internal)).Once()
+	err = svc.DeleteLink(ctx, "b-123", "l-1", user)
+	a.NotNil(err)
+
+	a.Len(ep.events, 3)
+	a.Equal(LinkCreated{Link: link}, ep.events[0])
+	rated, ok := ep.events[1].(LinkRated)
+	a.True(ok)
+	a.Equal(link.LinkId, rated.LinkId)
+	a.Equal(1, rated.Rating.Rating)
+	a.Equal(LinkDeleted{BoardId: "b-123", LinkId: link.LinkId, DeletedBy: user}, ep.events[2])
+
+	ds.AssertExpectations(t)
+}
+
+func TestLinkLimits(t *testing.T) {
+	a := assert.New(t)
+
+	ds := &MockLinkDataStore{}
+	svc := NewLinkService(ds, nil, LimitsConfig{
+		Default:        Limits{MaxTitleLength: 5},
+		BoardOverrides: map[string]Limits{"b-premium": {MaxTitleLength: 50}},
+	}, nil)
+	ctx := context.Background()
+	user := User{UserId: "u-123"}
+
+	_, err := svc.CreateLink(ctx, "b-123", "A long title", "https://example.com", user)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errTitleTooLong))
+
+	ds.On("CreateLink", "b-premium", mock.Anything).Return(nil).Once()
+	_, err = svc.CreateLink(ctx, "b-premium", "A long title", "https://example.com", user)
+	a.Nil(err)
+	ds.AssertExpectations(t)
+
+	a.Nil(LimitsConfig{}.Validate())
+	a.NotNil(LimitsConfig{BoardOverrides: map[string]Limits{"b-1": {MaxTagsPerLink: -1}}}.Validate())
+}
+
+func TestLinkQuota(t *testing.T) {
+	a := assert.New(t)
+
+	ds := &MockLinkDataStore{}
+	svc := NewLinkService(ds, nil, LimitsConfig{
+		Default:        Limits{MaxLinksPerUserPerDay: 1},
+		BoardOverrides: map[string]Limits{"b-premium": {MaxLinksPerUserPerDay: 2}},
+	}, ratelimit.NewInmemQuotaStore())
+	ctx := context.Background()
+	user := User{UserId: "u-123"}
+
+	ds.On("CreateLink", mock.Anything, mock.Anything).Return(nil)
+	_, err := svc.CreateLink(ctx, "b-123", "A title", "https://example.com", user)
+	a.Nil(err)
+	_, err = svc.CreateLink(ctx, "b-123", "A title", "https://example.com", user)
+	a.NotNil(err)
+	a.True(errors.HasPublicCode(err, errLinkQuotaReached))
+	_, ok := ratelimit.RetryAfter(err)
+	a.True(ok)
+
+	// quotas are counted per board and user
+	_, err = svc.CreateLink(ctx, "b-123", "A title", "https://example.com", User{UserId: "u-456"})
+	a.Nil(err)
+	for i := 0; i < 2; i++ {
+		_, err = svc.CreateLink(ctx, "b-premium", "A title", "https://example.com", user)
+		a.Nil(err)
+	}
+	_, err = svc.CreateLink(ctx, "b-premium", "A title", "https://example.com", user)
+	a.True(errors.HasPublicCode(err, errLinkQuotaReached))
+
+	// links that could not be written to the data store don't count against the quota
+	quotas := ratelimit.NewInmemQuotaStore()
+	failingDs := &MockLinkDataStore{}
+	failingDs.On("CreateLink", mock.Anything, mock.Anything).Return(errors.New(nil, "test", errors.Internal))
+	svc = NewLinkService(failingDs, nil, LimitsConfig{Default: Limits{MaxLinksPerUserPerDay: 1}}, quotas)
+	_, err = svc.CreateLink(ctx, "b-123", "A title", "https://example.com", user)
+	a.True(errors.IsInternalError(err))
+	svc = NewLinkService(ds, nil, LimitsConfig{Default: Limits{MaxLinksPerUserPerDay: 1}}, quotas)
+	_, err = svc.CreateLink(ctx, "b-123", "A title", "https://example.com", user)
+	a.Nil(err)
+}
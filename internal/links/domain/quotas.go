@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	stdtime "time"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+// QuotaStore is used by LinkService to enforce the MaxLinksPerUserPerDay limit,
+// see the QuotaStore interface of the boards component for more details.
+type QuotaStore interface {
+	Use(ctx context.Context, key string, max int) (bool, stdtime.Duration, error)
+	Refund(ctx context.Context, key string) error
+}
+
+// Inner error of the errors returned when a quota is used up, see ratelimit.RetryAfter.
+type quotaExceededError struct {
+	retryAfter stdtime.Duration
+}
+
+func (e quotaExceededError) Error() string {
+	return "quota exceeded, retry after " + e.retryAfter.String()
+}
+
+func (e quotaExceededError) RetryAfter() stdtime.Duration {
+	return e.retryAfter
+}
+
+// Returns true if the error was returned because a quota is used up, e.g. by LinkService.CreateLink.
+func IsQuotaExceededError(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case quotaExceededError:
+			return true
+		case errors.Error:
+			err = e.Inner
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+// Package feeds writes Atom (RFC 4287) and RSS 2.0 feeds.
+package feeds
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+type Feed struct {
+	// Globally unique and permanent identifier of the feed, should be a URI.
+	Id    string
+	Title string
+	// Time the feed was last updated, usually the time of the newest item.
+	Updated time.Time
+	Items   []Item
+}
+
+type Item struct {
+	// Globally unique and permanent identifier of the item, should be a URI.
+	Id     string
+	Title  string
+	Url    string
+	Author string
+	// Published and updated time of the item.
+	Published  time.Time
+	Categories []string
+}
+
+func newError(inner error, code errors.ErrorCode) errors.Error {
+	return errors.New(inner, "feeds", code)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Id         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Author     atomAuthor     `xml:"author"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes the feed in the Atom format.
+func WriteAtom(w io.Writer, feed Feed) error {
+	af := atomFeed{
+		Id:      feed.Id,
+		Title:   feed.Title,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Entries: make([]atomEntry, len(feed.Items)),
+	}
+	for i, item := range feed.Items {
+		categories := make([]atomCategory, len(item.Categories))
+		for j, c := range item.Categories {
+			categories[j] = atomCategory{Term: c}
+		}
+		t := item.Published.UTC().Format(time.RFC3339)
+		af.Entries[i] = atomEntry{
+			Id:         item.Id,
+			Title:      item.Title,
+			Link:       atomLink{Href: item.Url},
+			Author:     atomAuthor{Name: item.Author},
+			Published:  t,
+			Updated:    t,
+			Categories: categories,
+		}
+	}
+	return writeXML(w, af)
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title      string   `xml:"title"`
+	Link       string   `xml:"link"`
+	Guid       rssGuid  `xml:"guid"`
+	PubDate    string   `xml:"pubDate"`
+	Categories []string `xml:"category"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes the feed in the RSS 2.0 format.
+// Since RSS channels require a link, the id of the feed is used.
+// Authors are omitted, the author element of RSS must contain an email address.
+func WriteRSS(w io.Writer, feed Feed) error {
+	r := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Id,
+			Description:   feed.Title,
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, len(feed.Items)),
+		},
+	}
+	for i, item := range feed.Items {
+		r.Channel.Items[i] = rssItem{
+			Title:      item.Title,
+			Link:       item.Url,
+			Guid:       rssGuid{IsPermaLink: false, Value: item.Id},
+			PubDate:    item.Published.UTC().Format(time.RFC1123Z),
+			Categories: item.Categories,
+		}
+	}
+	return writeXML(w, r)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return newError(err, errors.Internal).WithInternalMessage("could not write feed")
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return newError(err, errors.Internal).WithInternalMessage("could not write feed")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFeed = Feed{
+	Id:      "urn:linkboards:board:b-1",
+	Title:   "Board & more",
+	Updated: time.Date(2022, 4, 4, 12, 0, 0, 0, time.UTC),
+	Items: []Item{
+		{
+			Id:         "urn:linkboards:link:l-1",
+			Title:      "Go <3",
+			Url:        "https://go.dev?a=1&b=2",
+			Author:     "User One",
+			Published:  time.Date(2022, 4, 4, 12, 0, 0, 0, time.UTC),
+			Categories: []string{"Programming", "Go"},
+		},
+		{
+			Id:        "urn:linkboards:link:l-2",
+			Title:     "Example",
+			Url:       "https://example.com",
+			Published: time.Date(2022, 4, 3, 12, 0, 0, 0, time.UTC),
+		},
+	},
+}
+
+func TestWriteAtom(t *testing.T) {
+	a := assert.New(t)
+
+	var buf bytes.Buffer
+	err := WriteAtom(&buf, testFeed)
+	a.Nil(err)
+
+	var parsed atomFeed
+	err = xml.Unmarshal(buf.Bytes(), &parsed)
+	a.Nil(err)
+	a.Equal("urn:linkboards:board:b-1", parsed.Id)
+	a.Equal("Board & more", parsed.Title)
+	a.Equal("2022-04-04T12:00:00Z", parsed.Updated)
+	a.Len(parsed.Entries, 2)
+	e := parsed.Entries[0]
+	a.Equal("urn:linkboards:link:l-1", e.Id)
+	a.Equal("Go <3", e.Title)
+	a.Equal("https://go.dev?a=1&b=2", e.Link.Href)
+	a.Equal("User One", e.Author.Name)
+	a.Equal("2022-04-04T12:00:00Z", e.Published)
+	a.Equal([]atomCategory{{Term: "Programming"}, {Term: "Go"}}, e.Categories)
+}
+
+func TestWriteRSS(t *testing.T) {
+	a := assert.New(t)
+
+	var buf bytes.Buffer
+	err := WriteRSS(&buf, testFeed)
+	a.Nil(err)
+
+	var parsed rss
+	err = xml.Unmarshal(buf.Bytes(), &parsed)
+	a.Nil(err)
+	a.Equal("2.0", parsed.Version)
+	a.Equal("Board & more", parsed.Channel.Title)
+	a.Equal("Mon, 04 Apr 2022 12:00:00 +0000", parsed.Channel.LastBuildDate)
+	a.Len(parsed.Channel.Items, 2)
+	i := parsed.Channel.Items[1]
+	a.Equal("Example", i.Title)
+	a.Equal("https://example.com", i.Link)
+	a.Equal("urn:linkboards:link:l-2", i.Guid.Value)
+	a.False(i.Guid.IsPermaLink)
+	a.Equal("Sun, 03 Apr 2022 12:00:00 +0000", i.PubDate)
+}
@@ -1,12 +1,21 @@
 package links
 
 import (
+	"context"
+
 	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/endpointname"
 	"github.com/dkinzler/linkboards/internal/links/application"
 	fs "github.com/dkinzler/linkboards/internal/links/datastore/firestore"
 	"github.com/dkinzler/linkboards/internal/links/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/links/datastore/instrumented"
 	"github.com/dkinzler/linkboards/internal/links/domain"
 	"github.com/dkinzler/linkboards/internal/links/transport"
+	"github.com/dkinzler/linkboards/internal/links/transport/pb"
+	"github.com/dkinzler/linkboards/internal/metrics"
+	"github.com/dkinzler/linkboards/internal/realtime"
+	"github.com/dkinzler/linkboards/internal/tracing"
 
 	e "github.com/dkinzler/kit/endpoint"
 	"github.com/dkinzler/kit/errors"
@@ -14,8 +23,11 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
 // Contains all the necessary elements for running the links service of the API.
@@ -23,6 +35,11 @@ type Component struct {
 	ApplicationService application.LinkApplicationService
 	DataStore          domain.LinkDataStore
 	Endpoints          transport.EndpointSet
+	BookmarkEndpoints  transport.BookmarkEndpointSet
+	// Only set if feeds are enabled.
+	FeedEndpoints *transport.FeedEndpointSet
+	// Used to authorize streams of board events, see AuthorizeLinkQueries.
+	AuthorizeLinkQueriesEndpoint endpoint.Endpoint
 }
 
 // Configures link component.
@@ -35,14 +52,35 @@ type Config struct {
 	FirestoreConfig *FirestoreConfig
 	// AuthorizationStore used to perform authorization in the application service.
 	AuthorizationStore auth.AuthorizationStore
-
-	// Middlewares that should be applied to all endpoints
+	// Optional, used to publish domain events, e.g. to push changes to clients.
+	EventPublisher domain.EventPublisher
+	// Limits for the title and tags of links, zero values are replaced by the values of domain.DefaultLimits.
+	Limits domain.LimitsConfig
+	// Optional, used to enforce daily quotas, e.g. the number of links a user can create per day on a board.
+	// If nil, there are no quotas.
+	Quotas domain.QuotaStore
+
+	// Middlewares that should be applied to all endpoints.
+	// They run after the authentication middleware and can get the name of the endpoint using package endpointname,
+	// e.g. to limit the rate of requests per endpoint, see ratelimit.NewEndpointMiddleware.
 	Middlewares []endpoint.Middleware
 	// Authentication middleware for endpoints.
 	AuthMiddleware endpoint.Middleware
+	// Authentication middleware for endpoints that can also be used without authentication,
+	// e.g. to view boards that are visible to non-members, see middleware.NewOptionalAuthEndpointMiddleware.
+	// If nil, AuthMiddleware is used.
+	OptionalAuthMiddleware endpoint.Middleware
+	// If not nil, metrics are recorded for all endpoints and data store calls.
+	Metrics *metrics.Metrics
+	// If not nil, spans are created for all endpoints and data store calls, see package tracing.
+	TracerProvider trace.TracerProvider
 	// Whether to add the logging middleware from the "internal/pkg/endpoint" package to every endpoint.
 	// It logs errors from the underlying application service, not any errors produced by endpoint middlewares.
 	UseLoggingMiddleware bool
+
+	// Used to create and verify the tokens that authenticate requests for board feeds.
+	// If nil, the feed endpoints are disabled.
+	FeedTokens *auth.FeedTokens
 }
 
 type FirestoreConfig struct {
@@ -63,30 +101,121 @@ func NewComponent(config Config) (*Component, error) {
 		return nil, errors.New(nil, "links", errors.InvalidArgument).WithInternalMessage("no datastore configured")
 	}
 
+	if config.Metrics != nil {
+		ds = instrumented.NewInstrumentedLinkDataStore(ds, config.Metrics)
+	}
+	if config.TracerProvider != nil {
+		ds = instrumented.NewTracingLinkDataStore(ds, config.TracerProvider)
+	}
+
 	if config.AuthorizationStore == nil {
 		return nil, errors.New(nil, "links", errors.InvalidArgument).WithInternalMessage("no authorization store provided")
 	}
 
-	applicationService := application.NewLinkApplicationService(ds, config.AuthorizationStore)
+	if err := config.Limits.Validate(); err != nil {
+		return nil, errors.New(err, "links", errors.InvalidArgument).WithInternalMessage("invalid limits")
+	}
+
+	applicationService := application.NewLinkApplicationService(ds, config.AuthorizationStore, config.EventPublisher, config.Limits, config.Quotas)
 
 	mwBuilder := mwBuilder{config: config}
 	endpoints := transport.NewEndpoints(applicationService, transport.Middlewares{
 		CreateLinkEndpoint: mwBuilder.buildMiddlewares("createLink"),
 		DeleteLinkEndpoint: mwBuilder.buildMiddlewares("deleteLink"),
 		RateLinkEndpoint:   mwBuilder.buildMiddlewares("rateLink"),
-		LinkEndpoint:       mwBuilder.buildMiddlewares("getLink"),
-		LinksEndpoint:      mwBuilder.buildMiddlewares("getLinks"),
+		LinkEndpoint:       mwBuilder.buildMiddlewaresWithOptionalAuth("getLink"),
+		LinksEndpoint:      mwBuilder.buildMiddlewaresWithOptionalAuth("getLinks"),
 	})
 
+	bookmarkEndpoints := transport.NewBookmarkEndpoints(applicationService, transport.BookmarkMiddlewares{
+		ImportLinksEndpoint: mwBuilder.buildMiddlewares("importLinks"),
+		ExportLinksEndpoint: mwBuilder.buildMiddlewares("exportLinks"),
+	})
+
+	var feedEndpoints *transport.FeedEndpointSet
+	if config.FeedTokens != nil {
+		fe := transport.NewFeedEndpoints(applicationService, config.FeedTokens, transport.FeedMiddlewares{
+			FeedEndpoint:           mwBuilder.buildMiddlewaresWithAuth("getFeed", middleware.NewFeedTokenEndpointMiddleware(config.FeedTokens)),
+			FeedTokenEndpoint:      mwBuilder.buildMiddlewares("getFeedToken"),
+			RenewFeedTokenEndpoint: mwBuilder.buildMiddlewares("renewFeedToken"),
+		})
+		feedEndpoints = &fe
+	}
+
+	authorizeLinkQueriesEndpoint := e.ApplyMiddlewares(
+		transport.MakeAuthorizeLinkQueriesEndpoint(applicationService),
+		mwBuilder.buildMiddlewares("authorizeLinkQueries")...,
+	)
+
 	return &Component{
-		ApplicationService: applicationService,
-		DataStore:          ds,
-		Endpoints:          endpoints,
+		ApplicationService:           applicationService,
+		DataStore:                    ds,
+		Endpoints:                    endpoints,
+		BookmarkEndpoints:            bookmarkEndpoints,
+		FeedEndpoints:                feedEndpoints,
+		AuthorizeLinkQueriesEndpoint: authorizeLinkQueriesEndpoint,
 	}, nil
 }
 
+// Returns nil if the user making the request is allowed to query the links of the board.
+// Since the check is performed using an endpoint, the context must contain the same values
+// as for a regular request, e.g. the authentication token.
+func (c *Component) AuthorizeLinkQueries(ctx context.Context, boardId string) error {
+	response, err := c.AuthorizeLinkQueriesEndpoint(ctx, transport.AuthorizeLinkQueriesRequest{BoardId: boardId})
+	if err != nil {
+		return err
+	}
+	if r, ok := response.(e.Responder); ok {
+		return r.Error()
+	}
+	return nil
+}
+
+// Returns an error if the data store of the component cannot be queried, can be used for readiness checks.
+func (c *Component) Ping(ctx context.Context) error {
+	// Queries the links of a board that does not exist, this is cheap but still requires a round trip to the data store.
+	_, err := c.DataStore.Links(ctx, pingBoardId, domain.LinkReturnFields{}, domain.LinkQueryParams{Limit: 1})
+	return err
+}
+
+const pingBoardId = "healthcheck"
+
 func (c *Component) RegisterHttpHandlers(router *mux.Router, httpOpts []http.ServerOption) {
 	transport.RegisterHttpHandlers(c.Endpoints, router, httpOpts)
+	transport.RegisterBookmarkHttpHandlers(c.BookmarkEndpoints, router, httpOpts)
+	if c.FeedEndpoints != nil {
+		feedOpts := make([]http.ServerOption, 0, len(httpOpts)+1)
+		feedOpts = append(feedOpts, httpOpts...)
+		feedOpts = append(feedOpts, http.ServerBefore(middleware.FeedTokenToContext))
+		transport.RegisterFeedHttpHandlers(*c.FeedEndpoints, router, feedOpts, httpOpts)
+	}
+}
+
+// Returns the commands clients can send over a WebSocket connection, see realtime.NewWebSocketHandler.
+func (c *Component) WebSocketCommands() map[string]realtime.Command {
+	return transport.NewWebSocketCommands(c.Endpoints)
+}
+
+// Registers a gRPC service that uses the same endpoints as the http handlers.
+func (c *Component) RegisterGRPCServer(s *grpc.Server, opts []kitgrpc.ServerOption) {
+	pb.RegisterLinkServiceServer(s, transport.NewGRPCServer(c.Endpoints, opts))
+}
+
+// Must list every endpoint name passed to mwBuilder, so that rate limits for them pass config validation.
+func init() {
+	endpointname.Register(
+		"createLink",
+		"deleteLink",
+		"rateLink",
+		"getLink",
+		"getLinks",
+		"importLinks",
+		"exportLinks",
+		"getFeed",
+		"getFeedToken",
+		"renewFeedToken",
+		"authorizeLinkQueries",
+	)
 }
 
 type mwBuilder struct {
@@ -94,13 +223,33 @@ type mwBuilder struct {
 }
 
 func (b mwBuilder) buildMiddlewares(endpointName string) []endpoint.Middleware {
+	return b.buildMiddlewaresWithAuth(endpointName, b.config.AuthMiddleware)
+}
+
+func (b mwBuilder) buildMiddlewaresWithOptionalAuth(endpointName string) []endpoint.Middleware {
+	if b.config.OptionalAuthMiddleware != nil {
+		return b.buildMiddlewaresWithAuth(endpointName, b.config.OptionalAuthMiddleware)
+	}
+	return b.buildMiddlewares(endpointName)
+}
+
+// Like buildMiddlewares but uses the given authentication middleware instead of the one from the config.
+func (b mwBuilder) buildMiddlewaresWithAuth(endpointName string, authMiddleware endpoint.Middleware) []endpoint.Middleware {
 	var mws []endpoint.Middleware
 	mws = append(mws, b.config.Middlewares...)
-	if b.config.AuthMiddleware != nil {
-		mws = append(mws, b.config.AuthMiddleware)
+	if authMiddleware != nil {
+		mws = append(mws, authMiddleware)
 	}
 	if b.config.UseLoggingMiddleware && b.config.Logger != nil {
 		mws = append(mws, e.ErrorLoggingMiddleware(b.config.Logger.With("component", "links", "endpoint", endpointName)))
 	}
+	if b.config.Metrics != nil {
+		mws = append(mws, b.config.Metrics.EndpointMiddleware("links", endpointName))
+	}
+	if b.config.TracerProvider != nil {
+		mws = append(mws, tracing.EndpointMiddleware(b.config.TracerProvider, "links", endpointName))
+	}
+	// outermost middleware, makes the endpoint name available to all other middlewares
+	mws = append(mws, endpointname.Middleware(endpointName))
 	return mws
 }
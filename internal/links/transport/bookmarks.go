@@ -0,0 +1,176 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/links/bookmarks"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+// The endpoints to import and export bookmark files cannot be generated,
+// since the request and response bodies are HTML documents instead of JSON.
+
+type ImportLinksRequest struct {
+	BoardId string
+	Nls     []application.NewLink
+}
+
+func MakeImportLinksEndpoint(svc application.LinkApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ImportLinksRequest)
+		r, err := svc.ImportLinks(ctx, req.BoardId, req.Nls)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
+type ExportLinksRequest struct {
+	BoardId string
+}
+
+func MakeExportLinksEndpoint(svc application.LinkApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ExportLinksRequest)
+		r, err := svc.ExportLinks(ctx, req.BoardId)
+		return e.Response{
+			Err: err,
+			R:   r,
+		}, nil
+	}
+}
+
+type BookmarkEndpointSet struct {
+	ImportLinksEndpoint endpoint.Endpoint
+	ExportLinksEndpoint endpoint.Endpoint
+}
+
+type BookmarkMiddlewares struct {
+	ImportLinksEndpoint []endpoint.Middleware
+	ExportLinksEndpoint []endpoint.Middleware
+}
+
+func NewBookmarkEndpoints(svc application.LinkApplicationService, mws BookmarkMiddlewares) BookmarkEndpointSet {
+	var importLinksEndpoint endpoint.Endpoint
+	{
+		importLinksEndpoint = MakeImportLinksEndpoint(svc)
+		importLinksEndpoint = e.ApplyMiddlewares(importLinksEndpoint, mws.ImportLinksEndpoint...)
+	}
+
+	var exportLinksEndpoint endpoint.Endpoint
+	{
+		exportLinksEndpoint = MakeExportLinksEndpoint(svc)
+		exportLinksEndpoint = e.ApplyMiddlewares(exportLinksEndpoint, mws.ExportLinksEndpoint...)
+	}
+
+	return BookmarkEndpointSet{
+		ImportLinksEndpoint: importLinksEndpoint,
+		ExportLinksEndpoint: exportLinksEndpoint,
+	}
+}
+
+// Bookmark files exported by browsers can contain icons of the bookmarked sites as data URLs,
+// the limit allows for large files while preventing clients from making the server read arbitrarily large ones.
+const maxImportBodyBytes = 10 << 20
+
+// Set on export responses if the board has more links than fit into a single export.
+const ExportTruncatedHeader = "Linkboards-Export-Truncated"
+
+// The request body is a bookmark file in the Netscape bookmark file format.
+// The folders of a bookmark become tags of the link.
+func decodeHttpImportLinksRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	// One bookmark more than can be imported is parsed, so that the application service can reject files with too many bookmarks.
+	bms, err := bookmarks.Parse(http.MaxBytesReader(nil, r.Body, maxImportBodyBytes), application.MaxImportLinks+1)
+	if err != nil {
+		if e, ok := err.(errors.Error); ok {
+			if _, tooLarge := e.Inner.(*http.MaxBytesError); tooLarge {
+				return nil, errors.New(nil, "bookmarks", errors.InvalidArgument).WithPublicMessage("bookmark file too large")
+			}
+		}
+		return nil, err
+	}
+
+	nls := make([]application.NewLink, len(bms))
+	for i, bm := range bms {
+		var tags []string
+		tags = append(tags, bm.Folders...)
+		tags = append(tags, bm.Tags...)
+		nls[i] = application.NewLink{
+			Title: bm.Title,
+			Url:   bm.Url,
+			Tags:  tags,
+		}
+	}
+
+	return ImportLinksRequest{
+		BoardId: boardId,
+		Nls:     nls,
+	}, nil
+}
+
+func decodeHttpExportLinksRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	return ExportLinksRequest{
+		BoardId: boardId,
+	}, nil
+}
+
+// Writes the links as a bookmark file, the tags of a link are used as the folder path of the bookmark.
+// Importing the file again will therefore recreate the links with the same tags.
+func encodeHttpExportLinksResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp, ok := response.(e.Responder)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return errors.New(nil, "bookmarks", errors.Internal).WithInternalMessage("response does not implement Responder")
+	}
+	if resp.Error() != nil {
+		return t.EncodeError(ctx, resp.Error(), w)
+	}
+
+	export, _ := resp.Response().(application.LinkExport)
+	bms := make([]bookmarks.Bookmark, len(export.Links))
+	for i, link := range export.Links {
+		bms[i] = bookmarks.Bookmark{
+			Title:   link.Title,
+			Url:     link.Url,
+			Folders: link.Tags,
+			// created time is in nanoseconds, bookmark files use seconds
+			AddDate: link.CreatedTime / 1e9,
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"bookmarks.html\"")
+	if export.Truncated {
+		w.Header().Set(ExportTruncatedHeader, "true")
+	}
+	w.WriteHeader(http.StatusOK)
+	return bookmarks.Write(w, "Bookmarks", bms)
+}
+
+func RegisterBookmarkHttpHandlers(endpoints BookmarkEndpointSet, router *mux.Router, opts []kithttp.ServerOption) {
+	importLinksHandler := kithttp.NewServer(endpoints.ImportLinksEndpoint, decodeHttpImportLinksRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/boards/{boardId}/bookmarks", importLinksHandler).Methods("POST", "OPTIONS")
+
+	exportLinksHandler := kithttp.NewServer(endpoints.ExportLinksEndpoint, decodeHttpExportLinksRequest, encodeHttpExportLinksResponse, opts...)
+	router.Handle("/boards/{boardId}/bookmarks", exportLinksHandler).Methods("GET", "OPTIONS")
+}
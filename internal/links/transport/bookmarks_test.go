@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/links/application"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeImportLinksRequest(t *testing.T) {
+	a := assert.New(t)
+
+	newRequest := func(body string) (interface{}, error) {
+		r := httptest.NewRequest("POST", "/boards/b-1/bookmarks", strings.NewReader(body))
+		r = mux.SetURLVars(r, map[string]string{"boardId": "b-1"})
+		return decodeHttpImportLinksRequest(context.Background(), r)
+	}
+
+	req, err := newRequest(`<DL><p><DT><H3>Go</H3><DL><p><DT><A HREF="https://go.dev">Go</A></DL><p></DL><p>`)
+	a.Nil(err)
+	a.Equal(ImportLinksRequest{
+		BoardId: "b-1",
+		Nls:     []application.NewLink{{Title: "Go", Url: "https://go.dev", Tags: []string{"Go"}}},
+	}, req)
+
+	// parsing stops after one bookmark more than can be imported
+	bookmark := `<DT><A HREF="https://go.dev">Go</A>`
+	req, err = newRequest("<DL><p>" + strings.Repeat(bookmark, 2*application.MaxImportLinks) + "</DL><p>")
+	a.Nil(err)
+	a.Len(req.(ImportLinksRequest).Nls, application.MaxImportLinks+1)
+
+	// body too large
+	_, err = newRequest(`<DL><p><DT><A HREF="https://go.dev" ICON="` + strings.Repeat("a", maxImportBodyBytes) + `">Go</A></DL><p>`)
+	a.True(errors.IsInvalidArgumentError(err))
+	a.Equal("bookmark file too large", err.(errors.Error).PublicMessage)
+}
+
+func TestEncodeExportLinksResponse(t *testing.T) {
+	a := assert.New(t)
+
+	w := httptest.NewRecorder()
+	err := encodeHttpExportLinksResponse(context.Background(), w, e.Response{R: application.LinkExport{
+		Links: []application.Link{{Title: "Go", Url: "https://go.dev", Tags: []string{"Go"}, CreatedTime: 2e9}},
+	}})
+	a.Nil(err)
+	a.Equal(200, w.Code)
+	a.Contains(w.Body.String(), `HREF="https://go.dev"`)
+	a.Empty(w.Header().Get(ExportTruncatedHeader))
+
+	w = httptest.NewRecorder()
+	err = encodeHttpExportLinksResponse(context.Background(), w, e.Response{R: application.LinkExport{Truncated: true}})
+	a.Nil(err)
+	a.Equal("true", w.Header().Get(ExportTruncatedHeader))
+}
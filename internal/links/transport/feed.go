@@ -0,0 +1,293 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/links/feeds"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+)
+
+// Number of links contained in a feed.
+const feedSize = 50
+
+type FeedRequest struct {
+	BoardId string
+	Format  string
+}
+
+type FeedResponse struct {
+	BoardId string
+	Format  string
+	Links   []application.Link
+}
+
+// Returns the newest links of a board.
+func MakeFeedEndpoint(svc application.LinkApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(FeedRequest)
+		links, err := svc.Links(ctx, req.BoardId, application.LinkQueryParams{
+			Limit: feedSize,
+			Sort:  "newest",
+		})
+		return e.Response{
+			Err: err,
+			R: FeedResponse{
+				BoardId: req.BoardId,
+				Format:  req.Format,
+				Links:   links,
+			},
+		}, nil
+	}
+}
+
+type FeedToken struct {
+	Token string `json:"token"`
+}
+
+// Returns the feed token of the user making the request.
+// The token can be added to the URL of a feed using the "token" query parameter.
+func MakeFeedTokenEndpoint(feedTokens *auth.FeedTokens) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		user, err := feedTokenUser(ctx)
+		if err != nil {
+			return e.Response{Err: err}, nil
+		}
+		token, err := feedTokens.Token(ctx, user.UserId)
+		return e.Response{
+			Err: err,
+			R:   FeedToken{Token: token},
+		}, nil
+	}
+}
+
+// Revokes the feed token of the user making the request and returns the new one.
+// Feeds that were added to feed readers using the previous token can no longer be requested.
+func MakeRenewFeedTokenEndpoint(feedTokens *auth.FeedTokens) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		user, err := feedTokenUser(ctx)
+		if err != nil {
+			return e.Response{Err: err}, nil
+		}
+		if err := feedTokens.Revoke(ctx, user.UserId); err != nil {
+			return e.Response{Err: err}, nil
+		}
+		token, err := feedTokens.Token(ctx, user.UserId)
+		return e.Response{
+			Err: err,
+			R:   FeedToken{Token: token},
+		}, nil
+	}
+}
+
+// Returns the user making a request to obtain a feed token.
+func feedTokenUser(ctx context.Context) (auth.User, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return auth.User{}, errors.New(nil, "FeedTokenEndpoint", errors.Unauthenticated)
+	}
+	// A feed token grants access to all boards of the user,
+	// it must not be obtainable with an access token that is restricted to fewer boards or scopes.
+	if _, ok := auth.RestrictionsFromContext(ctx); ok {
+		return auth.User{}, errors.New(nil, "FeedTokenEndpoint", errors.PermissionDenied).WithPublicMessage("feed tokens cannot be created using an access token")
+	}
+	return user, nil
+}
+
+type FeedEndpointSet struct {
+	FeedEndpoint           endpoint.Endpoint
+	FeedTokenEndpoint      endpoint.Endpoint
+	RenewFeedTokenEndpoint endpoint.Endpoint
+}
+
+type FeedMiddlewares struct {
+	FeedEndpoint           []endpoint.Middleware
+	FeedTokenEndpoint      []endpoint.Middleware
+	RenewFeedTokenEndpoint []endpoint.Middleware
+}
+
+func NewFeedEndpoints(svc application.LinkApplicationService, feedTokens *auth.FeedTokens, mws FeedMiddlewares) FeedEndpointSet {
+	var feedEndpoint endpoint.Endpoint
+	{
+		feedEndpoint = MakeFeedEndpoint(svc)
+		feedEndpoint = e.ApplyMiddlewares(feedEndpoint, mws.FeedEndpoint...)
+	}
+
+	var feedTokenEndpoint endpoint.Endpoint
+	{
+		feedTokenEndpoint = MakeFeedTokenEndpoint(feedTokens)
+		feedTokenEndpoint = e.ApplyMiddlewares(feedTokenEndpoint, mws.FeedTokenEndpoint...)
+	}
+
+	var renewFeedTokenEndpoint endpoint.Endpoint
+	{
+		renewFeedTokenEndpoint = MakeRenewFeedTokenEndpoint(feedTokens)
+		renewFeedTokenEndpoint = e.ApplyMiddlewares(renewFeedTokenEndpoint, mws.RenewFeedTokenEndpoint...)
+	}
+
+	return FeedEndpointSet{
+		FeedEndpoint:           feedEndpoint,
+		FeedTokenEndpoint:      feedTokenEndpoint,
+		RenewFeedTokenEndpoint: renewFeedTokenEndpoint,
+	}
+}
+
+func decodeHttpFeedRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := t.DecodeURLParameter(r, "format")
+	if err != nil {
+		return nil, err
+	}
+
+	return FeedRequest{
+		BoardId: boardId,
+		Format:  format,
+	}, nil
+}
+
+func decodeHttpFeedTokenRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+type contextKey string
+
+const ifNoneMatchContextKey contextKey = "ifNoneMatch"
+
+func ifNoneMatchToContext(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, ifNoneMatchContextKey, r.Header.Get("If-None-Match"))
+}
+
+// Writes the feed in the requested format.
+// The ETag of the response is the hash of the feed, if it matches the If-None-Match header of the request,
+// the feed has not changed and an empty response with status 304 is returned.
+func encodeHttpFeedResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp, ok := response.(e.Responder)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return errors.New(nil, "feed", errors.Internal).WithInternalMessage("response does not implement Responder")
+	}
+	if resp.Error() != nil {
+		return t.EncodeError(ctx, resp.Error(), w)
+	}
+
+	fr, _ := resp.Response().(FeedResponse)
+	feed := newFeed(fr.BoardId, fr.Links)
+
+	var buf bytes.Buffer
+	var contentType string
+	var err error
+	if fr.Format == FeedFormatRSS {
+		contentType = "application/rss+xml; charset=utf-8"
+		err = feeds.WriteRSS(&buf, feed)
+	} else {
+		contentType = "application/atom+xml; charset=utf-8"
+		err = feeds.WriteAtom(&buf, feed)
+	}
+	if err != nil {
+		return t.EncodeError(ctx, err, w)
+	}
+
+	hash := sha256.Sum256(buf.Bytes())
+	etag := fmt.Sprintf("\"%v\"", hex.EncodeToString(hash[:16]))
+	w.Header().Set("ETag", etag)
+
+	if ifNoneMatch, _ := ctx.Value(ifNoneMatchContextKey).(string); etagMatches(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Returns true if the value of an If-None-Match header matches the given ETag.
+// The header is either "*" or a comma separated list of ETags, that are compared using the weak comparison of RFC 9110,
+// i.e. a weak ETag like W/"abc" matches "abc".
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate != "" && candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Links are expected to be sorted by created time in descending order.
+func newFeed(boardId string, links []application.Link) feeds.Feed {
+	feed := feeds.Feed{
+		Id:    fmt.Sprintf("urn:linkboards:board:%v", boardId),
+		Title: fmt.Sprintf("Linkboards board %v", boardId),
+		// Use the time of the newest link instead of the current time, so that the feed (and therefore its ETag)
+		// only changes when links are added or removed.
+		Updated: stdtime.Unix(0, 0),
+		Items:   make([]feeds.Item, len(links)),
+	}
+	if len(links) > 0 {
+		feed.Updated = stdtime.Unix(0, links[0].CreatedTime)
+	}
+
+	for i, link := range links {
+		author := link.CreatedBy.Name
+		if author == "" {
+			author = link.CreatedBy.UserId
+		}
+		feed.Items[i] = feeds.Item{
+			Id:         fmt.Sprintf("urn:linkboards:link:%v", link.LinkId),
+			Title:      link.Title,
+			Url:        link.Url,
+			Author:     author,
+			Published:  stdtime.Unix(0, link.CreatedTime),
+			Categories: link.Tags,
+		}
+	}
+
+	return feed
+}
+
+// The feed handlers need to use a different authentication mechanism than the other endpoints, since feed readers
+// cannot obtain JWTs. The opts for the feed handler should therefore add the feed token to the request context.
+func RegisterFeedHttpHandlers(endpoints FeedEndpointSet, router *mux.Router, feedOpts []kithttp.ServerOption, opts []kithttp.ServerOption) {
+	fo := make([]kithttp.ServerOption, 0, len(feedOpts)+1)
+	fo = append(fo, feedOpts...)
+	fo = append(fo, kithttp.ServerBefore(ifNoneMatchToContext))
+
+	feedHandler := kithttp.NewServer(endpoints.FeedEndpoint, decodeHttpFeedRequest, encodeHttpFeedResponse, fo...)
+	router.Handle("/boards/{boardId}/feed.{format:atom|rss}", feedHandler).Methods("GET", "OPTIONS")
+
+	feedTokenHandler := kithttp.NewServer(endpoints.FeedTokenEndpoint, decodeHttpFeedTokenRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/me/feedtoken", feedTokenHandler).Methods("GET", "OPTIONS")
+
+	renewFeedTokenHandler := kithttp.NewServer(endpoints.RenewFeedTokenEndpoint, decodeHttpFeedTokenRequest, t.MakeGenericJSONEncodeFunc(200), opts...)
+	router.Handle("/me/feedtoken", renewFeedTokenHandler).Methods("POST", "OPTIONS")
+}
@@ -0,0 +1,170 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/auth/tokens"
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/links/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/links/domain"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthorizationStore struct{}
+
+func (t *testAuthorizationStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	if userId == "u-1" {
+		return []string{auth.BoardRoleViewer}, nil
+	}
+	return nil, nil
+}
+
+func TestFeed(t *testing.T) {
+	a := assert.New(t)
+
+	ds := inmem.NewInmemLinkDataStore()
+	svc := application.NewLinkApplicationService(ds, &testAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+	feedTokens := auth.NewFeedTokens([]byte("secret"), tokens.NewInmemFeedTokenVersionStore())
+
+	endpoints := NewFeedEndpoints(svc, feedTokens, FeedMiddlewares{
+		FeedEndpoint: []endpoint.Middleware{middleware.NewFeedTokenEndpointMiddleware(feedTokens)},
+	})
+	router := mux.NewRouter()
+	opts := []kithttp.ServerOption{kithttp.ServerErrorEncoder(encodeError)}
+	feedOpts := append([]kithttp.ServerOption{kithttp.ServerBefore(middleware.FeedTokenToContext)}, opts...)
+	RegisterFeedHttpHandlers(endpoints, router, feedOpts, opts)
+
+	get := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	token, _ := feedTokens.Token(context.Background(), "u-1")
+	otherToken, _ := feedTokens.Token(context.Background(), "u-2")
+
+	// no or invalid token
+	w := get("/boards/b-1/feed.atom", nil)
+	a.Equal(http.StatusUnauthorized, w.Code)
+	w = get("/boards/b-1/feed.atom?token="+otherToken, nil)
+	a.Equal(http.StatusForbidden, w.Code)
+
+	w = get("/boards/b-1/feed.atom?token="+token, nil)
+	a.Equal(http.StatusOK, w.Code)
+	a.Contains(w.Header().Get("Content-Type"), "application/atom+xml")
+	a.Contains(w.Body.String(), "<feed xmlns=\"http://www.w3.org/2005/Atom\">")
+	etag := w.Header().Get("ETag")
+	a.NotEmpty(etag)
+
+	w = get("/boards/b-1/feed.rss?token="+token, nil)
+	a.Equal(http.StatusOK, w.Code)
+	a.Contains(w.Header().Get("Content-Type"), "application/rss+xml")
+	a.Contains(w.Body.String(), "<rss version=\"2.0\">")
+
+	// feed has not changed
+	w = get("/boards/b-1/feed.atom?token="+token, map[string]string{"If-None-Match": etag})
+	a.Equal(http.StatusNotModified, w.Code)
+	a.Empty(w.Body.String())
+	// weak ETags and lists of ETags are accepted too
+	w = get("/boards/b-1/feed.atom?token="+token, map[string]string{"If-None-Match": "W/" + etag})
+	a.Equal(http.StatusNotModified, w.Code)
+	w = get("/boards/b-1/feed.atom?token="+token, map[string]string{"If-None-Match": `"other", ` + etag})
+	a.Equal(http.StatusNotModified, w.Code)
+
+	// after adding a link the feed changes
+	err := ds.CreateLink(context.Background(), "b-1", domain.Link{
+		BoardId:     "b-1",
+		LinkId:      "l-1",
+		Title:       "Go",
+		Url:         "https://go.dev",
+		CreatedTime: 1649073600000000000,
+		CreatedBy:   domain.User{UserId: "u-1", Name: "User One"},
+	})
+	a.Nil(err)
+	w = get("/boards/b-1/feed.atom?token="+token, map[string]string{"If-None-Match": etag})
+	a.Equal(http.StatusOK, w.Code)
+	a.NotEqual(etag, w.Header().Get("ETag"))
+	a.Contains(w.Body.String(), "https://go.dev")
+	a.Contains(w.Body.String(), "urn:linkboards:link:l-1")
+	a.Contains(w.Body.String(), "User One")
+
+	// feed token requires the user to be authenticated
+	w = get("/me/feedtoken", nil)
+	a.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func TestFeedTokenEndpoint(t *testing.T) {
+	a := assert.New(t)
+
+	feedTokens := auth.NewFeedTokens([]byte("secret"), tokens.NewInmemFeedTokenVersionStore())
+	ep := MakeFeedTokenEndpoint(feedTokens)
+	renewEp := MakeRenewFeedTokenEndpoint(feedTokens)
+
+	ctx := auth.ContextWithUser(context.Background(), auth.User{UserId: "u-1"})
+	r, err := ep(ctx, nil)
+	a.Nil(err)
+	a.Nil(r.(e.Response).Err)
+	token := r.(e.Response).R.(FeedToken).Token
+	userId, err := feedTokens.UserId(ctx, token)
+	a.Nil(err)
+	a.Equal("u-1", userId)
+
+	// renewing the token invalidates the previous one
+	r, err = renewEp(ctx, nil)
+	a.Nil(err)
+	a.Nil(r.(e.Response).Err)
+	newToken := r.(e.Response).R.(FeedToken).Token
+	a.NotEqual(token, newToken)
+	_, err = feedTokens.UserId(ctx, token)
+	a.True(errors.IsUnauthenticatedError(err))
+	userId, err = feedTokens.UserId(ctx, newToken)
+	a.Nil(err)
+	a.Equal("u-1", userId)
+
+	// a feed token is not restricted, it cannot be created or renewed using a restricted access token
+	restrictedCtx := auth.ContextWithRestrictions(ctx, auth.Restrictions{BoardIds: []string{"b-1"}})
+	r, err = ep(restrictedCtx, nil)
+	a.Nil(err)
+	a.True(errors.IsPermissionDeniedError(r.(e.Response).Err))
+	r, err = renewEp(restrictedCtx, nil)
+	a.Nil(err)
+	a.True(errors.IsPermissionDeniedError(r.(e.Response).Err))
+	_, err = feedTokens.UserId(ctx, newToken)
+	a.Nil(err)
+}
+
+func TestEtagMatches(t *testing.T) {
+	a := assert.New(t)
+
+	etag := `"abc"`
+	a.True(etagMatches(`"abc"`, etag))
+	a.True(etagMatches(`W/"abc"`, etag))
+	a.True(etagMatches(`"xyz", W/"abc"`, etag))
+	a.True(etagMatches(`"xyz",W/"abc" , "123"`, etag))
+	a.True(etagMatches(`*`, etag))
+	a.False(etagMatches(``, etag))
+	a.False(etagMatches(`"xyz"`, etag))
+	a.False(etagMatches(`"xyz", "abcd"`, etag))
+	a.False(etagMatches(`abc`, etag))
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	t.EncodeError(ctx, err, w)
+}
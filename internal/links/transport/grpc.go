@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/grpcutil"
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/links/transport/pb"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+)
+
+type grpcServer struct {
+	pb.UnimplementedLinkServiceServer
+
+	createLink kitgrpc.Handler
+	deleteLink kitgrpc.Handler
+	rateLink   kitgrpc.Handler
+	link       kitgrpc.Handler
+	links      kitgrpc.Handler
+}
+
+// Returns a gRPC server that uses the given endpoints, i.e. requests go through the same middlewares as http requests.
+func NewGRPCServer(endpoints EndpointSet, opts []kitgrpc.ServerOption) pb.LinkServiceServer {
+	return &grpcServer{
+		createLink: kitgrpc.NewServer(endpoints.CreateLinkEndpoint, decodeGRPCCreateLinkRequest, encodeGRPCLinkResponse, opts...),
+		deleteLink: kitgrpc.NewServer(endpoints.DeleteLinkEndpoint, decodeGRPCDeleteLinkRequest, encodeGRPCDeleteLinkResponse, opts...),
+		rateLink:   kitgrpc.NewServer(endpoints.RateLinkEndpoint, decodeGRPCRateLinkRequest, encodeGRPCRateLinkResponse, opts...),
+		link:       kitgrpc.NewServer(endpoints.LinkEndpoint, decodeGRPCLinkRequest, encodeGRPCLinkResponse, opts...),
+		links:      kitgrpc.NewServer(endpoints.LinksEndpoint, decodeGRPCLinksRequest, encodeGRPCLinksResponse, opts...),
+	}
+}
+
+func serveGRPC(ctx context.Context, h kitgrpc.Handler, req interface{}) (interface{}, error) {
+	_, resp, err := h.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.ErrorToStatus(err)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) CreateLink(ctx context.Context, req *pb.CreateLinkRequest) (*pb.Link, error) {
+	resp, err := serveGRPC(ctx, s.createLink, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Link), nil
+}
+
+func (s *grpcServer) DeleteLink(ctx context.Context, req *pb.DeleteLinkRequest) (*pb.DeleteLinkResponse, error) {
+	resp, err := serveGRPC(ctx, s.deleteLink, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.DeleteLinkResponse), nil
+}
+
+func (s *grpcServer) RateLink(ctx context.Context, req *pb.RateLinkRequest) (*pb.RateLinkResponse, error) {
+	resp, err := serveGRPC(ctx, s.rateLink, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.RateLinkResponse), nil
+}
+
+func (s *grpcServer) GetLink(ctx context.Context, req *pb.GetLinkRequest) (*pb.Link, error) {
+	resp, err := serveGRPC(ctx, s.link, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Link), nil
+}
+
+func (s *grpcServer) ListLinks(ctx context.Context, req *pb.ListLinksRequest) (*pb.ListLinksResponse, error) {
+	resp, err := serveGRPC(ctx, s.links, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListLinksResponse), nil
+}
+
+func decodeGRPCCreateLinkRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.CreateLinkRequest)
+	return CreateLinkRequest{
+		BoardId: req.BoardId,
+		Nl: application.NewLink{
+			Title: req.Title,
+			Url:   req.Url,
+			Tags:  req.Tags,
+		},
+	}, nil
+}
+
+func decodeGRPCDeleteLinkRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.DeleteLinkRequest)
+	return DeleteLinkRequest{
+		BoardId: req.BoardId,
+		LinkId:  req.LinkId,
+	}, nil
+}
+
+func decodeGRPCRateLinkRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.RateLinkRequest)
+	return RateLinkRequest{
+		BoardId: req.BoardId,
+		LinkId:  req.LinkId,
+		Lr:      application.LinkRating{Rating: int(req.Rating)},
+	}, nil
+}
+
+func decodeGRPCLinkRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.GetLinkRequest)
+	return LinkRequest{
+		BoardId: req.BoardId,
+		LinkId:  req.LinkId,
+	}, nil
+}
+
+func decodeGRPCLinksRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.ListLinksRequest)
+	qp := application.LinkQueryParams{
+		Limit:             int(req.Limit),
+		Sort:              req.Sort,
+		CursorCreatedTime: req.CursorCreatedTime,
+	}
+	if req.CursorScore != nil {
+		score := int(*req.CursorScore)
+		qp.CursorScore = &score
+	}
+	return LinksRequest{
+		BoardId: req.BoardId,
+		Qp:      qp,
+	}, nil
+}
+
+func encodeGRPCLinkResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return linkToPB(r.(application.Link)), nil
+}
+
+func encodeGRPCDeleteLinkResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteLinkResponse{}, nil
+}
+
+func encodeGRPCRateLinkResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	if _, err := grpcutil.UnwrapResponse(response); err != nil {
+		return nil, err
+	}
+	return &pb.RateLinkResponse{}, nil
+}
+
+func encodeGRPCLinksResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	r, err := grpcutil.UnwrapResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	links := r.([]application.Link)
+	result := &pb.ListLinksResponse{Links: make([]*pb.Link, len(links))}
+	for i, l := range links {
+		result.Links[i] = linkToPB(l)
+	}
+	return result, nil
+}
+
+func linkToPB(l application.Link) *pb.Link {
+	return &pb.Link{
+		BoardId:     l.BoardId,
+		LinkId:      l.LinkId,
+		Title:       l.Title,
+		Url:         l.Url,
+		CreatedTime: l.CreatedTime,
+		CreatedBy:   &pb.User{UserId: l.CreatedBy.UserId, Name: l.CreatedBy.Name},
+		Tags:        l.Tags,
+		Score:       int32(l.Score),
+		Upvotes:     int32(l.Upvotes),
+		Downvotes:   int32(l.Downvotes),
+		UserRating:  int32(l.UserRating),
+	}
+}
@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"testing"
+
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/grpcutil"
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/links/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/links/transport/pb"
+
+	"github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	a := assert.New(t)
+
+	svc := application.NewLinkApplicationService(inmem.NewInmemLinkDataStore(), &editorAuthorizationStore{}, nil, domain.LimitsConfig{}, nil)
+	mws := []endpoint.Middleware{middleware.NewFakeAuthEndpointMiddleware()}
+	endpoints := NewEndpoints(svc, Middlewares{
+		CreateLinkEndpoint: mws,
+		DeleteLinkEndpoint: mws,
+		RateLinkEndpoint:   mws,
+		LinkEndpoint:       mws,
+		LinksEndpoint:      mws,
+	})
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterLinkServiceServer(s, NewGRPCServer(endpoints, []kitgrpc.ServerOption{
+		kitgrpc.ServerBefore(middleware.GRPCAuthorizationToContext),
+	}))
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	a.Nil(err)
+	defer conn.Close()
+	client := pb.NewLinkServiceClient(conn)
+
+	withUser := func(userId string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userId+":pw")))
+	}
+	ctx := withUser("u-1")
+
+	// not authenticated
+	_, err = client.ListLinks(context.Background(), &pb.ListLinksRequest{BoardId: "b-1"})
+	a.Equal(codes.Unauthenticated, status.Code(err))
+
+	// not a member of the board
+	_, err = client.ListLinks(withUser("u-2"), &pb.ListLinksRequest{BoardId: "b-1"})
+	a.Equal(codes.PermissionDenied, status.Code(err))
+
+	link, err := client.CreateLink(ctx, &pb.CreateLinkRequest{BoardId: "b-1", Title: "Go", Url: "https://go.dev", Tags: []string{"go"}})
+	a.Nil(err)
+	a.Equal("Go", link.Title)
+	a.Equal([]string{"go"}, link.Tags)
+	a.Equal("u-1", link.CreatedBy.UserId)
+
+	// invalid urls return the public error code of the application service
+	_, err = client.CreateLink(ctx, &pb.CreateLinkRequest{BoardId: "b-1", Title: "Go", Url: "not a url"})
+	a.Equal(codes.InvalidArgument, status.Code(err))
+	a.NotZero(grpcutil.PublicCode(err))
+
+	_, err = client.RateLink(ctx, &pb.RateLinkRequest{BoardId: "b-1", LinkId: link.LinkId, Rating: 1})
+	a.Nil(err)
+
+	l, err := client.GetLink(ctx, &pb.GetLinkRequest{BoardId: "b-1", LinkId: link.LinkId})
+	a.Nil(err)
+	a.Equal(int32(1), l.Score)
+	a.Equal(int32(1), l.UserRating)
+
+	links, err := client.ListLinks(ctx, &pb.ListLinksRequest{BoardId: "b-1", Sort: "top"})
+	a.Nil(err)
+	a.Len(links.Links, 1)
+
+	_, err = client.DeleteLink(ctx, &pb.DeleteLinkRequest{BoardId: "b-1", LinkId: link.LinkId})
+	a.Nil(err)
+	_, err = client.GetLink(ctx, &pb.GetLinkRequest{BoardId: "b-1", LinkId: link.LinkId})
+	a.Equal(codes.NotFound, status.Code(err))
+}
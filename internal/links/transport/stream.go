@@ -0,0 +1,28 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/links/application"
+
+	e "github.com/dkinzler/kit/endpoint"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type AuthorizeLinkQueriesRequest struct {
+	BoardId string
+}
+
+// Checks if the user making the request is allowed to query the links of a board.
+// Is not exposed as a http handler, but used to authorize streams of board events.
+// Going through an endpoint makes sure that the same middlewares (e.g. authentication) are applied as for the other endpoints.
+func MakeAuthorizeLinkQueriesEndpoint(svc application.LinkApplicationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AuthorizeLinkQueriesRequest)
+		err := svc.AuthorizeLinkQueries(ctx, req.BoardId)
+		return e.Response{
+			Err: err,
+		}, nil
+	}
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/realtime"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+// Returns the commands that clients can send over a WebSocket connection.
+// Commands are dispatched to the given endpoints, i.e. the same middlewares apply as for http requests.
+//
+//   - createLink: the data of the message is a NewLink
+//   - rateLink: the data of the message is a LinkRating, the link is identified by the linkId of the message
+func NewWebSocketCommands(endpoints EndpointSet) map[string]realtime.Command {
+	return map[string]realtime.Command{
+		"createLink": {
+			Decode:   decodeWebSocketCreateLinkCommand,
+			Endpoint: endpoints.CreateLinkEndpoint,
+		},
+		"rateLink": {
+			Decode:   decodeWebSocketRateLinkCommand,
+			Endpoint: endpoints.RateLinkEndpoint,
+		},
+	}
+}
+
+func decodeWebSocketCreateLinkCommand(ctx context.Context, msg realtime.ClientMessage) (interface{}, error) {
+	var nl application.NewLink
+	if err := decodeCommandData(msg, &nl); err != nil {
+		return nil, err
+	}
+	return CreateLinkRequest{
+		BoardId: msg.BoardId,
+		Nl:      nl,
+	}, nil
+}
+
+func decodeWebSocketRateLinkCommand(ctx context.Context, msg realtime.ClientMessage) (interface{}, error) {
+	var lr application.LinkRating
+	if err := decodeCommandData(msg, &lr); err != nil {
+		return nil, err
+	}
+	return RateLinkRequest{
+		BoardId: msg.BoardId,
+		LinkId:  msg.LinkId,
+		Lr:      lr,
+	}, nil
+}
+
+func decodeCommandData(msg realtime.ClientMessage, v interface{}) error {
+	if len(msg.Data) == 0 {
+		return errors.New(nil, "WebSocketCommand", errors.InvalidArgument).WithPublicMessage("missing data")
+	}
+	if err := json.Unmarshal(msg.Data, v); err != nil {
+		return errors.New(err, "WebSocketCommand", errors.InvalidArgument).WithPublicMessage("invalid data")
+	}
+	return nil
+}
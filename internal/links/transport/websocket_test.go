@@ -0,0 +1,141 @@
+package transport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/auth/middleware"
+	"github.com/dkinzler/linkboards/internal/links/application"
+	"github.com/dkinzler/linkboards/internal/links/datastore/inmem"
+	"github.com/dkinzler/linkboards/internal/links/domain"
+	"github.com/dkinzler/linkboards/internal/realtime"
+
+	e "github.com/dkinzler/kit/endpoint"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type editorAuthorizationStore struct{}
+
+func (s *editorAuthorizationStore) Roles(ctx context.Context, boardId string, userId string) ([]string, error) {
+	if userId == "u-1" {
+		return []string{auth.BoardRoleEditor}, nil
+	}
+	return nil, nil
+}
+
+// Runs the WebSocket handler with the real application service and endpoints, authentication is performed by the fake auth middleware.
+func TestWebSocketCommandsAndEvents(t *testing.T) {
+	a := assert.New(t)
+
+	hub := realtime.NewHub(0)
+	defer hub.Close()
+	svc := application.NewLinkApplicationService(inmem.NewInmemLinkDataStore(), &editorAuthorizationStore{}, hub.LinkEventPublisher(), domain.LimitsConfig{}, nil)
+
+	mws := []endpoint.Middleware{middleware.NewFakeAuthEndpointMiddleware()}
+	endpoints := NewEndpoints(svc, Middlewares{
+		CreateLinkEndpoint: mws,
+		RateLinkEndpoint:   mws,
+	})
+	authorizeEndpoint := e.ApplyMiddlewares(MakeAuthorizeLinkQueriesEndpoint(svc), mws...)
+
+	router := mux.NewRouter()
+	router.Path("/ws").Handler(realtime.NewWebSocketHandler(hub, realtime.WebSocketConfig{
+		Authorize: func(ctx context.Context, boardId string) error {
+			response, err := authorizeEndpoint(ctx, AuthorizeLinkQueriesRequest{BoardId: boardId})
+			if err != nil {
+				return err
+			}
+			return response.(e.Responder).Error()
+		},
+		RequestFuncs: []kithttp.RequestFunc{kithttp.PopulateRequestContext},
+		Commands:     NewWebSocketCommands(endpoints),
+	}))
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	dial := func(userId, name string) *websocket.Conn {
+		header := http.Header{}
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userId+":"+name)))
+		ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+	read := func(ws *websocket.Conn) realtime.ServerMessage {
+		ws.SetReadDeadline(stdtime.Now().Add(2 * stdtime.Second))
+		var msg realtime.ServerMessage
+		if err := ws.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	ws := dial("u-1", "User One")
+	defer ws.Close()
+
+	a.Nil(ws.WriteJSON(realtime.ClientMessage{Id: "1", Type: realtime.MessageSubscribe, BoardId: "b-1"}))
+	a.Equal(realtime.MessageResult, read(ws).Type)
+
+	a.Nil(ws.WriteJSON(realtime.ClientMessage{
+		Id:      "2",
+		Type:    "createLink",
+		BoardId: "b-1",
+		Data:    json.RawMessage(`{"title":"Go","url":"https://go.dev"}`),
+	}))
+	// the event might be received before the result of the command
+	var linkId string
+	for i := 0; i < 2; i++ {
+		msg := read(ws)
+		if msg.Type == realtime.MessageResult {
+			a.Equal("2", msg.Id)
+			link := msg.Data.(map[string]interface{})
+			a.Equal("Go", link["title"])
+			linkId = link["linkId"].(string)
+		} else {
+			a.Equal(realtime.MessageEvent, msg.Type)
+			a.Equal(realtime.EventLinkCreated, msg.Event)
+		}
+	}
+	a.NotEmpty(linkId)
+
+	a.Nil(ws.WriteJSON(realtime.ClientMessage{Id: "3", Type: "rateLink", BoardId: "b-1", LinkId: linkId, Data: json.RawMessage(`{"rating":1}`)}))
+	for i := 0; i < 2; i++ {
+		msg := read(ws)
+		if msg.Type == realtime.MessageEvent {
+			a.Equal(realtime.EventLinkRated, msg.Event)
+		} else {
+			a.Equal(realtime.MessageResult, msg.Type)
+			a.Equal("3", msg.Id)
+		}
+	}
+
+	// invalid command data
+	a.Nil(ws.WriteJSON(realtime.ClientMessage{Id: "4", Type: "rateLink", BoardId: "b-1", LinkId: linkId, Data: json.RawMessage(`{"rating":5}`)}))
+	msg := read(ws)
+	a.Equal(realtime.MessageError, msg.Type)
+	a.Equal(http.StatusBadRequest, msg.Error.Status)
+
+	// user that is not a member of the board
+	other := dial("u-2", "User Two")
+	defer other.Close()
+	a.Nil(other.WriteJSON(realtime.ClientMessage{Id: "1", Type: realtime.MessageSubscribe, BoardId: "b-1"}))
+	msg = read(other)
+	a.Equal(realtime.MessageError, msg.Type)
+	a.Equal(http.StatusForbidden, msg.Error.Status)
+	a.Nil(other.WriteJSON(realtime.ClientMessage{Id: "2", Type: "createLink", BoardId: "b-1", Data: json.RawMessage(`{"title":"Go","url":"https://go.dev"}`)}))
+	msg = read(other)
+	a.Equal(http.StatusForbidden, msg.Error.Status)
+}
@@ -0,0 +1,154 @@
+// Package metrics collects Prometheus metrics for the endpoints and data stores of the boards and links components.
+//
+// For every endpoint the number of requests by error code and the latency of requests are recorded, see Metrics.EndpointMiddleware.
+// Data stores can be instrumented with ObserveDataStoreCall to record the latency of calls and the number of optimistic transaction conflicts,
+// i.e. updates that failed because the data was changed concurrently.
+// Use Handler to expose the metrics on an HTTP endpoint, e.g. "/metrics", that requires a bearer token.
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	stdtime "time"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "linkboards"
+
+// Value of the "code" label for requests and data store calls that succeeded.
+const codeOK = "OK"
+
+type Metrics struct {
+	requests             *prometheus.CounterVec
+	requestDuration      *prometheus.HistogramVec
+	dataStoreDuration    *prometheus.HistogramVec
+	transactionConflicts *prometheus.CounterVec
+}
+
+// Creates the metrics and registers them with the given registerer.
+// Returns an error if metrics with the same names were already registered.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "endpoint",
+			Name:      "requests_total",
+			Help:      "Number of requests by component, endpoint and error code.",
+		}, []string{"component", "endpoint", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "endpoint",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests by component and endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"component", "endpoint"}),
+		dataStoreDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "datastore",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of data store calls by data store, method and error code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"datastore", "method", "code"}),
+		transactionConflicts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "datastore",
+			Name:      "transaction_conflicts_total",
+			Help:      "Number of optimistic transactions that failed because the data was modified concurrently.",
+		}, []string{"datastore", "method"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.requests, m.requestDuration, m.dataStoreDuration, m.transactionConflicts} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Returns a middleware that records the number of requests, their error codes and latency for the given endpoint.
+// Errors returned by the endpoint as well as errors contained in responses (see package "github.com/dkinzler/kit/endpoint") are counted.
+// To include errors of the authentication middleware, it should be applied outside of it.
+func (m *Metrics) EndpointMiddleware(component, endpointName string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			start := time.CurrTime()
+			response, err := next(ctx, request)
+
+			resultErr := err
+			if r, ok := response.(e.Responder); ok && err == nil {
+				resultErr = r.Error()
+			}
+			m.requests.WithLabelValues(component, endpointName, errorCode(resultErr)).Inc()
+			m.requestDuration.WithLabelValues(component, endpointName).Observe(time.CurrTime().Sub(start).Seconds())
+
+			return response, err
+		}
+	}
+}
+
+// Records the latency of a data store call that started at the given time and returned the given error.
+// If the error indicates that an optimistic transaction failed, a conflict is counted.
+//
+// Use it with defer at the start of a data store method:
+//
+//	defer m.ObserveDataStoreCall("boards", "UpdateBoard", time.CurrTime(), &err)
+func (m *Metrics) ObserveDataStoreCall(dataStore, method string, start stdtime.Time, err *error) {
+	var callErr error
+	if err != nil {
+		callErr = *err
+	}
+	m.dataStoreDuration.WithLabelValues(dataStore, method, errorCode(callErr)).Observe(time.CurrTime().Sub(start).Seconds())
+	if isTransactionConflict(callErr) {
+		m.transactionConflicts.WithLabelValues(dataStore, method).Inc()
+	}
+}
+
+// Returns a label value for the code of the given error, e.g. "NotFound" or "InvalidArgument".
+func errorCode(err error) string {
+	if err == nil {
+		return codeOK
+	}
+	if e, ok := err.(errors.Error); ok {
+		return e.Code.String()
+	}
+	return errors.Unknown.String()
+}
+
+// Transactions that use transaction expectations fail with FailedPrecondition if the data was modified (see domain.TransactionExpectation),
+// firestore transactions can also fail with Aborted if there was contention on a document.
+func isTransactionConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.IsFailedPreconditionError(err) || errors.IsAbortedError(err) {
+		return true
+	}
+	return status.Code(err) == codes.Aborted
+}
+
+// Returns a handler that serves the metrics collected by the given gatherer in the Prometheus text format.
+// Metrics reveal internals like the endpoints used and their error rates, requests must therefore send the given token
+// in an Authorization header "Bearer <token>", otherwise an error with status 401 is returned.
+// The token must not be empty.
+func Handler(g prometheus.Gatherer, token string) http.Handler {
+	metricsHandler := promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			t.EncodeError(r.Context(), errors.New(nil, "metrics", errors.Unauthenticated).WithPublicMessage("invalid metrics token"), w)
+			return
+		}
+		metricsHandler.ServeHTTP(w, r)
+	})
+}
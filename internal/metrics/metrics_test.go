@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	stdtime "time"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEndpointMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	m, err := New(prometheus.NewRegistry())
+	a.Nil(err)
+
+	var response interface{}
+	var responseErr error
+	endpoint := m.EndpointMiddleware("links", "createLink")(func(ctx context.Context, request interface{}) (interface{}, error) {
+		return response, responseErr
+	})
+
+	response = e.Response{R: "ok"}
+	endpoint(context.Background(), nil)
+	endpoint(context.Background(), nil)
+	// errors contained in responses are counted
+	response = e.Response{Err: errors.New(nil, "test", errors.InvalidArgument)}
+	endpoint(context.Background(), nil)
+	// as well as errors returned by the endpoint, e.g. by the authentication middleware
+	response, responseErr = nil, errors.New(nil, "test", errors.Unauthenticated)
+	endpoint(context.Background(), nil)
+	responseErr = assert.AnError
+	endpoint(context.Background(), nil)
+
+	a.Equal(2.0, testutil.ToFloat64(m.requests.WithLabelValues("links", "createLink", "OK")))
+	a.Equal(1.0, testutil.ToFloat64(m.requests.WithLabelValues("links", "createLink", "InvalidArgument")))
+	a.Equal(1.0, testutil.ToFloat64(m.requests.WithLabelValues("links", "createLink", "Unauthenticated")))
+	a.Equal(1.0, testutil.ToFloat64(m.requests.WithLabelValues("links", "createLink", "Unknown")))
+	a.Equal(1, testutil.CollectAndCount(m.requestDuration))
+
+	// metrics can only be registered once
+	reg := prometheus.NewRegistry()
+	_, err = New(reg)
+	a.Nil(err)
+	_, err = New(reg)
+	a.NotNil(err)
+}
+
+func TestObserveDataStoreCall(t *testing.T) {
+	a := assert.New(t)
+	now := stdtime.Date(2022, 6, 1, 12, 0, 0, 0, stdtime.UTC)
+	time.TimeFunc = func() stdtime.Time {
+		return now
+	}
+
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	a.Nil(err)
+
+	call := func(method string, callErr error) {
+		defer m.ObserveDataStoreCall("boards", method, time.CurrTime(), &callErr)
+		now = now.Add(100 * stdtime.Millisecond)
+	}
+
+	call("Board", nil)
+	call("UpdateBoard", nil)
+	call("UpdateBoard", errors.New(nil, "test", errors.FailedPrecondition))
+	call("UpdateBoard", errors.New(nil, "test", errors.Internal))
+	call("UpdateRating", status.Error(codes.Aborted, "contention"))
+
+	a.Equal(1.0, testutil.ToFloat64(m.transactionConflicts.WithLabelValues("boards", "UpdateBoard")))
+	a.Equal(1.0, testutil.ToFloat64(m.transactionConflicts.WithLabelValues("boards", "UpdateRating")))
+	a.Equal(5, testutil.CollectAndCount(m.dataStoreDuration))
+
+	// metrics are served in the Prometheus text format, if the request contains the token
+	handler := Handler(reg, "metrics-token")
+	for _, authorization := range []string{"", "Bearer", "Bearer other-token", "metrics-token"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		r.Header.Set("Authorization", authorization)
+		handler.ServeHTTP(w, r)
+		a.Equal(401, w.Code, authorization)
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set("Authorization", "Bearer metrics-token")
+	handler.ServeHTTP(w, r)
+	a.Equal(200, w.Code)
+	body := w.Body.String()
+	a.True(strings.Contains(body, `linkboards_datastore_call_duration_seconds_sum{code="OK",datastore="boards",method="Board"} 0.1`))
+	a.True(strings.Contains(body, `linkboards_datastore_transaction_conflicts_total{datastore="boards",method="UpdateBoard"} 1`))
+}
@@ -0,0 +1,51 @@
+// Package paging reads all results of a data store query that returns its results page by page,
+// e.g. to export all links of a board or to search all boards.
+package paging
+
+import (
+	"context"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+// Query returns the page of items that follows last, the last item of the previous page, or the first page if last is nil.
+// Pages contain at most pageSize items (see Each) and must be sorted in the order used by the cursor.
+type Query[T any] func(ctx context.Context, last *T) ([]T, error)
+
+// Calls f for every item returned by query, until f returns false or there are no more items.
+//
+// The cursor derived from the last item of a page may be inclusive, e.g. "created at or before the created time of last",
+// such that items that share the cursor value with the last item are not skipped.
+// Items that are returned again are recognized by their id and skipped.
+// If a full page contains only items that were already returned, the cursor cannot make progress and an error is returned.
+func Each[T any](ctx context.Context, pageSize int, query Query[T], id func(T) string, f func(T) bool) error {
+	seen := make(map[string]struct{})
+	var last *T
+	for {
+		page, err := query(ctx, last)
+		if err != nil {
+			return err
+		}
+
+		newItems := 0
+		for _, item := range page {
+			if _, ok := seen[id(item)]; ok {
+				continue
+			}
+			seen[id(item)] = struct{}{}
+			newItems++
+			if !f(item) {
+				return nil
+			}
+		}
+
+		if len(page) < pageSize {
+			return nil
+		}
+		if newItems == 0 {
+			return errors.New(nil, "paging", errors.Internal).
+				WithInternalMessage("more items than fit on a page share the same cursor value")
+		}
+		last = &page[len(page)-1]
+	}
+}
@@ -0,0 +1,71 @@
+package paging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	id          string
+	createdTime int64
+}
+
+// Returns a query over the given items sorted by descending created time, that uses the created time as inclusive cursor.
+func testQuery(items []item, pageSize int) Query[item] {
+	return func(ctx context.Context, last *item) ([]item, error) {
+		var page []item
+		for _, i := range items {
+			if last != nil && i.createdTime > last.createdTime {
+				continue
+			}
+			page = append(page, i)
+			if len(page) == pageSize {
+				break
+			}
+		}
+		return page, nil
+	}
+}
+
+func itemId(i item) string {
+	return i.id
+}
+
+func TestEach(t *testing.T) {
+	a := assert.New(t)
+
+	// every created time is shared by 3 items, so pages end in the middle of a created time
+	var items []item
+	for i := 0; i < 20; i++ {
+		items = append(items, item{id: fmt.Sprint(i), createdTime: int64(100 - i/3)})
+	}
+
+	var ids []string
+	err := Each(context.Background(), 5, testQuery(items, 5), itemId, func(i item) bool {
+		ids = append(ids, i.id)
+		return true
+	})
+	a.Nil(err)
+	a.Len(ids, 20)
+	for i, id := range ids {
+		a.Equal(fmt.Sprint(i), id)
+	}
+
+	// iteration stops once f returns false
+	ids = nil
+	err = Each(context.Background(), 5, testQuery(items, 5), itemId, func(i item) bool {
+		ids = append(ids, i.id)
+		return len(ids) < 7
+	})
+	a.Nil(err)
+	a.Len(ids, 7)
+
+	// a full page of items with the same created time, the cursor cannot make progress
+	err = Each(context.Background(), 2, testQuery(items, 2), itemId, func(i item) bool {
+		return true
+	})
+	a.NotNil(err)
+}
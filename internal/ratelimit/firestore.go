@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/docstore"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	bucketsCollectionName = "rateLimitBuckets"
+	quotasCollectionName  = "dailyQuotas"
+)
+
+// Buckets and quota counters of different instances of the application are updated in the same document,
+// transactions that fail because of contention are therefore retried.
+const maxTransactionAttempts = 5
+
+// FirestoreStore keeps token buckets in Firestore, every bucket is stored in its own document with the key as document id.
+// Buckets are shared by all instances of the application.
+//
+// Documents contain the time after which the bucket is full again in the field "expiresTime",
+// a Firestore TTL policy on this field can be used to delete buckets that are no longer needed.
+type FirestoreStore struct {
+	store docstore.Store
+}
+
+func NewFirestoreStore(client *firestore.Client) *FirestoreStore {
+	return newFirestoreStore(docstore.NewFirestore(client))
+}
+
+// Tests use an in-memory docstore.Fake instead of a firestore client.
+func newFirestoreStore(store docstore.Store) *FirestoreStore {
+	return &FirestoreStore{store: store}
+}
+
+type fsBucket struct {
+	Tokens float64 `firestore:"tokens"`
+	// Last time tokens were added to the bucket, in nanoseconds since the unix epoch.
+	Updated     int64        `firestore:"updated"`
+	ExpiresTime stdtime.Time `firestore:"expiresTime"`
+}
+
+func (s *FirestoreStore) Take(ctx context.Context, key string, limit Limit) (bool, stdtime.Duration, error) {
+	ref := docstore.DocRef{Collection: bucketsCollectionName, Id: key}
+
+	var ok bool
+	var retryAfter stdtime.Duration
+	err := runTransaction(ctx, s.store, func(ctx context.Context, t docstore.Transaction) error {
+		now := time.CurrTime()
+		b := bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+
+		doc, err := t.Get(ref)
+		if err != nil && !errors.IsNotFoundError(err) {
+			return err
+		}
+		if err == nil {
+			var fb fsBucket
+			if err := doc.DataTo(&fb); err != nil {
+				return err
+			}
+			b.tokens = fb.Tokens
+			b.updated = stdtime.Unix(0, fb.Updated)
+		}
+		b.refill(now)
+
+		ok, retryAfter = b.take()
+		return t.Set(ref, fsBucket{
+			Tokens:      b.tokens,
+			Updated:     b.updated.UnixNano(),
+			ExpiresTime: b.fullAt(),
+		})
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	return ok, retryAfter, nil
+}
+
+// FirestoreQuotaStore counts how often users performed an action on the current day (UTC) in Firestore,
+// the counter of a key and day is stored in a document with id "<day>:<key>", e.g. "2022-06-01:createBoard:u-123".
+// It implements the QuotaStore interfaces of the domain packages, quotas are shared by all instances of the application.
+//
+// Documents contain the start of the next day in the field "expiresTime",
+// a Firestore TTL policy on this field can be used to delete counters of previous days.
+type FirestoreQuotaStore struct {
+	store docstore.Store
+}
+
+func NewFirestoreQuotaStore(client *firestore.Client) *FirestoreQuotaStore {
+	return newFirestoreQuotaStore(docstore.NewFirestore(client))
+}
+
+func newFirestoreQuotaStore(store docstore.Store) *FirestoreQuotaStore {
+	return &FirestoreQuotaStore{store: store}
+}
+
+type fsQuota struct {
+	Count       int          `firestore:"count"`
+	ExpiresTime stdtime.Time `firestore:"expiresTime"`
+}
+
+// Increments the counter with the given key if it is below max.
+// If the quota is used up, false is returned together with the duration until the start of the next day (UTC).
+func (s *FirestoreQuotaStore) Use(ctx context.Context, key string, max int) (bool, stdtime.Duration, error) {
+	var ok bool
+	var retryAfter stdtime.Duration
+	err := s.update(ctx, key, func(count int) (int, bool) {
+		if count >= max {
+			retryAfter = untilNextDay()
+			return count, false
+		}
+		ok = true
+		return count + 1, true
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	return ok, retryAfter, nil
+}
+
+// Decrements the counter with the given key, e.g. if the action could not be completed.
+func (s *FirestoreQuotaStore) Refund(ctx context.Context, key string) error {
+	return s.update(ctx, key, func(count int) (int, bool) {
+		if count > 0 {
+			return count - 1, true
+		}
+		return count, false
+	})
+}
+
+// Reads the counter of the current day in a transaction and writes the value returned by f if it returns true.
+func (s *FirestoreQuotaStore) update(ctx context.Context, key string, f func(count int) (int, bool)) error {
+	return runTransaction(ctx, s.store, func(ctx context.Context, t docstore.Transaction) error {
+		now := time.CurrTime().UTC()
+		ref := docstore.DocRef{Collection: quotasCollectionName, Id: now.Format("2006-01-02") + ":" + key}
+
+		var q fsQuota
+		doc, err := t.Get(ref)
+		if err != nil && !errors.IsNotFoundError(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&q); err != nil {
+				return err
+			}
+		}
+
+		count, write := f(q.Count)
+		if !write {
+			return nil
+		}
+		return t.Set(ref, fsQuota{Count: count, ExpiresTime: now.Add(untilNextDay())})
+	})
+}
+
+// Runs f in a transaction, retries the transaction if it failed because of contention.
+func runTransaction(ctx context.Context, store docstore.Store, f func(ctx context.Context, t docstore.Transaction) error) error {
+	var err error
+	for i := 0; i < maxTransactionAttempts; i++ {
+		err = store.RunTransaction(ctx, f)
+		if status.Code(err) != codes.Aborted {
+			return err
+		}
+	}
+	return err
+}
+
+// Returns the time after which the bucket is full again.
+func (b *bucket) fullAt() stdtime.Time {
+	missing := float64(b.limit.Burst) - b.tokens
+	if missing <= 0 || b.limit.Rate <= 0 {
+		return b.updated
+	}
+	return b.updated.Add(stdtime.Duration(math.Ceil(missing / b.limit.Rate * float64(stdtime.Second))))
+}
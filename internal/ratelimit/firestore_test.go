@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/docstore"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirestoreStore(t *testing.T) {
+	a := assert.New(t)
+	advance := initTestTime()
+	ctx := context.Background()
+
+	fake := docstore.NewFake()
+	s := newFirestoreStore(fake)
+	limit := Limit{Rate: 1, Burst: 2}
+
+	ok, _, err := s.Take(ctx, "a", limit)
+	a.Nil(err)
+	a.True(ok)
+
+	// buckets are shared by all instances of the application
+	other := newFirestoreStore(fake)
+	ok, _, _ = other.Take(ctx, "a", limit)
+	a.True(ok)
+	ok, retryAfter, _ := s.Take(ctx, "a", limit)
+	a.False(ok)
+	a.Equal(stdtime.Second, retryAfter)
+
+	// other keys have their own bucket
+	ok, _, _ = s.Take(ctx, "b", limit)
+	a.True(ok)
+
+	advance(500 * stdtime.Millisecond)
+	ok, retryAfter, _ = other.Take(ctx, "a", limit)
+	a.False(ok)
+	a.Equal(500*stdtime.Millisecond, retryAfter)
+
+	advance(500 * stdtime.Millisecond)
+	ok, _, _ = s.Take(ctx, "a", limit)
+	a.True(ok)
+
+	doc, err := fake.Get(ctx, docstore.DocRef{Collection: bucketsCollectionName, Id: "a"})
+	a.Nil(err)
+	var b fsBucket
+	a.Nil(doc.DataTo(&b))
+	a.Equal(testTime.Add(3*stdtime.Second), b.ExpiresTime)
+
+	// buckets are refilled at most up to the burst
+	advance(stdtime.Hour)
+	for i := 0; i < 2; i++ {
+		ok, _, _ = s.Take(ctx, "a", limit)
+		a.True(ok)
+	}
+	ok, _, _ = s.Take(ctx, "a", limit)
+	a.False(ok)
+}
+
+func TestFirestoreQuotaStore(t *testing.T) {
+	a := assert.New(t)
+	advance := initTestTime()
+	ctx := context.Background()
+
+	fake := docstore.NewFake()
+	s := newFirestoreQuotaStore(fake)
+	other := newFirestoreQuotaStore(fake)
+
+	for i := 0; i < 3; i++ {
+		ok, _, err := s.Use(ctx, "a", 3)
+		a.Nil(err)
+		a.True(ok)
+	}
+	// quotas are shared by all instances of the application
+	ok, retryAfter, _ := other.Use(ctx, "a", 3)
+	a.False(ok)
+	a.Equal(2*stdtime.Hour, retryAfter)
+	ok, _, _ = s.Use(ctx, "b", 3)
+	a.True(ok)
+
+	// refunded uses can be used again
+	a.Nil(other.Refund(ctx, "a"))
+	ok, _, _ = s.Use(ctx, "a", 3)
+	a.True(ok)
+	ok, _, _ = s.Use(ctx, "a", 3)
+	a.False(ok)
+
+	// refunding a counter that was not used has no effect
+	a.Nil(s.Refund(ctx, "c"))
+	_, err := fake.Get(ctx, docstore.DocRef{Collection: quotasCollectionName, Id: "2022-06-01:c"})
+	a.NotNil(err)
+
+	doc, err := fake.Get(ctx, docstore.DocRef{Collection: quotasCollectionName, Id: "2022-06-01:a"})
+	a.Nil(err)
+	var q fsQuota
+	a.Nil(doc.DataTo(&q))
+	a.Equal(fsQuota{Count: 3, ExpiresTime: testTime.Add(2 * stdtime.Hour)}, q)
+
+	// quotas are reset at the start of the next day
+	advance(2 * stdtime.Hour)
+	ok, _, _ = s.Use(ctx, "a", 3)
+	a.True(ok)
+}
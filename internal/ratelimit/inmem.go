@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	stdtime "time"
+
+	"github.com/dkinzler/kit/time"
+)
+
+// How often buckets that are full, i.e. that have not been used for a while, are removed from an InmemStore.
+const cleanupInterval = stdtime.Minute
+
+// InmemStore keeps token buckets in memory, it limits requests per instance of the application.
+// Use FirestoreStore to share buckets between instances.
+type InmemStore struct {
+	m           sync.Mutex
+	buckets     map[string]*bucket
+	lastCleanup stdtime.Time
+}
+
+type bucket struct {
+	tokens float64
+	// Last time tokens were added to the bucket.
+	updated stdtime.Time
+	limit   Limit
+}
+
+func NewInmemStore() *InmemStore {
+	return &InmemStore{
+		buckets:     make(map[string]*bucket),
+		lastCleanup: time.CurrTime(),
+	}
+}
+
+func (s *InmemStore) Take(ctx context.Context, key string, limit Limit) (bool, stdtime.Duration, error) {
+	now := time.CurrTime()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if now.Sub(s.lastCleanup) >= cleanupInterval {
+		s.cleanup(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	ok, retryAfter := b.take()
+	return ok, retryAfter, nil
+}
+
+// Takes a token from the bucket, if it is empty false is returned together with the duration after which a token will be available.
+func (b *bucket) take() (bool, stdtime.Duration) {
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	if b.limit.Rate <= 0 {
+		return false, stdtime.Hour
+	}
+	missing := 1 - b.tokens
+	return false, stdtime.Duration(missing / b.limit.Rate * float64(stdtime.Second))
+}
+
+func (b *bucket) refill(now stdtime.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.limit.Rate
+		if max := float64(b.limit.Burst); b.tokens > max {
+			b.tokens = max
+		}
+	}
+	b.updated = now
+}
+
+// Removes full buckets, they behave the same as buckets that don't exist.
+func (s *InmemStore) cleanup(now stdtime.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastCleanup = now
+}
+
+// InmemQuotaStore counts how often users performed an action on the current day (UTC), e.g. how many boards they created.
+// It implements the QuotaStore interfaces of the domain packages, quotas are enforced per instance of the application.
+// Use FirestoreQuotaStore to share quotas between instances.
+type InmemQuotaStore struct {
+	m      sync.Mutex
+	day    string
+	counts map[string]int
+}
+
+func NewInmemQuotaStore() *InmemQuotaStore {
+	return &InmemQuotaStore{
+		counts: make(map[string]int),
+	}
+}
+
+// Increments the counter with the given key if it is below max.
+// If the quota is used up, false is returned together with the duration until the start of the next day (UTC).
+func (s *InmemQuotaStore) Use(ctx context.Context, key string, max int) (bool, stdtime.Duration, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.resetIfNewDay()
+
+	if s.counts[key] >= max {
+		return false, untilNextDay(), nil
+	}
+	s.counts[key]++
+	return true, 0, nil
+}
+
+// Decrements the counter with the given key, e.g. if the action could not be completed.
+func (s *InmemQuotaStore) Refund(ctx context.Context, key string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.resetIfNewDay()
+
+	if s.counts[key] > 0 {
+		s.counts[key]--
+	}
+	return nil
+}
+
+// Counters of previous days are no longer needed.
+func (s *InmemQuotaStore) resetIfNewDay() {
+	day := time.CurrTime().UTC().Format("2006-01-02")
+	if day != s.day {
+		s.day = day
+		s.counts = make(map[string]int)
+	}
+}
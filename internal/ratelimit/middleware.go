@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/endpointname"
+
+	e "github.com/dkinzler/kit/endpoint"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Limits maps endpoint names (e.g. "createBoard" or "createLink") to the limit for that endpoint.
+type Limits map[string]Limit
+
+// Returns a middleware that limits the rate of requests per authenticated user and endpoint.
+// The middleware can be added to the Middlewares of the boards and links components, which store the name of an endpoint in the context
+// (see package endpointname), requests to endpoints without a limit are not limited.
+// Requests without an authenticated user are not limited either, they are handled by the authentication middleware,
+// which runs before this middleware.
+//
+// If the store returns an error, the request is allowed, a failing store should not make the whole API unavailable.
+//
+// Errors caused by exceeded daily quotas that are returned by the application services are returned as errors of the endpoint,
+// so that they are encoded the same way as errors for exceeded rate limits.
+func NewEndpointMiddleware(store Store, limits Limits) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			name, _ := endpointname.FromContext(ctx)
+			limit, hasLimit := limits[name]
+			user, authenticated := auth.UserFromContext(ctx)
+
+			if hasLimit && authenticated {
+				ok, retryAfter, err := store.Take(ctx, name+":"+user.UserId, limit)
+				if err == nil && !ok {
+					return nil, newRateLimitError(retryAfter)
+				}
+			}
+
+			response, err := next(ctx, request)
+			if r, ok := response.(e.Responder); ok && err == nil {
+				if _, exceeded := RetryAfter(r.Error()); exceeded {
+					return nil, r.Error()
+				}
+			}
+			return response, err
+		}
+	}
+}
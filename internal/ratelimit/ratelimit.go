@@ -0,0 +1,127 @@
+// Package ratelimit limits how often users can call endpoints and how many entities they can create per day.
+//
+// Requests are limited per user and endpoint using the token bucket algorithm, see NewEndpointMiddleware.
+// Every bucket holds up to Limit.Burst tokens and is refilled with Limit.Rate tokens per second,
+// a request takes one token from the bucket and is rejected if the bucket is empty.
+//
+// Daily quotas, e.g. the number of boards a user can create per day, are enforced by the domain services,
+// InmemQuotaStore and FirestoreQuotaStore implement the QuotaStore interfaces they define.
+//
+// Errors returned when a limit or quota is exceeded contain the time after which the request can be retried, see RetryAfter.
+// Use EncodeError to send them as HTTP responses with status 429 and a Retry-After header.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	stdtime "time"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+	t "github.com/dkinzler/kit/transport/http"
+)
+
+type Limit struct {
+	// Number of tokens added to a bucket per second.
+	Rate float64
+	// Maximum number of tokens in a bucket, i.e. the number of requests that can be made at once.
+	Burst int
+}
+
+// Returns a limit that allows n requests per minute, all of which can be made at once.
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Store contains the token buckets used to limit requests.
+// The in-memory implementation limits requests per instance of the application,
+// FirestoreStore shares buckets between all instances.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Takes a token from the bucket with the given key.
+	// If the bucket is empty, false is returned together with the duration after which a token will be available.
+	Take(ctx context.Context, key string, limit Limit) (bool, stdtime.Duration, error)
+}
+
+// Returned when a rate limit is exceeded, wrapped in an error from package "github.com/dkinzler/kit/errors".
+type limitExceededError struct {
+	retryAfter stdtime.Duration
+}
+
+func (e limitExceededError) Error() string {
+	return "limit exceeded, retry after " + e.retryAfter.String()
+}
+
+func (e limitExceededError) RetryAfter() stdtime.Duration {
+	return e.retryAfter
+}
+
+// Implemented by the errors that are returned when a limit or quota is exceeded,
+// e.g. by the errors of the domain services for exceeded daily quotas.
+type retryableError interface {
+	RetryAfter() stdtime.Duration
+}
+
+func newRateLimitError(retryAfter stdtime.Duration) errors.Error {
+	return errors.New(limitExceededError{retryAfter: retryAfter}, "ratelimit", errors.Unavailable).WithPublicMessage("rate limit exceeded")
+}
+
+// Returns the duration until the start of the next day (UTC).
+func untilNextDay() stdtime.Duration {
+	now := time.CurrTime().UTC()
+	next := stdtime.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, stdtime.UTC)
+	return next.Sub(now)
+}
+
+// Returns the duration after which the request that failed with the given error can be retried,
+// or false if the error was not caused by an exceeded limit or quota.
+// An error is caused by an exceeded limit or quota if it or one of its inner errors has a method RetryAfter() time.Duration.
+func RetryAfter(err error) (stdtime.Duration, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case retryableError:
+			return e.RetryAfter(), true
+		case errors.Error:
+			err = e.Inner
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// Encodes errors caused by exceeded limits or quotas as responses with status 429 (Too Many Requests)
+// and a Retry-After header, other errors are encoded using EncodeError from package "github.com/dkinzler/kit/transport/http".
+// Like the other error responses, the body contains the public code and message of the error.
+func EncodeError(ctx context.Context, err error, w http.ResponseWriter) error {
+	retryAfter, ok := RetryAfter(err)
+	if !ok {
+		return t.EncodeError(ctx, err, w)
+	}
+
+	// Retry-After is specified in whole seconds
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusTooManyRequests)
+
+	body := jsonError{Message: "rate limit exceeded"}
+	if e, ok := err.(errors.Error); ok {
+		body.Code = e.PublicCode
+		if e.PublicMessage != "" {
+			body.Message = e.PublicMessage
+		}
+	}
+	return json.NewEncoder(w).Encode(map[string]jsonError{"error": body})
+}
+
+type jsonError struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
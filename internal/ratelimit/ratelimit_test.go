@@ -0,0 +1,215 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/linkboards/internal/auth"
+	"github.com/dkinzler/linkboards/internal/endpointname"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTime = stdtime.Date(2022, 6, 1, 22, 0, 0, 0, stdtime.UTC)
+
+// Sets the current time to testTime, the returned function can be used to advance the time.
+func initTestTime() func(stdtime.Duration) {
+	now := testTime
+	time.TimeFunc = func() stdtime.Time {
+		return now
+	}
+	return func(d stdtime.Duration) {
+		now = now.Add(d)
+	}
+}
+
+func TestInmemStore(t *testing.T) {
+	a := assert.New(t)
+	advance := initTestTime()
+	ctx := context.Background()
+
+	s := NewInmemStore()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	ok, _, err := s.Take(ctx, "a", limit)
+	a.Nil(err)
+	a.True(ok)
+	ok, _, _ = s.Take(ctx, "a", limit)
+	a.True(ok)
+
+	// bucket is empty
+	ok, retryAfter, _ := s.Take(ctx, "a", limit)
+	a.False(ok)
+	a.Equal(stdtime.Second, retryAfter)
+
+	// other keys have their own bucket
+	ok, _, _ = s.Take(ctx, "b", limit)
+	a.True(ok)
+
+	advance(500 * stdtime.Millisecond)
+	ok, retryAfter, _ = s.Take(ctx, "a", limit)
+	a.False(ok)
+	a.Equal(500*stdtime.Millisecond, retryAfter)
+
+	advance(500 * stdtime.Millisecond)
+	ok, _, _ = s.Take(ctx, "a", limit)
+	a.True(ok)
+
+	// buckets are refilled at most up to the burst
+	advance(stdtime.Hour)
+	for i := 0; i < 2; i++ {
+		ok, _, _ = s.Take(ctx, "a", limit)
+		a.True(ok)
+	}
+	ok, _, _ = s.Take(ctx, "a", limit)
+	a.False(ok)
+
+	// full buckets are removed
+	advance(cleanupInterval)
+	s.Take(ctx, "c", limit)
+	a.Len(s.buckets, 1)
+}
+
+func TestInmemQuotaStore(t *testing.T) {
+	a := assert.New(t)
+	advance := initTestTime()
+	ctx := context.Background()
+
+	s := NewInmemQuotaStore()
+
+	for i := 0; i < 3; i++ {
+		ok, _, err := s.Use(ctx, "a", 3)
+		a.Nil(err)
+		a.True(ok)
+	}
+	ok, retryAfter, _ := s.Use(ctx, "a", 3)
+	a.False(ok)
+	a.Equal(2*stdtime.Hour, retryAfter)
+	ok, _, _ = s.Use(ctx, "b", 3)
+	a.True(ok)
+
+	// refunded uses can be used again
+	a.Nil(s.Refund(ctx, "a"))
+	ok, _, _ = s.Use(ctx, "a", 3)
+	a.True(ok)
+	ok, _, _ = s.Use(ctx, "a", 3)
+	a.False(ok)
+
+	// quotas are reset at the start of the next day
+	advance(2 * stdtime.Hour)
+	ok, _, _ = s.Use(ctx, "a", 3)
+	a.True(ok)
+}
+
+// Like the errors returned by the domain services for exceeded quotas.
+type quotaExceededError struct{}
+
+func (e quotaExceededError) Error() string {
+	return "quota exceeded"
+}
+
+func (e quotaExceededError) RetryAfter() stdtime.Duration {
+	return untilNextDay()
+}
+
+func newQuotaExceededError(publicCode int, publicMessage string) errors.Error {
+	return errors.New(quotaExceededError{}, "test", errors.FailedPrecondition).WithPublicCode(publicCode).WithPublicMessage(publicMessage)
+}
+
+func TestRetryAfter(t *testing.T) {
+	a := assert.New(t)
+	initTestTime()
+
+	_, ok := RetryAfter(errors.New(nil, "test", errors.Internal))
+	a.False(ok)
+	_, ok = RetryAfter(nil)
+	a.False(ok)
+
+	retryAfter, ok := RetryAfter(errors.New(newRateLimitError(3*stdtime.Second), "test", errors.Unavailable))
+	a.True(ok)
+	a.Equal(3*stdtime.Second, retryAfter)
+
+	err := newQuotaExceededError(21, "daily quota reached")
+	a.True(errors.HasPublicCode(err, 21))
+	retryAfter, ok = RetryAfter(err)
+	a.True(ok)
+	a.Equal(2*stdtime.Hour, retryAfter)
+}
+
+func TestEncodeError(t *testing.T) {
+	a := assert.New(t)
+	initTestTime()
+
+	w := httptest.NewRecorder()
+	a.Nil(EncodeError(context.Background(), newRateLimitError(1500*stdtime.Millisecond), w))
+	a.Equal(429, w.Code)
+	a.Equal("2", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	EncodeError(context.Background(), newQuotaExceededError(21, "daily quota reached"), w)
+	a.Equal(429, w.Code)
+	a.Equal("7200", w.Header().Get("Retry-After"))
+	var body map[string]jsonError
+	a.Nil(json.NewDecoder(w.Body).Decode(&body))
+	a.Equal(jsonError{Code: 21, Message: "daily quota reached"}, body["error"])
+
+	// other errors are not affected
+	w = httptest.NewRecorder()
+	EncodeError(context.Background(), errors.New(nil, "test", errors.NotFound), w)
+	a.Equal(404, w.Code)
+	a.Empty(w.Header().Get("Retry-After"))
+}
+
+func TestEndpointMiddleware(t *testing.T) {
+	a := assert.New(t)
+	initTestTime()
+
+	var response interface{} = "ok"
+	mw := NewEndpointMiddleware(NewInmemStore(), Limits{"createLink": Limit{Rate: 1, Burst: 1}})
+	endpoint := endpointname.Middleware("createLink")(mw(func(ctx context.Context, request interface{}) (interface{}, error) {
+		return response, nil
+	}))
+	otherEndpoint := endpointname.Middleware("getLinks")(mw(func(ctx context.Context, request interface{}) (interface{}, error) {
+		return response, nil
+	}))
+
+	ctx := auth.ContextWithUser(context.Background(), auth.User{UserId: "u-123"})
+	_, err := endpoint(ctx, nil)
+	a.Nil(err)
+	_, err = endpoint(ctx, nil)
+	a.NotNil(err)
+	retryAfter, ok := RetryAfter(err)
+	a.True(ok)
+	a.Equal(stdtime.Second, retryAfter)
+
+	// other users and endpoints are not affected
+	_, err = endpoint(auth.ContextWithUser(context.Background(), auth.User{UserId: "u-456"}), nil)
+	a.Nil(err)
+	_, err = otherEndpoint(ctx, nil)
+	a.Nil(err)
+
+	// requests of unauthenticated users are not limited
+	_, err = endpoint(context.Background(), nil)
+	a.Nil(err)
+	_, err = endpoint(context.Background(), nil)
+	a.Nil(err)
+
+	// exceeded quotas are returned as errors of the endpoint
+	quotaErr := newQuotaExceededError(9, "daily link quota reached")
+	response = e.Response{Err: quotaErr}
+	_, err = otherEndpoint(ctx, nil)
+	a.Equal(quotaErr, err)
+
+	// other errors are returned unchanged as part of the response
+	response = e.Response{Err: errors.New(nil, "test", errors.NotFound)}
+	r, err := otherEndpoint(ctx, nil)
+	a.Nil(err)
+	a.Equal(response, r)
+}
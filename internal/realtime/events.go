@@ -0,0 +1,132 @@
+package realtime
+
+import (
+	"context"
+
+	boards "github.com/dkinzler/linkboards/internal/boards/domain"
+	links "github.com/dkinzler/linkboards/internal/links/domain"
+)
+
+// Types of events pushed to clients.
+const (
+	EventLinkCreated      = "linkCreated"
+	EventLinkDeleted      = "linkDeleted"
+	EventLinkRated        = "linkRated"
+	EventBoardUserAdded   = "boardUserAdded"
+	EventBoardUserEdited  = "boardUserEdited"
+	EventBoardUserRemoved = "boardUserRemoved"
+	EventBoardDeleted     = "boardDeleted"
+)
+
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+type LinkCreatedData struct {
+	LinkId      string   `json:"linkId"`
+	Title       string   `json:"title"`
+	Url         string   `json:"url"`
+	Tags        []string `json:"tags"`
+	CreatedTime int64    `json:"createdTime"`
+	CreatedBy   User     `json:"createdBy"`
+}
+
+type LinkDeletedData struct {
+	LinkId    string `json:"linkId"`
+	DeletedBy User   `json:"deletedBy"`
+}
+
+// Clients can reload the link to get the updated score.
+type LinkRatedData struct {
+	LinkId string `json:"linkId"`
+	UserId string `json:"userId"`
+	Rating int    `json:"rating"`
+}
+
+type BoardUserData struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+type BoardDeletedData struct {
+	DeletedBy User `json:"deletedBy"`
+}
+
+// Returns true if the event might change the set of users that are allowed to receive the events of a board.
+func IsMembershipEvent(eventType string) bool {
+	switch eventType {
+	case EventBoardUserEdited, EventBoardUserRemoved, EventBoardDeleted:
+		return true
+	}
+	return false
+}
+
+type boardEventPublisher struct {
+	hub *Hub
+}
+
+// Returns an EventPublisher for the boards component that publishes events to the hub.
+func (h *Hub) BoardEventPublisher() boards.EventPublisher {
+	return &boardEventPublisher{hub: h}
+}
+
+func (p *boardEventPublisher) PublishEvent(ctx context.Context, event interface{}) {
+	switch e := event.(type) {
+	case boards.BoardUserAdded:
+		p.hub.Publish(e.BoardId, EventBoardUserAdded, BoardUserData{
+			UserId: e.User.User.UserId,
+			Name:   e.User.User.Name,
+			Role:   e.User.Role,
+		})
+	case boards.BoardUserEdited:
+		p.hub.Publish(e.BoardId, EventBoardUserEdited, BoardUserData{
+			UserId: e.User.User.UserId,
+			Name:   e.User.User.Name,
+			Role:   e.User.Role,
+		})
+	case boards.BoardUserRemoved:
+		p.hub.Publish(e.BoardId, EventBoardUserRemoved, BoardUserData{
+			UserId: e.UserId,
+		})
+	case boards.BoardDeleted:
+		p.hub.Publish(e.BoardId, EventBoardDeleted, BoardDeletedData{
+			DeletedBy: User{UserId: e.DeletedBy.UserId, Name: e.DeletedBy.Name},
+		})
+	}
+}
+
+type linkEventPublisher struct {
+	hub *Hub
+}
+
+// Returns an EventPublisher for the links component that publishes events to the hub.
+func (h *Hub) LinkEventPublisher() links.EventPublisher {
+	return &linkEventPublisher{hub: h}
+}
+
+func (p *linkEventPublisher) PublishEvent(ctx context.Context, event interface{}) {
+	switch e := event.(type) {
+	case links.LinkCreated:
+		p.hub.Publish(e.Link.BoardId, EventLinkCreated, LinkCreatedData{
+			LinkId:      e.Link.LinkId,
+			Title:       e.Link.Title,
+			Url:         e.Link.Url,
+			Tags:        e.Link.Tags,
+			CreatedTime: e.Link.CreatedTime,
+			CreatedBy:   User{UserId: e.Link.CreatedBy.UserId, Name: e.Link.CreatedBy.Name},
+		})
+	case links.LinkDeleted:
+		p.hub.Publish(e.BoardId, EventLinkDeleted, LinkDeletedData{
+			LinkId:    e.LinkId,
+			DeletedBy: User{UserId: e.DeletedBy.UserId, Name: e.DeletedBy.Name},
+		})
+	case links.LinkRated:
+		p.hub.Publish(e.BoardId, EventLinkRated, LinkRatedData{
+			LinkId: e.LinkId,
+			UserId: e.Rating.UserId,
+			Rating: e.Rating.Rating,
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Package realtime pushes changes of boards and links to connected clients.
+//
+// Components publish their domain events to a Hub, which keeps a bounded buffer of recent events and forwards them
+// to the subscriptions for the board an event belongs to.
+// Clients that lose their connection can resume from the id of the last event they received,
+// as long as the event is still contained in the buffer.
+//
+// Note that a Hub only knows about events published in the same process.
+// If multiple instances of the application run at the same time, events would have to be
+// distributed between them, e.g. using Google Pub/Sub or Redis.
+package realtime
+
+import (
+	"sync"
+	stdtime "time"
+
+	"github.com/dkinzler/kit/errors"
+)
+
+type Event struct {
+	// Ids are increasing, an event with a larger id was published later.
+	Id      uint64
+	BoardId string
+	// Type of the event, e.g. "linkCreated".
+	Type string
+	// Payload of the event, should be serializable to JSON.
+	Data interface{}
+}
+
+const defaultBufferSize = 1024
+const subscriptionBufferSize = 64
+
+type Hub struct {
+	m sync.Mutex
+
+	nextId uint64
+
+	// Ring buffer containing the most recent events of all boards.
+	buffer []Event
+	// index of the oldest event in the buffer
+	start int
+	size  int
+
+	subscriptions map[string]map[*Subscription]struct{}
+	closed        bool
+	done          chan struct{}
+}
+
+// Creates a new hub that keeps the given number of recent events to resume subscriptions.
+// If bufferSize <= 0, a default value is used.
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	return &Hub{
+		// Start with the current time instead of 1, so that event ids keep increasing when the application restarts.
+		// A client that resumes with the id of an event published before the restart will then be told that events are missing.
+		nextId:        uint64(stdtime.Now().UnixMicro()),
+		buffer:        make([]Event, bufferSize),
+		subscriptions: make(map[string]map[*Subscription]struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+func newError(inner error, code errors.ErrorCode) errors.Error {
+	return errors.New(inner, "realtime", code)
+}
+
+// Publishes an event for the given board.
+// Never blocks, subscriptions that cannot keep up with the events of a board are closed.
+func (h *Hub) Publish(boardId string, eventType string, data interface{}) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	event := Event{
+		Id:      h.nextId,
+		BoardId: boardId,
+		Type:    eventType,
+		Data:    data,
+	}
+	h.nextId++
+
+	if h.size < len(h.buffer) {
+		h.buffer[(h.start+h.size)%len(h.buffer)] = event
+		h.size++
+	} else {
+		h.buffer[h.start] = event
+		h.start = (h.start + 1) % len(h.buffer)
+	}
+
+	for sub := range h.subscriptions[boardId] {
+		select {
+		case sub.events <- event:
+		default:
+			// The subscriber is too slow, close the subscription instead of blocking publishers.
+			// The client can reconnect and resume from the last event it received.
+			h.removeSubscription(sub)
+		}
+	}
+}
+
+// Subscribes to the events of a board.
+// If lastEventId is not 0, the events of the board published after the event with this id are returned.
+// The returned bool is false if some of these events are no longer available, clients should then reload the state of the board.
+func (h *Hub) Subscribe(boardId string, lastEventId uint64) (*Subscription, []Event, bool, error) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.closed {
+		return nil, nil, false, newError(nil, errors.Unavailable).WithInternalMessage("hub closed")
+	}
+
+	sub := &Subscription{
+		BoardId:      boardId,
+		StartEventId: h.nextId - 1,
+		events:       make(chan Event, subscriptionBufferSize),
+		hub:          h,
+	}
+	subs, ok := h.subscriptions[boardId]
+	if !ok {
+		subs = make(map[*Subscription]struct{})
+		h.subscriptions[boardId] = subs
+	}
+	subs[sub] = struct{}{}
+
+	if lastEventId == 0 {
+		return sub, nil, true, nil
+	}
+
+	// If the buffer is empty, lastEventId should be the id of the last event published (i.e. nextId - 1).
+	// Otherwise the event after lastEventId must still be in the buffer.
+	oldestId := h.nextId
+	if h.size > 0 {
+		oldestId = h.buffer[h.start].Id
+	}
+	complete := lastEventId+1 >= oldestId && lastEventId < h.nextId
+
+	var missed []Event
+	for i := 0; i < h.size; i++ {
+		event := h.buffer[(h.start+i)%len(h.buffer)]
+		if event.Id > lastEventId && event.BoardId == boardId {
+			missed = append(missed, event)
+		}
+	}
+
+	return sub, missed, complete, nil
+}
+
+// Closes all subscriptions, no new subscriptions can be created.
+// Should be called when the application shuts down.
+func (h *Hub) Close() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+	for _, subs := range h.subscriptions {
+		for sub := range subs {
+			h.removeSubscription(sub)
+		}
+	}
+}
+
+// Returns a channel that is closed when the hub is closed.
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+// Caller must hold the lock.
+func (h *Hub) removeSubscription(sub *Subscription) {
+	subs, ok := h.subscriptions[sub.BoardId]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscriptions, sub.BoardId)
+	}
+	close(sub.events)
+}
+
+type Subscription struct {
+	BoardId string
+	// Id of the last event published before the subscription was created.
+	StartEventId uint64
+	events       chan Event
+	hub          *Hub
+}
+
+// Returns the channel on which the events of the board are delivered.
+// The channel is closed when the subscription ends, i.e. if it was closed, the subscriber could not keep up or the hub was closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.hub.m.Lock()
+	defer s.hub.m.Unlock()
+	s.hub.removeSubscription(s)
+}
@@ -0,0 +1,107 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionReceivesEventsOfBoard(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(10)
+
+	sub, missed, complete, err := hub.Subscribe("b1", 0)
+	a.Nil(err)
+	a.Empty(missed)
+	a.True(complete)
+
+	hub.Publish("b2", EventLinkCreated, nil)
+	hub.Publish("b1", EventLinkDeleted, nil)
+
+	event := <-sub.Events()
+	a.Equal("b1", event.BoardId)
+	a.Equal(EventLinkDeleted, event.Type)
+	a.Len(sub.Events(), 0)
+
+	sub.Close()
+	_, ok := <-sub.Events()
+	a.False(ok)
+	// closing twice is fine
+	sub.Close()
+}
+
+func TestResumeSubscription(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(4)
+
+	sub, _, _, err := hub.Subscribe("b1", 0)
+	a.Nil(err)
+	hub.Publish("b1", EventLinkCreated, 1)
+	hub.Publish("b2", EventLinkCreated, 2)
+	hub.Publish("b1", EventLinkCreated, 3)
+	first := <-sub.Events()
+	sub.Close()
+
+	_, missed, complete, err := hub.Subscribe("b1", first.Id)
+	a.Nil(err)
+	a.True(complete)
+	a.Len(missed, 1)
+	a.Equal(3, missed[0].Data)
+
+	// resuming from the last event is complete without any missed events
+	_, missed, complete, err = hub.Subscribe("b1", missed[0].Id)
+	a.Nil(err)
+	a.True(complete)
+	a.Empty(missed)
+
+	// events are dropped from the buffer
+	hub.Publish("b2", EventLinkCreated, 4)
+	hub.Publish("b2", EventLinkCreated, 5)
+	hub.Publish("b2", EventLinkCreated, 6)
+	_, missed, complete, err = hub.Subscribe("b1", first.Id)
+	a.Nil(err)
+	a.False(complete)
+	a.Len(missed, 1)
+
+	// unknown ids are incomplete
+	_, _, complete, err = hub.Subscribe("b1", 1)
+	a.Nil(err)
+	a.False(complete)
+	_, _, complete, err = hub.Subscribe("b1", first.Id+1000)
+	a.Nil(err)
+	a.False(complete)
+}
+
+func TestSlowSubscriptionIsClosed(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+
+	sub, _, _, err := hub.Subscribe("b1", 0)
+	a.Nil(err)
+	for i := 0; i < subscriptionBufferSize+1; i++ {
+		hub.Publish("b1", EventLinkCreated, i)
+	}
+
+	n := 0
+	for range sub.Events() {
+		n++
+	}
+	a.Equal(subscriptionBufferSize, n)
+}
+
+func TestCloseHub(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+
+	sub, _, _, err := hub.Subscribe("b1", 0)
+	a.Nil(err)
+	hub.Close()
+	_, ok := <-sub.Events()
+	a.False(ok)
+
+	_, _, _, err = hub.Subscribe("b1", 0)
+	a.NotNil(err)
+
+	// does nothing
+	hub.Publish("b1", EventLinkCreated, nil)
+}
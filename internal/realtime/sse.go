@@ -0,0 +1,195 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	stdtime "time"
+
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/log"
+	t "github.com/dkinzler/kit/transport/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+// Sent when a client resumes a stream but some of the events it missed are no longer available.
+// The client should reload the state of the board.
+const EventReset = "reset"
+
+const defaultHeartbeatInterval = 15 * stdtime.Second
+const defaultAuthorizationInterval = 5 * stdtime.Minute
+
+// Tells clients how long to wait before reconnecting.
+const retryMillis = 3000
+
+type SSEConfig struct {
+	// Returns nil if the user making the request is allowed to receive the events of the board.
+	// Is called when a client connects, whenever the users of the board change and every AuthorizationInterval.
+	Authorize func(ctx context.Context, boardId string) error
+	// Called before Authorize with the http request, e.g. to add the Authorization header to the context.
+	// These should be the same as the ServerBefore options used for the other http handlers.
+	RequestFuncs []kithttp.RequestFunc
+	// Used to encode errors that occur before the stream is started, e.g. if the request is not authorized.
+	ErrorEncoder kithttp.ErrorEncoder
+	// Interval at which comments are sent to keep the connection alive, defaults to 15s.
+	HeartbeatInterval stdtime.Duration
+	// Defaults to 5m.
+	AuthorizationInterval stdtime.Duration
+	Logger                *log.Logger
+}
+
+type sseHandler struct {
+	hub    *Hub
+	config SSEConfig
+}
+
+// Returns a http handler that streams the events of a board using server-sent events.
+// The route must contain a "boardId" parameter, e.g. "/boards/{boardId}/stream".
+//
+// Clients can resume a stream by sending the id of the last event they received in the Last-Event-ID header
+// (which browsers do automatically) or the "lastEventId" query parameter.
+// The stream ends when the user is no longer authorized, the board is deleted or the hub is closed.
+//
+// Note that streams are long-lived requests, the handler should not be wrapped in a handler that times out requests.
+func NewSSEHandler(hub *Hub, config SSEConfig) http.Handler {
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if config.AuthorizationInterval <= 0 {
+		config.AuthorizationInterval = defaultAuthorizationInterval
+	}
+	if config.ErrorEncoder == nil {
+		config.ErrorEncoder = func(ctx context.Context, err error, w http.ResponseWriter) {
+			t.EncodeError(ctx, err, w)
+		}
+	}
+	return &sseHandler{hub: hub, config: config}
+}
+
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	for _, f := range h.config.RequestFuncs {
+		ctx = f(ctx, r)
+	}
+
+	boardId, err := t.DecodeURLParameter(r, "boardId")
+	if err != nil {
+		h.config.ErrorEncoder(ctx, err, w)
+		return
+	}
+
+	if err := h.config.Authorize(ctx, boardId); err != nil {
+		h.config.ErrorEncoder(ctx, err, w)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.config.ErrorEncoder(ctx, newError(nil, errors.Internal).WithInternalMessage("response writer does not support flushing"), w)
+		return
+	}
+
+	lastEventId := decodeLastEventId(r)
+
+	sub, missed, complete, err := h.hub.Subscribe(boardId, lastEventId)
+	if err != nil {
+		h.config.ErrorEncoder(ctx, err, w)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// disable response buffering of nginx proxies
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %v\n\n", retryMillis); err != nil {
+		return
+	}
+	if !complete {
+		if err := writeEvent(w, Event{Id: sub.StartEventId, BoardId: boardId, Type: EventReset, Data: struct{}{}}); err != nil {
+			return
+		}
+	}
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := stdtime.NewTicker(h.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+	authorization := stdtime.NewTicker(h.config.AuthorizationInterval)
+	defer authorization.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+			if event.Type == EventBoardDeleted {
+				return
+			}
+			if IsMembershipEvent(event.Type) && !h.authorized(ctx, boardId) {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-authorization.C:
+			if !h.authorized(ctx, boardId) {
+				return
+			}
+		}
+	}
+}
+
+func (h *sseHandler) authorized(ctx context.Context, boardId string) bool {
+	return checkAuthorization(ctx, h.config.Authorize, h.config.Logger, boardId)
+}
+
+// Returns true if authorize returns no error.
+// Errors other than the expected ones for users that are not authenticated or authorized are logged.
+func checkAuthorization(ctx context.Context, authorize func(context.Context, string) error, logger *log.Logger, boardId string) bool {
+	err := authorize(ctx, boardId)
+	if err != nil && !errors.IsPermissionDeniedError(err) && !errors.IsUnauthenticatedError(err) && logger != nil {
+		logger.Log("msg", "could not authorize stream", "boardId", boardId, "error", err)
+	}
+	return err == nil
+}
+
+func decodeLastEventId(r *http.Request) uint64 {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("lastEventId")
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func writeEvent(w io.Writer, event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %v\nevent: %v\ndata: %s\n\n", event.Id, event.Type, data)
+	return err
+}
@@ -0,0 +1,189 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	stdtime "time"
+
+	"github.com/dkinzler/kit/errors"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserHeader = "X-Test-User"
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+type testAuthorizer struct {
+	m       sync.Mutex
+	allowed map[string]bool
+}
+
+func (ta *testAuthorizer) setAllowed(allowed map[string]bool) {
+	ta.m.Lock()
+	defer ta.m.Unlock()
+	ta.allowed = allowed
+}
+
+func (ta *testAuthorizer) authorize(ctx context.Context, boardId string) error {
+	ta.m.Lock()
+	defer ta.m.Unlock()
+	user, _ := ctx.Value(userContextKey).(string)
+	if !ta.allowed[user] {
+		return errors.New(nil, "test", errors.PermissionDenied)
+	}
+	return nil
+}
+
+func newTestServer(hub *Hub, ta *testAuthorizer) *httptest.Server {
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/boards/{boardId}/stream").Handler(NewSSEHandler(hub, SSEConfig{
+		Authorize: ta.authorize,
+		RequestFuncs: []kithttp.RequestFunc{
+			func(ctx context.Context, r *http.Request) context.Context {
+				return context.WithValue(ctx, userContextKey, r.Header.Get(testUserHeader))
+			},
+		},
+		HeartbeatInterval: 50 * stdtime.Millisecond,
+	}))
+	return httptest.NewServer(router)
+}
+
+type testStream struct {
+	resp    *http.Response
+	scanner *bufio.Scanner
+}
+
+func openStream(t *testing.T, url string, user string, lastEventId string) *testStream {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(testUserHeader, user)
+	if lastEventId != "" {
+		req.Header.Set("Last-Event-ID", lastEventId)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testStream{resp: resp, scanner: bufio.NewScanner(resp.Body)}
+}
+
+// Returns the lines of the next message, i.e. up to the next empty line.
+func (s *testStream) next() []string {
+	var lines []string
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Returns the lines of the next event, skipping comments.
+func (s *testStream) nextEvent() []string {
+	for {
+		lines := s.next()
+		if len(lines) == 0 || !strings.HasPrefix(lines[0], ":") {
+			return lines
+		}
+	}
+}
+
+func (s *testStream) close() {
+	s.resp.Body.Close()
+}
+
+func TestStreamRequiresAuthorization(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+	srv := newTestServer(hub, &testAuthorizer{allowed: map[string]bool{"u1": true}})
+	defer srv.Close()
+
+	s := openStream(t, srv.URL+"/boards/b1/stream", "u2", "")
+	defer s.close()
+	a.Equal(http.StatusForbidden, s.resp.StatusCode)
+}
+
+func TestStreamEvents(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+	srv := newTestServer(hub, &testAuthorizer{allowed: map[string]bool{"u1": true}})
+	defer srv.Close()
+
+	s := openStream(t, srv.URL+"/boards/b1/stream", "u1", "")
+	defer s.close()
+	a.Equal(http.StatusOK, s.resp.StatusCode)
+	a.Equal("text/event-stream", s.resp.Header.Get("Content-Type"))
+	a.Equal([]string{"retry: 3000"}, s.next())
+
+	hub.Publish("b1", EventLinkDeleted, LinkDeletedData{LinkId: "l1", DeletedBy: User{UserId: "u1", Name: "Peter"}})
+	lines := s.nextEvent()
+	a.Len(lines, 3)
+	a.True(strings.HasPrefix(lines[0], "id: "))
+	a.Equal("event: linkDeleted", lines[1])
+	a.Equal(`data: {"linkId":"l1","deletedBy":{"userId":"u1","name":"Peter"}}`, lines[2])
+
+	// heartbeats are sent as comments
+	a.Equal([]string{": heartbeat"}, s.next())
+
+	// a stream can be resumed
+	resumed := openStream(t, srv.URL+"/boards/b1/stream", "u1", strings.TrimPrefix(lines[0], "id: "))
+	defer resumed.close()
+	resumed.next()
+	hub.Publish("b1", EventLinkCreated, LinkCreatedData{LinkId: "l2"})
+	a.Equal("event: linkCreated", resumed.nextEvent()[1])
+	a.Equal("event: linkCreated", s.nextEvent()[1])
+
+	// stream ends when the hub is closed
+	hub.Close()
+	for s.scanner.Scan() {
+	}
+	a.Nil(s.scanner.Err())
+}
+
+func TestStreamSendsResetIfEventsMissing(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(1)
+	srv := newTestServer(hub, &testAuthorizer{allowed: map[string]bool{"u1": true}})
+	defer srv.Close()
+
+	hub.Publish("b1", EventLinkCreated, nil)
+	hub.Publish("b1", EventLinkCreated, nil)
+	s := openStream(t, srv.URL+"/boards/b1/stream", "u1", "1")
+	defer s.close()
+	s.next()
+	a.Equal("event: reset", s.nextEvent()[1])
+	a.Equal("event: linkCreated", s.nextEvent()[1])
+}
+
+func TestStreamEndsWhenNoLongerAuthorized(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+	ta := &testAuthorizer{allowed: map[string]bool{"u1": true}}
+	srv := newTestServer(hub, ta)
+	defer srv.Close()
+
+	s := openStream(t, srv.URL+"/boards/b1/stream", "u1", "")
+	defer s.close()
+	s.next()
+
+	ta.setAllowed(map[string]bool{})
+	hub.Publish("b1", EventBoardUserRemoved, BoardUserData{UserId: "u1"})
+	a.Equal("event: boardUserRemoved", s.nextEvent()[1])
+	for s.scanner.Scan() {
+	}
+	a.Nil(s.scanner.Err())
+}
@@ -0,0 +1,476 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	stdtime "time"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+	"github.com/dkinzler/kit/log"
+	t "github.com/dkinzler/kit/transport/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/websocket"
+)
+
+// Types of messages sent by clients, any other type is interpreted as the name of a command.
+const (
+	MessageSubscribe   = "subscribe"
+	MessageUnsubscribe = "unsubscribe"
+)
+
+// Types of messages sent by the server.
+const (
+	// Response to a successful subscribe, unsubscribe or command message.
+	MessageResult = "result"
+	// Response to a message that could not be processed.
+	MessageError = "error"
+	// An event of a board the client is subscribed to.
+	MessageEvent = "event"
+	// A subscription was ended by the server, the Reason field contains one of the values below.
+	MessageUnsubscribed = "unsubscribed"
+)
+
+// Reasons for ending a subscription.
+const (
+	// The user is no longer allowed to receive the events of the board.
+	ReasonUnauthorized = "unauthorized"
+	ReasonBoardDeleted = "boardDeleted"
+	// The client could not keep up with the events of the board.
+	// It can subscribe again with the id of the last event it received.
+	ReasonOverflow = "overflow"
+)
+
+// Message sent by clients.
+type ClientMessage struct {
+	// Chosen by the client, the response to this message will contain the same id.
+	Id string `json:"id"`
+	// "subscribe", "unsubscribe" or the name of a command, e.g. "createLink".
+	Type    string `json:"type"`
+	BoardId string `json:"boardId"`
+	LinkId  string `json:"linkId,omitempty"`
+	// Used by subscribe messages to resume a subscription after the event with this id.
+	LastEventId uint64 `json:"lastEventId,omitempty"`
+	// Arguments of a command.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Message sent by the server.
+type ServerMessage struct {
+	Type string `json:"type"`
+	// Id of the client message this message is a response to.
+	Id      string `json:"id,omitempty"`
+	BoardId string `json:"boardId,omitempty"`
+	// Id and type of an event.
+	EventId uint64 `json:"eventId,omitempty"`
+	Event   string `json:"event,omitempty"`
+	// Why a subscription was ended.
+	Reason string            `json:"reason,omitempty"`
+	Data   interface{}       `json:"data,omitempty"`
+	Error  *MessageErrorData `json:"error,omitempty"`
+}
+
+type MessageErrorData struct {
+	// The http status code that would have been returned by the HTTP API for the same error.
+	Status  int    `json:"status"`
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Returned when subscribing to a board.
+type SubscribeResult struct {
+	// Id of the last event published before the subscription was created.
+	// Can be used to resume the subscription if no event was received yet.
+	LastEventId uint64 `json:"lastEventId"`
+}
+
+// A command clients can send over a WebSocket connection.
+// The command is decoded into a request and passed to an endpoint,
+// the response of the endpoint is sent back to the client.
+type Command struct {
+	Decode   func(ctx context.Context, msg ClientMessage) (interface{}, error)
+	Endpoint endpoint.Endpoint
+}
+
+const defaultPingInterval = 30 * stdtime.Second
+const defaultCommandTimeout = 7 * stdtime.Second
+const maxMessageBytes = 64 * 1024
+const maxSubscriptionsPerConnection = 32
+const writeTimeout = 10 * stdtime.Second
+const sendBufferSize = 64
+
+type WebSocketConfig struct {
+	// Returns nil if the user making the request is allowed to receive the events of the board.
+	// Is called when a client subscribes to a board, whenever the users of the board change and every AuthorizationInterval.
+	Authorize func(ctx context.Context, boardId string) error
+	// Called with the http request that opens the connection, e.g. to add the Authorization header to the context.
+	// The resulting context is used for all messages of the connection.
+	RequestFuncs []kithttp.RequestFunc
+	// Used to encode errors that occur before the connection is upgraded.
+	ErrorEncoder kithttp.ErrorEncoder
+	// Commands by name.
+	Commands map[string]Command
+	// Defaults to 7s.
+	CommandTimeout stdtime.Duration
+	// Interval at which pings are sent to keep the connection alive, defaults to 30s.
+	// The connection is closed if no pong is received within twice the interval.
+	PingInterval stdtime.Duration
+	// Defaults to 5m.
+	AuthorizationInterval stdtime.Duration
+	// Returns true if the origin of a request is allowed.
+	// If nil, the origin must match the host of the request.
+	CheckOrigin func(r *http.Request) bool
+	Logger      *log.Logger
+}
+
+type webSocketHandler struct {
+	hub      *Hub
+	config   WebSocketConfig
+	upgrader websocket.Upgrader
+}
+
+// Returns a http handler that speaks a JSON message protocol over WebSocket connections.
+// Clients can subscribe to the events of multiple boards and send commands.
+//
+// Every client message receives exactly one response of type "result" or "error" with the id of the message.
+// Subscriptions can be resumed, e.g. after reconnecting, by sending the id of the last event received with the subscribe message.
+// If events are missing, a "reset" event is sent first (see the SSE handler).
+// When the server ends a subscription, an "unsubscribed" message is sent and the client can decide whether to subscribe again.
+//
+// The connection is closed when the hub is closed.
+func NewWebSocketHandler(hub *Hub, config WebSocketConfig) http.Handler {
+	if config.CommandTimeout <= 0 {
+		config.CommandTimeout = defaultCommandTimeout
+	}
+	if config.PingInterval <= 0 {
+		config.PingInterval = defaultPingInterval
+	}
+	if config.AuthorizationInterval <= 0 {
+		config.AuthorizationInterval = defaultAuthorizationInterval
+	}
+	if config.ErrorEncoder == nil {
+		config.ErrorEncoder = func(ctx context.Context, err error, w http.ResponseWriter) {
+			t.EncodeError(ctx, err, w)
+		}
+	}
+	return &webSocketHandler{
+		hub:    hub,
+		config: config,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: config.CheckOrigin,
+		},
+	}
+}
+
+func (h *webSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	for _, f := range h.config.RequestFuncs {
+		ctx = f(ctx, r)
+	}
+
+	select {
+	case <-h.hub.Done():
+		h.config.ErrorEncoder(ctx, newError(nil, errors.Unavailable).WithInternalMessage("hub closed"), w)
+		return
+	default:
+	}
+
+	// the upgrader writes an error response itself
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := &wsConn{
+		h:             h,
+		ws:            ws,
+		ctx:           ctx,
+		cancel:        cancel,
+		send:          make(chan ServerMessage, sendBufferSize),
+		subscriptions: make(map[string]*wsSubscription),
+	}
+	c.run()
+}
+
+type wsConn struct {
+	h      *webSocketHandler
+	ws     *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	send   chan ServerMessage
+
+	m             sync.Mutex
+	subscriptions map[string]*wsSubscription
+	// goroutines forwarding the events of subscriptions
+	wg sync.WaitGroup
+}
+
+type wsSubscription struct {
+	sub  *Subscription
+	done chan struct{}
+}
+
+// Reads and processes messages until the connection is closed.
+func (c *wsConn) run() {
+	defer func() {
+		c.cancel()
+		c.m.Lock()
+		for boardId, s := range c.subscriptions {
+			delete(c.subscriptions, boardId)
+			close(s.done)
+			s.sub.Close()
+		}
+		c.m.Unlock()
+		c.wg.Wait()
+		c.ws.Close()
+	}()
+
+	go c.writeLoop()
+	go func() {
+		select {
+		case <-c.h.hub.Done():
+			c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), stdtime.Now().Add(writeTimeout))
+			// unblocks the read below
+			c.ws.Close()
+		case <-c.ctx.Done():
+		}
+	}()
+
+	pongTimeout := 2 * c.h.config.PingInterval
+	c.ws.SetReadLimit(maxMessageBytes)
+	c.ws.SetReadDeadline(stdtime.Now().Add(pongTimeout))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(stdtime.Now().Add(pongTimeout))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg ClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.write(errorMessage("", newError(err, errors.InvalidArgument).WithPublicMessage("invalid message")))
+			continue
+		}
+		c.handle(msg)
+	}
+}
+
+// Writes messages and pings, there can only be one concurrent writer for a connection.
+func (c *wsConn) writeLoop() {
+	ping := stdtime.NewTicker(c.h.config.PingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case msg := <-c.send:
+			c.ws.SetWriteDeadline(stdtime.Now().Add(writeTimeout))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				c.cancel()
+				c.ws.Close()
+				return
+			}
+		case <-ping.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, stdtime.Now().Add(writeTimeout)); err != nil {
+				c.cancel()
+				c.ws.Close()
+				return
+			}
+		}
+	}
+}
+
+// Blocks until the message is queued or the connection is closed.
+func (c *wsConn) write(msg ServerMessage) {
+	select {
+	case c.send <- msg:
+	case <-c.ctx.Done():
+	}
+}
+
+func (c *wsConn) handle(msg ClientMessage) {
+	switch msg.Type {
+	case MessageSubscribe:
+		c.subscribe(msg)
+	case MessageUnsubscribe:
+		c.unsubscribe(msg)
+	default:
+		c.command(msg)
+	}
+}
+
+func (c *wsConn) subscribe(msg ClientMessage) {
+	if msg.BoardId == "" {
+		c.write(errorMessage(msg.Id, newError(nil, errors.InvalidArgument).WithPublicMessage("missing board id")))
+		return
+	}
+
+	if err := c.h.config.Authorize(c.ctx, msg.BoardId); err != nil {
+		c.write(errorMessage(msg.Id, err))
+		return
+	}
+
+	c.m.Lock()
+	// Subscribing again to the same board replaces the existing subscription, e.g. to resume from an older event.
+	if old, ok := c.subscriptions[msg.BoardId]; ok {
+		delete(c.subscriptions, msg.BoardId)
+		close(old.done)
+		old.sub.Close()
+	}
+	if len(c.subscriptions) >= maxSubscriptionsPerConnection {
+		c.m.Unlock()
+		c.write(errorMessage(msg.Id, newError(nil, errors.InvalidArgument).WithPublicMessage("too many subscriptions")))
+		return
+	}
+	sub, missed, complete, err := c.h.hub.Subscribe(msg.BoardId, msg.LastEventId)
+	if err != nil {
+		c.m.Unlock()
+		c.write(errorMessage(msg.Id, err))
+		return
+	}
+	s := &wsSubscription{sub: sub, done: make(chan struct{})}
+	c.subscriptions[msg.BoardId] = s
+	c.m.Unlock()
+
+	// Queue the result and missed events before starting to forward new events to preserve the order.
+	c.write(ServerMessage{Type: MessageResult, Id: msg.Id, BoardId: msg.BoardId, Data: SubscribeResult{LastEventId: sub.StartEventId}})
+	if !complete {
+		c.write(eventMessage(Event{Id: sub.StartEventId, BoardId: msg.BoardId, Type: EventReset, Data: struct{}{}}))
+	}
+	for _, event := range missed {
+		c.write(eventMessage(event))
+	}
+
+	c.wg.Add(1)
+	go c.forward(s)
+}
+
+func (c *wsConn) unsubscribe(msg ClientMessage) {
+	c.m.Lock()
+	if s, ok := c.subscriptions[msg.BoardId]; ok {
+		delete(c.subscriptions, msg.BoardId)
+		close(s.done)
+		s.sub.Close()
+	}
+	c.m.Unlock()
+	c.write(ServerMessage{Type: MessageResult, Id: msg.Id, BoardId: msg.BoardId})
+}
+
+// Ends a subscription from the server side, does nothing if the subscription was already removed.
+func (c *wsConn) endSubscription(s *wsSubscription, reason string) {
+	c.m.Lock()
+	current, ok := c.subscriptions[s.sub.BoardId]
+	if !ok || current != s {
+		c.m.Unlock()
+		return
+	}
+	delete(c.subscriptions, s.sub.BoardId)
+	s.sub.Close()
+	c.m.Unlock()
+	c.write(ServerMessage{Type: MessageUnsubscribed, BoardId: s.sub.BoardId, Reason: reason})
+}
+
+// Forwards the events of a subscription to the client.
+func (c *wsConn) forward(s *wsSubscription) {
+	defer c.wg.Done()
+
+	authorization := stdtime.NewTicker(c.h.config.AuthorizationInterval)
+	defer authorization.Stop()
+
+	boardId := s.sub.BoardId
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-s.done:
+			return
+		case event, ok := <-s.sub.Events():
+			if !ok {
+				select {
+				case <-c.h.hub.Done():
+					// the connection will be closed
+				default:
+					c.endSubscription(s, ReasonOverflow)
+				}
+				return
+			}
+			c.write(eventMessage(event))
+			if event.Type == EventBoardDeleted {
+				c.endSubscription(s, ReasonBoardDeleted)
+				return
+			}
+			if IsMembershipEvent(event.Type) && !checkAuthorization(c.ctx, c.h.config.Authorize, c.h.config.Logger, boardId) {
+				c.endSubscription(s, ReasonUnauthorized)
+				return
+			}
+		case <-authorization.C:
+			if !checkAuthorization(c.ctx, c.h.config.Authorize, c.h.config.Logger, boardId) {
+				c.endSubscription(s, ReasonUnauthorized)
+				return
+			}
+		}
+	}
+}
+
+// Commands are processed one after another in the order they are received.
+func (c *wsConn) command(msg ClientMessage) {
+	cmd, ok := c.h.config.Commands[msg.Type]
+	if !ok {
+		c.write(errorMessage(msg.Id, newError(nil, errors.InvalidArgument).WithPublicMessage("unknown message type")))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, c.h.config.CommandTimeout)
+	defer cancel()
+
+	request, err := cmd.Decode(ctx, msg)
+	if err != nil {
+		c.write(errorMessage(msg.Id, err))
+		return
+	}
+
+	// Errors from endpoint middlewares are returned directly, errors from the application service are contained in the response.
+	response, err := cmd.Endpoint(ctx, request)
+	if err != nil {
+		c.write(errorMessage(msg.Id, err))
+		return
+	}
+
+	var data interface{} = response
+	if r, ok := response.(e.Responder); ok {
+		if err := r.Error(); err != nil {
+			c.write(errorMessage(msg.Id, err))
+			return
+		}
+		data = r.Response()
+	}
+
+	c.write(ServerMessage{Type: MessageResult, Id: msg.Id, BoardId: msg.BoardId, Data: data})
+}
+
+func eventMessage(event Event) ServerMessage {
+	return ServerMessage{
+		Type:    MessageEvent,
+		BoardId: event.BoardId,
+		EventId: event.Id,
+		Event:   event.Type,
+		Data:    event.Data,
+	}
+}
+
+// Like the error responses of the HTTP API, only the public code and message of an error are sent to the client.
+func errorMessage(id string, err error) ServerMessage {
+	data := &MessageErrorData{Status: t.ErrToCode(err)}
+	if e, ok := err.(errors.Error); ok {
+		data.Code = e.PublicCode
+		data.Message = e.PublicMessage
+	}
+	return ServerMessage{Type: MessageError, Id: id, Error: data}
+}
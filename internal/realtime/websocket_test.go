@@ -0,0 +1,213 @@
+package realtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	stdtime "time"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWebSocketServer(hub *Hub, ta *testAuthorizer, commands map[string]Command) *httptest.Server {
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/ws").Handler(NewWebSocketHandler(hub, WebSocketConfig{
+		Authorize: ta.authorize,
+		RequestFuncs: []kithttp.RequestFunc{
+			func(ctx context.Context, r *http.Request) context.Context {
+				return context.WithValue(ctx, userContextKey, r.Header.Get(testUserHeader))
+			},
+		},
+		Commands: commands,
+	}))
+	return httptest.NewServer(router)
+}
+
+func dialTestServer(t *testing.T, srv *httptest.Server, user string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	header := http.Header{}
+	header.Set(testUserHeader, user)
+	ws, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ws
+}
+
+// Reads messages into a generic map, so that tests can check the exact JSON properties sent.
+func readMessage(t *testing.T, ws *websocket.Conn) map[string]interface{} {
+	ws.SetReadDeadline(stdtime.Now().Add(2 * stdtime.Second))
+	var msg map[string]interface{}
+	if err := ws.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+	ta := &testAuthorizer{allowed: map[string]bool{"u1": true}}
+	srv := newTestWebSocketServer(hub, ta, nil)
+	defer srv.Close()
+
+	ws := dialTestServer(t, srv, "u1")
+	defer ws.Close()
+
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "1", Type: MessageSubscribe, BoardId: "b1"}))
+	msg := readMessage(t, ws)
+	a.Equal(MessageResult, msg["type"])
+	a.Equal("1", msg["id"])
+
+	hub.Publish("b2", EventLinkCreated, LinkCreatedData{LinkId: "l0"})
+	hub.Publish("b1", EventLinkCreated, LinkCreatedData{LinkId: "l1"})
+	msg = readMessage(t, ws)
+	a.Equal(MessageEvent, msg["type"])
+	a.Equal("b1", msg["boardId"])
+	a.Equal(EventLinkCreated, msg["event"])
+	a.Equal("l1", msg["data"].(map[string]interface{})["linkId"])
+	lastEventId := uint64(msg["eventId"].(float64))
+
+	// reconnect and resume
+	ws.Close()
+	hub.Publish("b1", EventLinkDeleted, LinkDeletedData{LinkId: "l1"})
+	ws = dialTestServer(t, srv, "u1")
+	defer ws.Close()
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "2", Type: MessageSubscribe, BoardId: "b1", LastEventId: lastEventId}))
+	a.Equal(MessageResult, readMessage(t, ws)["type"])
+	msg = readMessage(t, ws)
+	a.Equal(EventLinkDeleted, msg["event"])
+
+	// subscription ends when the user is no longer authorized
+	ta.setAllowed(map[string]bool{})
+	hub.Publish("b1", EventBoardUserRemoved, BoardUserData{UserId: "u1"})
+	a.Equal(EventBoardUserRemoved, readMessage(t, ws)["event"])
+	msg = readMessage(t, ws)
+	a.Equal(MessageUnsubscribed, msg["type"])
+	a.Equal(ReasonUnauthorized, msg["reason"])
+
+	// cannot subscribe again
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "3", Type: MessageSubscribe, BoardId: "b1"}))
+	msg = readMessage(t, ws)
+	a.Equal(MessageError, msg["type"])
+	a.Equal("3", msg["id"])
+	a.Equal(float64(http.StatusForbidden), msg["error"].(map[string]interface{})["status"])
+}
+
+func TestWebSocketUnsubscribe(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+	srv := newTestWebSocketServer(hub, &testAuthorizer{allowed: map[string]bool{"u1": true}}, nil)
+	defer srv.Close()
+
+	ws := dialTestServer(t, srv, "u1")
+	defer ws.Close()
+
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "1", Type: MessageSubscribe, BoardId: "b1"}))
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "2", Type: MessageSubscribe, BoardId: "b2"}))
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "3", Type: MessageUnsubscribe, BoardId: "b1"}))
+	for i := 0; i < 3; i++ {
+		a.Equal(MessageResult, readMessage(t, ws)["type"])
+	}
+
+	hub.Publish("b1", EventLinkCreated, nil)
+	hub.Publish("b2", EventLinkCreated, nil)
+	a.Equal("b2", readMessage(t, ws)["boardId"])
+}
+
+func TestWebSocketCommands(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+
+	commands := map[string]Command{
+		"echo": {
+			Decode: func(ctx context.Context, msg ClientMessage) (interface{}, error) {
+				if len(msg.Data) == 0 {
+					return nil, errors.New(nil, "test", errors.InvalidArgument).WithPublicCode(1).WithPublicMessage("missing data")
+				}
+				return string(msg.Data), nil
+			},
+			Endpoint: func(ctx context.Context, request interface{}) (interface{}, error) {
+				user, _ := ctx.Value(userContextKey).(string)
+				if user == "" {
+					return nil, errors.New(nil, "test", errors.Unauthenticated)
+				}
+				return e.Response{R: map[string]string{"echo": request.(string)}}, nil
+			},
+		},
+		"fail": {
+			Decode: func(ctx context.Context, msg ClientMessage) (interface{}, error) {
+				return nil, nil
+			},
+			Endpoint: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return e.Response{Err: errors.New(nil, "test", errors.NotFound)}, nil
+			},
+		},
+	}
+	srv := newTestWebSocketServer(hub, &testAuthorizer{}, commands)
+	defer srv.Close()
+
+	ws := dialTestServer(t, srv, "u1")
+	defer ws.Close()
+
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "1", Type: "echo", Data: []byte(`"hello"`)}))
+	msg := readMessage(t, ws)
+	a.Equal(MessageResult, msg["type"])
+	a.Equal("1", msg["id"])
+	a.Equal(map[string]interface{}{"echo": `"hello"`}, msg["data"])
+
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "2", Type: "echo"}))
+	msg = readMessage(t, ws)
+	a.Equal(MessageError, msg["type"])
+	a.Equal(map[string]interface{}{"status": float64(400), "code": float64(1), "message": "missing data"}, msg["error"])
+
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "3", Type: "fail"}))
+	msg = readMessage(t, ws)
+	a.Equal(float64(404), msg["error"].(map[string]interface{})["status"])
+
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "4", Type: "unknown"}))
+	msg = readMessage(t, ws)
+	a.Equal("4", msg["id"])
+	a.Equal(float64(400), msg["error"].(map[string]interface{})["status"])
+
+	a.Nil(ws.WriteMessage(websocket.TextMessage, []byte("not json")))
+	a.Equal(MessageError, readMessage(t, ws)["type"])
+
+	// errors of endpoint middlewares are returned too
+	unauthenticated := dialTestServer(t, srv, "")
+	defer unauthenticated.Close()
+	a.Nil(unauthenticated.WriteJSON(ClientMessage{Id: "1", Type: "echo", Data: []byte(`"hello"`)}))
+	msg = readMessage(t, unauthenticated)
+	a.Equal(float64(401), msg["error"].(map[string]interface{})["status"])
+}
+
+func TestWebSocketClosedWithHub(t *testing.T) {
+	a := assert.New(t)
+	hub := NewHub(0)
+	srv := newTestWebSocketServer(hub, &testAuthorizer{allowed: map[string]bool{"u1": true}}, nil)
+	defer srv.Close()
+
+	ws := dialTestServer(t, srv, "u1")
+	defer ws.Close()
+	a.Nil(ws.WriteJSON(ClientMessage{Id: "1", Type: MessageSubscribe, BoardId: "b1"}))
+	readMessage(t, ws)
+
+	hub.Close()
+	ws.SetReadDeadline(stdtime.Now().Add(2 * stdtime.Second))
+	_, _, err := ws.ReadMessage()
+	a.True(websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	// new connections are rejected
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	a.NotNil(err)
+	a.Equal(http.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,223 @@
+// Package tracing records OpenTelemetry traces of requests to the API.
+//
+// A trace of a request consists of a span for the HTTP request (see HTTPServerOptions) or gRPC call (see GRPCUnaryServerInterceptor),
+// a span for the endpoint that handles it (see EndpointMiddleware) and spans for the data store calls made while handling the request
+// (see StartDataStoreSpan).
+// Incoming requests can continue an existing trace using W3C trace context headers or metadata ("traceparent" and "tracestate").
+//
+// Spans are sent to the exporter of the TracerProvider, e.g. an OTLP collector.
+// For tests, NewInmemTracerProvider keeps spans in memory.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Name of the tracers created by this package.
+const instrumentationName = "github.com/dkinzler/linkboards"
+
+// Attribute that contains the code of an error, e.g. "NotFound", see package "github.com/dkinzler/kit/errors".
+const errorCodeKey = attribute.Key("linkboards.error_code")
+
+// Trace context is propagated using the W3C format.
+var propagator = propagation.TraceContext{}
+
+// Returns a TracerProvider that sends spans to the given exporter in batches and samples the given fraction of new traces.
+// Requests that continue a trace are sampled if the parent span was sampled.
+// Call Shutdown on the returned value to export the remaining spans.
+func NewTracerProvider(exporter sdktrace.SpanExporter, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(serviceName))),
+	)
+}
+
+// Returns a TracerProvider that records all spans in the returned exporter, spans can be inspected as soon as they end.
+// Can be used for testing.
+func NewInmemTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return tp, exporter
+}
+
+// Returns options for go-kit HTTP servers that create a span for every request.
+// If a request contains W3C trace context headers, the span continues the trace of the caller.
+//
+// Spans are named after the method and route of the request, e.g. "GET /boards/{boardId}".
+func HTTPServerOptions(tp trace.TracerProvider) []kithttp.ServerOption {
+	tracer := tp.Tracer(instrumentationName)
+	return []kithttp.ServerOption{
+		kithttp.ServerBefore(func(ctx context.Context, r *http.Request) context.Context {
+			ctx = propagator.Extract(ctx, propagation.HeaderCarrier(r.Header))
+			route := routeTemplate(r)
+			ctx, _ = tracer.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(r.Method),
+					semconv.HTTPRouteKey.String(route),
+					semconv.HTTPTargetKey.String(r.URL.Path),
+				),
+			)
+			return ctx
+		}),
+		kithttp.ServerFinalizer(func(ctx context.Context, code int, r *http.Request) {
+			span := trace.SpanFromContext(ctx)
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(code))
+			if code >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(code))
+			}
+			span.End()
+		}),
+	}
+}
+
+// Returns a gRPC interceptor that creates a span for every unary call.
+// If the metadata of a call contains W3C trace context, the span continues the trace of the caller.
+//
+// Spans are named after the full method of the call, e.g. "boards.v1.BoardService/GetBoard".
+func GRPCUnaryServerInterceptor(tp trace.TracerProvider) grpc.UnaryServerInterceptor {
+	tracer := tp.Tracer(instrumentationName)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagator.Extract(ctx, metadataCarrier(md))
+		name := strings.TrimPrefix(info.FullMethod, "/")
+		service, method, _ := strings.Cut(name, "/")
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.RPCSystemGRPC,
+				semconv.RPCServiceKey.String(service),
+				semconv.RPCMethodKey.String(method),
+			),
+		)
+		defer span.End()
+
+		response, err := handler(ctx, req)
+
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int64(int64(code)))
+		switch code {
+		case grpccodes.Internal, grpccodes.Unknown, grpccodes.Unavailable, grpccodes.DeadlineExceeded, grpccodes.DataLoss:
+			span.SetStatus(codes.Error, code.String())
+		}
+		return response, err
+	}
+}
+
+// Lets the propagator read trace context from incoming gRPC metadata, whose keys are lowercase.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Returns the path template of the route that matched the request, or the path of the request if there is none.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if t, err := route.GetPathTemplate(); err == nil {
+			return t
+		}
+	}
+	return r.URL.Path
+}
+
+// Returns a middleware that creates a span named "<component>.<endpointName>" for every call of an endpoint.
+// Errors returned by the endpoint as well as errors contained in responses (see package "github.com/dkinzler/kit/endpoint") are recorded.
+func EndpointMiddleware(tp trace.TracerProvider, component, endpointName string) endpoint.Middleware {
+	tracer := tp.Tracer(instrumentationName)
+	spanName := component + "." + endpointName
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, span := tracer.Start(ctx, spanName)
+			defer span.End()
+
+			response, err := next(ctx, request)
+
+			resultErr := err
+			if r, ok := response.(e.Responder); ok && err == nil {
+				resultErr = r.Error()
+			}
+			recordError(span, resultErr)
+			return response, err
+		}
+	}
+}
+
+// Starts a span named "<dataStore>.<method>" for a data store call, e.g. "boards.UpdateBoard".
+// The span must be ended using EndSpan.
+func StartDataStoreSpan(ctx context.Context, tracer trace.Tracer, dataStore, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, dataStore+"."+method, trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// Records the error the call returned, if any, and ends the span.
+// Use it with defer and a named error result:
+//
+//	ctx, span := tracing.StartDataStoreSpan(ctx, tracer, "boards", "UpdateBoard")
+//	defer tracing.EndSpan(span, &err)
+func EndSpan(span trace.Span, err *error) {
+	if err != nil {
+		recordError(span, *err)
+	}
+	span.End()
+}
+
+// Returns a tracer of the given provider, that can be used with StartDataStoreSpan.
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	return tp.Tracer(instrumentationName)
+}
+
+// Errors caused by invalid requests, e.g. NotFound or InvalidArgument, are added as attributes,
+// only other errors, e.g. Internal, set the status of the span to Error.
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	code := errors.Unknown
+	if e, ok := err.(errors.Error); ok {
+		code = e.Code
+	}
+	span.SetAttributes(errorCodeKey.String(code.String()))
+	switch code {
+	case errors.Internal, errors.Unknown, errors.Unavailable, errors.DeadlineExceeded:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, code.String())
+	}
+}
@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	e "github.com/dkinzler/kit/endpoint"
+	"github.com/dkinzler/kit/errors"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Returns the span with the given name, or an empty span if there is none.
+func findSpan(spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	return tracetest.SpanStub{}
+}
+
+func TestTracing(t *testing.T) {
+	a := assert.New(t)
+
+	tp, exporter := NewInmemTracerProvider()
+	tracer := Tracer(tp)
+
+	var dsErr error
+	endpoint := func(ctx context.Context, request interface{}) (interface{}, error) {
+		// simulates a data store call
+		_, span := StartDataStoreSpan(ctx, tracer, "boards", "Board")
+		EndSpan(span, &dsErr)
+		return e.Response{R: "ok", Err: dsErr}, nil
+	}
+	endpoint = EndpointMiddleware(tp, "boards", "getBoard")(endpoint)
+
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/boards/{boardId}").Handler(kithttp.NewServer(
+		endpoint,
+		func(ctx context.Context, r *http.Request) (interface{}, error) { return nil, nil },
+		kithttp.EncodeJSONResponse,
+		HTTPServerOptions(tp)...,
+	))
+
+	// the trace of the caller is continued
+	r := httptest.NewRequest(http.MethodGet, "/boards/b-123", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := exporter.GetSpans()
+	a.Len(spans, 3)
+	httpSpan := findSpan(spans, "GET /boards/{boardId}")
+	endpointSpan := findSpan(spans, "boards.getBoard")
+	dsSpan := findSpan(spans, "boards.Board")
+
+	a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", httpSpan.SpanContext.TraceID().String())
+	a.Equal("00f067aa0ba902b7", httpSpan.Parent.SpanID().String())
+	a.True(httpSpan.Parent.IsRemote())
+	a.Equal(trace.SpanKindServer, httpSpan.SpanKind)
+	a.Equal(httpSpan.SpanContext.SpanID(), endpointSpan.Parent.SpanID())
+	a.Equal(endpointSpan.SpanContext.SpanID(), dsSpan.Parent.SpanID())
+	a.Equal(httpSpan.SpanContext.TraceID(), dsSpan.SpanContext.TraceID())
+	a.Contains(httpSpan.Attributes, semconv.HTTPStatusCodeKey.Int(200))
+
+	// errors of data store calls are recorded
+	exporter.Reset()
+	dsErr = errors.New(nil, "test", errors.Internal)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boards/b-123", nil))
+	spans = exporter.GetSpans()
+	a.Len(spans, 3)
+	a.Equal(codes.Error, findSpan(spans, "boards.Board").Status.Code)
+	a.Equal(codes.Error, findSpan(spans, "boards.getBoard").Status.Code)
+	// without a traceparent header a new trace is started
+	a.False(findSpan(spans, "GET /boards/{boardId}").Parent.IsValid())
+
+	// errors caused by invalid requests don't set the status of a span
+	exporter.Reset()
+	dsErr = errors.New(nil, "test", errors.NotFound)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boards/b-123", nil))
+	s := findSpan(exporter.GetSpans(), "boards.getBoard")
+	a.Equal(codes.Unset, s.Status.Code)
+	a.Contains(s.Attributes, errorCodeKey.String("NotFound"))
+}
+
+func TestGRPCTracing(t *testing.T) {
+	a := assert.New(t)
+
+	tp, exporter := NewInmemTracerProvider()
+	interceptor := GRPCUnaryServerInterceptor(tp)
+	info := &grpc.UnaryServerInfo{FullMethod: "/boards.v1.BoardService/GetBoard"}
+
+	var handlerErr error
+	endpoint := EndpointMiddleware(tp, "boards", "getBoard")(func(ctx context.Context, request interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, err := endpoint(ctx, req); err != nil {
+			return nil, err
+		}
+		return "ok", handlerErr
+	}
+
+	// the trace of the caller is continued
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	_, err := interceptor(ctx, nil, info, handler)
+	a.Nil(err)
+
+	spans := exporter.GetSpans()
+	a.Len(spans, 2)
+	grpcSpan := findSpan(spans, "boards.v1.BoardService/GetBoard")
+	a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", grpcSpan.SpanContext.TraceID().String())
+	a.True(grpcSpan.Parent.IsRemote())
+	a.Equal(trace.SpanKindServer, grpcSpan.SpanKind)
+	a.Contains(grpcSpan.Attributes, semconv.RPCMethodKey.String("GetBoard"))
+	a.Equal(grpcSpan.SpanContext.SpanID(), findSpan(spans, "boards.getBoard").Parent.SpanID())
+
+	// only server errors set the status of the span
+	exporter.Reset()
+	handlerErr = status.Error(grpccodes.NotFound, "not found")
+	_, err = interceptor(context.Background(), nil, info, handler)
+	a.NotNil(err)
+	grpcSpan = findSpan(exporter.GetSpans(), "boards.v1.BoardService/GetBoard")
+	a.Equal(codes.Unset, grpcSpan.Status.Code)
+	a.Contains(grpcSpan.Attributes, semconv.RPCGRPCStatusCodeKey.Int64(int64(grpccodes.NotFound)))
+	a.False(grpcSpan.Parent.IsValid())
+
+	exporter.Reset()
+	handlerErr = status.Error(grpccodes.Internal, "internal")
+	_, _ = interceptor(context.Background(), nil, info, handler)
+	a.Equal(codes.Error, findSpan(exporter.GetSpans(), "boards.v1.BoardService/GetBoard").Status.Code)
+}
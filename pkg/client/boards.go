@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func (c *Client) CreateBoard(ctx context.Context, nb NewBoard) (BoardWithUsersAndInvites, error) {
+	var board BoardWithUsersAndInvites
+	err := c.do(ctx, request{method: http.MethodPost, path: "/boards", body: nb}, &board)
+	return board, err
+}
+
+func (c *Client) DeleteBoard(ctx context.Context, boardId string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("/boards/%v", boardId)}, nil)
+}
+
+func (c *Client) EditBoard(ctx context.Context, boardId string, be BoardEdit) (Board, error) {
+	var board Board
+	err := c.do(ctx, request{method: http.MethodPatch, path: path("/boards/%v", boardId), body: be}, &board)
+	return board, err
+}
+
+// Returns the board with the given id, its users and invites are only included if the user is authorized to view them.
+// Boards with visibility "link" or "public" can also be requested by clients without a token.
+func (c *Client) GetBoard(ctx context.Context, boardId string) (BoardWithUsersAndInvites, error) {
+	var board BoardWithUsersAndInvites
+	err := c.do(ctx, request{method: http.MethodGet, path: path("/boards/%v", boardId)}, &board)
+	return board, err
+}
+
+// Returns the boards the user is a member of, sorted by the time the user joined the board, newest first.
+// The cursor is the time the user joined a board.
+func (c *Client) GetBoards(ctx context.Context, qp QueryParams) ([]Board, error) {
+	var boards []Board
+	err := c.do(ctx, request{method: http.MethodGet, path: "/boards", queryParams: queryParams(qp)}, &boards)
+	return boards, err
+}
+
+// Returns boards with visibility "public", sorted by created time, newest first.
+// Can be used without a token.
+func (c *Client) GetPublicBoards(ctx context.Context, qp QueryParams) ([]Board, error) {
+	var boards []Board
+	err := c.do(ctx, request{method: http.MethodGet, path: "/public/boards", queryParams: queryParams(qp)}, &boards)
+	return boards, err
+}
+
+// Iterates over all public boards, starting at the cursor of qp if set.
+// Pages of qp.Limit boards are requested.
+func (c *Client) AllPublicBoards(qp QueryParams) *Iterator[Board] {
+	return newIterator(func(ctx context.Context, last *Board) ([]Board, error) {
+		if last != nil {
+			qp.Cursor = last.CreatedTime - 1
+		}
+		return c.GetPublicBoards(ctx, qp)
+	}, qp.Limit)
+}
+
+func (c *Client) CreateInvite(ctx context.Context, boardId string, ni NewInvite) (BoardInvite, error) {
+	var invite BoardInvite
+	err := c.do(ctx, request{method: http.MethodPost, path: path("/boards/%v/invites", boardId), body: ni}, &invite)
+	return invite, err
+}
+
+func (c *Client) RespondToInvite(ctx context.Context, boardId string, inviteId string, ir InviteResponse) error {
+	return c.do(ctx, request{method: http.MethodPost, path: path("/boards/%v/invites/%v", boardId, inviteId), body: ir}, nil)
+}
+
+func (c *Client) DeleteInvite(ctx context.Context, boardId string, inviteId string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("/boards/%v/invites/%v", boardId, inviteId)}, nil)
+}
+
+// Returns the invites for the user, sorted by created time, newest first.
+func (c *Client) GetInvites(ctx context.Context, qp QueryParams) ([]BoardInvite, error) {
+	var invites []BoardInvite
+	err := c.do(ctx, request{method: http.MethodGet, path: "/invites", queryParams: queryParams(qp)}, &invites)
+	return invites, err
+}
+
+// Iterates over all invites for the user, starting at the cursor of qp if set.
+// Pages of qp.Limit invites are requested.
+func (c *Client) AllInvites(qp QueryParams) *Iterator[BoardInvite] {
+	return newIterator(func(ctx context.Context, last *BoardInvite) ([]BoardInvite, error) {
+		if last != nil {
+			qp.Cursor = last.CreatedTime - 1
+		}
+		return c.GetInvites(ctx, qp)
+	}, qp.Limit)
+}
+
+func (c *Client) CreateJoinRequest(ctx context.Context, boardId string) (JoinRequest, error) {
+	var jr JoinRequest
+	err := c.do(ctx, request{method: http.MethodPost, path: path("/boards/%v/joinRequests", boardId)}, &jr)
+	return jr, err
+}
+
+// Returns the pending join requests of a board, newest first.
+func (c *Client) GetJoinRequests(ctx context.Context, boardId string) ([]JoinRequest, error) {
+	var jrs []JoinRequest
+	err := c.do(ctx, request{method: http.MethodGet, path: path("/boards/%v/joinRequests", boardId)}, &jrs)
+	return jrs, err
+}
+
+func (c *Client) RespondToJoinRequest(ctx context.Context, boardId string, joinRequestId string, jrr JoinRequestResponse) error {
+	return c.do(ctx, request{method: http.MethodPost, path: path("/boards/%v/joinRequests/%v", boardId, joinRequestId), body: jrr}, nil)
+}
+
+func (c *Client) RemoveUserFromBoard(ctx context.Context, boardId string, userId string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("/boards/%v/users/%v", boardId, userId)}, nil)
+}
+
+func (c *Client) EditBoardUser(ctx context.Context, boardId string, userId string, bue BoardUserEdit) (BoardUser, error) {
+	var user BoardUser
+	err := c.do(ctx, request{method: http.MethodPatch, path: path("/boards/%v/users/%v", boardId, userId), body: bue}, &user)
+	return user, err
+}
+
+// Creates a custom role or replaces the scopes of an existing one.
+func (c *Client) SetCustomRole(ctx context.Context, boardId string, role string, scopes []string) (Board, error) {
+	var board Board
+	body := struct {
+		Scopes []string `json:"scopes"`
+	}{Scopes: scopes}
+	err := c.do(ctx, request{method: http.MethodPut, path: path("/boards/%v/roles/%v", boardId, role), body: body}, &board)
+	return board, err
+}
+
+func (c *Client) DeleteCustomRole(ctx context.Context, boardId string, role string) (Board, error) {
+	var board Board
+	err := c.do(ctx, request{method: http.MethodDelete, path: path("/boards/%v/roles/%v", boardId, role)}, &board)
+	return board, err
+}
+
+// Returns the boards of the user with their member count and top links, as well as the invites of the user.
+// linksPerBoard must be between 1 and 10, the API uses 3 if it is 0.
+func (c *Client) GetDashboard(ctx context.Context, linksPerBoard int) (Dashboard, error) {
+	var dashboard Dashboard
+	qp := url.Values{}
+	addQueryParam(qp, "links", int64(linksPerBoard))
+	err := c.do(ctx, request{method: http.MethodGet, path: "/me/dashboard", queryParams: qp}, &dashboard)
+	return dashboard, err
+}
+
+func queryParams(qp QueryParams) url.Values {
+	result := url.Values{}
+	addQueryParam(result, "limit", int64(qp.Limit))
+	addQueryParam(result, "cursor", qp.Cursor)
+	return result
+}
@@ -0,0 +1,347 @@
+// Package client is a Go client for the HTTP API of the application, as described in api/openapi.yaml.
+//
+// Create a client with New, every method takes a context that can be used to cancel the request.
+// Failed requests return an *Error that contains the http status code as well as the error code and message of the API,
+// use e.g. IsNotFound or errors.As to inspect it.
+//
+// Idempotent requests (GET, PUT and DELETE) are retried with exponential backoff if they fail because of a network error
+// or the API responds with status 429, 502, 503 or 504. For status 429 the Retry-After header of the response is respected,
+// if it is longer than 10 seconds the error is returned without retrying.
+//
+// Paginated queries can be iterated with the All* methods, e.g. AllLinks, which fetch the next page when needed.
+// The boards of a user (GetBoards) cannot be iterated automatically, since its cursor is the time the user joined a board,
+// which is not part of the response.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 30 * time.Second
+const defaultMaxRetries = 3
+const defaultRetryBackoff = 200 * time.Millisecond
+const maxRetryBackoff = 10 * time.Second
+
+type Config struct {
+	// Base URL of the API, e.g. "https://api.example.com" or "http://localhost:9001".
+	// The paths of requests are appended to the path of the URL.
+	BaseURL string
+
+	// Sent as bearer token in the Authorization header, can be a JWT or a personal access token.
+	// If both Token and Authorization are empty, requests are unauthenticated.
+	Token string
+	// If set, is sent as Authorization header instead of Token, e.g. "Basic ..." for the fake authentication of the API.
+	Authorization string
+
+	// Used to perform requests. If nil, a client with the given TLSConfig and Timeout is created.
+	HTTPClient *http.Client
+	// TLS configuration for https connections, e.g. to trust additional root certificates.
+	TLSConfig *tls.Config
+	// Timeout of a single request, defaults to 30 seconds. Not used for streams.
+	Timeout time.Duration
+
+	// Maximum number of times an idempotent request is retried, defaults to 3.
+	// Set to a negative value to disable retries.
+	MaxRetries int
+	// Backoff before the first retry, doubled for every further retry. Defaults to 200ms.
+	RetryBackoff time.Duration
+}
+
+type Client struct {
+	baseURL       url.URL
+	authorization string
+
+	httpClient *http.Client
+	// Used for long-lived requests like streams, has no timeout.
+	streamClient *http.Client
+	tlsConfig    *tls.Config
+	timeout      time.Duration
+
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+func New(config Config) (*Client, error) {
+	u, err := url.Parse(config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: scheme must be http or https", config.BaseURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q: host is missing", config.BaseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	c := &Client{
+		baseURL:      *u,
+		tlsConfig:    config.TLSConfig,
+		timeout:      config.Timeout,
+		maxRetries:   config.MaxRetries,
+		retryBackoff: config.RetryBackoff,
+	}
+
+	if config.Authorization != "" {
+		c.authorization = config.Authorization
+	} else if config.Token != "" {
+		c.authorization = "Bearer " + config.Token
+	}
+
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = defaultMaxRetries
+	} else if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.retryBackoff <= 0 {
+		c.retryBackoff = defaultRetryBackoff
+	}
+
+	if config.HTTPClient != nil {
+		c.httpClient = config.HTTPClient
+		c.streamClient = config.HTTPClient
+	} else {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if config.TLSConfig != nil {
+			transport.TLSClientConfig = config.TLSConfig
+		}
+		c.httpClient = &http.Client{Transport: transport, Timeout: c.timeout}
+		c.streamClient = &http.Client{Transport: transport}
+	}
+
+	return c, nil
+}
+
+type statusCodeKey struct{}
+
+// Returns a context that records the http status code of every response to a request made with it in statusCode,
+// e.g. to check whether the API created a resource (201) or returned an existing one (200).
+// Error responses are recorded as well, for retried requests the status of the last attempt remains.
+// The context must not be used for concurrent requests.
+func WithStatusCode(ctx context.Context, statusCode *int) context.Context {
+	return context.WithValue(ctx, statusCodeKey{}, statusCode)
+}
+
+// Returns a copy of the client that uses the given token, e.g. to perform requests for another user.
+func (c *Client) WithToken(token string) *Client {
+	result := *c
+	result.authorization = "Bearer " + token
+	return &result
+}
+
+type request struct {
+	method      string
+	path        string
+	queryParams url.Values
+	// Encoded as JSON if not nil.
+	body interface{}
+	// If set, is sent as the request body instead of the JSON encoding of body.
+	rawBody     []byte
+	contentType string
+	headers     map[string]string
+	// Status code >= 300 that is not treated as an error.
+	allowStatus int
+	// If true, the request has no timeout and is not retried.
+	stream bool
+}
+
+// Returns a path with the given path parameters escaped, e.g. path("/boards/%v", boardId).
+func path(format string, params ...string) string {
+	escaped := make([]interface{}, len(params))
+	for i, p := range params {
+		escaped[i] = url.PathEscape(p)
+	}
+	return fmt.Sprintf(format, escaped...)
+}
+
+// The path p must already be escaped, see path.
+func (c *Client) url(p string, queryParams url.Values) string {
+	u := c.baseURL
+	unescaped, err := url.PathUnescape(p)
+	if err != nil {
+		unescaped = p
+	}
+	u.Path = c.baseURL.Path + unescaped
+	u.RawPath = c.baseURL.EscapedPath() + p
+	u.RawQuery = queryParams.Encode()
+	return u.String()
+}
+
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete || method == http.MethodHead
+}
+
+// Performs a request and decodes the JSON response body into result, if result is not nil.
+// If result is an io.Writer, the response body is copied to it as is.
+func (c *Client) do(ctx context.Context, r request, result interface{}) error {
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if w, ok := result.(io.Writer); ok {
+		if _, err := io.Copy(w, resp.Body); err != nil {
+			return fmt.Errorf("could not read response body: %w", err)
+		}
+	} else if result != nil {
+		return decodeJSON(resp.Body, result)
+	}
+	return nil
+}
+
+func decodeJSON(body io.Reader, result interface{}) error {
+	if err := json.NewDecoder(body).Decode(result); err != nil {
+		return fmt.Errorf("could not decode response body: %w", err)
+	}
+	return nil
+}
+
+// Performs a request, retrying it if possible. Returns an *Error if the API responded with an error status.
+// The caller must close the body of the returned response.
+func (c *Client) send(ctx context.Context, r request) (*http.Response, error) {
+	var body []byte
+	contentType := r.contentType
+	if r.rawBody != nil {
+		body = r.rawBody
+	} else if r.body != nil {
+		var err error
+		body, err = json.Marshal(r.body)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode request body: %w", err)
+		}
+		contentType = "application/json"
+	}
+
+	maxRetries := c.maxRetries
+	if !isIdempotent(r.method) || r.stream {
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendOnce(ctx, r, body, contentType)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= maxRetries || !isRetryable(ctx, err) {
+			return nil, err
+		}
+
+		wait := c.backoff(attempt)
+		var e *Error
+		if errors.As(err, &e) && e.RetryAfter > 0 {
+			// e.g. a daily quota is used up, waiting until it resets would block the caller for hours
+			if e.RetryAfter > maxRetryBackoff {
+				return nil, err
+			}
+			wait = e.RetryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, r request, body []byte, contentType string) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, c.url(r.path, r.queryParams), bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+
+	hc := c.httpClient
+	if r.stream {
+		hc = c.streamClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, &networkError{err: err}
+	}
+	if statusCode, ok := ctx.Value(statusCodeKey{}).(*int); ok {
+		*statusCode = resp.StatusCode
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotModified && resp.StatusCode != r.allowStatus {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
+
+// Returns the backoff before the given retry (starting at 0), with some jitter so that clients don't retry at the same time.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retryBackoff << attempt
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// The request could not be performed or no response was received.
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string {
+	return "request failed: " + e.err.Error()
+}
+
+func (e *networkError) Unwrap() error {
+	return e.err
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var ne *networkError
+	if errors.As(err, &ne) {
+		return true
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		switch e.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+func addQueryParam(qp url.Values, key string, value int64) {
+	if value != 0 {
+		qp.Set(key, strconv.FormatInt(value, 10))
+	}
+}
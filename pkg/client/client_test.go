@@ -0,0 +1,367 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, server *httptest.Server, config Config) *Client {
+	config.BaseURL = server.URL + config.BaseURL
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Millisecond
+	}
+	c, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNewValidatesBaseURL(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := New(Config{BaseURL: "localhost:9001"})
+	a.NotNil(err)
+	_, err = New(Config{BaseURL: "ftp://localhost:9001"})
+	a.NotNil(err)
+	_, err = New(Config{BaseURL: "http://"})
+	a.NotNil(err)
+
+	c, err := New(Config{BaseURL: "https://api.example.com/v1/"})
+	a.Nil(err)
+	a.Equal("https://api.example.com/v1/boards/b%2F1?limit=5", c.url(path("/boards/%v", "b/1"), map[string][]string{"limit": {"5"}}))
+}
+
+func TestRequestsUseBaseURLAndToken(t *testing.T) {
+	a := assert.New(t)
+
+	var gotPath, gotQuery, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotAuth = r.URL.EscapedPath(), r.URL.RawQuery, r.Header.Get("Authorization")
+		w.Write([]byte(`[{"boardId":"b-1","name":"Go"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, Config{BaseURL: "/api", Token: "token-1"})
+	boards, err := c.GetBoards(context.Background(), QueryParams{Limit: 5, Cursor: 10})
+	a.Nil(err)
+	a.Equal([]Board{{BoardId: "b-1", Name: "Go"}}, boards)
+	a.Equal("/api/boards", gotPath)
+	a.Equal("cursor=10&limit=5", gotQuery)
+	a.Equal("Bearer token-1", gotAuth)
+
+	_, err = c.WithToken("token-2").GetBoards(context.Background(), QueryParams{})
+	a.Nil(err)
+	a.Equal("Bearer token-2", gotAuth)
+	a.Equal("", gotQuery)
+
+	c = newTestClient(t, server, Config{Authorization: "Basic dXNlcjo="})
+	_, err = c.GetBoards(context.Background(), QueryParams{})
+	a.Nil(err)
+	a.Equal("Basic dXNlcjo=", gotAuth)
+}
+
+func TestTLS(t *testing.T) {
+	a := assert.New(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	// the certificate of the test server is not trusted by default
+	c := newTestClient(t, server, Config{MaxRetries: -1})
+	_, err := c.Health(context.Background())
+	a.NotNil(err)
+
+	c = newTestClient(t, server, Config{TLSConfig: server.Client().Transport.(*http.Transport).TLSClientConfig})
+	status, err := c.Health(context.Background())
+	a.Nil(err)
+	a.Equal("ok", status.Status)
+}
+
+func TestErrorsAreDecoded(t *testing.T) {
+	a := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/boards/b-1":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":12,"message":"user not on board"}}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	_, err := c.GetBoard(context.Background(), "b-1")
+	var e *Error
+	a.True(errors.As(err, &e))
+	a.Equal(&Error{StatusCode: 404, Code: BoardErrUserNotOnBoard, Message: "user not on board"}, e)
+	a.True(IsNotFound(err))
+	a.Equal(BoardErrUserNotOnBoard, ErrorCode(err))
+	a.Equal("api error (status 404, code 12): user not on board", err.Error())
+
+	// responses without a body
+	err = c.DeleteBoard(context.Background(), "b-2")
+	a.True(IsForbidden(err))
+	a.Equal(0, ErrorCode(err))
+	a.Equal("api error (status 403): Forbidden", err.Error())
+
+	a.Equal(0, StatusCode(errors.New("other error")))
+}
+
+func TestStatusCodeIsRecorded(t *testing.T) {
+	a := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"boardId":"b-1"}`))
+		case http.MethodGet:
+			w.Write([]byte(`{"boardId":"b-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	var status int
+	ctx := WithStatusCode(context.Background(), &status)
+	_, err := c.CreateBoard(ctx, NewBoard{Name: "Go"})
+	a.Nil(err)
+	a.Equal(201, status)
+	_, err = c.GetBoard(ctx, "b-1")
+	a.Nil(err)
+	a.Equal(200, status)
+	err = c.DeleteBoard(ctx, "b-1")
+	a.True(IsNotFound(err))
+	a.Equal(404, status)
+}
+
+func TestRetries(t *testing.T) {
+	a := assert.New(t)
+
+	var requests int32
+	failures := int32(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if n <= atomic.LoadInt32(&failures) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"boardId":"b-1"}`))
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{MaxRetries: 2})
+
+	// idempotent requests are retried
+	_, err := c.GetBoard(context.Background(), "b-1")
+	a.Nil(err)
+	a.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	// give up after the maximum number of retries
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&failures, 5)
+	err = c.DeleteBoard(context.Background(), "b-1")
+	a.True(IsTooManyRequests(err))
+	a.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	// other requests are not retried
+	atomic.StoreInt32(&requests, 0)
+	_, err = c.CreateBoard(context.Background(), NewBoard{Name: "Go"})
+	a.True(IsTooManyRequests(err))
+	a.Equal(int32(1), atomic.LoadInt32(&requests))
+
+	// retries can be disabled
+	atomic.StoreInt32(&requests, 0)
+	c = newTestClient(t, server, Config{MaxRetries: -1})
+	_, err = c.GetBoard(context.Background(), "b-1")
+	a.NotNil(err)
+	a.Equal(int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	a := assert.New(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	_, err := c.GetBoard(context.Background(), "b-1")
+	a.True(IsBadRequest(err))
+	a.Equal(int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRetryAfterIsDecoded(t *testing.T) {
+	a := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{MaxRetries: -1})
+
+	_, err := c.GetBoard(context.Background(), "b-1")
+	var e *Error
+	a.True(errors.As(err, &e))
+	a.Equal(30*time.Second, e.RetryAfter)
+
+	// waiting for a retry is stopped when the context is canceled
+	c = newTestClient(t, server, Config{})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.GetBoard(ctx, "b-1")
+	a.True(IsTooManyRequests(err))
+	a.Less(time.Since(start), 5*time.Second)
+}
+
+func TestLongRetryAfterIsNotWaitedFor(t *testing.T) {
+	a := assert.New(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	start := time.Now()
+	_, err := c.GetBoard(context.Background(), "b-1")
+	a.True(IsTooManyRequests(err))
+	a.Equal(int32(1), atomic.LoadInt32(&requests))
+	a.Less(time.Since(start), 5*time.Second)
+}
+
+func TestAllLinks(t *testing.T) {
+	a := assert.New(t)
+
+	// links sorted by score and created time, descending
+	var links []Link
+	for i := 0; i < 7; i++ {
+		links = append(links, Link{LinkId: strconv.Itoa(i), Score: 3 - i/2, CreatedTime: int64(100 - i%2)})
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		var result []Link
+		for _, l := range links {
+			if q.Has("cursorScore") {
+				score, _ := strconv.Atoi(q.Get("cursorScore"))
+				createdTime, _ := strconv.ParseInt(q.Get("cursorCreatedTime"), 10, 64)
+				if l.Score > score || (l.Score == score && l.CreatedTime > createdTime) {
+					continue
+				}
+			}
+			if len(result) < limit {
+				result = append(result, l)
+			}
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	it := c.AllLinks("b-1", LinkQueryParams{Limit: 3, Sort: LinkSortTop})
+	var result []string
+	for it.Next(context.Background()) {
+		result = append(result, it.Value().LinkId)
+	}
+	a.Nil(it.Err())
+	a.Equal([]string{"0", "1", "2", "3", "4", "5", "6"}, result)
+	a.Equal([]string{
+		"limit=3&sort=top",
+		"cursorCreatedTime=99&cursorScore=2&limit=3&sort=top",
+		"cursorCreatedTime=98&cursorScore=1&limit=3&sort=top",
+	}, queries)
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	a := assert.New(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"boardId":"b-1","createdTime":10},{"boardId":"b-2","createdTime":5}]`))
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	it := c.AllPublicBoards(QueryParams{Limit: 2})
+	var result []string
+	for it.Next(context.Background()) {
+		result = append(result, it.Value().BoardId)
+	}
+	a.Equal([]string{"b-1", "b-2"}, result)
+	a.True(IsUnauthenticated(it.Err()))
+	a.False(it.Next(context.Background()))
+}
+
+func TestStreamBoardEvents(t *testing.T) {
+	a := assert.New(t)
+
+	var lastEventId string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventId = r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "retry: 3000\n\n")
+		io.WriteString(w, "id: 3\nevent: linkCreated\ndata: {\"linkId\":\"l-1\",\"title\":\"Go\"}\n\n")
+		io.WriteString(w, ": heartbeat\n\n")
+		io.WriteString(w, "id: 4\nevent: linkRated\ndata: {\"linkId\":\"l-1\",\n")
+		io.WriteString(w, "data: \"rating\":-1}\n\n")
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, Config{})
+
+	stream, err := c.StreamBoardEvents(context.Background(), "b-1", "2")
+	a.Nil(err)
+	defer stream.Close()
+	a.Equal("2", lastEventId)
+
+	event, err := stream.Next()
+	a.Nil(err)
+	a.Equal("3", event.Id)
+	a.Equal(EventLinkCreated, event.Type)
+	payload, err := event.Payload()
+	a.Nil(err)
+	a.Equal(EventPayload{LinkId: "l-1", Title: "Go"}, payload)
+
+	event, err = stream.Next()
+	a.Nil(err)
+	a.Equal(EventLinkRated, event.Type)
+	payload, err = event.Payload()
+	a.Nil(err)
+	a.Equal(EventPayload{LinkId: "l-1", Rating: -1}, payload)
+	a.Equal("4", stream.LastEventId())
+
+	_, err = stream.Next()
+	a.Equal(io.EOF, err)
+}
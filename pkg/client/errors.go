@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error codes returned by board endpoints, see the responses in api/openapi.yaml.
+const (
+	BoardErrEmptyName            = 1
+	BoardErrNameTooLong          = 2
+	BoardErrDescriptionTooLong   = 3
+	BoardErrInvalidRole          = 4
+	BoardErrMaxInvites           = 5
+	BoardErrBoardFull            = 6
+	BoardErrUserAlreadyInvited   = 7
+	BoardErrUserAlreadyOnBoard   = 8
+	BoardErrInviteExpired        = 9
+	BoardErrInviteForOtherUser   = 10
+	BoardErrCannotDeclineInvite  = 11
+	BoardErrUserNotOnBoard       = 12
+	BoardErrCannotRemoveOwner    = 13
+	BoardErrOwnerRoleOnlyCreator = 14
+	BoardErrCannotChangeOwner    = 15
+	BoardErrInvalidCustomRole    = 16
+	BoardErrMaxCustomRoles       = 17
+	BoardErrCustomRoleInUse      = 18
+	BoardErrInvalidVisibility    = 19
+	BoardErrAlreadyRequested     = 20
+	BoardErrBoardQuotaReached    = 21
+)
+
+// Error codes returned by link endpoints, see the responses in api/openapi.yaml.
+const (
+	LinkErrEmptyTitle       = 1
+	LinkErrTitleTooLong     = 2
+	LinkErrEmptyUrl         = 3
+	LinkErrInvalidUrl       = 4
+	LinkErrInsecureUrl      = 5
+	LinkErrInvalidRating    = 6
+	LinkErrTooManyTags      = 7
+	LinkErrInvalidTag       = 8
+	LinkErrLinkQuotaReached = 9
+)
+
+// Returned by the methods of Client if the API responded with an error status.
+type Error struct {
+	// Http status code of the response.
+	StatusCode int
+	// Error code of the API, 0 if the response did not contain one.
+	// The meaning of codes depends on the endpoint, see e.g. the BoardErr* and LinkErr* constants.
+	Code int
+	// Public error message of the API, can be empty.
+	Message string
+	// Set if the response had status 429 and a Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != 0 {
+		return fmt.Sprintf("api error (status %v, code %v): %v", e.StatusCode, e.Code, msg)
+	}
+	return fmt.Sprintf("api error (status %v): %v", e.StatusCode, msg)
+}
+
+// Decodes the JSON error body of the API:
+//
+//	{"error": {"code": 1, "message": "..."}}
+func decodeError(resp *http.Response) *Error {
+	e := &Error{StatusCode: resp.StatusCode}
+	var body struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil {
+		e.Code = body.Error.Code
+		e.Message = body.Error.Message
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if seconds, err := strconv.Atoi(s); err == nil && seconds >= 0 {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return e
+}
+
+// Returns the http status code of the error, or 0 if err is not an *Error.
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// Returns the error code of the API, or 0 if err is not an *Error.
+func ErrorCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return 0
+}
+
+func IsBadRequest(err error) bool {
+	return StatusCode(err) == http.StatusBadRequest
+}
+
+func IsUnauthenticated(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// The user is authenticated but does not have permission to perform the request.
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+func IsTooManyRequests(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
@@ -0,0 +1,73 @@
+package client
+
+import "context"
+
+// Iterates over the results of a paginated query, requesting the next page when needed.
+//
+//	it := c.AllLinks(boardId, LinkQueryParams{Sort: LinkSortTop})
+//	for it.Next(ctx) {
+//		link := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	// Returns the page after the given element, or the first page if last is nil.
+	fetch func(ctx context.Context, last *T) ([]T, error)
+	// Number of elements requested per page, 0 if the API default is used.
+	pageSize int
+
+	page    []T
+	index   int
+	last    *T
+	done    bool
+	err     error
+	current T
+}
+
+func newIterator[T any](fetch func(ctx context.Context, last *T) ([]T, error), pageSize int) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, pageSize: pageSize, index: -1}
+}
+
+// Advances to the next element and returns true, or returns false if there are no more elements or an error occurred.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		it.current = it.page[it.index]
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	page, err := it.fetch(ctx, it.last)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	// a page with less elements than requested is the last one
+	if len(page) == 0 || (it.pageSize > 0 && len(page) < it.pageSize) {
+		it.done = true
+	}
+	if len(page) == 0 {
+		return false
+	}
+	it.page = page
+	it.index = 0
+	it.last = &page[len(page)-1]
+	it.current = page[0]
+	return true
+}
+
+// Returns the current element, i.e. the one Next advanced to.
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) CreateLink(ctx context.Context, boardId string, nl NewLink) (Link, error) {
+	var link Link
+	err := c.do(ctx, request{method: http.MethodPost, path: path("/boards/%v/links", boardId), body: nl}, &link)
+	return link, err
+}
+
+func (c *Client) DeleteLink(ctx context.Context, boardId string, linkId string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("/boards/%v/links/%v", boardId, linkId)}, nil)
+}
+
+// Rating must be 1 for an upvote or -1 for a downvote.
+func (c *Client) RateLink(ctx context.Context, boardId string, linkId string, lr LinkRating) error {
+	return c.do(ctx, request{method: http.MethodPost, path: path("/boards/%v/links/%v/ratings", boardId, linkId), body: lr}, nil)
+}
+
+func (c *Client) GetLink(ctx context.Context, boardId string, linkId string) (Link, error) {
+	var link Link
+	err := c.do(ctx, request{method: http.MethodGet, path: path("/boards/%v/links/%v", boardId, linkId)}, &link)
+	return link, err
+}
+
+// Returns links of a board sorted by newest or top, use LinkQueryParams.After to get the next page.
+func (c *Client) GetLinks(ctx context.Context, boardId string, qp LinkQueryParams) ([]Link, error) {
+	uqp := url.Values{}
+	addQueryParam(uqp, "limit", int64(qp.Limit))
+	if qp.Sort != "" {
+		uqp.Set("sort", qp.Sort)
+	}
+	if qp.CursorScore != nil {
+		uqp.Set("cursorScore", strconv.Itoa(*qp.CursorScore))
+	}
+	if qp.CursorCreatedTime != nil {
+		uqp.Set("cursorCreatedTime", strconv.FormatInt(*qp.CursorCreatedTime, 10))
+	}
+
+	var links []Link
+	err := c.do(ctx, request{method: http.MethodGet, path: path("/boards/%v/links", boardId), queryParams: uqp}, &links)
+	return links, err
+}
+
+// Iterates over all links of a board in the order given by qp, starting at its cursors if set.
+// Pages of qp.Limit links are requested.
+func (c *Client) AllLinks(boardId string, qp LinkQueryParams) *Iterator[Link] {
+	return newIterator(func(ctx context.Context, last *Link) ([]Link, error) {
+		if last != nil {
+			qp = qp.After(*last)
+		}
+		return c.GetLinks(ctx, boardId, qp)
+	}, qp.Limit)
+}
+
+// Imports the links from a bookmark file in the Netscape bookmark file format, as exported by most browsers.
+func (c *Client) ImportBookmarks(ctx context.Context, boardId string, bookmarks io.Reader) (LinkImportResult, error) {
+	var result LinkImportResult
+	body, err := io.ReadAll(bookmarks)
+	if err != nil {
+		return result, err
+	}
+	err = c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        path("/boards/%v/bookmarks", boardId),
+		rawBody:     body,
+		contentType: "text/html",
+	}, &result)
+	return result, err
+}
+
+// Writes the links of a board to w as a bookmark file in the Netscape bookmark file format.
+// Only the newest links are exported, returns true if the board has more links than were written to w.
+func (c *Client) ExportBookmarks(ctx context.Context, boardId string, w io.Writer) (bool, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: path("/boards/%v/bookmarks", boardId)})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// buffer the response, so that nothing is written to w if reading the body fails
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, resp.Body); err != nil {
+		return false, fmt.Errorf("could not read response body: %w", err)
+	}
+	if _, err := buf.WriteTo(w); err != nil {
+		return false, err
+	}
+	return resp.Header.Get("Linkboards-Export-Truncated") == "true", nil
+}
+
+// Returns the token that authenticates the user when requesting feeds.
+func (c *Client) GetFeedToken(ctx context.Context) (string, error) {
+	var result struct {
+		Token string `json:"token"`
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/me/feedtoken"}, &result)
+	return result.Token, err
+}
+
+// Revokes the feed token of the user and returns a new one, feed URLs containing the previous token stop working.
+func (c *Client) RenewFeedToken(ctx context.Context) (string, error) {
+	var result struct {
+		Token string `json:"token"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/me/feedtoken"}, &result)
+	return result.Token, err
+}
+
+// Returns the newest links of a board as Atom or RSS feed, authenticated with the given feed token.
+// If ifNoneMatch is the ETag of a previous response and the feed has not changed, the result has NotModified set.
+func (c *Client) GetFeed(ctx context.Context, boardId string, format string, feedToken string, ifNoneMatch string) (Feed, error) {
+	r := request{
+		method:      http.MethodGet,
+		path:        path("/boards/%v/feed.%v", boardId, format),
+		queryParams: url.Values{"token": []string{feedToken}},
+	}
+	if ifNoneMatch != "" {
+		r.headers = map[string]string{"If-None-Match": ifNoneMatch}
+	}
+	// feeds are authenticated with the token in the url
+	client := *c
+	client.authorization = ""
+	resp, err := client.send(ctx, r)
+	if err != nil {
+		return Feed{}, err
+	}
+	defer resp.Body.Close()
+
+	feed := Feed{ETag: resp.Header.Get("ETag")}
+	if resp.StatusCode == http.StatusNotModified {
+		feed.NotModified = true
+		return feed, nil
+	}
+	feed.Body, err = io.ReadAll(resp.Body)
+	return feed, err
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Creates a personal access token, the token itself is only returned by this method.
+// Cannot be called with a client that uses an access token.
+func (c *Client) CreateAccessToken(ctx context.Context, nt NewAccessToken) (CreatedAccessToken, error) {
+	var token CreatedAccessToken
+	err := c.do(ctx, request{method: http.MethodPost, path: "/me/tokens", body: nt}, &token)
+	return token, err
+}
+
+// Returns the access tokens of the user, including expired ones.
+func (c *Client) GetAccessTokens(ctx context.Context) ([]AccessToken, error) {
+	var tokens []AccessToken
+	err := c.do(ctx, request{method: http.MethodGet, path: "/me/tokens"}, &tokens)
+	return tokens, err
+}
+
+func (c *Client) RevokeAccessToken(ctx context.Context, tokenId string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("/me/tokens/%v", tokenId)}, nil)
+}
+
+// Executes a GraphQL query or mutation.
+// Errors of individual fields are returned in the Errors of the response, not as error.
+func (c *Client) GraphQL(ctx context.Context, r GraphQLRequest) (GraphQLResponse, error) {
+	var resp GraphQLResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/graphql", body: r}, &resp)
+	return resp, err
+}
+
+// Returns status "ok" as long as the API is running.
+func (c *Client) Health(ctx context.Context) (HealthStatus, error) {
+	var status HealthStatus
+	err := c.do(ctx, request{method: http.MethodGet, path: "/healthz"}, &status)
+	return status, err
+}
+
+// Returns the result of the readiness checks of the API.
+// If a check failed, the status is returned together with an *Error with status 503.
+func (c *Client) Ready(ctx context.Context) (HealthStatus, error) {
+	var status HealthStatus
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/readyz", allowStatus: http.StatusServiceUnavailable})
+	if err != nil {
+		return status, err
+	}
+	defer resp.Body.Close()
+	if err := decodeJSON(resp.Body, &status); err != nil {
+		return status, err
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return status, &Error{StatusCode: resp.StatusCode, Message: "not ready"}
+	}
+	return status, nil
+}
+
+func (c *Client) Version(ctx context.Context) (VersionInfo, error) {
+	var info VersionInfo
+	err := c.do(ctx, request{method: http.MethodGet, path: "/version"}, &info)
+	return info, err
+}
+
+// Writes the metrics of the API in the Prometheus text format to w.
+func (c *Client) Metrics(ctx context.Context, w io.Writer) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/metrics"}, w)
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Types of events of a board.
+const (
+	EventLinkCreated      = "linkCreated"
+	EventLinkDeleted      = "linkDeleted"
+	EventLinkRated        = "linkRated"
+	EventBoardUserAdded   = "boardUserAdded"
+	EventBoardUserEdited  = "boardUserEdited"
+	EventBoardUserRemoved = "boardUserRemoved"
+	EventBoardDeleted     = "boardDeleted"
+	// Sent first when resuming a stream if some of the missed events are no longer available, the board should be reloaded.
+	EventReset = "reset"
+)
+
+// An event of a board, received from a stream or WebSocket subscription.
+type Event struct {
+	Id string
+	// One of the Event* constants.
+	Type string
+	// JSON payload of the event, use Payload to decode it.
+	Data json.RawMessage
+}
+
+// Payload of an event, which fields are set depends on the type of the event.
+type EventPayload struct {
+	LinkId      string   `json:"linkId,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Url         string   `json:"url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	CreatedTime int64    `json:"createdTime,omitempty"`
+	CreatedBy   *User    `json:"createdBy,omitempty"`
+	DeletedBy   *User    `json:"deletedBy,omitempty"`
+	UserId      string   `json:"userId,omitempty"`
+	Rating      int      `json:"rating,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Role        string   `json:"role,omitempty"`
+}
+
+func (e Event) Payload() (EventPayload, error) {
+	var p EventPayload
+	err := json.Unmarshal(e.Data, &p)
+	return p, err
+}
+
+// Stream of the events of a board, received as server-sent events.
+type EventStream struct {
+	body        io.ReadCloser
+	reader      *bufio.Reader
+	lastEventId string
+}
+
+// Opens a stream of the events of a board. If lastEventId is not empty, the stream is resumed after this event.
+// The stream ends when the context is canceled, the user is no longer allowed to view the links of the board or the board is deleted.
+// Unlike other requests, the stream has no timeout and is not retried.
+func (c *Client) StreamBoardEvents(ctx context.Context, boardId string, lastEventId string) (*EventStream, error) {
+	r := request{
+		method:  http.MethodGet,
+		path:    path("/boards/%v/stream", boardId),
+		headers: map[string]string{"Accept": "text/event-stream"},
+		stream:  true,
+	}
+	if lastEventId != "" {
+		r.headers["Last-Event-ID"] = lastEventId
+	}
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{body: resp.Body, reader: bufio.NewReader(resp.Body), lastEventId: lastEventId}, nil
+}
+
+// Blocks until the next event is received. Returns io.EOF when the stream has ended.
+func (s *EventStream) Next() (Event, error) {
+	var event Event
+	var data []string
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" {
+				return Event{}, io.EOF
+			}
+			if err != io.EOF {
+				return Event{}, err
+			}
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			// an empty line ends an event, events without data are e.g. retry instructions
+			if len(data) > 0 {
+				event.Data = json.RawMessage(strings.Join(data, "\n"))
+				if event.Id != "" {
+					s.lastEventId = event.Id
+				}
+				return event, nil
+			}
+			event = Event{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comment, e.g. heartbeat
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.Id = value
+		case "event":
+			event.Type = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
+
+// Returns the id of the last event received, can be used to resume the stream.
+func (s *EventStream) LastEventId() string {
+	return s.lastEventId
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
+
+// Types of messages sent over a WebSocket connection.
+const (
+	MessageTypeSubscribe   = "subscribe"
+	MessageTypeUnsubscribe = "unsubscribe"
+	// Commands, the data of the message is a NewLink or LinkRating respectively.
+	MessageTypeCreateLink = "createLink"
+	MessageTypeRateLink   = "rateLink"
+
+	MessageTypeResult = "result"
+	MessageTypeError  = "error"
+	MessageTypeEvent  = "event"
+	// A subscription was ended by the API, the Reason of the message contains one of the Reason* constants.
+	MessageTypeUnsubscribed = "unsubscribed"
+)
+
+// Reasons for ending a subscription.
+const (
+	ReasonUnauthorized = "unauthorized"
+	ReasonBoardDeleted = "boardDeleted"
+	// The client could not keep up with the events, it can subscribe again with the id of the last event it received.
+	ReasonOverflow = "overflow"
+)
+
+// Message sent to the API over a WebSocket connection.
+type ClientMessage struct {
+	// Chosen by the client, the response to this message has the same id.
+	Id string `json:"id"`
+	// One of the MessageType* constants.
+	Type    string `json:"type"`
+	BoardId string `json:"boardId"`
+	LinkId  string `json:"linkId,omitempty"`
+	// Resume a subscription after the event with this id.
+	LastEventId uint64 `json:"lastEventId,omitempty"`
+	// Arguments of a command, e.g. a NewLink for MessageTypeCreateLink.
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Message received from the API over a WebSocket connection.
+type ServerMessage struct {
+	// One of MessageTypeResult, MessageTypeError, MessageTypeEvent and MessageTypeUnsubscribed.
+	Type string `json:"type"`
+	// Id of the client message this message is a response to.
+	Id      string `json:"id,omitempty"`
+	BoardId string `json:"boardId,omitempty"`
+	EventId uint64 `json:"eventId,omitempty"`
+	// Type of the event, one of the Event* constants.
+	Event string `json:"event,omitempty"`
+	// Why a subscription was ended, one of the Reason* constants.
+	Reason string `json:"reason,omitempty"`
+	// Result of a command or payload of an event.
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error *MessageError   `json:"error,omitempty"`
+}
+
+// Returns the event of a message of type MessageTypeEvent.
+func (m ServerMessage) ToEvent() Event {
+	return Event{Id: fmt.Sprint(m.EventId), Type: m.Event, Data: m.Data}
+}
+
+type MessageError struct {
+	// Http status code the HTTP API would have returned for the same error.
+	Status  int    `json:"status"`
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Returns the error as *Error, so that it can be inspected like errors of other requests.
+func (e *MessageError) Err() error {
+	return &Error{StatusCode: e.Status, Code: e.Code, Message: e.Message}
+}
+
+// A WebSocket connection to the API, see api/openapi.yaml for the message protocol.
+// Send and Receive can be called concurrently, but not by multiple goroutines each.
+type WebSocket struct {
+	conn *websocket.Conn
+}
+
+// Opens a WebSocket connection that is authenticated with the token of the client.
+// Note that the connection does not use Config.HTTPClient, only Config.TLSConfig.
+func (c *Client) DialWebSocket(ctx context.Context) (*WebSocket, error) {
+	u, err := url.Parse(c.url("/ws", nil))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: c.timeout,
+		TLSClientConfig:  c.tlsConfig,
+	}
+	header := http.Header{}
+	if c.authorization != "" {
+		header.Set("Authorization", c.authorization)
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil && resp.StatusCode >= 300 {
+			defer resp.Body.Close()
+			return nil, decodeError(resp)
+		}
+		return nil, &networkError{err: err}
+	}
+	return &WebSocket{conn: conn}, nil
+}
+
+func (ws *WebSocket) Send(m ClientMessage) error {
+	return ws.conn.WriteJSON(m)
+}
+
+// Blocks until the next message is received.
+func (ws *WebSocket) Receive() (ServerMessage, error) {
+	var m ServerMessage
+	err := ws.conn.ReadJSON(&m)
+	return m, err
+}
+
+func (ws *WebSocket) Close() error {
+	return ws.conn.Close()
+}
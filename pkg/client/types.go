@@ -0,0 +1,282 @@
+package client
+
+import "encoding/json"
+
+// Times are Unix times in nanoseconds.
+
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name,omitempty"`
+}
+
+const RoleOwner = "owner"
+const RoleEditor = "editor"
+const RoleViewer = "viewer"
+
+const VisibilityPrivate = "private"
+const VisibilityLink = "link"
+const VisibilityPublic = "public"
+
+type NewBoard struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Board struct {
+	BoardId      string `json:"boardId"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	CreatedTime  int64  `json:"createdTime"`
+	CreatedBy    User   `json:"createdBy"`
+	ModifiedTime int64  `json:"modifiedTime,omitempty"`
+	ModifiedBy   User   `json:"modifiedBy,omitempty"`
+	// One of VisibilityPrivate, VisibilityLink and VisibilityPublic.
+	Visibility  string       `json:"visibility,omitempty"`
+	CustomRoles []CustomRole `json:"customRoles,omitempty"`
+}
+
+// A board with its users, invites and join requests.
+// These are only included if the user making the request is authorized to view them.
+type BoardWithUsersAndInvites struct {
+	Board
+	Users        []BoardUser   `json:"users"`
+	Invites      []BoardInvite `json:"invites"`
+	JoinRequests []JoinRequest `json:"joinRequests,omitempty"`
+}
+
+// Only the fields that were set are changed, use the With* methods to set them.
+type BoardEdit map[string]interface{}
+
+func (b BoardEdit) WithName(name string) BoardEdit {
+	b["name"] = name
+	return b
+}
+
+func (b BoardEdit) WithDescription(description string) BoardEdit {
+	b["description"] = description
+	return b
+}
+
+func (b BoardEdit) WithVisibility(visibility string) BoardEdit {
+	b["visibility"] = visibility
+	return b
+}
+
+type CustomRole struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type BoardUser struct {
+	User         User   `json:"user"`
+	Role         string `json:"role"`
+	CreatedTime  int64  `json:"createdTime"`
+	InvitedBy    User   `json:"invitedBy"`
+	ModifiedTime int64  `json:"modifiedTime,omitempty"`
+	ModifiedBy   User   `json:"modifiedBy,omitempty"`
+}
+
+type BoardUserEdit struct {
+	Role string `json:"role"`
+}
+
+type NewInvite struct {
+	// If empty, any user can accept the invite.
+	User User   `json:"user,omitempty"`
+	Role string `json:"role"`
+}
+
+type BoardInvite struct {
+	BoardId     string `json:"boardId"`
+	InviteId    string `json:"inviteId"`
+	Role        string `json:"role"`
+	User        User   `json:"user"`
+	CreatedTime int64  `json:"createdTime"`
+	CreatedBy   User   `json:"createdBy"`
+	ExpiresTime int64  `json:"expiresTime"`
+}
+
+const InviteResponseAccept = "accept"
+const InviteResponseDecline = "decline"
+
+type InviteResponse struct {
+	Response string `json:"response"`
+}
+
+type JoinRequest struct {
+	BoardId       string `json:"boardId,omitempty"`
+	JoinRequestId string `json:"joinRequestId"`
+	User          User   `json:"user"`
+	CreatedTime   int64  `json:"createdTime"`
+}
+
+const JoinRequestResponseApprove = "approve"
+const JoinRequestResponseReject = "reject"
+
+type JoinRequestResponse struct {
+	Response string `json:"response"`
+	// Role the user is added with if the request is approved, defaults to viewer.
+	Role string `json:"role,omitempty"`
+}
+
+type QueryParams struct {
+	// Maximum number of results, the API uses a default if 0.
+	Limit int
+	// Return only results at or before this time, see the endpoints for which time is used.
+	Cursor int64
+}
+
+type NewLink struct {
+	Title string   `json:"title"`
+	Url   string   `json:"url"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type Link struct {
+	BoardId     string   `json:"boardId"`
+	LinkId      string   `json:"linkId"`
+	Title       string   `json:"title"`
+	Url         string   `json:"url"`
+	CreatedTime int64    `json:"createdTime"`
+	CreatedBy   User     `json:"createdBy"`
+	Tags        []string `json:"tags,omitempty"`
+	Score       int      `json:"score"`
+	Upvotes     int      `json:"upvotes"`
+	// Downvotes are counted negatively, i.e. Score = Upvotes + Downvotes.
+	Downvotes int `json:"downvotes"`
+	// Rating of the user making the request, 1, -1 or 0 if the user has not rated the link.
+	UserRating int `json:"userRating"`
+}
+
+const LinkSortNewest = "newest"
+const LinkSortTop = "top"
+
+type LinkQueryParams struct {
+	// Between 10 and 100, the API uses 20 if not set.
+	Limit int
+	// LinkSortNewest or LinkSortTop, defaults to LinkSortNewest.
+	Sort string
+	// Return only links with a score less than or equal to CursorScore, only used when sorting by top.
+	CursorScore *int
+	// Return only links created at or before this time.
+	// When sorting by top, only applies to links with a score equal to CursorScore.
+	CursorCreatedTime *int64
+}
+
+// Returns the query params to get the links after the given link, which should be the last link of the previous page.
+func (qp LinkQueryParams) After(l Link) LinkQueryParams {
+	createdTime := l.CreatedTime - 1
+	qp.CursorCreatedTime = &createdTime
+	if qp.Sort == LinkSortTop {
+		score := l.Score
+		qp.CursorScore = &score
+	} else {
+		qp.CursorScore = nil
+	}
+	return qp
+}
+
+type LinkRating struct {
+	// 1 for an upvote, -1 for a downvote.
+	Rating int `json:"rating"`
+}
+
+type LinkImportResult struct {
+	Imported []Link              `json:"imported"`
+	Failed   []LinkImportFailure `json:"failed"`
+}
+
+type LinkImportFailure struct {
+	// Position of the bookmark in the file.
+	Index   int    `json:"index"`
+	Title   string `json:"title"`
+	Url     string `json:"url"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const FeedFormatAtom = "atom"
+const FeedFormatRSS = "rss"
+
+type Feed struct {
+	// Atom or RSS document, empty if the feed has not changed.
+	Body []byte
+	// Can be used as ifNoneMatch value to only get the feed if it changed.
+	ETag string
+	// True if the feed did not change since the request that returned the given ETag.
+	NotModified bool
+}
+
+type NewAccessToken struct {
+	Name string `json:"name"`
+	// Defaults to 90 days after creation.
+	ExpiresTime int64 `json:"expiresTime,omitempty"`
+	// Restrict the token to these boards and scopes, if not empty.
+	BoardIds []string `json:"boardIds,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+type AccessToken struct {
+	TokenId     string   `json:"tokenId"`
+	User        User     `json:"user"`
+	Name        string   `json:"name"`
+	CreatedTime int64    `json:"createdTime"`
+	ExpiresTime int64    `json:"expiresTime"`
+	BoardIds    []string `json:"boardIds,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+}
+
+type CreatedAccessToken struct {
+	AccessToken
+	// Only returned when the token is created.
+	Token string `json:"token"`
+}
+
+type Dashboard struct {
+	Boards       []DashboardBoard `json:"boards,omitempty"`
+	BoardsError  string           `json:"boardsError,omitempty"`
+	Invites      []BoardInvite    `json:"invites,omitempty"`
+	InvitesError string           `json:"invitesError,omitempty"`
+}
+
+type DashboardBoard struct {
+	Board
+	MemberCount int    `json:"memberCount,omitempty"`
+	TopLinks    []Link `json:"topLinks,omitempty"`
+	// Set if the member count or links of the board could not be loaded.
+	Error string `json:"error,omitempty"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message    string        `json:"message"`
+	Path       []interface{} `json:"path,omitempty"`
+	Extensions struct {
+		// Http status code and error code the corresponding endpoint would have returned.
+		Status int `json:"status"`
+		Code   int `json:"code"`
+	} `json:"extensions"`
+}
+
+type HealthStatus struct {
+	// "ok" or "unavailable".
+	Status string `json:"status"`
+	// Result of every check, only returned by Ready.
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type VersionInfo struct {
+	Version  string            `json:"version"`
+	Commit   string            `json:"commit"`
+	Backends map[string]string `json:"backends"`
+}
@@ -1,26 +1,45 @@
 package api
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
 	"math/rand"
+	"net/http/httptest"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 
-	"github.com/dkinzler/linkboards/tools/client"
+	"github.com/dkinzler/linkboards/cmd/api/app"
+	"github.com/dkinzler/linkboards/pkg/client"
 
 	"github.com/dkinzler/kit/firebase/emulator"
+	kitlog "github.com/dkinzler/kit/log"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// Address of the API when it is run in-process, empty if the tests run against an external API.
+var inprocessAddress string
+
 func TestMain(m *testing.M) {
-	// Skip these tests if the environment variable is not set.
+	// Without FIREBASE_PROJECT_ID, the tests run against an in-process instance of the API that uses
+	// in-memory data stores and fake authentication, otherwise against the API at API_ADDRESS using the Firebase auth emulator.
 	if pid := os.Getenv("FIREBASE_PROJECT_ID"); pid == "" {
-		log.Println("set FIREBASE_PROJECT_ID to run these tests")
-		os.Exit(0)
+		a, err := app.New(app.Config{UseInmemDependencies: true}, kitlog.DefaultJSONLogger(kitlog.AllowError))
+		if err != nil {
+			log.Fatalf("could not create application: %v", err)
+		}
+		server := httptest.NewServer(a)
+		inprocessAddress = server.URL
+
+		exitCode := m.Run()
+		a.Hub.Close()
+		server.Close()
+		os.Exit(exitCode)
 	}
 
 	exitCode := m.Run()
@@ -28,6 +47,10 @@ func TestMain(m *testing.M) {
 }
 
 func newClientBuilder() (*clientBuilder, error) {
+	if inprocessAddress != "" {
+		return &clientBuilder{baseURL: inprocessAddress}, nil
+	}
+
 	authClient, err := emulator.NewAuthEmulatorClient()
 	if err != nil {
 		return nil, err
@@ -38,9 +61,13 @@ func newClientBuilder() (*clientBuilder, error) {
 		apiAddress = aa
 	}
 
+	if !strings.Contains(apiAddress, "://") {
+		apiAddress = "http://" + apiAddress
+	}
+
 	ub := &clientBuilder{
 		authClient: authClient,
-		address:    apiAddress,
+		baseURL:    apiAddress,
 	}
 
 	return ub, nil
@@ -48,6 +75,8 @@ func newClientBuilder() (*clientBuilder, error) {
 
 func TestEditBoard(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -57,56 +86,55 @@ func TestEditBoard(t *testing.T) {
 	user2, client2, err := cb.createUser()
 	a.Nil(err)
 
-	board, resp, err := client1.CreateBoard(client.NewBoard{
+	board, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name:        "test board 123",
 		Description: "abc",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal("test board 123", board.Name)
 	a.Equal("abc", board.Description)
 
 	boardId := board.BoardId
 
 	// edit board
-	newBoard, resp, err := client1.EditBoard(boardId, client.BoardEdit{}.WithName("new board name").WithDescription("new board description"))
+	newBoard, err := client1.EditBoard(ctx, boardId, client.BoardEdit{}.WithName("new board name").WithDescription("new board description"))
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(boardId, newBoard.BoardId)
 	a.Equal("new board name", newBoard.Name)
 	a.Equal("new board description", newBoard.Description)
 	a.Equal(user1.Uid, newBoard.ModifiedBy.UserId)
 
-	newBoard, resp, err = client1.GetBoard(boardId)
+	board, err = client1.GetBoard(ctx, boardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
-	a.Equal(boardId, newBoard.BoardId)
-	a.Equal("new board name", newBoard.Name)
-	a.Equal("new board description", newBoard.Description)
-	a.Equal(user1.Uid, newBoard.ModifiedBy.UserId)
+	a.Equal(200, status)
+	a.Equal(boardId, board.BoardId)
+	a.Equal("new board name", board.Name)
+	a.Equal("new board description", board.Description)
+	a.Equal(user1.Uid, board.ModifiedBy.UserId)
 
 	// user2 is not part of the board, should not be able to edit board
-	newBoard, resp, err = client2.EditBoard(boardId, client.BoardEdit{}.WithName("another board name").WithDescription("xyz"))
-	a.Nil(err)
-	a.Equal(403, resp.HttpCode)
+	newBoard, err = client2.EditBoard(ctx, boardId, client.BoardEdit{}.WithName("another board name").WithDescription("xyz"))
+	a.Equal(403, client.StatusCode(err))
 	a.Empty(newBoard)
 
 	// add the user to the board with editor role, they should then be able to edit the board
-	invite, resp, err := client1.CreateInvite(boardId, client.NewInvite{
+	invite, err := client1.CreateInvite(ctx, boardId, client.NewInvite{
 		User: client.User{UserId: user2.Uid},
 		Role: client.RoleEditor,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	// accept the invite
-	resp, err = client2.RespondToInvite(boardId, invite.InviteId, client.InviteResponse{Response: client.InviteResponseAccept})
+	err = client2.RespondToInvite(ctx, boardId, invite.InviteId, client.InviteResponse{Response: client.InviteResponseAccept})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
 	// user2 is not part of the board, should not be able to edit board
-	newBoard, resp, err = client2.EditBoard(boardId, client.BoardEdit{}.WithName("another board name").WithDescription("xyz"))
+	newBoard, err = client2.EditBoard(ctx, boardId, client.BoardEdit{}.WithName("another board name").WithDescription("xyz"))
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(boardId, newBoard.BoardId)
 	a.Equal("another board name", newBoard.Name)
 	a.Equal("xyz", newBoard.Description)
@@ -114,6 +142,8 @@ func TestEditBoard(t *testing.T) {
 
 func TestInvitingUsersToBoard(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -129,99 +159,98 @@ func TestInvitingUsersToBoard(t *testing.T) {
 	user5, client5, err := cb.createUser()
 	a.Nil(err)
 
-	board, resp, err := client1.CreateBoard(client.NewBoard{
+	board, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name:        "This is a test",
 		Description: "Just a description, don't mind me.",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal("This is a test", board.Name)
 	a.Equal("Just a description, don't mind me.", board.Description)
 	a.Equal(user1.Uid, board.CreatedBy.UserId)
 	boardId := board.BoardId
 
 	// create two normal invites and one specific to user4
-	invite, resp, err := client1.CreateInvite(board.BoardId, client.NewInvite{
+	invite, err := client1.CreateInvite(ctx, board.BoardId, client.NewInvite{
 		Role: client.RoleEditor,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal(board.BoardId, invite.BoardId)
 	a.Equal(client.RoleEditor, invite.Role)
 	a.Equal(user1.Uid, invite.CreatedBy.UserId)
 
-	invite2, resp, err := client1.CreateInvite(board.BoardId, client.NewInvite{
+	invite2, err := client1.CreateInvite(ctx, board.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal(board.BoardId, invite2.BoardId)
 	a.Equal(client.RoleViewer, invite2.Role)
 	a.Equal(user1.Uid, invite2.CreatedBy.UserId)
 
-	invite3, resp, err := client1.CreateInvite(board.BoardId, client.NewInvite{
+	invite3, err := client1.CreateInvite(ctx, board.BoardId, client.NewInvite{
 		User: client.User{
 			UserId: user4.Uid,
 		},
 		Role: client.RoleViewer,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal(board.BoardId, invite3.BoardId)
 	a.Equal(client.RoleViewer, invite3.Role)
 	a.Equal(user1.Uid, invite3.CreatedBy.UserId)
 
-	board, resp, err = client1.GetBoard(board.BoardId)
+	board, err = client1.GetBoard(ctx, board.BoardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board.Users, 1)
 	a.Len(board.Invites, 3)
 
-	resp, err = client2.RespondToInvite(board.BoardId, invite.InviteId, client.InviteResponse{
+	err = client2.RespondToInvite(ctx, board.BoardId, invite.InviteId, client.InviteResponse{
 		Response: client.InviteResponseAccept,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
 	// user2 has role "editor", so they should be able to see invites and users
-	board, resp, err = client2.GetBoard(board.BoardId)
+	board, err = client2.GetBoard(ctx, board.BoardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board.Users, 2)
 	a.Len(board.Invites, 2)
 
 	// user3 should not be able to respond to invite3 since it is meant for user4
-	resp, err = client3.RespondToInvite(board.BoardId, invite3.InviteId, client.InviteResponse{
+	err = client3.RespondToInvite(ctx, board.BoardId, invite3.InviteId, client.InviteResponse{
 		Response: client.InviteResponseAccept,
 	})
-	a.Nil(err)
-	a.Equal(400, resp.HttpCode)
-	a.NotZero(resp.ErrorCode)
-	a.NotEmpty(resp.ErrorMessage)
+	a.Equal(400, client.StatusCode(err))
+	a.NotZero(client.ErrorCode(err))
+	a.NotEmpty(errorMessage(err))
 
-	resp, err = client3.RespondToInvite(board.BoardId, invite2.InviteId, client.InviteResponse{
+	err = client3.RespondToInvite(ctx, board.BoardId, invite2.InviteId, client.InviteResponse{
 		Response: client.InviteResponseAccept,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
 	// user3 has role "viewer", they should NOT be able to see invites and users
-	board, resp, err = client3.GetBoard(board.BoardId)
+	board, err = client3.GetBoard(ctx, board.BoardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board.Users, 0)
 	a.Len(board.Invites, 0)
 
-	resp, err = client4.RespondToInvite(board.BoardId, invite3.InviteId, client.InviteResponse{
+	err = client4.RespondToInvite(ctx, board.BoardId, invite3.InviteId, client.InviteResponse{
 		Response: client.InviteResponseAccept,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
 	// there should be no more invites
-	board, resp, err = client1.GetBoard(board.BoardId)
+	board, err = client1.GetBoard(ctx, board.BoardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board.Users, 4)
 	a.Len(board.Invites, 0)
 	a.ElementsMatch([]string{
@@ -229,72 +258,72 @@ func TestInvitingUsersToBoard(t *testing.T) {
 	}, userIdsFromUsers(board.Users))
 
 	// user5 is not on the board, can't access it
-	newBoard, resp, err := client5.GetBoard(boardId)
-	a.Nil(err)
-	a.Equal(403, resp.HttpCode)
+	newBoard, err := client5.GetBoard(ctx, boardId)
+	a.Equal(403, client.StatusCode(err))
 	a.Empty(newBoard)
 
 	// user2 has role editor, should be able to create an invite
-	invite5, resp, err := client2.CreateInvite(board.BoardId, client.NewInvite{
+	invite5, err := client2.CreateInvite(ctx, board.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 		User: client.User{
 			UserId: user5.Uid,
 		},
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal(board.BoardId, invite5.BoardId)
 	a.Equal(client.RoleViewer, invite5.Role)
 	a.Equal(user2.Uid, invite5.CreatedBy.UserId)
 	a.Equal(user5.Uid, invite5.User.UserId)
 
-	invite6, resp, err := client2.CreateInvite(board.BoardId, client.NewInvite{
+	invite6, err := client2.CreateInvite(ctx, board.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 
 	// shouldn't be able to create an invite for the same user again
-	invite7, resp, err := client2.CreateInvite(board.BoardId, client.NewInvite{
+	invite7, err := client2.CreateInvite(ctx, board.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 		User: client.User{
 			UserId: user5.Uid,
 		},
 	})
-	a.Nil(err)
-	a.Equal(400, resp.HttpCode)
+	a.Equal(400, client.StatusCode(err))
 	a.Empty(invite7)
-	a.NotZero(resp.ErrorCode)
-	a.NotEmpty(resp.ErrorMessage)
+	a.NotZero(client.ErrorCode(err))
+	a.NotEmpty(errorMessage(err))
 
 	// there should now be 2 invites
-	board2, resp, err := client2.GetBoard(board.BoardId)
+	board2, err := client2.GetBoard(ctx, board.BoardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board2.Users, 4)
 	a.Len(board2.Invites, 2)
 
 	// decline one invite and delete the other
-	resp, err = client5.RespondToInvite(boardId, invite5.InviteId, client.InviteResponse{
+	err = client5.RespondToInvite(ctx, boardId, invite5.InviteId, client.InviteResponse{
 		Response: client.InviteResponseDecline,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
-	resp, err = client2.DeleteInvite(boardId, invite6.InviteId)
+	err = client2.DeleteInvite(ctx, boardId, invite6.InviteId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
 	// there should now be 0 invites
-	board2, resp, err = client2.GetBoard(board.BoardId)
+	board2, err = client2.GetBoard(ctx, board.BoardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board2.Users, 4)
 	a.Len(board2.Invites, 0)
 }
 
 func TestEditingBoardUsers(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -306,77 +335,77 @@ func TestEditingBoardUsers(t *testing.T) {
 	user3, client3, err := cb.createUser()
 	a.Nil(err)
 
-	board, resp, err := client1.CreateBoard(client.NewBoard{
+	board, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name:        "test board 123",
 		Description: "abc",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	a.Equal("test board 123", board.Name)
 	a.Equal("abc", board.Description)
 
 	boardId := board.BoardId
 
 	// add user2 and user3 to the board
-	invite2, resp, err := client1.CreateInvite(boardId, client.NewInvite{
+	invite2, err := client1.CreateInvite(ctx, boardId, client.NewInvite{
 		User: client.User{UserId: user2.Uid},
 		Role: client.RoleViewer,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
-	invite3, resp, err := client1.CreateInvite(boardId, client.NewInvite{
+	a.Equal(201, status)
+	invite3, err := client1.CreateInvite(ctx, boardId, client.NewInvite{
 		User: client.User{UserId: user3.Uid},
 		Role: client.RoleViewer,
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	// accept the invites
-	resp, err = client2.RespondToInvite(boardId, invite2.InviteId, client.InviteResponse{Response: client.InviteResponseAccept})
+	err = client2.RespondToInvite(ctx, boardId, invite2.InviteId, client.InviteResponse{Response: client.InviteResponseAccept})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
-	resp, err = client3.RespondToInvite(boardId, invite3.InviteId, client.InviteResponse{Response: client.InviteResponseAccept})
+	a.Equal(200, status)
+	err = client3.RespondToInvite(ctx, boardId, invite3.InviteId, client.InviteResponse{Response: client.InviteResponseAccept})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
-	board, resp, err = client1.GetBoard(boardId)
+	board, err = client1.GetBoard(ctx, boardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board.Users, 3)
 	a.ElementsMatch([]string{user1.Uid, user2.Uid, user3.Uid}, userIdsFromUsers(board.Users))
 
 	// user2 has role viewer, should not be able to remove user4
-	resp, err = client2.RemoveUserFromBoard(boardId, user3.Uid)
-	a.Nil(err)
-	a.Equal(403, resp.HttpCode)
+	err = client2.RemoveUserFromBoard(ctx, boardId, user3.Uid)
+	a.Equal(403, client.StatusCode(err))
 
 	// change the role of user2 to be editor, they should then be able to delete/remove user3 from the board
-	boardUser, resp, err := client1.EditBoardUser(boardId, user2.Uid, client.BoardUserEdit{}.WithRole(client.RoleEditor))
+	boardUser, err := client1.EditBoardUser(ctx, boardId, user2.Uid, client.BoardUserEdit{Role: client.RoleEditor})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(client.RoleEditor, boardUser.Role)
 	a.Equal(user2.Uid, boardUser.User.UserId)
 	a.Equal(user1.Uid, boardUser.ModifiedBy.UserId)
 
-	resp, err = client2.RemoveUserFromBoard(boardId, user3.Uid)
+	err = client2.RemoveUserFromBoard(ctx, boardId, user3.Uid)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
-	board, resp, err = client2.GetBoard(boardId)
+	board, err = client2.GetBoard(ctx, boardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(board.Invites, 0)
 	a.Len(board.Users, 2)
 	a.ElementsMatch([]string{user1.Uid, user2.Uid}, userIdsFromUsers(board.Users))
 
 	// client 3 should not longer be able to get board
-	board, resp, err = client3.GetBoard(boardId)
-	a.Nil(err)
-	a.Equal(403, resp.HttpCode)
+	board, err = client3.GetBoard(ctx, boardId)
+	a.Equal(403, client.StatusCode(err))
 	a.Empty(board)
 }
 
 func TestGetInvitesAndBoardsForUser(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -387,60 +416,62 @@ func TestGetInvitesAndBoardsForUser(t *testing.T) {
 	a.Nil(err)
 
 	// create multiple boards, and send multiple invites
-	b1, resp, err := client1.CreateBoard(client.NewBoard{
+	b1, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name: "board1",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
-	b2, resp, err := client1.CreateBoard(client.NewBoard{
+	a.Equal(201, status)
+	b2, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name: "board2",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
-	b3, resp, err := client1.CreateBoard(client.NewBoard{
+	a.Equal(201, status)
+	b3, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name: "board3",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 
-	i1, resp, err := client1.CreateInvite(b1.BoardId, client.NewInvite{
+	i1, err := client1.CreateInvite(ctx, b1.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 		User: client.User{
 			UserId: user2.Uid,
 		},
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
-	i2, resp, err := client1.CreateInvite(b2.BoardId, client.NewInvite{
+	a.Equal(201, status)
+	i2, err := client1.CreateInvite(ctx, b2.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 		User: client.User{
 			UserId: user2.Uid,
 		},
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
-	i3, resp, err := client1.CreateInvite(b3.BoardId, client.NewInvite{
+	a.Equal(201, status)
+	i3, err := client1.CreateInvite(ctx, b3.BoardId, client.NewInvite{
 		Role: client.RoleViewer,
 		User: client.User{
 			UserId: user2.Uid,
 		},
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 
-	invites, resp, err := client2.GetInvites(client.QueryParams{})
+	invites, err := client2.GetInvites(ctx, client.QueryParams{})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.ElementsMatch([]string{i1.InviteId, i2.InviteId, i3.InviteId}, inviteIdsFromInvites(invites))
 
-	boards, resp, err := client1.GetBoards(client.QueryParams{})
+	boards, err := client1.GetBoards(ctx, client.QueryParams{})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.ElementsMatch([]string{b1.BoardId, b2.BoardId, b3.BoardId}, boardIdsFromBoards(boards))
 }
 
 func TestDeleteBoard(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -448,31 +479,32 @@ func TestDeleteBoard(t *testing.T) {
 	_, client1, err := cb.createUser()
 	a.Nil(err)
 
-	board, resp, err := client1.CreateBoard(client.NewBoard{
+	board, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name: "board1",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	boardId := board.BoardId
 
-	newBoard, resp, err := client1.GetBoard(boardId)
+	newBoard, err := client1.GetBoard(ctx, boardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(newBoard.BoardId, board.BoardId)
 	a.Equal(newBoard.Users, board.Users)
 
-	resp, err = client1.DeleteBoard(boardId)
+	err = client1.DeleteBoard(ctx, boardId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
-	newBoard, resp, err = client1.GetBoard(boardId)
-	a.Nil(err)
-	a.Equal(403, resp.HttpCode)
+	newBoard, err = client1.GetBoard(ctx, boardId)
+	a.Equal(403, client.StatusCode(err))
 	a.Empty(newBoard)
 }
 
 func TestLink(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -486,32 +518,32 @@ func TestLink(t *testing.T) {
 	_, client4, err := cb.createUser()
 	a.Nil(err)
 
-	boardId, err := createBoardWithUsers(t, []*client.ApiClient{
+	boardId, err := createBoardWithUsers(t, []*client.Client{
 		client1, client2, client3, client4,
 	})
 	a.Nil(err)
 
 	// create link and let other users rate it
 
-	link, resp, err := client2.CreateLink(boardId, client.NewLink{
+	link, err := client2.CreateLink(ctx, boardId, client.NewLink{
 		Title: "just a link",
 		Url:   "https://justarandomhost.com/amazing.png",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	linkId := link.LinkId
 
-	for _, c := range []*client.ApiClient{client1, client2, client3} {
-		resp, err := c.RateLink(boardId, linkId, client.LinkRating{
+	for _, c := range []*client.Client{client1, client2, client3} {
+		err := c.RateLink(ctx, boardId, linkId, client.LinkRating{
 			Rating: 1,
 		})
 		a.Nil(err)
-		a.Equal(200, resp.HttpCode)
+		a.Equal(200, status)
 	}
 
-	link, resp, err = client1.GetLink(boardId, linkId)
+	link, err = client1.GetLink(ctx, boardId, linkId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(linkId, link.LinkId)
 	a.Equal("just a link", link.Title)
 	a.Equal("https://justarandomhost.com/amazing.png", link.Url)
@@ -521,39 +553,37 @@ func TestLink(t *testing.T) {
 	a.Equal(1, link.UserRating)
 
 	// user 4 has not rated yet, should have 0 user rating
-	link, resp, err = client4.GetLink(boardId, linkId)
+	link, err = client4.GetLink(ctx, boardId, linkId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(3, link.Score)
 	a.Equal(0, link.Downvotes)
 	a.Equal(3, link.Upvotes)
 	a.Equal(0, link.UserRating)
 
 	// user 4 downvotes
-	resp, err = client4.RateLink(boardId, linkId, client.LinkRating{Rating: -1})
+	err = client4.RateLink(ctx, boardId, linkId, client.LinkRating{Rating: -1})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
-	link, resp, err = client4.GetLink(boardId, linkId)
+	link, err = client4.GetLink(ctx, boardId, linkId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Equal(2, link.Score)
 	a.Equal(-1, link.Downvotes)
 	a.Equal(3, link.Upvotes)
 	a.Equal(-1, link.UserRating)
 
 	// delete link, client4 shouldn't be able to, but client 2 and 1 can
-	resp, err = client4.DeleteLink(boardId, linkId)
-	a.Nil(err)
-	a.Equal(403, resp.HttpCode)
+	err = client4.DeleteLink(ctx, boardId, linkId)
+	a.Equal(403, client.StatusCode(err))
 
-	resp, err = client1.DeleteLink(boardId, linkId)
+	err = client1.DeleteLink(ctx, boardId, linkId)
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 
-	link, resp, err = client2.GetLink(boardId, linkId)
-	a.Nil(err)
-	a.Equal(404, resp.HttpCode)
+	link, err = client2.GetLink(ctx, boardId, linkId)
+	a.Equal(404, client.StatusCode(err))
 	a.Empty(link)
 }
 
@@ -621,6 +651,8 @@ func sortInviteQueryElements(invites []inviteQueryElement) []inviteQueryElement
 
 func TestBoardsAndInvitesQueryAndPagination(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -633,23 +665,23 @@ func TestBoardsAndInvitesQueryAndPagination(t *testing.T) {
 	invites := make([]inviteQueryElement, boardCount)
 	boards := make([]boardQueryElement, boardCount)
 	for i := 0; i < boardCount; i++ {
-		board, resp, err := client1.CreateBoard(client.NewBoard{
+		board, err := client1.CreateBoard(ctx, client.NewBoard{
 			Name: "board1",
 		})
 		a.Nil(err)
-		a.Equal(201, resp.HttpCode)
+		a.Equal(201, status)
 		boardId := board.BoardId
 		boards[i] = boardQueryElement{
 			BoardId:     boardId,
 			CreatedTime: board.CreatedTime,
 		}
 
-		invite, resp, err := client1.CreateInvite(boardId, client.NewInvite{
+		invite, err := client1.CreateInvite(ctx, boardId, client.NewInvite{
 			User: client.User{UserId: user2.Uid},
 			Role: client.RoleViewer,
 		})
 		a.Nil(err)
-		a.Equal(201, resp.HttpCode)
+		a.Equal(201, status)
 		invites[i] = inviteQueryElement{
 			BoardId:     boardId,
 			InviteId:    invite.InviteId,
@@ -660,44 +692,46 @@ func TestBoardsAndInvitesQueryAndPagination(t *testing.T) {
 	sortedInvites := sortInviteQueryElements(invites)
 
 	// test board queries and pagination
-	br, resp, err := client1.GetBoards(client.QueryParams{
+	br, err := client1.GetBoards(ctx, client.QueryParams{
 		Limit: 11,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(br, 11)
 	a.Equal(sortedBoards[:11], boardQueryElementsFromBoards(br))
 
-	br, resp, err = client1.GetBoards(client.QueryParams{
+	br, err = client1.GetBoards(ctx, client.QueryParams{
 		Limit:  11,
 		Cursor: sortedBoards[10].CreatedTime - 10,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(br, boardCount-11)
 	a.Equal(sortedBoards[11:], boardQueryElementsFromBoards(br))
 
 	// test invite queries and pagination
-	ir, resp, err := client2.GetInvites(client.QueryParams{
+	ir, err := client2.GetInvites(ctx, client.QueryParams{
 		Limit: 13,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(ir, 13)
 	a.Equal(sortedInvites[:13], inviteQueryElementsFromInvites(ir))
 
-	ir, resp, err = client2.GetInvites(client.QueryParams{
+	ir, err = client2.GetInvites(ctx, client.QueryParams{
 		Limit:  13,
 		Cursor: sortedInvites[12].CreatedTime - 3,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(ir, boardCount-13)
 	a.Equal(sortedInvites[13:], inviteQueryElementsFromInvites(ir))
 }
 
 func TestLinksQueryAndPagination(t *testing.T) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	cb, err := newClientBuilder()
 	a.Nil(err)
@@ -709,12 +743,12 @@ func TestLinksQueryAndPagination(t *testing.T) {
 	a.Nil(err)
 
 	c := clients[0]
-	links, resp, err := c.GetLinks(boardId, client.LinkQueryParams{
+	links, err := c.GetLinks(ctx, boardId, client.LinkQueryParams{
 		Limit: 10,
 		Sort:  client.LinkSortTop,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(links, 10)
 	linkIdsByScore := sortLinksByScore(linkIds)
 	a.Equal(linkIdsByScore[:10], linkIdSortFromLinks(links))
@@ -722,37 +756,37 @@ func TestLinksQueryAndPagination(t *testing.T) {
 	// load remaining results
 	scoreCursor := linkIdsByScore[9].Score
 	timeCreatedCursor := linkIdsByScore[9].CreatedTime - 2
-	links, resp, err = c.GetLinks(boardId, client.LinkQueryParams{
+	links, err = c.GetLinks(ctx, boardId, client.LinkQueryParams{
 		Limit:             10,
 		Sort:              client.LinkSortTop,
 		CursorScore:       &scoreCursor,
 		CursorCreatedTime: &timeCreatedCursor,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(links, len(scores)-10)
 	a.Equal(linkIdsByScore[10:], linkIdSortFromLinks(links))
 
 	// sort by newest
-	links, resp, err = c.GetLinks(boardId, client.LinkQueryParams{
+	links, err = c.GetLinks(ctx, boardId, client.LinkQueryParams{
 		Limit: 10,
 		Sort:  client.LinkSortNewest,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(links, 10)
 	linkIdsByNewest := sortLinksByNewest(linkIds)
 	a.Equal(linkIdsByNewest[:10], linkIdSortFromLinks(links))
 
 	// load remaining results
 	lastCreatedTime := linkIdsByNewest[9].CreatedTime - 2
-	links, resp, err = c.GetLinks(boardId, client.LinkQueryParams{
+	links, err = c.GetLinks(ctx, boardId, client.LinkQueryParams{
 		Limit:             10,
 		Sort:              client.LinkSortNewest,
 		CursorCreatedTime: &lastCreatedTime,
 	})
 	a.Nil(err)
-	a.Equal(200, resp.HttpCode)
+	a.Equal(200, status)
 	a.Len(links, len(scores)-10)
 	a.Equal(linkIdsByNewest[10:], linkIdSortFromLinks(links))
 }
@@ -803,32 +837,34 @@ func linkIdSortFromLinks(links []client.Link) []linkIdSort {
 }
 
 // first client passed will create the board, others will be added with viewer role
-func createBoardWithUsers(t *testing.T, clients []*client.ApiClient) (string, error) {
+func createBoardWithUsers(t *testing.T, clients []*client.Client) (string, error) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	if len(clients) < 1 {
 		return "", errors.New("need to pass at least one client")
 	}
 	client1 := clients[0]
 
-	board, resp, err := client1.CreateBoard(client.NewBoard{
+	board, err := client1.CreateBoard(ctx, client.NewBoard{
 		Name: "board1",
 	})
 	a.Nil(err)
-	a.Equal(201, resp.HttpCode)
+	a.Equal(201, status)
 	boardId := board.BoardId
 
 	for _, c := range clients[1:] {
-		invite, resp, err := client1.CreateInvite(boardId, client.NewInvite{
+		invite, err := client1.CreateInvite(ctx, boardId, client.NewInvite{
 			Role: client.RoleViewer,
 		})
 		a.Nil(err)
-		a.Equal(201, resp.HttpCode)
-		resp, err = c.RespondToInvite(boardId, invite.InviteId, client.InviteResponse{
+		a.Equal(201, status)
+		err = c.RespondToInvite(ctx, boardId, invite.InviteId, client.InviteResponse{
 			Response: client.InviteResponseAccept,
 		})
 		a.Nil(err)
-		a.Equal(200, resp.HttpCode)
+		a.Equal(200, status)
 	}
 
 	return boardId, nil
@@ -840,8 +876,10 @@ type linkIdSort struct {
 	Score       int
 }
 
-func createLinksWithScoes(t *testing.T, cb *clientBuilder, scores []int) (string, []linkIdSort, []*client.ApiClient, error) {
+func createLinksWithScoes(t *testing.T, cb *clientBuilder, scores []int) (string, []linkIdSort, []*client.Client, error) {
 	a := assert.New(t)
+	var status int
+	ctx := client.WithStatusCode(context.Background(), &status)
 
 	if len(scores) == 0 {
 		return "", nil, nil, errors.New("empty list of scores")
@@ -863,7 +901,7 @@ func createLinksWithScoes(t *testing.T, cb *clientBuilder, scores []int) (string
 		return "", nil, nil, fmt.Errorf("cannot have link with score: %v", clientsNeeded)
 	}
 
-	clients := make([]*client.ApiClient, clientsNeeded)
+	clients := make([]*client.Client, clientsNeeded)
 	for i := 0; i < clientsNeeded; i++ {
 		_, client, err := cb.createUser()
 		if err != nil {
@@ -882,12 +920,12 @@ func createLinksWithScoes(t *testing.T, cb *clientBuilder, scores []int) (string
 	// create links and vote for them
 	linkIds := make([]linkIdSort, len(scores))
 	for i, score := range scores {
-		link, resp, err := linkCreater.CreateLink(boardId, client.NewLink{
+		link, err := linkCreater.CreateLink(ctx, boardId, client.NewLink{
 			Title: "some title",
 			Url:   "https://justaurl.com/amazing.png",
 		})
 		a.Nil(err)
-		a.Equal(201, resp.HttpCode)
+		a.Equal(201, status)
 		linkIds[i] = linkIdSort{
 			LinkId:      link.LinkId,
 			CreatedTime: link.CreatedTime,
@@ -903,11 +941,11 @@ func createLinksWithScoes(t *testing.T, cb *clientBuilder, scores []int) (string
 
 		for j := 0; j < absScore; j++ {
 			c := clients[j]
-			resp, err := c.RateLink(boardId, link.LinkId, client.LinkRating{
+			err := c.RateLink(ctx, boardId, link.LinkId, client.LinkRating{
 				Rating: rating,
 			})
 			a.Nil(err)
-			a.Equal(200, resp.HttpCode)
+			a.Equal(200, status)
 		}
 	}
 
@@ -930,6 +968,15 @@ func inviteIdsFromInvites(invites []client.BoardInvite) []string {
 	return result
 }
 
+// Returns the public error message of the API, or the empty string if err is not an *client.Error.
+func errorMessage(err error) string {
+	var e *client.Error
+	if errors.As(err, &e) {
+		return e.Message
+	}
+	return ""
+}
+
 func boardIdsFromBoards(boards []client.Board) []string {
 	result := make([]string, len(boards))
 	for i, board := range boards {
@@ -945,14 +992,27 @@ type user struct {
 }
 
 type clientBuilder struct {
+	// If nil, users are not created but authenticated with basic auth credentials, as expected by the fake authentication mechanism.
 	authClient *emulator.AuthEmulatorClient
-	address    string
+	baseURL    string
 }
 
 // create a new user with a random email address
-func (u clientBuilder) createUser() (user, *client.ApiClient, error) {
+func (u clientBuilder) createUser() (user, *client.Client, error) {
 	email := fmt.Sprintf("%v@test.de", rand.Int63())
 
+	if u.authClient == nil {
+		uid := fmt.Sprintf("u-%v", rand.Int63())
+		apiClient, err := client.New(client.Config{
+			BaseURL:       u.baseURL,
+			Authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte(uid+":")),
+		})
+		if err != nil {
+			return user{}, nil, err
+		}
+		return user{Uid: uid, Email: email}, apiClient, nil
+	}
+
 	uid, err := u.authClient.CreateUser(email, "test123", true)
 	if err != nil {
 		return user{}, nil, err
@@ -962,7 +1022,10 @@ func (u clientBuilder) createUser() (user, *client.ApiClient, error) {
 		return user{}, nil, err
 	}
 
-	apiClient := client.NewApiClient(uid, token, u.address)
+	apiClient, err := client.New(client.Config{BaseURL: u.baseURL, Token: token})
+	if err != nil {
+		return user{}, nil, err
+	}
 	return user{
 		Uid:   uid,
 		Email: email,